@@ -53,7 +53,7 @@ func run() error {
 		if err != nil {
 			return fmt.Errorf("import bolt state: %w", err)
 		}
-		logger.Info("imported bolt state into postgres", "guild_defaults", result.GuildDefaultsImported, "user_defaults", result.UserDefaultsImported)
+		logger.Info("imported bolt state into postgres", "guild_defaults", result.GuildDefaultsImported, "user_defaults", result.UserDefaultsImported, "outbox_cursors", result.OutboxCursorsImported)
 		return nil
 	}
 
@@ -50,6 +50,7 @@ type Instance struct {
 	Name           string            `json:"name"`
 	Season         int32             `json:"season"`
 	CreatedAt      string            `json:"created_at"`
+	State          string            `json:"state,omitempty"`
 	CurrentEpisode *InstanceEpisode  `json:"current_episode,omitempty"`
 	Episodes       []InstanceEpisode `json:"episodes,omitempty"`
 }
@@ -89,11 +90,63 @@ type LeaderboardRow struct {
 	ParticipantName          string `json:"participant_name"`
 	ParticipantDiscordUserID string `json:"participant_discord_user_id"`
 	CurrentTribeName         string `json:"current_tribe_name"`
+	Rank                     int    `json:"rank"`
+	PreviousRank             *int   `json:"previous_rank"`
+	RankChange               *int   `json:"rank_change"`
 	Score                    int    `json:"score"`
 	DraftPoints              int    `json:"draft_points"`
 	BonusPoints              int    `json:"bonus_points"`
 	TotalPoints              int    `json:"total_points"`
 	PointsAvailable          int    `json:"points_available"`
+	MaxPossiblePoints        int    `json:"max_possible_points"`
+	EliminatedOnCurrentBonus bool   `json:"eliminated_on_current_bonus"`
+	ClinchedOnCurrentBonus   bool   `json:"clinched_on_current_bonus"`
+}
+
+// Leaderboard is a scored leaderboard along with the scoring strategy the
+// instance used to produce it.
+type Leaderboard struct {
+	ScoringStrategy string           `json:"scoring_strategy"`
+	Rows            []LeaderboardRow `json:"leaderboard"`
+}
+
+// WhatIfElimination is a hypothetical elimination applied on top of the real
+// outcomes, as resolved by the server.
+type WhatIfElimination struct {
+	ContestantID   string `json:"contestant_id"`
+	ContestantName string `json:"contestant_name"`
+	Position       int    `json:"position"`
+}
+
+type WhatIfRow struct {
+	ParticipantID      string `json:"participant_id"`
+	ParticipantName    string `json:"participant_name"`
+	Rank               int    `json:"rank"`
+	CurrentRank        int    `json:"current_rank"`
+	RankChange         int    `json:"rank_change"`
+	DraftPoints        int    `json:"draft_points"`
+	BonusPoints        int    `json:"bonus_points"`
+	TotalPoints        int    `json:"total_points"`
+	CurrentTotalPoints int    `json:"current_total_points"`
+	PointsChange       int    `json:"points_change"`
+	PointsAvailable    int    `json:"points_available"`
+}
+
+// WhatIfLeaderboard is the leaderboard projected from hypothetical
+// eliminations. Nothing in it has been persisted.
+type WhatIfLeaderboard struct {
+	ScoringStrategy string              `json:"scoring_strategy"`
+	Eliminations    []WhatIfElimination `json:"eliminations"`
+	Rows            []WhatIfRow         `json:"leaderboard"`
+}
+
+// Standing returns the API rank, falling back to the row's 1-based position
+// for servers that do not report ranks.
+func (r LeaderboardRow) Standing(index int) int {
+	if r.Rank > 0 {
+		return r.Rank
+	}
+	return index + 1
 }
 
 func (r LeaderboardRow) Total() int {
@@ -319,22 +372,60 @@ type OwnedPony struct {
 }
 
 type LoanStatus struct {
-	HasActiveLoan         bool   `json:"has_active_loan"`
-	LoanID                string `json:"loan_id,omitempty"`
-	Status                string `json:"status,omitempty"`
-	PrincipalPoints       int    `json:"principal_points"`
-	InterestPoints        int    `json:"interest_points"`
-	PrincipalRepaidPoints int    `json:"principal_repaid_points"`
-	InterestRepaidPoints  int    `json:"interest_repaid_points"`
-	PrincipalOutstanding  int    `json:"principal_outstanding_points"`
-	InterestOutstanding   int    `json:"interest_outstanding_points"`
-	TotalDuePoints        int    `json:"total_due_points"`
-	RemainingBorrowPoints int    `json:"remaining_borrow_points"`
-	MaxPrincipalPoints    int    `json:"max_principal_points"`
-	BonusPointsAvailable  int    `json:"bonus_points_available"`
-	GrantedAt             string `json:"granted_at,omitempty"`
-	DueAt                 string `json:"due_at,omitempty"`
-	ActivityID            string `json:"activity_id,omitempty"`
+	HasActiveLoan         bool         `json:"has_active_loan"`
+	LoanID                string       `json:"loan_id,omitempty"`
+	Status                string       `json:"status,omitempty"`
+	PrincipalPoints       int          `json:"principal_points"`
+	InterestPoints        int          `json:"interest_points"`
+	PrincipalRepaidPoints int          `json:"principal_repaid_points"`
+	InterestRepaidPoints  int          `json:"interest_repaid_points"`
+	PrincipalOutstanding  int          `json:"principal_outstanding_points"`
+	InterestOutstanding   int          `json:"interest_outstanding_points"`
+	TotalDuePoints        int          `json:"total_due_points"`
+	RemainingBorrowPoints int          `json:"remaining_borrow_points"`
+	MaxPrincipalPoints    int          `json:"max_principal_points"`
+	BonusPointsAvailable  int          `json:"bonus_points_available"`
+	GrantedAt             string       `json:"granted_at,omitempty"`
+	DueAt                 string       `json:"due_at,omitempty"`
+	ActivityID            string       `json:"activity_id,omitempty"`
+	LastDefault           *LoanDefault `json:"last_default,omitempty"`
+}
+
+// LoanDefault summarizes a loan the Loan Shark settled because it went unpaid
+// past its due date.
+type LoanDefault struct {
+	LoanID          string `json:"loan_id"`
+	PrincipalPoints int    `json:"principal_points"`
+	InterestPoints  int    `json:"interest_points"`
+	CollectedPoints int    `json:"collected_points"`
+	ShortfallPoints int    `json:"shortfall_points"`
+	PenaltyPoints   int    `json:"penalty_points"`
+	DueAt           string `json:"due_at"`
+	DefaultedAt     string `json:"defaulted_at"`
+}
+
+// OutboxEvent is a domain event read from an instance's outbox.
+type OutboxEvent struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt string          `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// OutboxEventPage is one outbox poll, oldest event first. NextAfter is the
+// cursor to pass as After on the next poll.
+type OutboxEventPage struct {
+	Events    []OutboxEvent `json:"events"`
+	NextAfter string        `json:"next_after"`
+}
+
+// LoanDefaultedEvent is the data of a loan.defaulted outbox event.
+type LoanDefaultedEvent struct {
+	LoanID          string `json:"loan_id"`
+	ParticipantID   string `json:"participant_id"`
+	ParticipantName string `json:"participant_name"`
+	OccurrenceID    string `json:"occurrence_id"`
+	DefaultedAt     string `json:"defaulted_at"`
 }
 
 type AuctionStatus struct {
@@ -404,6 +495,19 @@ type ListParticipantsOptions struct {
 	Name string
 }
 
+// ListOutboxEventsOptions filters an outbox poll. After is the next_after
+// cursor from the previous poll; Since bounds a poll that has no cursor yet.
+type ListOutboxEventsOptions struct {
+	Types []string
+	After string
+	Since time.Time
+}
+
+type LeaderboardOptions struct {
+	ParticipantID string
+	Episode       *int
+}
+
 type APIError struct {
 	StatusCode int
 	Message    string
@@ -490,21 +594,38 @@ func (c *Client) ListParticipants(ctx context.Context, instanceID string, opts L
 	return response.Participants, nil
 }
 
-func (c *Client) GetLeaderboard(ctx context.Context, instanceID string, participantID string) ([]LeaderboardRow, error) {
+func (c *Client) GetLeaderboard(ctx context.Context, instanceID string, opts LeaderboardOptions) (Leaderboard, error) {
 	requestURL := c.endpoint(path.Join("/instances", instanceID, "leaderboard"))
 	query := requestURL.Query()
-	if strings.TrimSpace(participantID) != "" {
-		query.Set("participant_id", strings.TrimSpace(participantID))
+	if strings.TrimSpace(opts.ParticipantID) != "" {
+		query.Set("participant_id", strings.TrimSpace(opts.ParticipantID))
+	}
+	if opts.Episode != nil {
+		query.Set("episode", strconv.Itoa(*opts.Episode))
 	}
 	requestURL.RawQuery = query.Encode()
 
-	var response struct {
-		Leaderboard []LeaderboardRow `json:"leaderboard"`
-	}
+	var response Leaderboard
 	if err := c.getJSON(ctx, requestURL, nil, &response); err != nil {
-		return nil, err
+		return Leaderboard{}, err
+	}
+	return response, nil
+}
+
+// WhatIf projects the leaderboard if the named contestants were eliminated
+// next, in order.
+func (c *Client) WhatIf(ctx context.Context, instanceID string, contestantNames []string) (WhatIfLeaderboard, error) {
+	eliminations := make([]map[string]string, 0, len(contestantNames))
+	for _, name := range contestantNames {
+		eliminations = append(eliminations, map[string]string{"contestant_name": name})
+	}
+	body := map[string]any{"eliminations": eliminations}
+
+	var result WhatIfLeaderboard
+	if err := c.doJSONBody(ctx, http.MethodPost, c.endpoint(path.Join("/instances", instanceID, "leaderboard", "what-if")), nil, body, &result); err != nil {
+		return WhatIfLeaderboard{}, err
 	}
-	return response.Leaderboard, nil
+	return result, nil
 }
 
 func (c *Client) ListActivities(ctx context.Context, instanceID string) ([]Activity, error) {
@@ -649,9 +770,9 @@ func (c *Client) CloseStirThePotRound(ctx context.Context, instanceID, actorDisc
 	return result, nil
 }
 
-func (c *Client) AddStirThePotContribution(ctx context.Context, instanceID, discordUserID, participantID string, points int) (StirThePotContributionResult, error) {
+func (c *Client) AddStirThePotContribution(ctx context.Context, instanceID, discordUserID, participantID string, points int, idempotencyKey string) (StirThePotContributionResult, error) {
 	var result StirThePotContributionResult
-	headers := requestHeadersForDiscordUser(discordUserID)
+	headers := requestHeadersForIdempotentWrite(discordUserID, idempotencyKey)
 	body := map[string]any{"points": points}
 	if strings.TrimSpace(participantID) != "" {
 		body["participant_id"] = strings.TrimSpace(participantID)
@@ -690,9 +811,9 @@ func (c *Client) StopAuctionLot(ctx context.Context, instanceID, actorDiscordUse
 	return result, nil
 }
 
-func (c *Client) SetAuctionBid(ctx context.Context, instanceID, contestantID, discordUserID, participantID string, points int) (AuctionBidResult, error) {
+func (c *Client) SetAuctionBid(ctx context.Context, instanceID, contestantID, discordUserID, participantID string, points int, idempotencyKey string) (AuctionBidResult, error) {
 	var result AuctionBidResult
-	headers := requestHeadersForDiscordUser(discordUserID)
+	headers := requestHeadersForIdempotentWrite(discordUserID, idempotencyKey)
 	body := map[string]any{"points": points}
 	if strings.TrimSpace(participantID) != "" {
 		body["participant_id"] = strings.TrimSpace(participantID)
@@ -721,9 +842,9 @@ func (c *Client) GetLoanSharkStatus(ctx context.Context, instanceID, discordUser
 	return result, nil
 }
 
-func (c *Client) BorrowFromLoanShark(ctx context.Context, instanceID, discordUserID string, points int) (LoanStatusResponse, error) {
+func (c *Client) BorrowFromLoanShark(ctx context.Context, instanceID, discordUserID string, points int, idempotencyKey string) (LoanStatusResponse, error) {
 	var result LoanStatusResponse
-	headers := requestHeadersForDiscordUser(discordUserID)
+	headers := requestHeadersForIdempotentWrite(discordUserID, idempotencyKey)
 	body := map[string]int{"points": points}
 	if err := c.doJSONBody(ctx, http.MethodPost, c.endpoint(path.Join("/instances", instanceID, "loan-shark", "me", "borrow")), headers, body, &result); err != nil {
 		return LoanStatusResponse{}, err
@@ -731,9 +852,9 @@ func (c *Client) BorrowFromLoanShark(ctx context.Context, instanceID, discordUse
 	return result, nil
 }
 
-func (c *Client) RepayLoanShark(ctx context.Context, instanceID, discordUserID string, points int) (LoanStatusResponse, error) {
+func (c *Client) RepayLoanShark(ctx context.Context, instanceID, discordUserID string, points int, idempotencyKey string) (LoanStatusResponse, error) {
 	var result LoanStatusResponse
-	headers := requestHeadersForDiscordUser(discordUserID)
+	headers := requestHeadersForIdempotentWrite(discordUserID, idempotencyKey)
 	body := map[string]int{"points": points}
 	if err := c.doJSONBody(ctx, http.MethodPost, c.endpoint(path.Join("/instances", instanceID, "loan-shark", "me", "repay")), headers, body, &result); err != nil {
 		return LoanStatusResponse{}, err
@@ -751,6 +872,29 @@ func (c *Client) RecordIndividualPonyImmunity(ctx context.Context, instanceID, a
 	return result, nil
 }
 
+func (c *Client) ListOutboxEvents(ctx context.Context, instanceID string, opts ListOutboxEventsOptions) (OutboxEventPage, error) {
+	requestURL := c.endpoint(path.Join("/instances", instanceID, "outbox"))
+	query := requestURL.Query()
+	for _, eventType := range opts.Types {
+		if strings.TrimSpace(eventType) != "" {
+			query.Add("type", strings.TrimSpace(eventType))
+		}
+	}
+	if strings.TrimSpace(opts.After) != "" {
+		query.Set("after", strings.TrimSpace(opts.After))
+	}
+	if !opts.Since.IsZero() {
+		query.Set("since", opts.Since.UTC().Format(time.RFC3339))
+	}
+	requestURL.RawQuery = query.Encode()
+
+	var result OutboxEventPage
+	if err := c.getJSON(ctx, requestURL, nil, &result); err != nil {
+		return OutboxEventPage{}, err
+	}
+	return result, nil
+}
+
 func (c *Client) endpoint(relativePath string) *url.URL {
 	resolved := *c.baseURL
 	resolved.Path = path.Join(c.baseURL.Path, relativePath)
@@ -874,3 +1018,16 @@ func requestHeadersForDiscordUser(discordUserID string) map[string]string {
 	}
 	return map[string]string{"X-Discord-User-ID": strings.TrimSpace(discordUserID)}
 }
+
+// requestHeadersForIdempotentWrite adds an Idempotency-Key so the API
+// returns the original result if the same write is sent again.
+func requestHeadersForIdempotentWrite(discordUserID, idempotencyKey string) map[string]string {
+	headers := requestHeadersForDiscordUser(discordUserID)
+	if key := strings.TrimSpace(idempotencyKey); key != "" {
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		headers["Idempotency-Key"] = key
+	}
+	return headers
+}
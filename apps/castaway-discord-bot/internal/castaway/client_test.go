@@ -39,6 +39,47 @@ func TestListInstancesSendsFilters(t *testing.T) {
 	}
 }
 
+func TestListOutboxEventsSendsCursorAndTypes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/instances/i1/outbox" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if got := query["type"]; len(got) != 1 || got[0] != "loan.defaulted" {
+			t.Fatalf("expected type filter, got %v", got)
+		}
+		if got := query.Get("after"); got != "e1" {
+			t.Fatalf("expected after cursor, got %q", got)
+		}
+		if got := query.Get("since"); got != "" {
+			t.Fatalf("expected no since bound, got %q", got)
+		}
+		if _, err := w.Write([]byte(`{"events":[{"id":"e2","type":"loan.defaulted","occurred_at":"2026-03-12T01:00:00Z","data":{"participant_id":"p1","participant_name":"Bryan"}}],"next_after":"e2"}`)); err != nil {
+			t.Fatalf("write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, Options{})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	page, err := client.ListOutboxEvents(context.Background(), "i1", ListOutboxEventsOptions{Types: []string{"loan.defaulted"}, After: "e1"})
+	if err != nil {
+		t.Fatalf("list outbox events: %v", err)
+	}
+	if len(page.Events) != 1 || page.NextAfter != "e2" {
+		t.Fatalf("unexpected page: %#v", page)
+	}
+	var event LoanDefaultedEvent
+	if err := json.Unmarshal(page.Events[0].Data, &event); err != nil {
+		t.Fatalf("decode event data: %v", err)
+	}
+	if event.ParticipantID != "p1" || event.ParticipantName != "Bryan" {
+		t.Fatalf("unexpected event data: %#v", event)
+	}
+}
+
 func TestListParticipantsSendsNameFilter(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/instances/i1/participants" {
@@ -84,10 +125,11 @@ func TestGetLeaderboardSendsParticipantFilter(t *testing.T) {
 	if err != nil {
 		t.Fatalf("new client: %v", err)
 	}
-	rows, err := client.GetLeaderboard(context.Background(), "i1", "p1")
+	leaderboard, err := client.GetLeaderboard(context.Background(), "i1", LeaderboardOptions{ParticipantID: "p1"})
 	if err != nil {
 		t.Fatalf("get leaderboard: %v", err)
 	}
+	rows := leaderboard.Rows
 	if len(rows) != 1 {
 		t.Fatalf("unexpected rows: %#v", rows)
 	}
@@ -97,6 +139,73 @@ func TestGetLeaderboardSendsParticipantFilter(t *testing.T) {
 	}
 }
 
+func TestGetLeaderboardSendsEpisodeAndDecodesRankChange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("episode"); got != "3" {
+			t.Fatalf("expected episode filter, got %q", got)
+		}
+		if _, err := w.Write([]byte(`{"as_of":"2026-03-24T23:59:59Z","leaderboard":[{"participant_id":"p1","participant_name":"Bryan","rank":2,"previous_rank":4,"rank_change":2,"total_points":21}],"scoring_strategy":"winner-double"}`)); err != nil {
+			t.Fatalf("write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, Options{})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	episode := 3
+	leaderboard, err := client.GetLeaderboard(context.Background(), "i1", LeaderboardOptions{Episode: &episode})
+	if err != nil {
+		t.Fatalf("get leaderboard: %v", err)
+	}
+	if leaderboard.ScoringStrategy != "winner-double" {
+		t.Fatalf("expected scoring strategy to be decoded, got %q", leaderboard.ScoringStrategy)
+	}
+	rows := leaderboard.Rows
+	if len(rows) != 1 || rows[0].Rank != 2 || rows[0].PreviousRank == nil || *rows[0].PreviousRank != 4 || rows[0].RankChange == nil || *rows[0].RankChange != 2 {
+		t.Fatalf("unexpected rows: %#v", rows)
+	}
+}
+
+func TestWhatIfPostsEliminationsByName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/instances/i1/leaderboard/what-if" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Eliminations []struct {
+				ContestantName string `json:"contestant_name"`
+			} `json:"eliminations"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if len(body.Eliminations) != 2 || body.Eliminations[0].ContestantName != "Rizo" || body.Eliminations[1].ContestantName != "Joe" {
+			t.Fatalf("unexpected eliminations: %+v", body.Eliminations)
+		}
+		if _, err := w.Write([]byte(`{"scoring_strategy":"distance","eliminations":[{"contestant_name":"Rizo","position":18},{"contestant_name":"Joe","position":17}],"leaderboard":[{"participant_name":"Bryan","rank":1,"current_rank":2,"rank_change":1,"total_points":30,"points_change":4}]}`)); err != nil {
+			t.Fatalf("write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, Options{})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	result, err := client.WhatIf(context.Background(), "i1", []string{"Rizo", "Joe"})
+	if err != nil {
+		t.Fatalf("what if: %v", err)
+	}
+	if len(result.Eliminations) != 2 || result.Eliminations[1].Position != 17 {
+		t.Fatalf("unexpected eliminations: %#v", result.Eliminations)
+	}
+	if len(result.Rows) != 1 || result.Rows[0].RankChange != 1 || result.Rows[0].PointsChange != 4 {
+		t.Fatalf("unexpected rows: %#v", result.Rows)
+	}
+}
+
 func TestClientAddsBearerAuthorizationHeader(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if got := r.Header.Get("Authorization"); got != "Bearer shared-token" {
@@ -356,6 +465,9 @@ func TestSetAuctionBidSendsDiscordHeaderAndJSONBody(t *testing.T) {
 		if got := r.Header.Get("X-Discord-User-ID"); got != "user-1" {
 			t.Fatalf("unexpected discord header: %q", got)
 		}
+		if got := r.Header.Get("Idempotency-Key"); got != "discord-interaction:123" {
+			t.Fatalf("unexpected idempotency key: %q", got)
+		}
 		var body struct {
 			ParticipantID string `json:"participant_id"`
 			Points        int    `json:"points"`
@@ -376,7 +488,7 @@ func TestSetAuctionBidSendsDiscordHeaderAndJSONBody(t *testing.T) {
 	if err != nil {
 		t.Fatalf("new client: %v", err)
 	}
-	result, err := client.SetAuctionBid(context.Background(), "i1", "c1", "user-1", "participant-1", 4)
+	result, err := client.SetAuctionBid(context.Background(), "i1", "c1", "user-1", "participant-1", 4, "discord-interaction:123")
 	if err != nil {
 		t.Fatalf("set auction bid: %v", err)
 	}
@@ -393,6 +505,9 @@ func TestAddStirThePotContributionSendsOptionalParticipantID(t *testing.T) {
 		if got := r.Header.Get("X-Discord-User-ID"); got != "admin-1" {
 			t.Fatalf("unexpected discord header: %q", got)
 		}
+		if got, ok := r.Header["Idempotency-Key"]; ok {
+			t.Fatalf("expected no idempotency key without one, got %q", got)
+		}
 		var body struct {
 			ParticipantID string `json:"participant_id"`
 			Points        int    `json:"points"`
@@ -413,7 +528,7 @@ func TestAddStirThePotContributionSendsOptionalParticipantID(t *testing.T) {
 	if err != nil {
 		t.Fatalf("new client: %v", err)
 	}
-	result, err := client.AddStirThePotContribution(context.Background(), "i1", "admin-1", "participant-keith", 1)
+	result, err := client.AddStirThePotContribution(context.Background(), "i1", "admin-1", "participant-keith", 1, "")
 	if err != nil {
 		t.Fatalf("add stir the pot contribution: %v", err)
 	}
@@ -504,3 +619,17 @@ func TestGetJSONReturnsTypedAPIError(t *testing.T) {
 		t.Fatalf("unexpected api error: %#v", apiErr)
 	}
 }
+
+func TestRequestHeadersForIdempotentWriteTrimsAndOmitsEmptyValues(t *testing.T) {
+	headers := requestHeadersForIdempotentWrite(" alice-discord ", " interaction-1 ")
+	if headers["X-Discord-User-ID"] != "alice-discord" || headers["Idempotency-Key"] != "interaction-1" {
+		t.Fatalf("unexpected headers: %#v", headers)
+	}
+	headers = requestHeadersForIdempotentWrite("  ", "interaction-1")
+	if _, ok := headers["X-Discord-User-ID"]; ok {
+		t.Fatalf("expected an empty discord user ID to be omitted, got %#v", headers)
+	}
+	if headers := requestHeadersForIdempotentWrite("", ""); headers != nil {
+		t.Fatalf("expected no headers, got %#v", headers)
+	}
+}
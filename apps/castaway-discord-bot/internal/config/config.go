@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/bry-guy/srvivor/apps/castaway-discord-bot/internal/state"
 	"github.com/kelseyhightower/envconfig"
@@ -18,6 +19,9 @@ type Config struct {
 	DiscordApplicationID  string `envconfig:"CASTAWAY_DISCORD_APPLICATION_ID" required:"true"`
 	DiscordTargetServerID string `envconfig:"DISCORD_TARGET_SEVER_ID"`
 	AnnouncementChannelID string `envconfig:"CASTAWAY_ANNOUNCEMENT_CHANNEL_ID"`
+	// NotificationInterval is how often the bot polls instance outboxes for
+	// events to notify players about. Zero turns notifications off.
+	NotificationInterval time.Duration `envconfig:"BOT_NOTIFICATION_INTERVAL" default:"30s"`
 
 	CastawayAPIBaseURL   string `envconfig:"CASTAWAY_API_BASE_URL" default:"http://localhost:8080"`
 	CastawayAPIAuthToken string `envconfig:"CASTAWAY_API_AUTH_TOKEN"`
@@ -60,6 +64,10 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid BOT_STATE_BACKEND: %s", cfg.StateBackend)
 	}
 
+	if cfg.NotificationInterval < 0 {
+		return nil, fmt.Errorf("BOT_NOTIFICATION_INTERVAL must not be negative")
+	}
+
 	switch strings.ToUpper(cfg.LogLevelStr) {
 	case "DEBUG":
 		cfg.LogLevel = slog.LevelDebug
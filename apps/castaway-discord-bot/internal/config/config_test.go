@@ -1,6 +1,9 @@
 package config
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestLoadDefaultsToBoltState(t *testing.T) {
 	t.Setenv("CASTAWAY_DISCORD_BOT_TOKEN", "token")
@@ -16,6 +19,9 @@ func TestLoadDefaultsToBoltState(t *testing.T) {
 	if cfg.StatePath == "" {
 		t.Fatal("expected default state path")
 	}
+	if cfg.NotificationInterval != 30*time.Second {
+		t.Fatalf("expected 30s notification interval, got %s", cfg.NotificationInterval)
+	}
 }
 
 func TestLoadRequiresPostgresURLForPostgresBackend(t *testing.T) {
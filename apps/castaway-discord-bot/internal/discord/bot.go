@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/bry-guy/srvivor/apps/castaway-discord-bot/internal/castaway"
 	"github.com/bry-guy/srvivor/apps/castaway-discord-bot/internal/config"
@@ -16,11 +17,13 @@ type Bot struct {
 	appID                 string
 	targetServerID        string
 	announcementChannelID string
+	notificationInterval  time.Duration
 	log                   *slog.Logger
 
-	castaway *castaway.Client
-	state    state.Store
-	session  *discordgo.Session
+	castaway  *castaway.Client
+	state     state.Store
+	session   *discordgo.Session
+	messenger discordMessenger
 }
 
 func New(cfg *config.Config, client *castaway.Client, store state.Store, logger *slog.Logger) (*Bot, error) {
@@ -34,10 +37,12 @@ func New(cfg *config.Config, client *castaway.Client, store state.Store, logger
 		appID:                 cfg.DiscordApplicationID,
 		targetServerID:        cfg.DiscordTargetServerID,
 		announcementChannelID: cfg.AnnouncementChannelID,
+		notificationInterval:  cfg.NotificationInterval,
 		log:                   logger,
 		castaway:              client,
 		state:                 store,
 		session:               session,
+		messenger:             session,
 	}
 
 	session.AddHandler(bot.handleInteraction)
@@ -62,6 +67,10 @@ func (b *Bot) Start(ctx context.Context) error {
 		}
 	}()
 
+	if b.notificationInterval > 0 {
+		go b.runOutboxNotifications(ctx, b.notificationInterval)
+	}
+
 	botDiscordGatewayConnected.Set(1)
 	b.log.Info("discord session opened", "command_scope", commandScope)
 	return nil
@@ -26,6 +26,7 @@ func applicationCommands() []*discordgo.ApplicationCommand {
 				scoreCommand(),
 				scoresCommand(),
 				unlinkCommand(),
+				whatifCommand(),
 			},
 		},
 	}
@@ -309,6 +310,7 @@ func scoresCommand() *discordgo.ApplicationCommandOption {
 		Description: "Show the leaderboard for an instance",
 		Options: []*discordgo.ApplicationCommandOption{
 			instanceOption(false),
+			episodeOption(),
 		},
 	}
 }
@@ -325,6 +327,23 @@ func unlinkCommand() *discordgo.ApplicationCommandOption {
 	}
 }
 
+func whatifCommand() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        "whatif",
+		Description: "Project the leaderboard if these survivors go next",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "eliminated",
+				Description: "Comma-separated survivor names, next to go first",
+				Required:    true,
+			},
+			instanceOption(false),
+		},
+	}
+}
+
 func participantOption(required bool) *discordgo.ApplicationCommandOption {
 	return namedAutocompleteOption("participant", "Participant name", required)
 }
@@ -365,6 +384,15 @@ func pointsOption(required bool) *discordgo.ApplicationCommandOption {
 	}
 }
 
+func episodeOption() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionInteger,
+		Name:        "episode",
+		Description: "Show standings as of the end of this episode",
+		Required:    false,
+	}
+}
+
 func userOption(name, description string, required bool) *discordgo.ApplicationCommandOption {
 	return &discordgo.ApplicationCommandOption{
 		Type:        discordgo.ApplicationCommandOptionUser,
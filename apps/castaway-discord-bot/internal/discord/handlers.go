@@ -151,6 +151,8 @@ func (b *Bot) executeCommand(ctx context.Context, interaction *discordgo.Interac
 			return b.handleBids(ctx, interaction, command)
 		case "ponies":
 			return b.handlePonies(ctx, interaction, command)
+		case "whatif":
+			return b.handleWhatIf(ctx, interaction, command)
 		default:
 			return "", fmt.Errorf("unsupported castaway command: %s", command.name)
 		}
@@ -162,7 +164,7 @@ func (b *Bot) commandShouldBeEphemeral(ctx context.Context, interaction *discord
 		return true, nil
 	}
 	switch command.name {
-	case "link", "unlink", "bid", "bids", "ponies", "draft", "history", "scores":
+	case "link", "unlink", "bid", "bids", "ponies", "draft", "history", "scores", "whatif":
 		return true, nil
 	case "score":
 		season, err := seasonOptionValue(command)
@@ -204,10 +206,11 @@ func (b *Bot) handleScore(ctx context.Context, interaction *discordgo.Interactio
 	if err != nil {
 		return "", err
 	}
-	rows, err := b.castaway.GetLeaderboard(ctx, instance.ID, "")
+	leaderboard, err := b.castaway.GetLeaderboard(ctx, instance.ID, castaway.LeaderboardOptions{})
 	if err != nil {
 		return "", err
 	}
+	rows := leaderboard.Rows
 	if len(rows) == 0 {
 		return "", fmt.Errorf("no score found for %s in %s", participant.Name, format.InstanceLabel(instance))
 	}
@@ -221,9 +224,9 @@ func (b *Bot) handleScore(ctx context.Context, interaction *discordgo.Interactio
 			return "", err
 		}
 		if privateView {
-			return format.PrivateScore(instance, row, index+1, visibleBonusPoints, secretBonusPoints), nil
+			return format.PrivateScore(instance, row, row.Standing(index), visibleBonusPoints, secretBonusPoints), nil
 		}
-		return format.SingleScore(instance, row, index+1), nil
+		return format.SingleScore(instance, row, row.Standing(index)), nil
 	}
 	return "", fmt.Errorf("no score found for %s in %s", participant.Name, format.InstanceLabel(instance))
 }
@@ -237,14 +240,50 @@ func (b *Bot) handleScores(ctx context.Context, interaction *discordgo.Interacti
 	if err != nil {
 		return "", err
 	}
-	rows, err := b.castaway.GetLeaderboard(ctx, instance.ID, "")
+	episode, err := episodeOptionValue(command)
 	if err != nil {
 		return "", err
 	}
-	if len(rows) == 0 {
+	leaderboard, err := b.castaway.GetLeaderboard(ctx, instance.ID, castaway.LeaderboardOptions{Episode: episode})
+	if err != nil {
+		return "", err
+	}
+	if len(leaderboard.Rows) == 0 {
+		return "No leaderboard rows found yet.", nil
+	}
+	leaderboard.Rows = b.decorateLeaderboardRows(interaction, leaderboard.Rows)
+	if episode != nil {
+		return format.EpisodeLeaderboard(instance, *episode, leaderboard), nil
+	}
+	return format.Leaderboard(instance, leaderboard), nil
+}
+
+func (b *Bot) handleWhatIf(ctx context.Context, interaction *discordgo.InteractionCreate, command commandSpec) (string, error) {
+	season, err := seasonOptionValue(command)
+	if err != nil {
+		return "", err
+	}
+	instance, err := b.resolveInstance(ctx, interaction, optionString(command, "instance"), season)
+	if err != nil {
+		return "", err
+	}
+	names := []string{}
+	for _, name := range strings.Split(optionString(command, "eliminated"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("name at least one survivor to eliminate")
+	}
+	projection, err := b.castaway.WhatIf(ctx, instance.ID, names)
+	if err != nil {
+		return "", err
+	}
+	if len(projection.Rows) == 0 {
 		return "No leaderboard rows found yet.", nil
 	}
-	return format.Leaderboard(instance, b.decorateLeaderboardRows(interaction, rows)), nil
+	return format.WhatIfLeaderboard(instance, projection), nil
 }
 
 func (b *Bot) scoreBreakdown(ctx context.Context, instanceID, actorDiscordUserID, participantID string, row castaway.LeaderboardRow) (visibleBonusPoints int, secretBonusPoints int, privateView bool, err error) {
@@ -1100,6 +1139,24 @@ func seasonOptionValue(command commandSpec) (*int32, error) {
 	return nil, nil
 }
 
+func episodeOptionValue(command commandSpec) (*int, error) {
+	for _, option := range command.options {
+		if option.Name != "episode" {
+			continue
+		}
+		value := option.IntValue()
+		if value < 0 {
+			return nil, fmt.Errorf("episode must not be negative")
+		}
+		if value > math.MaxInt32 {
+			return nil, fmt.Errorf("episode is too large")
+		}
+		episode := int(value)
+		return &episode, nil
+	}
+	return nil, nil
+}
+
 func scopeOptionValue(command commandSpec) string {
 	scope := optionString(command, "scope")
 	if scope == "guild" {
@@ -1126,6 +1183,16 @@ func interactionUserID(interaction *discordgo.InteractionCreate) string {
 	return ""
 }
 
+// interactionIdempotencyKey ties a point-spending write to the Discord
+// interaction that asked for it, so a retried interaction is not applied
+// twice.
+func interactionIdempotencyKey(interaction *discordgo.InteractionCreate) string {
+	if interaction == nil || interaction.Interaction == nil || strings.TrimSpace(interaction.ID) == "" {
+		return ""
+	}
+	return "discord-interaction:" + interaction.ID
+}
+
 func selectInstanceByName(query string, instances []castaway.Instance) (castaway.Instance, error) {
 	if len(instances) == 0 {
 		return castaway.Instance{}, fmt.Errorf("no instances matched %q", query)
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bry-guy/srvivor/apps/castaway-discord-bot/internal/castaway"
 	"github.com/bry-guy/srvivor/apps/castaway-discord-bot/internal/state"
@@ -23,6 +24,8 @@ type testCastawayAPI struct {
 	participantsByInstance           map[string][]castaway.Participant
 	linkedParticipantByInstance      map[string]map[string]castaway.Participant
 	leaderboardByInstance            map[string][]castaway.LeaderboardRow
+	episodeLeaderboardByInstance     map[string]map[string][]castaway.LeaderboardRow
+	scoringStrategyByInstance        map[string]string
 	bonusLedgerByParticipant         map[string]castaway.ParticipantBonusLedger
 	draftsByInstance                 map[string]map[string]castaway.Draft
 	activitiesByInstance             map[string][]castaway.Activity
@@ -44,6 +47,8 @@ type testCastawayAPI struct {
 	loanBorrowByInstance             map[string]castaway.LoanStatusResponse
 	loanRepayByInstance              map[string]castaway.LoanStatusResponse
 	individualPonyByContestant       map[string]castaway.IndividualPonyImmunityResult
+	whatIfByInstance                 map[string]castaway.WhatIfLeaderboard
+	outboxByInstance                 map[string][]castaway.OutboxEvent
 }
 
 func TestScoreCommandRegression_UsesLeaderboardStyleOutput(t *testing.T) {
@@ -177,6 +182,58 @@ func TestScoresCommandRegression_ResolvesSingleSeasonInstance(t *testing.T) {
 	}
 }
 
+func TestScoresCommandShowsEpisodeStandingsWithRankChange(t *testing.T) {
+	up, down := 1, -1
+	bot, _ := newTestBot(t, testCastawayAPI{
+		instances: []castaway.Instance{{ID: "instance-50", Name: "Historical Season 50", Season: 50}},
+		leaderboardByInstance: map[string][]castaway.LeaderboardRow{"instance-50": {
+			{ParticipantID: "participant-keeling", ParticipantName: "Keeling", Rank: 1, TotalPoints: 9, DraftPoints: 8, BonusPoints: 1},
+		}},
+		episodeLeaderboardByInstance: map[string]map[string][]castaway.LeaderboardRow{"instance-50": {"2": {
+			{ParticipantID: "participant-adam", ParticipantName: "Adam", ParticipantDiscordUserID: "u-adam", CurrentTribeName: "Tangerine", Rank: 1, RankChange: &up, TotalPoints: 5, DraftPoints: 5},
+			{ParticipantID: "participant-keeling", ParticipantName: "Keeling", ParticipantDiscordUserID: "u-keeling", CurrentTribeName: "Lotus", Rank: 2, RankChange: &down, TotalPoints: 4, DraftPoints: 3, BonusPoints: 1},
+		}}},
+		scoringStrategyByInstance: map[string]string{"instance-50": "winner-double"},
+	})
+
+	message, err := bot.executeCommand(context.Background(), testInteraction("guild-1", "user-1", 0), commandSpec{name: "scores", options: []*discordgo.ApplicationCommandInteractionDataOption{intOption("season", 50), intOption("episode", 2)}})
+	if err != nil {
+		t.Fatalf("execute command: %v", err)
+	}
+
+	expected := strings.Join([]string{"**Season 50: Leaderboard after Episode 2**", "1. :tangerine: <@u-adam>: 5 (5+0) ▲1", "2. :lotus: <@u-keeling>: 4 (3+1) ▼1", "_Scoring: winner-double_"}, "\n")
+	if message != expected {
+		t.Fatalf("unexpected leaderboard message:\nexpected: %q\nactual:   %q", expected, message)
+	}
+}
+
+func TestWhatIfCommandProjectsLeaderboardForNamedEliminations(t *testing.T) {
+	bot, _ := newTestBot(t, testCastawayAPI{
+		instances: []castaway.Instance{{ID: "instance-50", Name: "Historical Season 50", Season: 50}},
+		whatIfByInstance: map[string]castaway.WhatIfLeaderboard{"instance-50": {
+			ScoringStrategy: "distance",
+			Rows: []castaway.WhatIfRow{
+				{ParticipantName: "Adam", Rank: 1, RankChange: 1, TotalPoints: 9, PointsChange: 4},
+				{ParticipantName: "Keeling", Rank: 2, RankChange: -1, TotalPoints: 7, PointsChange: 1},
+			},
+		}},
+	})
+
+	message, err := bot.executeCommand(context.Background(), testInteraction("guild-1", "user-1", 0), commandSpec{name: "whatif", options: []*discordgo.ApplicationCommandInteractionDataOption{intOption("season", 50), stringOption("eliminated", " Rizo, ,Joe ")}})
+	if err != nil {
+		t.Fatalf("execute command: %v", err)
+	}
+
+	expected := strings.Join([]string{"**Season 50: What if Rizo, Joe go next?**", "1. Adam: 9 (+4) ▲1", "2. Keeling: 7 (+1) ▼1", "_Scoring: distance_"}, "\n")
+	if message != expected {
+		t.Fatalf("unexpected what-if message:\nexpected: %q\nactual:   %q", expected, message)
+	}
+
+	if _, err := bot.executeCommand(context.Background(), testInteraction("guild-1", "user-1", 0), commandSpec{name: "whatif", options: []*discordgo.ApplicationCommandInteractionDataOption{intOption("season", 50), stringOption("eliminated", " , ")}}); err == nil {
+		t.Fatal("expected an error when no survivors are named")
+	}
+}
+
 func TestDraftCommandRegression_UsesGuildDefault(t *testing.T) {
 	bot, store := newTestBot(t, testCastawayAPI{
 		instances:              []castaway.Instance{{ID: "instance-50", Name: "Historical Season 50", Season: 50}},
@@ -550,6 +607,90 @@ func TestLoanRequestCommandRegression_BorrowsPoints(t *testing.T) {
 	}
 }
 
+func TestLoanStatusCommandRegression_ShowsLastDefault(t *testing.T) {
+	bot, store := newTestBot(t, testCastawayAPI{
+		instances:                   []castaway.Instance{{ID: "instance-50", Name: "Historical Season 50", Season: 50}},
+		linkedParticipantByInstance: map[string]map[string]castaway.Participant{"instance-50": {"user-1": {ID: "participant-1", Name: "Bryan"}}},
+		loanStatusByInstance: map[string]castaway.LoanStatusResponse{"instance-50": {
+			Participant: castaway.Participant{ID: "participant-1", Name: "Bryan"},
+			Loan: castaway.LoanStatus{RemainingBorrowPoints: 3, BonusPointsAvailable: -2, LastDefault: &castaway.LoanDefault{
+				LoanID:          "loan-1",
+				CollectedPoints: 3,
+				ShortfallPoints: 1,
+				PenaltyPoints:   2,
+				DefaultedAt:     "2026-03-12T01:00:00Z",
+			}},
+		}},
+	})
+	if err := store.SetUserDefault("guild-1", "user-1", "instance-50"); err != nil {
+		t.Fatalf("set user default: %v", err)
+	}
+
+	message, err := bot.executeCommand(context.Background(), testInteraction("guild-1", "user-1", 0), commandSpec{group: "loan", name: "status"})
+	if err != nil {
+		t.Fatalf("execute command: %v", err)
+	}
+	for _, fragment := range []string{"Your last loan defaulted at 2026-03-12 01:00 UTC.", "- Collected: 3", "- Unpaid: 1", "- Penalty: 2", "- Bonus points available: -2"} {
+		if !strings.Contains(message, fragment) {
+			t.Fatalf("expected fragment %q in %q", fragment, message)
+		}
+	}
+}
+
+func TestOutboxNotificationsDirectMessageLoanDefaultsOnce(t *testing.T) {
+	bot, store := newTestBot(t, testCastawayAPI{
+		instances: []castaway.Instance{
+			{ID: "instance-50", Name: "Historical Season 50", Season: 50, State: "active"},
+			{ID: "instance-49", Name: "Historical Season 49", Season: 49, State: "archived"},
+		},
+		participantsByInstance: map[string][]castaway.Participant{"instance-50": {
+			{ID: "participant-1", Name: "Bryan", DiscordUserID: "user-1"},
+			{ID: "participant-2", Name: "Riley"},
+		}},
+		loanStatusByInstance: map[string]castaway.LoanStatusResponse{"instance-50": {
+			Participant: castaway.Participant{ID: "participant-1", Name: "Bryan"},
+			Loan:        castaway.LoanStatus{BonusPointsAvailable: -2, LastDefault: &castaway.LoanDefault{CollectedPoints: 3, ShortfallPoints: 1, PenaltyPoints: 2}},
+		}},
+		outboxByInstance: map[string][]castaway.OutboxEvent{"instance-50": {
+			{ID: "event-1", Type: "loan.defaulted", Data: json.RawMessage(`{"participant_id":"participant-1","participant_name":"Bryan"}`)},
+			{ID: "event-2", Type: "loan.defaulted", Data: json.RawMessage(`{"participant_id":"participant-2","participant_name":"Riley"}`)},
+		}},
+	})
+	messenger := &recordingMessenger{}
+	bot.messenger = messenger
+	bot.announcementChannelID = "channel-announcements"
+
+	if err := bot.pollOutboxNotifications(context.Background(), time.Now()); err != nil {
+		t.Fatalf("poll outbox: %v", err)
+	}
+	if len(messenger.sent) != 1 {
+		t.Fatalf("expected only the linked participant to be notified, got %+v", messenger.sent)
+	}
+	dm := messenger.sent[0]
+	if dm.channelID != "dm-user-1" {
+		t.Fatalf("expected a DM to user-1, got %+v", dm)
+	}
+	for _, fragment := range []string{"**Season 50: Loan Shark**", "- Collected: 3", "- Unpaid: 1", "- Penalty: 2", "- Bonus points available: -2"} {
+		if !strings.Contains(dm.content, fragment) {
+			t.Fatalf("expected fragment %q in %q", fragment, dm.content)
+		}
+	}
+	cursor, err := store.GetOutboxCursor("instance-50")
+	if err != nil {
+		t.Fatalf("get outbox cursor: %v", err)
+	}
+	if cursor != "event-2" {
+		t.Fatalf("expected cursor at event-2, got %q", cursor)
+	}
+
+	if err := bot.pollOutboxNotifications(context.Background(), time.Now()); err != nil {
+		t.Fatalf("poll outbox again: %v", err)
+	}
+	if len(messenger.sent) != 1 {
+		t.Fatalf("expected no repeat notifications, got %+v", messenger.sent)
+	}
+}
+
 func TestAuctionAwardCommandRegression_RecordsIndividualImmunity(t *testing.T) {
 	bot, store := newTestBot(t, testCastawayAPI{
 		instances:             []castaway.Instance{{ID: "instance-50", Name: "Historical Season 50", Season: 50}},
@@ -778,6 +919,26 @@ func TestOccurrencesCommandRegression_ActivityNotFound(t *testing.T) {
 	}
 }
 
+type sentMessage struct {
+	channelID string
+	content   string
+}
+
+// recordingMessenger opens DM channels named after the user and records every
+// message instead of sending it.
+type recordingMessenger struct {
+	sent []sentMessage
+}
+
+func (m *recordingMessenger) UserChannelCreate(recipientID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	return &discordgo.Channel{ID: "dm-" + recipientID}, nil
+}
+
+func (m *recordingMessenger) ChannelMessageSend(channelID string, content string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.sent = append(m.sent, sentMessage{channelID: channelID, content: content})
+	return &discordgo.Message{ChannelID: channelID, Content: content}, nil
+}
+
 func newTestBot(t *testing.T, api testCastawayAPI) (*Bot, *state.BoltStore) {
 	t.Helper()
 	server := httptest.NewServer(api.handler(t))
@@ -943,6 +1104,9 @@ func (api testCastawayAPI) handler(t *testing.T) http.Handler {
 			writeJSON(http.StatusOK, map[string]any{"activities": activities})
 		case len(parts) == 3 && parts[2] == "leaderboard" && r.Method == http.MethodGet:
 			rows := api.leaderboardByInstance[instanceID]
+			if episode := strings.TrimSpace(r.URL.Query().Get("episode")); episode != "" {
+				rows = api.episodeLeaderboardByInstance[instanceID][episode]
+			}
 			if participantID := strings.TrimSpace(r.URL.Query().Get("participant_id")); participantID != "" {
 				filtered := make([]castaway.LeaderboardRow, 0, len(rows))
 				for _, row := range rows {
@@ -952,7 +1116,27 @@ func (api testCastawayAPI) handler(t *testing.T) http.Handler {
 				}
 				rows = filtered
 			}
-			writeJSON(http.StatusOK, map[string]any{"leaderboard": rows})
+			response := map[string]any{"leaderboard": rows}
+			if strategy := api.scoringStrategyByInstance[instanceID]; strategy != "" {
+				response["scoring_strategy"] = strategy
+			}
+			writeJSON(http.StatusOK, response)
+		case len(parts) == 4 && parts[2] == "leaderboard" && parts[3] == "what-if" && r.Method == http.MethodPost:
+			var body struct {
+				Eliminations []struct {
+					ContestantName string `json:"contestant_name"`
+				} `json:"eliminations"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(http.StatusBadRequest, map[string]any{"error": err.Error()})
+				return
+			}
+			projection := api.whatIfByInstance[instanceID]
+			projection.Eliminations = nil
+			for index, elimination := range body.Eliminations {
+				projection.Eliminations = append(projection.Eliminations, castaway.WhatIfElimination{ContestantName: elimination.ContestantName, Position: 18 - index})
+			}
+			writeJSON(http.StatusOK, projection)
 		case len(parts) == 4 && parts[2] == "drafts" && r.Method == http.MethodGet:
 			draft, ok := api.draftsByInstance[instanceID][parts[3]]
 			if !ok {
@@ -1060,6 +1244,21 @@ func (api testCastawayAPI) handler(t *testing.T) http.Handler {
 			writeJSON(http.StatusOK, api.auctionBidByContestant[parts[4]])
 		case len(parts) == 4 && parts[2] == "ponies" && parts[3] == "me" && r.Method == http.MethodGet:
 			writeJSON(http.StatusOK, api.ponyListByInstance[instanceID])
+		case len(parts) == 3 && parts[2] == "outbox" && r.Method == http.MethodGet:
+			after := r.URL.Query().Get("after")
+			events := []castaway.OutboxEvent{}
+			nextAfter := after
+			for _, event := range api.outboxByInstance[instanceID] {
+				if after != "" {
+					if event.ID == after {
+						after = ""
+					}
+					continue
+				}
+				events = append(events, event)
+				nextAfter = event.ID
+			}
+			writeJSON(http.StatusOK, castaway.OutboxEventPage{Events: events, NextAfter: nextAfter})
 		case len(parts) == 4 && parts[2] == "loan-shark" && parts[3] == "me" && r.Method == http.MethodGet:
 			writeJSON(http.StatusOK, api.loanStatusByInstance[instanceID])
 		case len(parts) == 5 && parts[2] == "loan-shark" && parts[3] == "me" && parts[4] == "borrow" && r.Method == http.MethodPost:
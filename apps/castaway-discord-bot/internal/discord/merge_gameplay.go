@@ -57,7 +57,7 @@ func (b *Bot) handlePotAdd(ctx context.Context, interaction *discordgo.Interacti
 	if err != nil {
 		return "", err
 	}
-	result, err := b.castaway.AddStirThePotContribution(ctx, instance.ID, interactionUserID(interaction), targetParticipantID, points)
+	result, err := b.castaway.AddStirThePotContribution(ctx, instance.ID, interactionUserID(interaction), targetParticipantID, points, interactionIdempotencyKey(interaction))
 	if err != nil {
 		var apiErr *castaway.APIError
 		switch {
@@ -200,7 +200,7 @@ func (b *Bot) handleBid(ctx context.Context, interaction *discordgo.InteractionC
 	if err != nil {
 		return "", err
 	}
-	result, err := b.castaway.SetAuctionBid(ctx, instance.ID, contestant.ID, interactionUserID(interaction), targetParticipantID, points)
+	result, err := b.castaway.SetAuctionBid(ctx, instance.ID, contestant.ID, interactionUserID(interaction), targetParticipantID, points, interactionIdempotencyKey(interaction))
 	if err != nil {
 		var apiErr *castaway.APIError
 		switch {
@@ -263,7 +263,7 @@ func (b *Bot) handleLoanRequest(ctx context.Context, interaction *discordgo.Inte
 	if points <= 0 {
 		return "", fmt.Errorf("points must be positive")
 	}
-	status, err := b.castaway.BorrowFromLoanShark(ctx, instance.ID, interactionUserID(interaction), points)
+	status, err := b.castaway.BorrowFromLoanShark(ctx, instance.ID, interactionUserID(interaction), points, interactionIdempotencyKey(interaction))
 	if err != nil {
 		return "", err
 	}
@@ -279,7 +279,7 @@ func (b *Bot) handleLoanRepay(ctx context.Context, interaction *discordgo.Intera
 	if points <= 0 {
 		return "", fmt.Errorf("points must be positive")
 	}
-	status, err := b.castaway.RepayLoanShark(ctx, instance.ID, interactionUserID(interaction), points)
+	status, err := b.castaway.RepayLoanShark(ctx, instance.ID, interactionUserID(interaction), points, interactionIdempotencyKey(interaction))
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,132 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-discord-bot/internal/castaway"
+	"github.com/bry-guy/srvivor/apps/castaway-discord-bot/internal/format"
+	"github.com/bwmarrin/discordgo"
+)
+
+// outboxEventLoanDefaulted is the outbox event the bot notifies players about.
+const outboxEventLoanDefaulted = "loan.defaulted"
+
+// discordMessenger is the part of *discordgo.Session notifications use.
+type discordMessenger interface {
+	UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	ChannelMessageSend(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+}
+
+// runOutboxNotifications polls instance outboxes every interval until ctx is
+// done.
+func (b *Bot) runOutboxNotifications(ctx context.Context, interval time.Duration) {
+	since := time.Now().UTC()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.pollOutboxNotifications(ctx, since); err != nil {
+				b.log.Warn("poll outbox notifications", "error", err)
+			}
+		}
+	}
+}
+
+// pollOutboxNotifications delivers new loan.defaulted events for every
+// instance that is not archived. An instance with no stored cursor starts at
+// since, so a bot seeing it for the first time does not replay old defaults.
+func (b *Bot) pollOutboxNotifications(ctx context.Context, since time.Time) error {
+	instances, err := b.castaway.ListInstances(ctx, castaway.ListInstancesOptions{})
+	if err != nil {
+		return err
+	}
+	for _, instance := range instances {
+		if instance.State == "archived" {
+			continue
+		}
+		if err := b.pollInstanceOutbox(ctx, instance, since); err != nil {
+			b.log.Warn("poll instance outbox", "instance_id", instance.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// pollInstanceOutbox notifies each new event in order and moves the stored
+// cursor past it, so an event whose notification failed is retried on the
+// next poll.
+func (b *Bot) pollInstanceOutbox(ctx context.Context, instance castaway.Instance, since time.Time) error {
+	cursor, err := b.state.GetOutboxCursor(instance.ID)
+	if err != nil {
+		return fmt.Errorf("get outbox cursor: %w", err)
+	}
+	opts := castaway.ListOutboxEventsOptions{Types: []string{outboxEventLoanDefaulted}, After: cursor}
+	if cursor == "" {
+		opts.Since = since
+	}
+	page, err := b.castaway.ListOutboxEvents(ctx, instance.ID, opts)
+	if err != nil {
+		return err
+	}
+	for _, event := range page.Events {
+		if event.Type == outboxEventLoanDefaulted {
+			if err := b.notifyLoanDefault(ctx, instance, event); err != nil {
+				return fmt.Errorf("notify loan default %s: %w", event.ID, err)
+			}
+		}
+		if err := b.state.SetOutboxCursor(instance.ID, event.ID); err != nil {
+			return fmt.Errorf("set outbox cursor: %w", err)
+		}
+	}
+	return nil
+}
+
+// notifyLoanDefault sends the defaulting participant their settlement as a
+// direct message. Loans are secret, so when they are not linked or Discord
+// will not open the DM the default is only logged, never posted publicly.
+func (b *Bot) notifyLoanDefault(ctx context.Context, instance castaway.Instance, event castaway.OutboxEvent) error {
+	var data castaway.LoanDefaultedEvent
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return fmt.Errorf("decode event data: %w", err)
+	}
+	participant := castaway.Participant{ID: data.ParticipantID, Name: data.ParticipantName}
+	participants, err := b.castaway.ListParticipants(ctx, instance.ID, castaway.ListParticipantsOptions{})
+	if err != nil {
+		return err
+	}
+	for _, candidate := range participants {
+		if candidate.ID == data.ParticipantID {
+			participant = candidate
+			break
+		}
+	}
+
+	discordUserID := strings.TrimSpace(participant.DiscordUserID)
+	if discordUserID == "" {
+		b.log.Warn("loan default not delivered; participant is not linked", "instance_id", instance.ID, "participant_id", participant.ID, "event_id", event.ID)
+		return nil
+	}
+	status, err := b.castaway.GetLoanSharkStatus(ctx, instance.ID, discordUserID)
+	if err != nil {
+		b.log.Warn("load loan status for default notice", "participant_id", participant.ID, "error", err)
+	}
+	if err := b.sendDirectMessage(discordUserID, format.LoanDefaultNotice(instance, status.Loan)); err != nil {
+		b.log.Warn("loan default not delivered; direct message failed", "instance_id", instance.ID, "participant_id", participant.ID, "event_id", event.ID, "error", err)
+	}
+	return nil
+}
+
+func (b *Bot) sendDirectMessage(discordUserID, message string) error {
+	channel, err := b.messenger.UserChannelCreate(discordUserID)
+	if err != nil {
+		return err
+	}
+	_, err = b.messenger.ChannelMessageSend(channel.ID, message)
+	return err
+}
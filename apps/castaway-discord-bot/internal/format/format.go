@@ -45,13 +45,49 @@ func PrivateScore(instance castaway.Instance, row castaway.LeaderboardRow, rank
 	}, "\n"))
 }
 
-func Leaderboard(instance castaway.Instance, rows []castaway.LeaderboardRow) string {
+func Leaderboard(instance castaway.Instance, leaderboard castaway.Leaderboard) string {
+	return leaderboardMessage(fmt.Sprintf("**Season %d: Leaderboard**", instance.Season), leaderboard)
+}
+
+func EpisodeLeaderboard(instance castaway.Instance, episode int, leaderboard castaway.Leaderboard) string {
+	return leaderboardMessage(fmt.Sprintf("**Season %d: Leaderboard after Episode %d**", instance.Season, episode), leaderboard)
+}
+
+func leaderboardMessage(title string, leaderboard castaway.Leaderboard) string {
 	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("**Season %d: Leaderboard**\n", instance.Season))
-	for index, row := range rows {
-		builder.WriteString(leaderboardLine(index+1, row))
+	builder.WriteString(title + "\n")
+	for index, row := range leaderboard.Rows {
+		builder.WriteString(leaderboardLine(row.Standing(index), row))
 		builder.WriteString("\n")
 	}
+	if strategy := strings.TrimSpace(leaderboard.ScoringStrategy); strategy != "" {
+		builder.WriteString(fmt.Sprintf("_Scoring: %s_\n", strategy))
+	}
+	return TrimMessage(strings.TrimSpace(builder.String()))
+}
+
+// WhatIfLeaderboard renders a projected leaderboard with each participant's
+// points and rank movement relative to the real standings.
+func WhatIfLeaderboard(instance castaway.Instance, projection castaway.WhatIfLeaderboard) string {
+	names := make([]string, 0, len(projection.Eliminations))
+	for _, elimination := range projection.Eliminations {
+		names = append(names, elimination.ContestantName)
+	}
+
+	verb := "go"
+	if len(names) == 1 {
+		verb = "goes"
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("**Season %d: What if %s %s next?**\n", instance.Season, strings.Join(names, ", "), verb))
+	for _, row := range projection.Rows {
+		rankChange := row.RankChange
+		builder.WriteString(fmt.Sprintf("%d. %s: %d (%+d)%s\n", row.Rank, strings.TrimSpace(row.ParticipantName), row.TotalPoints, row.PointsChange, rankChangeSuffix(&rankChange)))
+	}
+	if strategy := strings.TrimSpace(projection.ScoringStrategy); strategy != "" {
+		builder.WriteString(fmt.Sprintf("_Scoring: %s_\n", strategy))
+	}
 	return TrimMessage(strings.TrimSpace(builder.String()))
 }
 
@@ -64,7 +100,31 @@ func leaderboardLine(rank int, row castaway.LeaderboardRow) string {
 	if discordUserID := strings.TrimSpace(row.ParticipantDiscordUserID); discordUserID != "" {
 		displayName = "<@" + discordUserID + ">"
 	}
-	return fmt.Sprintf("%d. %s%s: %d (%d+%d)", rank, prefix, displayName, row.Total(), row.Draft(), row.Bonus())
+	return fmt.Sprintf("%d. %s%s: %d (%d+%d)%s%s", rank, prefix, displayName, row.Total(), row.Draft(), row.Bonus(), rankChangeSuffix(row.RankChange), contentionSuffix(row))
+}
+
+// contentionSuffix marks participants whose finish for first place is
+// decided as long as bonus totals stay where they are.
+func contentionSuffix(row castaway.LeaderboardRow) string {
+	switch {
+	case row.ClinchedOnCurrentBonus:
+		return " :trophy: clinched on current bonus"
+	case row.EliminatedOnCurrentBonus:
+		return " :x: eliminated on current bonus"
+	default:
+		return ""
+	}
+}
+
+func rankChangeSuffix(change *int) string {
+	switch {
+	case change == nil || *change == 0:
+		return ""
+	case *change > 0:
+		return fmt.Sprintf(" ▲%d", *change)
+	default:
+		return fmt.Sprintf(" ▼%d", -*change)
+	}
 }
 
 func tribeBadge(name string) string {
@@ -99,6 +159,11 @@ func InstanceList(instances []castaway.Instance) string {
 	for _, instance := range instances {
 		builder.WriteString("- ")
 		builder.WriteString(InstanceLabel(instance))
+		if state := strings.TrimSpace(instance.State); state != "" {
+			builder.WriteString(" (")
+			builder.WriteString(state)
+			builder.WriteString(")")
+		}
 		builder.WriteString("\n")
 	}
 	return TrimMessage(strings.TrimSpace(builder.String()))
@@ -18,6 +18,18 @@ func TestTrimMessageTruncatesLongResponses(t *testing.T) {
 	}
 }
 
+func TestInstanceListShowsLifecycleState(t *testing.T) {
+	message := InstanceList([]castaway.Instance{
+		{Name: "Office Pool", Season: 50, State: "active"},
+		{Name: "Season 49", Season: 49, State: "archived"},
+		{Name: "Legacy Pool", Season: 48},
+	})
+	expected := "**Instances**\n- Season 50 — Office Pool (active)\n- Season 49 (archived)\n- Season 48 — Legacy Pool"
+	if message != expected {
+		t.Fatalf("unexpected message:\nexpected: %q\nactual:   %q", expected, message)
+	}
+}
+
 func TestSingleScoreFormatsLeaderboardStyleOutput(t *testing.T) {
 	instance := castaway.Instance{Name: "Office Pool", Season: 49}
 	row := castaway.LeaderboardRow{ParticipantName: "Bryan", ParticipantDiscordUserID: "user-1", CurrentTribeName: "Lotus", Score: 26, DraftPoints: 21, BonusPoints: 5, TotalPoints: 26, PointsAvailable: 46}
@@ -234,15 +246,85 @@ func TestLeaderboardIncludesTotalDraftAndBonus(t *testing.T) {
 	instance := castaway.Instance{Name: "Office Pool", Season: 49}
 	rows := []castaway.LeaderboardRow{{ParticipantName: "Bryan", ParticipantDiscordUserID: "user-1", CurrentTribeName: "Leafy Green", Score: 26, DraftPoints: 21, BonusPoints: 5, TotalPoints: 26, PointsAvailable: 46}, {ParticipantName: "Keith", CurrentTribeName: "Tangerine", Score: 19, DraftPoints: 19, BonusPoints: 0, TotalPoints: 19, PointsAvailable: 41}}
 
-	message := Leaderboard(instance, rows)
+	message := Leaderboard(instance, castaway.Leaderboard{ScoringStrategy: "distance", Rows: rows})
 	expected := strings.Join([]string{
 		"**Season 49: Leaderboard**",
 		"1. :leafy_green: <@user-1>: 26 (21+5)",
 		"2. :tangerine: Keith: 19 (19+0)",
+		"_Scoring: distance_",
+	}, "\n")
+	if message != expected {
+		t.Fatalf("unexpected message:\nexpected: %q\nactual:   %q", expected, message)
+	}
+}
+
+func TestLeaderboardFlagsClinchedAndEliminatedParticipants(t *testing.T) {
+	instance := castaway.Instance{Name: "Office Pool", Season: 49}
+	rows := []castaway.LeaderboardRow{
+		{ParticipantName: "Amanda", Rank: 1, DraftPoints: 101, TotalPoints: 101, ClinchedOnCurrentBonus: true},
+		{ParticipantName: "Kate", Rank: 2, DraftPoints: 90, TotalPoints: 90},
+		{ParticipantName: "Katie", Rank: 3, DraftPoints: 40, TotalPoints: 40, EliminatedOnCurrentBonus: true},
+	}
+
+	message := Leaderboard(instance, castaway.Leaderboard{Rows: rows})
+	expected := strings.Join([]string{
+		"**Season 49: Leaderboard**",
+		"1. Amanda: 101 (101+0) :trophy: clinched on current bonus",
+		"2. Kate: 90 (90+0)",
+		"3. Katie: 40 (40+0) :x: eliminated on current bonus",
+	}, "\n")
+	if message != expected {
+		t.Fatalf("unexpected message:\nexpected: %q\nactual:   %q", expected, message)
+	}
+}
+
+func TestEpisodeLeaderboardShowsSharedRanksAndRankChange(t *testing.T) {
+	instance := castaway.Instance{Name: "Office Pool", Season: 50}
+	up, down, flat := 2, -1, 0
+	rows := []castaway.LeaderboardRow{
+		{ParticipantName: "Bryan", Rank: 1, RankChange: &up, DraftPoints: 20, BonusPoints: 4, TotalPoints: 24},
+		{ParticipantName: "Keith", Rank: 1, RankChange: &down, DraftPoints: 24, TotalPoints: 24},
+		{ParticipantName: "Amanda", Rank: 3, RankChange: &flat, DraftPoints: 10, TotalPoints: 10},
+	}
+
+	message := EpisodeLeaderboard(instance, 4, castaway.Leaderboard{Rows: rows})
+	expected := strings.Join([]string{
+		"**Season 50: Leaderboard after Episode 4**",
+		"1. Bryan: 24 (20+4) ▲2",
+		"1. Keith: 24 (24+0) ▼1",
+		"3. Amanda: 10 (10+0)",
+	}, "\n")
+	if message != expected {
+		t.Fatalf("unexpected message:\nexpected: %q\nactual:   %q", expected, message)
+	}
+}
+
+func TestWhatIfLeaderboardShowsPointsAndRankMovement(t *testing.T) {
+	instance := castaway.Instance{Name: "Office Pool", Season: 50}
+	projection := castaway.WhatIfLeaderboard{
+		ScoringStrategy: "distance",
+		Eliminations:    []castaway.WhatIfElimination{{ContestantName: "Rizo", Position: 18}, {ContestantName: "Joe", Position: 17}},
+		Rows: []castaway.WhatIfRow{
+			{ParticipantName: "Keith", Rank: 1, RankChange: 1, TotalPoints: 30, PointsChange: 6},
+			{ParticipantName: "Bryan", Rank: 2, RankChange: -1, TotalPoints: 27, PointsChange: 0},
+		},
+	}
+
+	message := WhatIfLeaderboard(instance, projection)
+	expected := strings.Join([]string{
+		"**Season 50: What if Rizo, Joe go next?**",
+		"1. Keith: 30 (+6) ▲1",
+		"2. Bryan: 27 (+0) ▼1",
+		"_Scoring: distance_",
 	}, "\n")
 	if message != expected {
 		t.Fatalf("unexpected message:\nexpected: %q\nactual:   %q", expected, message)
 	}
+
+	projection.Eliminations = projection.Eliminations[:1]
+	if message := WhatIfLeaderboard(instance, projection); !strings.HasPrefix(message, "**Season 50: What if Rizo goes next?**") {
+		t.Fatalf("unexpected single elimination title: %q", message)
+	}
 }
 
 func TestStirThePotTribeStatusFormatsCurrentTotal(t *testing.T) {
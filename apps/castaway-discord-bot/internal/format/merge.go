@@ -190,6 +190,33 @@ func LoanStatus(instance castaway.Instance, response castaway.LoanStatusResponse
 	if strings.TrimSpace(loan.DueAt) != "" {
 		lines = append(lines, fmt.Sprintf("- Due at: %s", formatTimeLong(loan.DueAt)))
 	}
+	if loan.LastDefault != nil {
+		lines = append(lines,
+			fmt.Sprintf("Your last loan defaulted at %s.", formatTimeLong(loan.LastDefault.DefaultedAt)),
+			fmt.Sprintf("- Collected: %d", loan.LastDefault.CollectedPoints),
+			fmt.Sprintf("- Unpaid: %d", loan.LastDefault.ShortfallPoints),
+			fmt.Sprintf("- Penalty: %d", loan.LastDefault.PenaltyPoints),
+		)
+	}
+	return TrimMessage(strings.Join(lines, "\n"))
+}
+
+// LoanDefaultNotice is the direct message sent when the Loan Shark collects a
+// participant's overdue loan. loan is their status after the default; the
+// settlement lines are left out when it has no LastDefault.
+func LoanDefaultNotice(instance castaway.Instance, loan castaway.LoanStatus) string {
+	lines := []string{
+		fmt.Sprintf("**Season %d: Loan Shark**", instance.Season),
+		"Your loan went unpaid past its due date, so the Loan Shark collected it.",
+	}
+	if loan.LastDefault != nil {
+		lines = append(lines,
+			fmt.Sprintf("- Collected: %d", loan.LastDefault.CollectedPoints),
+			fmt.Sprintf("- Unpaid: %d", loan.LastDefault.ShortfallPoints),
+			fmt.Sprintf("- Penalty: %d", loan.LastDefault.PenaltyPoints),
+			fmt.Sprintf("- Bonus points available: %d", loan.BonusPointsAvailable),
+		)
+	}
 	return TrimMessage(strings.Join(lines, "\n"))
 }
 
@@ -246,13 +273,7 @@ func isHiddenStirThePotTier(tier castaway.StirThePotRewardTier, tiers []castaway
 }
 
 func SecretRevealAnnouncement(participant castaway.Participant, revealedSecretPoints int) string {
-	label := strings.TrimSpace(participant.Name)
-	discordUserID := strings.TrimSpace(participant.DiscordUserID)
-	if discordUserID != "" && label != "" {
-		label = fmt.Sprintf("<@%s> (%s)", discordUserID, label)
-	} else if discordUserID != "" {
-		label = fmt.Sprintf("<@%s>", discordUserID)
-	}
+	label := participantMention(participant)
 	if label == "" || revealedSecretPoints <= 0 {
 		return ""
 	}
@@ -261,3 +282,17 @@ func SecretRevealAnnouncement(participant castaway.Participant, revealedSecretPo
 	}
 	return fmt.Sprintf("%s has revealed %d secret bonus points!", label, revealedSecretPoints)
 }
+
+// participantMention is a real Discord mention for linked participants, with
+// their name alongside when it is known.
+func participantMention(participant castaway.Participant) string {
+	label := strings.TrimSpace(participant.Name)
+	discordUserID := strings.TrimSpace(participant.DiscordUserID)
+	if discordUserID != "" && label != "" {
+		return fmt.Sprintf("<@%s> (%s)", discordUserID, label)
+	}
+	if discordUserID != "" {
+		return fmt.Sprintf("<@%s>", discordUserID)
+	}
+	return label
+}
@@ -12,6 +12,7 @@ import (
 const (
 	guildDefaultsBucket = "guild_defaults"
 	userDefaultsBucket  = "user_defaults"
+	outboxCursorsBucket = "outbox_cursors"
 )
 
 type BoltStore struct {
@@ -36,6 +37,9 @@ func OpenBolt(path string) (*BoltStore, error) {
 		if _, err := tx.CreateBucketIfNotExists([]byte(userDefaultsBucket)); err != nil {
 			return err
 		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(outboxCursorsBucket)); err != nil {
+			return err
+		}
 		return nil
 	}); err != nil {
 		_ = db.Close()
@@ -73,6 +77,14 @@ func (s *BoltStore) ClearUserDefault(guildID, userID string) error {
 	return s.delete(userDefaultsBucket, userKey(guildID, userID))
 }
 
+func (s *BoltStore) SetOutboxCursor(instanceID, eventID string) error {
+	return s.put(outboxCursorsBucket, trimOrEmpty(instanceID), trimOrEmpty(eventID))
+}
+
+func (s *BoltStore) GetOutboxCursor(instanceID string) (string, error) {
+	return s.get(outboxCursorsBucket, trimOrEmpty(instanceID))
+}
+
 func (s *BoltStore) put(bucketName, key, value string) error {
 	if key == "" {
 		return fmt.Errorf("state key is required")
@@ -11,6 +11,7 @@ import (
 type ImportResult struct {
 	GuildDefaultsImported int
 	UserDefaultsImported  int
+	OutboxCursorsImported int
 }
 
 func ImportBoltToPostgres(ctx context.Context, boltPath, postgresURL string) (*ImportResult, error) {
@@ -64,6 +65,22 @@ func ImportBoltToPostgres(ctx context.Context, boltPath, postgresURL string) (*I
 		return nil, fmt.Errorf("import user defaults: %w", err)
 	}
 
+	if err := source.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(outboxCursorsBucket))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			if err := target.SetOutboxCursor(string(k), string(v)); err != nil {
+				return err
+			}
+			result.OutboxCursorsImported++
+			return nil
+		})
+	}); err != nil {
+		return nil, fmt.Errorf("import outbox cursors: %w", err)
+	}
+
 	return result, nil
 }
 
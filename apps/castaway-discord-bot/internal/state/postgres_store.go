@@ -161,6 +161,49 @@ func (s *PostgresStore) ClearUserDefault(guildID, userID string) error {
 	return nil
 }
 
+func (s *PostgresStore) SetOutboxCursor(instanceID, eventID string) error {
+	instanceID = trimOrEmpty(instanceID)
+	eventID = trimOrEmpty(eventID)
+	if instanceID == "" {
+		return fmt.Errorf("state key is required")
+	}
+	if eventID == "" {
+		return fmt.Errorf("state value is required")
+	}
+
+	_, err := s.pool.Exec(context.Background(), `
+		INSERT INTO outbox_cursors (instance_id, event_id)
+		VALUES ($1, $2)
+		ON CONFLICT (instance_id)
+		DO UPDATE SET event_id = EXCLUDED.event_id, updated_at = NOW()
+	`, instanceID, eventID)
+	if err != nil {
+		return fmt.Errorf("upsert outbox cursor: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetOutboxCursor(instanceID string) (string, error) {
+	instanceID = trimOrEmpty(instanceID)
+	if instanceID == "" {
+		return "", nil
+	}
+
+	var eventID string
+	err := s.pool.QueryRow(context.Background(), `
+		SELECT event_id
+		FROM outbox_cursors
+		WHERE instance_id = $1
+	`, instanceID).Scan(&eventID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("get outbox cursor: %w", err)
+	}
+	return eventID, nil
+}
+
 func (s *PostgresStore) ensureSchema(ctx context.Context) error {
 	for _, stmt := range []string{
 		`CREATE TABLE IF NOT EXISTS guild_defaults (
@@ -175,6 +218,11 @@ func (s *PostgresStore) ensureSchema(ctx context.Context) error {
 			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 			PRIMARY KEY (guild_id, user_id)
 		)`,
+		`CREATE TABLE IF NOT EXISTS outbox_cursors (
+			instance_id TEXT PRIMARY KEY,
+			event_id TEXT NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`,
 	} {
 		if _, err := s.pool.Exec(ctx, stmt); err != nil {
 			return fmt.Errorf("ensure postgres state schema: %w", err)
@@ -17,6 +17,10 @@ type Store interface {
 	SetUserDefault(guildID, userID, instanceID string) error
 	GetUserDefault(guildID, userID string) (string, error)
 	ClearUserDefault(guildID, userID string) error
+	// SetOutboxCursor records the last outbox event the bot handled for an
+	// instance, so notifications resume there after a restart.
+	SetOutboxCursor(instanceID, eventID string) error
+	GetOutboxCursor(instanceID string) (string, error)
 }
 
 type Options struct {
@@ -58,6 +58,31 @@ func TestBoltStoreUserDefaultsAreScopedByGuild(t *testing.T) {
 	}
 }
 
+func TestBoltStoreOutboxCursors(t *testing.T) {
+	store := openTestBoltStore(t)
+	defer store.Close()
+
+	got, err := store.GetOutboxCursor("instance-1")
+	if err != nil {
+		t.Fatalf("get missing outbox cursor: %v", err)
+	}
+	if got != "" {
+		t.Fatalf("expected no outbox cursor, got %q", got)
+	}
+	for _, eventID := range []string{"event-1", "event-2"} {
+		if err := store.SetOutboxCursor("instance-1", eventID); err != nil {
+			t.Fatalf("set outbox cursor: %v", err)
+		}
+	}
+	got, err = store.GetOutboxCursor("instance-1")
+	if err != nil {
+		t.Fatalf("get outbox cursor: %v", err)
+	}
+	if got != "event-2" {
+		t.Fatalf("unexpected outbox cursor: %q", got)
+	}
+}
+
 func TestPostgresStoreGuildDefaults(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	if err != nil {
@@ -137,6 +162,43 @@ func TestPostgresStoreUserDefaultsAreScopedByGuild(t *testing.T) {
 	}
 }
 
+func TestPostgresStoreOutboxCursors(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("new pgxmock pool: %v", err)
+	}
+	defer mock.Close()
+
+	store := &PostgresStore{pool: mock}
+
+	mock.ExpectExec("INSERT INTO outbox_cursors").WithArgs("instance-1", "event-1").WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	if err := store.SetOutboxCursor("instance-1", "event-1"); err != nil {
+		t.Fatalf("set outbox cursor: %v", err)
+	}
+
+	mock.ExpectQuery("SELECT event_id").WithArgs("instance-1").WillReturnRows(pgxmock.NewRows([]string{"event_id"}).AddRow("event-1"))
+	got, err := store.GetOutboxCursor("instance-1")
+	if err != nil {
+		t.Fatalf("get outbox cursor: %v", err)
+	}
+	if got != "event-1" {
+		t.Fatalf("unexpected outbox cursor: %q", got)
+	}
+
+	mock.ExpectQuery("SELECT event_id").WithArgs("instance-2").WillReturnError(pgx.ErrNoRows)
+	got, err = store.GetOutboxCursor("instance-2")
+	if err != nil {
+		t.Fatalf("get missing outbox cursor: %v", err)
+	}
+	if got != "" {
+		t.Fatalf("expected no outbox cursor, got %q", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
 func TestSplitUserKey(t *testing.T) {
 	guildID, userID, ok := splitUserKey("guild-1:user-2")
 	if !ok {
@@ -451,6 +451,7 @@ func ensureAdvantage(ctx context.Context, q *db.Queries, instanceID, participant
 		EffectiveAt:                timestamptz(mustTime("2026-03-25T20:00:00-04:00")),
 		EffectiveUntil:             optionalTimestamptz(ptrTime(mustTime("2026-04-08T20:00:00-04:00"))),
 		Metadata:                   []byte("{}"),
+		Visibility:                 "secret",
 	}); err != nil {
 		return fmt.Errorf("create advantage for %q: %w", participantName, err)
 	}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/app"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/config"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/seeddata"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatalf("bootstrap-season: %v", err)
+	}
+}
+
+func run() error {
+	configPath := flag.String("config", os.Getenv("SEASON_CONFIG"), "path to the season config YAML")
+	flag.Parse()
+	if *configPath == "" {
+		return fmt.Errorf("season config is required (-config or SEASON_CONFIG)")
+	}
+
+	season, err := seeddata.LoadSeasonConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("create db pool: %w", err)
+	}
+	defer pool.Close()
+
+	if err := pool.Ping(ctx); err != nil {
+		return fmt.Errorf("ping database: %w", err)
+	}
+	if err := app.RunMigrations(ctx, pool, cfg.MigrationsDir); err != nil {
+		return fmt.Errorf("run migrations: %w", err)
+	}
+
+	result, err := app.BootstrapSeason(ctx, pool, season)
+	if err != nil {
+		return fmt.Errorf("bootstrap season %d: %w", season.Season, err)
+	}
+
+	fmt.Println(app.BootstrapSummary(result))
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -15,6 +16,8 @@ import (
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/buildinfo"
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/config"
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/httpapi"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/scheduler"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/webhooks"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -54,23 +57,49 @@ func run() error {
 		}
 	}
 
+	streamsDone := make(chan struct{})
 	server := httpapi.New(pool, httpapi.WithServiceAuth(httpapi.ServiceAuthConfig{
 		Enabled:      cfg.ServiceAuthEnabled,
 		BearerTokens: cfg.ServiceAuthBearerTokens,
 		Principal:    cfg.ServiceAuthPrincipal,
-	}))
+		Scopes:       cfg.ServiceAuthScopes,
+	}), httpapi.WithEventStreamInterval(cfg.EventStreamInterval), httpapi.WithWebhookPrivateTargets(cfg.WebhookPrivateTargets), httpapi.WithShutdown(streamsDone))
 	router := server.Router()
+
+	if cfg.ServiceAuthEnabled {
+		log.Printf("castaway-web shared SERVICE_AUTH_BEARER_TOKENS are deprecated for callers other than bootstrap; they carry scopes %s, prefer per-caller credentials from POST /credentials", strings.Join(cfg.ServiceAuthScopes, ","))
+	}
+
+	schedulerCtx, stopScheduler := context.WithCancel(ctx)
+	defer stopScheduler()
+	if cfg.SchedulerEnabled {
+		runner := scheduler.New(pool, server.JobHandlers(), scheduler.WithLookback(cfg.SchedulerLookback))
+		go runner.Run(schedulerCtx, cfg.SchedulerInterval)
+		log.Printf("castaway-web scheduler running every %s", cfg.SchedulerInterval)
+	}
+	if cfg.WebhooksEnabled {
+		dispatcher := webhooks.New(pool, webhooks.WithPrivateTargets(cfg.WebhookPrivateTargets))
+		go dispatcher.Run(schedulerCtx, cfg.WebhookInterval)
+		log.Printf("castaway-web webhook dispatcher running every %s", cfg.WebhookInterval)
+	}
+
 	httpServer := &http.Server{
 		Addr:              ":" + cfg.Port,
 		Handler:           router,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
+	// Event streams stay open until told otherwise; end them as soon as
+	// shutdown starts so it does not wait out its timeout.
+	httpServer.RegisterOnShutdown(func() {
+		close(streamsDone)
+	})
 
 	shutdownDone := make(chan struct{})
 	go func() {
 		sigCh := make(chan os.Signal, 1)
 		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 		<-sigCh
+		stopScheduler()
 
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
@@ -0,0 +1,33 @@
+ALTER TABLE outcome_positions
+    ADD COLUMN episode_id BIGINT REFERENCES instance_episodes(id) ON DELETE SET NULL,
+    ADD COLUMN reason TEXT NOT NULL DEFAULT 'voted_out'
+        CHECK (reason IN ('voted_out', 'medevac', 'quit', 'removed', 'finalist'));
+
+CREATE INDEX outcome_positions_episode_idx
+    ON outcome_positions(episode_id);
+
+CREATE TABLE outcome_position_history (
+    id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    instance_id BIGINT NOT NULL REFERENCES instances(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    contestant_id BIGINT REFERENCES contestants(id) ON DELETE SET NULL,
+    previous_contestant_id BIGINT REFERENCES contestants(id) ON DELETE SET NULL,
+    episode_id BIGINT REFERENCES instance_episodes(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL CHECK (reason IN ('voted_out', 'medevac', 'quit', 'removed', 'finalist')),
+    note TEXT,
+    recorded_by_discord_user_id TEXT,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX outcome_position_history_instance_recorded_idx
+    ON outcome_position_history(instance_id, recorded_at);
+
+CREATE INDEX outcome_position_history_instance_position_idx
+    ON outcome_position_history(instance_id, position, recorded_at);
+
+-- Existing outcomes predate episode tracking; seed a single history row for
+-- each so corrections made from here on have a baseline to diff against.
+INSERT INTO outcome_position_history (instance_id, position, contestant_id, reason, recorded_at)
+SELECT instance_id, position, contestant_id, reason, updated_at
+FROM outcome_positions;
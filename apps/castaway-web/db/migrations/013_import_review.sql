@@ -0,0 +1,3 @@
+ALTER TABLE imports
+    ADD COLUMN validation JSONB NOT NULL DEFAULT '{}'::jsonb,
+    ADD COLUMN applied_at TIMESTAMPTZ;
@@ -0,0 +1,18 @@
+ALTER TABLE instances
+    ADD COLUMN draft_deadline TIMESTAMPTZ;
+
+CREATE TABLE draft_overrides (
+    id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    instance_id BIGINT NOT NULL REFERENCES instances(id) ON DELETE CASCADE,
+    participant_id BIGINT NOT NULL REFERENCES participants(id) ON DELETE CASCADE,
+    actor_discord_user_id TEXT NOT NULL,
+    reason TEXT NOT NULL CHECK (btrim(reason) <> ''),
+    draft_deadline TIMESTAMPTZ NOT NULL,
+    previous_contestant_ids JSONB NOT NULL DEFAULT '[]'::jsonb,
+    contestant_ids JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX draft_overrides_instance_created_idx
+    ON draft_overrides(instance_id, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE instances
+    ADD COLUMN scoring_strategy TEXT NOT NULL DEFAULT 'distance'
+        CHECK (btrim(scoring_strategy) <> '');
@@ -0,0 +1,3 @@
+ALTER TABLE instance_contestants
+    ADD COLUMN win_odds DOUBLE PRECISION
+        CHECK (win_odds IS NULL OR win_odds > 0);
@@ -0,0 +1,9 @@
+ALTER TABLE instances
+    ADD COLUMN state TEXT NOT NULL DEFAULT 'active'
+        CHECK (state IN ('setup', 'drafting', 'active', 'completed', 'archived')),
+    ADD COLUMN state_changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- Instances that existed before the lifecycle are already underway; new
+-- instances start in setup.
+ALTER TABLE instances
+    ALTER COLUMN state SET DEFAULT 'setup';
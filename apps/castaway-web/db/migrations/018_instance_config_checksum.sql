@@ -0,0 +1,4 @@
+-- Checksum of the season config an instance was bootstrapped from, so
+-- re-applying the same config is a no-op.
+ALTER TABLE instances
+    ADD COLUMN config_checksum TEXT;
@@ -0,0 +1,37 @@
+-- Jobs the background scheduler runs when an episode airs. There is one row
+-- per instance, episode and job type, so planning the same boundary twice is
+-- a no-op.
+CREATE TABLE scheduled_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    instance_id BIGINT NOT NULL REFERENCES instances(id) ON DELETE CASCADE,
+    episode_id BIGINT NOT NULL REFERENCES instance_episodes(id) ON DELETE CASCADE,
+    job_type TEXT NOT NULL,
+    run_at TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'skipped', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+    last_error TEXT,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (instance_id, episode_id, job_type)
+);
+
+CREATE INDEX scheduled_jobs_due_idx
+    ON scheduled_jobs(run_at)
+    WHERE status = 'pending';
+
+CREATE TABLE scheduled_job_runs (
+    id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    scheduled_job_id BIGINT NOT NULL REFERENCES scheduled_jobs(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('succeeded', 'skipped', 'failed')),
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL,
+    result JSONB NOT NULL DEFAULT '{}'::jsonb,
+    error TEXT,
+    CHECK (finished_at >= started_at)
+);
+
+CREATE INDEX scheduled_job_runs_job_idx
+    ON scheduled_job_runs(scheduled_job_id, started_at);
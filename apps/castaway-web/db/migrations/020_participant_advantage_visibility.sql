@@ -0,0 +1,7 @@
+ALTER TABLE participant_advantages
+    ADD COLUMN visibility TEXT NOT NULL DEFAULT 'secret' CHECK (visibility IN ('public', 'secret', 'revealed')),
+    ADD COLUMN used_at TIMESTAMPTZ;
+
+CREATE INDEX participant_advantages_active_until_idx
+    ON participant_advantages(instance_id, effective_until)
+    WHERE status = 'active' AND effective_until IS NOT NULL;
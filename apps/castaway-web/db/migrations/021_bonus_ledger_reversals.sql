@@ -0,0 +1,17 @@
+-- Re-resolving an occurrence voids its ledger entries and offsets each with a
+-- correction that points back at it, so totals stay a plain SUM and history
+-- shows what changed. Award keys only need to be unique among live entries,
+-- which lets the resolver write the same keys again.
+ALTER TABLE bonus_point_ledger_entries
+    ADD COLUMN reverses_entry_id BIGINT REFERENCES bonus_point_ledger_entries(id) ON DELETE CASCADE,
+    ADD COLUMN voided_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX bonus_point_ledger_entries_reverses_entry_idx
+    ON bonus_point_ledger_entries(reverses_entry_id)
+    WHERE reverses_entry_id IS NOT NULL;
+
+DROP INDEX bonus_point_ledger_entries_occurrence_participant_award_key_idx;
+
+CREATE UNIQUE INDEX bonus_point_ledger_entries_occurrence_participant_award_key_idx
+    ON bonus_point_ledger_entries(activity_occurrence_id, participant_id, award_key)
+    WHERE award_key IS NOT NULL AND voided_at IS NULL;
@@ -0,0 +1,29 @@
+-- Admin mutations append one row here in the same transaction as the change,
+-- recording who made it and what the affected record looked like before and
+-- after. Rows are never edited; they only go away with their instance.
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    instance_id BIGINT NOT NULL REFERENCES instances(id) ON DELETE CASCADE,
+    action TEXT NOT NULL,
+    service_principal TEXT,
+    actor_discord_user_id TEXT,
+    method TEXT NOT NULL,
+    route TEXT NOT NULL,
+    before JSONB,
+    after JSONB,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX audit_events_instance_occurred_idx
+    ON audit_events(instance_id, occurred_at DESC, id DESC);
+
+CREATE FUNCTION audit_events_reject_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_reject_update();
@@ -0,0 +1,19 @@
+-- Player write routes accept an Idempotency-Key header so a retried Discord
+-- interaction cannot spend points twice. The first request claims the key
+-- and stores its response; a replay with the same fingerprint gets that
+-- response back instead of running again. Keys expire and are pruned.
+CREATE TABLE idempotency_keys (
+    id BIGSERIAL PRIMARY KEY,
+    instance_id BIGINT NOT NULL REFERENCES instances(id) ON DELETE CASCADE,
+    idempotency_key TEXT NOT NULL,
+    request_fingerprint TEXT NOT NULL,
+    status_code INTEGER,
+    response_body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (instance_id, idempotency_key)
+);
+
+CREATE INDEX idempotency_keys_expires_idx
+    ON idempotency_keys(expires_at);
@@ -0,0 +1,25 @@
+-- Named API credentials replace sharing one bearer token across every
+-- caller. Only a SHA-256 hash of each token is stored. Scopes limit what a
+-- credential may do and instance_ids, when not empty, limit which instances
+-- it may touch. Rotation keeps the previous hash valid for a grace period so
+-- callers can be redeployed without downtime.
+CREATE TABLE api_credentials (
+    id BIGSERIAL PRIMARY KEY,
+    public_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL UNIQUE CHECK (btrim(name) <> ''),
+    token_prefix TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    previous_token_hash TEXT UNIQUE,
+    previous_token_expires_at TIMESTAMPTZ,
+    scopes TEXT[] NOT NULL CHECK (
+        cardinality(scopes) > 0
+        AND scopes <@ ARRAY['read-only', 'player-actions', 'instance-admin', 'super-admin']::TEXT[]
+    ),
+    instance_ids UUID[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    rotated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,20 @@
+-- audit_events was only guarded against UPDATE. Deleting or truncating rows
+-- is now rejected too. Deleting an instance still cascades to its events: by
+-- the time the cascade reaches audit_events the instance row is gone, which
+-- is how the trigger tells a cascade apart from a direct DELETE.
+CREATE FUNCTION audit_events_reject_delete() RETURNS trigger AS $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM instances WHERE id = OLD.instance_id) THEN
+        RAISE EXCEPTION 'audit_events is append-only';
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_delete
+    BEFORE DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_reject_delete();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_reject_update();
@@ -0,0 +1,18 @@
+-- Some admin mutations, such as issuing or revoking API credentials, are not
+-- tied to any instance. Their audit events have no instance_id and, since no
+-- instance delete can cascade to them, can never be deleted.
+ALTER TABLE audit_events
+    ALTER COLUMN instance_id DROP NOT NULL;
+
+CREATE INDEX audit_events_global_occurred_idx
+    ON audit_events(occurred_at DESC, id DESC)
+    WHERE instance_id IS NULL;
+
+CREATE OR REPLACE FUNCTION audit_events_reject_delete() RETURNS trigger AS $$
+BEGIN
+    IF OLD.instance_id IS NULL OR EXISTS (SELECT 1 FROM instances WHERE id = OLD.instance_id) THEN
+        RAISE EXCEPTION 'audit_events is append-only';
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,5 @@
+-- A credential below instance-admin may only act for the Discord user it was
+-- issued to, so X-Discord-User-ID can no longer be used to impersonate other
+-- players. Unbound credentials keep working for routes that act for no user.
+ALTER TABLE api_credentials
+    ADD COLUMN discord_user_id TEXT CHECK (discord_user_id IS NULL OR btrim(discord_user_id) <> '');
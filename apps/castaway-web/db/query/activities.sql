@@ -155,6 +155,33 @@ WHERE ia.public_id = sqlc.arg(activity_id)
   AND (aga.ends_at IS NULL OR aga.ends_at > sqlc.arg(at))
 ORDER BY pg.name ASC, aga.id ASC;
 
+-- name: ListActiveActivityGroupAssignmentsByInstanceAt :many
+SELECT
+    aga.id,
+    ia.public_id AS activity_id,
+    ia.name AS activity_name,
+    ia.activity_type,
+    pg.public_id AS participant_group_id,
+    pg.name AS participant_group_name,
+    aga.role,
+    aga.starts_at,
+    aga.ends_at
+FROM activity_group_assignments aga
+JOIN instance_activities ia ON ia.id = aga.activity_id
+JOIN instances i ON i.id = ia.instance_id
+JOIN participant_groups pg ON pg.id = aga.participant_group_id
+WHERE i.public_id = sqlc.arg(instance_id)
+  AND aga.starts_at <= sqlc.arg(at)
+  AND (aga.ends_at IS NULL OR aga.ends_at > sqlc.arg(at))
+ORDER BY ia.name ASC, pg.name ASC, aga.id ASC;
+
+-- name: EndActivityGroupAssignment :execrows
+UPDATE activity_group_assignments
+SET ends_at = sqlc.arg(ends_at)
+WHERE id = sqlc.arg(id)
+  AND starts_at < sqlc.arg(ends_at)
+  AND (ends_at IS NULL OR ends_at > sqlc.arg(ends_at));
+
 -- name: CreateActivityParticipantAssignment :one
 WITH resolved_activity AS (
     SELECT
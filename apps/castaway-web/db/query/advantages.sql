@@ -31,7 +31,8 @@ INSERT INTO participant_advantages (
     granted_at,
     effective_at,
     effective_until,
-    metadata
+    metadata,
+    visibility
 )
 SELECT
     ri.instance_internal_id,
@@ -44,7 +45,8 @@ SELECT
     sqlc.arg(granted_at),
     sqlc.arg(effective_at),
     sqlc.arg(effective_until),
-    sqlc.arg(metadata)
+    sqlc.arg(metadata),
+    sqlc.arg(visibility)
 FROM resolved_instance ri
 CROSS JOIN resolved_participant rp
 LEFT JOIN resolved_group rg ON TRUE
@@ -57,6 +59,7 @@ RETURNING
     advantage_type,
     name,
     status,
+    visibility,
     (SELECT source_activity_occurrence_id FROM resolved_occurrence) AS source_activity_occurrence_id,
     granted_at,
     effective_at,
@@ -124,3 +127,80 @@ UPDATE participant_advantages
 SET status = 'used', updated_at = NOW()
 WHERE public_id = sqlc.arg(id)
   AND status = 'active';
+
+-- name: ListParticipantAdvantagesByInstance :many
+SELECT
+    pa.public_id AS id,
+    i.public_id AS instance_id,
+    p.public_id AS participant_id,
+    p.name AS participant_name,
+    pg.public_id AS participant_group_id,
+    pg.name AS participant_group_name,
+    pa.advantage_type,
+    pa.name,
+    pa.status,
+    pa.visibility,
+    ao.public_id AS source_activity_occurrence_id,
+    pa.granted_at,
+    pa.effective_at,
+    pa.effective_until,
+    pa.used_at,
+    pa.metadata,
+    pa.created_at,
+    pa.updated_at
+FROM participant_advantages pa
+JOIN instances i ON i.id = pa.instance_id
+JOIN participants p ON p.id = pa.participant_id
+LEFT JOIN participant_groups pg ON pg.id = pa.participant_group_id
+LEFT JOIN activity_occurrences ao ON ao.id = pa.source_activity_occurrence_id
+WHERE i.public_id = sqlc.arg(instance_id)
+ORDER BY pa.granted_at ASC, pa.id ASC;
+
+-- name: GetParticipantAdvantage :one
+SELECT
+    pa.public_id AS id,
+    i.public_id AS instance_id,
+    p.public_id AS participant_id,
+    p.name AS participant_name,
+    pg.public_id AS participant_group_id,
+    pg.name AS participant_group_name,
+    pa.advantage_type,
+    pa.name,
+    pa.status,
+    pa.visibility,
+    ao.public_id AS source_activity_occurrence_id,
+    pa.granted_at,
+    pa.effective_at,
+    pa.effective_until,
+    pa.used_at,
+    pa.metadata,
+    pa.created_at,
+    pa.updated_at
+FROM participant_advantages pa
+JOIN instances i ON i.id = pa.instance_id
+JOIN participants p ON p.id = pa.participant_id
+LEFT JOIN participant_groups pg ON pg.id = pa.participant_group_id
+LEFT JOIN activity_occurrences ao ON ao.id = pa.source_activity_occurrence_id
+WHERE pa.public_id = sqlc.arg(id);
+
+-- name: ExpireParticipantAdvantages :execrows
+UPDATE participant_advantages pa
+SET status = 'expired', updated_at = NOW()
+FROM instances i
+WHERE i.id = pa.instance_id
+  AND i.public_id = sqlc.arg(instance_id)
+  AND pa.status = 'active'
+  AND pa.effective_until IS NOT NULL
+  AND pa.effective_until <= sqlc.arg(at);
+
+-- name: PlayParticipantAdvantage :execrows
+UPDATE participant_advantages
+SET status = 'used',
+    used_at = sqlc.arg(at),
+    visibility = CASE WHEN sqlc.arg(reveal)::boolean AND visibility = 'secret' THEN 'revealed' ELSE visibility END,
+    metadata = metadata || sqlc.arg(metadata)::jsonb,
+    updated_at = NOW()
+WHERE public_id = sqlc.arg(id)
+  AND status = 'active'
+  AND effective_at <= sqlc.arg(at)
+  AND (effective_until IS NULL OR effective_until > sqlc.arg(at));
@@ -0,0 +1,74 @@
+-- name: CountInstancesByPublicIDs :one
+SELECT COUNT(*)
+FROM instances
+WHERE public_id = ANY(sqlc.arg(instance_ids)::uuid[]);
+
+-- name: CreateAPICredential :one
+INSERT INTO api_credentials (
+    name,
+    token_prefix,
+    token_hash,
+    scopes,
+    instance_ids,
+    discord_user_id,
+    expires_at
+)
+VALUES (
+    sqlc.arg(name),
+    sqlc.arg(token_prefix),
+    sqlc.arg(token_hash),
+    sqlc.arg(scopes)::text[],
+    sqlc.arg(instance_ids)::uuid[],
+    sqlc.narg(discord_user_id),
+    sqlc.narg(expires_at)
+)
+RETURNING public_id AS id, name, token_prefix, scopes, instance_ids, discord_user_id, expires_at, revoked_at, last_used_at, rotated_at, created_at;
+
+-- name: GetActiveAPICredentialByTokenHash :one
+SELECT
+    id,
+    public_id,
+    name,
+    scopes,
+    instance_ids,
+    discord_user_id
+FROM api_credentials
+WHERE revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > sqlc.arg(now))
+  AND (
+      token_hash = sqlc.arg(token_hash)
+      OR (previous_token_hash = sqlc.arg(token_hash) AND previous_token_expires_at > sqlc.arg(now))
+  );
+
+-- name: ListAPICredentials :many
+SELECT public_id AS id, name, token_prefix, scopes, instance_ids, discord_user_id, expires_at, revoked_at, last_used_at, rotated_at, created_at
+FROM api_credentials
+ORDER BY name;
+
+-- name: RevokeAPICredential :one
+UPDATE api_credentials
+SET revoked_at = COALESCE(revoked_at, sqlc.arg(revoked_at)),
+    previous_token_hash = NULL,
+    previous_token_expires_at = NULL,
+    updated_at = NOW()
+WHERE public_id = sqlc.arg(id)
+RETURNING public_id AS id, name, token_prefix, scopes, instance_ids, discord_user_id, expires_at, revoked_at, last_used_at, rotated_at, created_at;
+
+-- name: RotateAPICredential :one
+UPDATE api_credentials
+SET previous_token_hash = CASE WHEN sqlc.narg(previous_token_expires_at)::timestamptz IS NULL THEN NULL ELSE token_hash END,
+    previous_token_expires_at = sqlc.narg(previous_token_expires_at),
+    token_prefix = sqlc.arg(token_prefix),
+    token_hash = sqlc.arg(token_hash),
+    expires_at = COALESCE(sqlc.narg(expires_at), expires_at),
+    rotated_at = sqlc.arg(rotated_at),
+    updated_at = NOW()
+WHERE public_id = sqlc.arg(id)
+  AND revoked_at IS NULL
+RETURNING public_id AS id, name, token_prefix, scopes, instance_ids, discord_user_id, expires_at, revoked_at, last_used_at, rotated_at, created_at;
+
+-- name: TouchAPICredential :exec
+UPDATE api_credentials
+SET last_used_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id)
+  AND (last_used_at IS NULL OR last_used_at < sqlc.arg(now)::timestamptz - INTERVAL '1 minute');
@@ -0,0 +1,67 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (
+    instance_id,
+    action,
+    service_principal,
+    actor_discord_user_id,
+    method,
+    route,
+    before,
+    after
+)
+SELECT i.id, sqlc.arg(action), sqlc.narg(service_principal), sqlc.narg(actor_discord_user_id), sqlc.arg(method), sqlc.arg(route), sqlc.narg(before)::jsonb, sqlc.narg(after)::jsonb
+FROM instances i
+WHERE i.public_id = sqlc.arg(instance_id);
+
+-- name: CreateGlobalAuditEvent :exec
+INSERT INTO audit_events (
+    action,
+    service_principal,
+    actor_discord_user_id,
+    method,
+    route,
+    before,
+    after
+)
+VALUES (sqlc.arg(action), sqlc.narg(service_principal), sqlc.narg(actor_discord_user_id), sqlc.arg(method), sqlc.arg(route), sqlc.narg(before)::jsonb, sqlc.narg(after)::jsonb);
+
+-- name: ListAuditEventsByInstance :many
+SELECT
+    a.public_id AS id,
+    a.action,
+    a.service_principal,
+    a.actor_discord_user_id,
+    a.method,
+    a.route,
+    a.before,
+    a.after,
+    a.occurred_at
+FROM audit_events a
+JOIN instances i ON i.id = a.instance_id
+WHERE i.public_id = sqlc.arg(instance_id)
+  AND (sqlc.narg(action)::text IS NULL OR a.action = sqlc.narg(action)::text)
+  AND (sqlc.narg(actor_discord_user_id)::text IS NULL OR a.actor_discord_user_id = sqlc.narg(actor_discord_user_id)::text)
+  AND (sqlc.narg(since)::timestamptz IS NULL OR a.occurred_at >= sqlc.narg(since)::timestamptz)
+  AND (sqlc.narg(until)::timestamptz IS NULL OR a.occurred_at < sqlc.narg(until)::timestamptz)
+ORDER BY a.occurred_at DESC, a.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListGlobalAuditEvents :many
+SELECT
+    a.public_id AS id,
+    a.action,
+    a.service_principal,
+    a.actor_discord_user_id,
+    a.method,
+    a.route,
+    a.before,
+    a.after,
+    a.occurred_at
+FROM audit_events a
+WHERE a.instance_id IS NULL
+  AND (sqlc.narg(action)::text IS NULL OR a.action = sqlc.narg(action)::text)
+  AND (sqlc.narg(actor_discord_user_id)::text IS NULL OR a.actor_discord_user_id = sqlc.narg(actor_discord_user_id)::text)
+  AND (sqlc.narg(since)::timestamptz IS NULL OR a.occurred_at >= sqlc.narg(since)::timestamptz)
+  AND (sqlc.narg(until)::timestamptz IS NULL OR a.occurred_at < sqlc.narg(until)::timestamptz)
+ORDER BY a.occurred_at DESC, a.id DESC
+LIMIT sqlc.arg(row_limit);
@@ -159,6 +159,71 @@ WHERE ao.public_id = sqlc.arg(activity_occurrence_id)
   AND bple.visibility IN ('public', 'revealed')
 ORDER BY p.name ASC, bple.effective_at ASC, bple.created_at ASC, bple.id ASC;
 
+-- name: ListLiveBonusPointLedgerEntriesByOccurrence :many
+SELECT
+    bple.public_id AS id,
+    p.public_id AS participant_id,
+    p.name AS participant_name,
+    bple.entry_kind,
+    bple.points,
+    bple.visibility,
+    bple.reason,
+    bple.effective_at,
+    bple.award_key
+FROM bonus_point_ledger_entries bple
+JOIN participants p ON p.id = bple.participant_id
+JOIN activity_occurrences ao ON ao.id = bple.activity_occurrence_id
+WHERE ao.public_id = sqlc.arg(activity_occurrence_id)
+  AND bple.voided_at IS NULL
+  AND bple.reverses_entry_id IS NULL
+  AND bple.entry_kind NOT IN ('reveal', 'conversion')
+ORDER BY p.name ASC, bple.created_at ASC, bple.id ASC;
+
+-- name: ReverseBonusPointLedgerEntry :one
+WITH voided AS (
+    UPDATE bonus_point_ledger_entries
+    SET voided_at = sqlc.arg(voided_at)
+    WHERE public_id = sqlc.arg(id)
+      AND voided_at IS NULL
+      AND reverses_entry_id IS NULL
+    RETURNING *
+)
+INSERT INTO bonus_point_ledger_entries (
+    instance_id,
+    participant_id,
+    activity_occurrence_id,
+    source_group_id,
+    entry_kind,
+    points,
+    visibility,
+    reason,
+    effective_at,
+    metadata,
+    reverses_entry_id
+)
+SELECT
+    v.instance_id,
+    v.participant_id,
+    v.activity_occurrence_id,
+    v.source_group_id,
+    'correction',
+    -v.points,
+    v.visibility,
+    'Reversal: ' || v.reason,
+    v.effective_at,
+    v.metadata || jsonb_build_object('reverses_entry_id', v.public_id),
+    v.id
+FROM voided v
+RETURNING
+    public_id AS id,
+    (SELECT p.public_id FROM participants p WHERE p.id = participant_id) AS participant_id,
+    entry_kind,
+    points,
+    visibility,
+    reason,
+    effective_at,
+    created_at;
+
 -- name: GetVisibleBonusTotalByParticipant :one
 SELECT COALESCE(SUM(bple.points), 0)::INTEGER AS total_points
 FROM bonus_point_ledger_entries bple
@@ -43,3 +43,21 @@ SELECT EXISTS (
     WHERE i.public_id = sqlc.arg(instance_id)
       AND c.public_id = sqlc.arg(contestant_id)
 );
+
+-- name: ListContestantOddsByInstance :many
+SELECT c.public_id AS contestant_id, c.name AS contestant_name, ic.win_odds
+FROM instance_contestants ic
+JOIN instances i ON i.id = ic.instance_id
+JOIN contestants c ON c.id = ic.contestant_id
+WHERE i.public_id = sqlc.arg(instance_id)
+ORDER BY c.name ASC;
+
+-- name: SetContestantOdds :one
+UPDATE instance_contestants ic
+SET win_odds = sqlc.narg(win_odds)
+FROM instances i, contestants c
+WHERE ic.instance_id = i.id
+  AND ic.contestant_id = c.id
+  AND i.public_id = sqlc.arg(instance_id)
+  AND c.public_id = sqlc.arg(contestant_id)
+RETURNING c.public_id AS contestant_id, c.name AS contestant_name, ic.win_odds;
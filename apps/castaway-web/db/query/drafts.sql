@@ -56,3 +56,44 @@ JOIN participants p ON p.id = dp.participant_id
 JOIN contestants c ON c.id = dp.contestant_id
 WHERE i.public_id = sqlc.arg(instance_id)
 ORDER BY p.public_id ASC, dp.position ASC;
+
+-- name: CreateDraftOverride :one
+INSERT INTO draft_overrides (
+    instance_id,
+    participant_id,
+    actor_discord_user_id,
+    reason,
+    draft_deadline,
+    previous_contestant_ids,
+    contestant_ids
+)
+SELECT
+    i.id,
+    p.id,
+    sqlc.arg(actor_discord_user_id),
+    sqlc.arg(reason),
+    sqlc.arg(draft_deadline),
+    sqlc.arg(previous_contestant_ids),
+    sqlc.arg(contestant_ids)
+FROM instances i
+JOIN participants p ON p.instance_id = i.id
+WHERE i.public_id = sqlc.arg(instance_id)
+  AND p.public_id = sqlc.arg(participant_id)
+RETURNING public_id AS id, created_at;
+
+-- name: ListDraftOverridesByInstance :many
+SELECT
+    o.public_id AS id,
+    p.public_id AS participant_id,
+    p.name AS participant_name,
+    o.actor_discord_user_id,
+    o.reason,
+    o.draft_deadline,
+    o.previous_contestant_ids,
+    o.contestant_ids,
+    o.created_at
+FROM draft_overrides o
+JOIN instances i ON i.id = o.instance_id
+JOIN participants p ON p.id = o.participant_id
+WHERE i.public_id = sqlc.arg(instance_id)
+ORDER BY o.created_at DESC, o.id DESC;
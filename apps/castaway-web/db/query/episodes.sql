@@ -58,3 +58,87 @@ FROM instance_episodes ie
 JOIN instances i ON i.id = ie.instance_id
 WHERE i.public_id = sqlc.arg(instance_id)
 ORDER BY ie.episode_number ASC;
+
+-- name: GetInstanceEpisode :one
+SELECT
+    ie.public_id AS id,
+    i.public_id AS instance_id,
+    ie.episode_number,
+    ie.label,
+    ie.airs_at,
+    ie.metadata,
+    ie.created_at,
+    ie.updated_at
+FROM instance_episodes ie
+JOIN instances i ON i.id = ie.instance_id
+WHERE i.public_id = sqlc.arg(instance_id)
+  AND ie.public_id = sqlc.arg(id);
+
+-- name: UpdateInstanceEpisode :one
+UPDATE instance_episodes
+SET label = sqlc.arg(label),
+    airs_at = sqlc.arg(airs_at),
+    updated_at = NOW()
+WHERE public_id = sqlc.arg(id)
+RETURNING
+    public_id AS id,
+    (SELECT public_id FROM instances WHERE id = instance_episodes.instance_id) AS instance_id,
+    episode_number,
+    label,
+    airs_at,
+    metadata,
+    created_at,
+    updated_at;
+
+-- name: DeleteInstanceEpisode :exec
+DELETE FROM instance_episodes
+WHERE public_id = sqlc.arg(id);
+
+-- name: GetInstanceEpisodeUsage :one
+SELECT
+    (
+        SELECT COUNT(*)
+        FROM participant_group_membership_periods m
+        JOIN participant_groups g ON g.id = m.participant_group_id
+        WHERE g.instance_id = ie.instance_id
+          AND (m.starts_at = ie.airs_at OR m.ends_at = ie.airs_at)
+    ) AS membership_periods,
+    (
+        SELECT COUNT(*)
+        FROM activity_group_assignments aga
+        JOIN instance_activities a ON a.id = aga.activity_id
+        WHERE a.instance_id = ie.instance_id
+          AND (aga.starts_at = ie.airs_at OR aga.ends_at = ie.airs_at)
+    ) AS activity_group_assignments,
+    (
+        SELECT COUNT(*)
+        FROM activity_participant_assignments apa
+        JOIN instance_activities a ON a.id = apa.activity_id
+        WHERE a.instance_id = ie.instance_id
+          AND (apa.starts_at = ie.airs_at OR apa.ends_at = ie.airs_at)
+    ) AS activity_participant_assignments,
+    (
+        SELECT COUNT(*)
+        FROM instance_activities a
+        WHERE a.instance_id = ie.instance_id
+          AND (a.starts_at = ie.airs_at OR a.ends_at = ie.airs_at)
+    ) AS activities,
+    (
+        SELECT COUNT(*)
+        FROM activity_occurrences o
+        JOIN instance_activities a ON a.id = o.activity_id
+        WHERE a.instance_id = ie.instance_id
+          AND (o.starts_at = ie.airs_at OR o.ends_at = ie.airs_at)
+    ) AS occurrences,
+    (
+        SELECT COUNT(*)
+        FROM outcome_positions op
+        WHERE op.episode_id = ie.id
+    ) AS outcomes,
+    (
+        SELECT COUNT(*)
+        FROM outcome_position_history oph
+        WHERE oph.episode_id = ie.id
+    ) AS outcome_history
+FROM instance_episodes ie
+WHERE ie.public_id = sqlc.arg(id);
@@ -132,3 +132,23 @@ WHERE p.public_id = sqlc.arg(participant_id)
   AND pgmp.starts_at <= sqlc.arg(at)
   AND (pgmp.ends_at IS NULL OR pgmp.ends_at > sqlc.arg(at))
 ORDER BY pg.kind ASC, pg.name ASC, pgmp.id ASC;
+
+-- name: EndParticipantGroupMembershipPeriods :many
+UPDATE participant_group_membership_periods pgmp
+SET ends_at = sqlc.arg(ends_at)
+FROM participant_groups pg, participants p
+WHERE pg.id = pgmp.participant_group_id
+  AND p.id = pgmp.participant_id
+  AND pg.public_id = sqlc.arg(participant_group_id)
+  AND p.public_id = sqlc.arg(participant_id)
+  AND pgmp.starts_at < sqlc.arg(ends_at)
+  AND (pgmp.ends_at IS NULL OR pgmp.ends_at > sqlc.arg(ends_at))
+RETURNING
+    pgmp.id,
+    pg.public_id AS participant_group_id,
+    p.public_id AS participant_id,
+    pgmp.role,
+    pgmp.starts_at,
+    pgmp.ends_at,
+    pgmp.metadata,
+    pgmp.created_at;
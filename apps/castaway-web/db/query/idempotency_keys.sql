@@ -0,0 +1,38 @@
+-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (
+    instance_id,
+    idempotency_key,
+    request_fingerprint,
+    expires_at
+)
+SELECT i.id, sqlc.arg(idempotency_key), sqlc.arg(request_fingerprint), sqlc.arg(expires_at)
+FROM instances i
+WHERE i.public_id = sqlc.arg(instance_id)
+ON CONFLICT (instance_id, idempotency_key) DO NOTHING
+RETURNING id;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = sqlc.arg(status_code),
+    response_body = sqlc.arg(response_body),
+    completed_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: DeleteExpiredIdempotencyKeys :exec
+DELETE FROM idempotency_keys
+WHERE expires_at <= sqlc.arg(now);
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE id = sqlc.arg(id);
+
+-- name: GetIdempotencyKey :one
+SELECT
+    k.id,
+    k.request_fingerprint,
+    k.status_code,
+    k.response_body
+FROM idempotency_keys k
+JOIN instances i ON i.id = k.instance_id
+WHERE i.public_id = sqlc.arg(instance_id)
+  AND k.idempotency_key = sqlc.arg(idempotency_key);
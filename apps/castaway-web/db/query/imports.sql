@@ -0,0 +1,59 @@
+-- name: CreateImport :one
+INSERT INTO imports (name, season, content_type, payload, status, submissions_count)
+VALUES (
+    sqlc.arg(name),
+    sqlc.arg(season),
+    sqlc.arg(content_type),
+    sqlc.arg(payload),
+    'accepted',
+    sqlc.arg(submissions_count)
+)
+RETURNING public_id AS id, created_at;
+
+-- name: GetImport :one
+SELECT
+    imp.public_id AS id,
+    i.public_id AS instance_id,
+    imp.name,
+    imp.season,
+    imp.content_type,
+    imp.payload,
+    imp.status,
+    imp.error,
+    imp.submissions_count,
+    imp.validation,
+    imp.applied_at,
+    imp.created_at,
+    imp.updated_at
+FROM imports imp
+LEFT JOIN instances i ON i.id = imp.instance_id
+WHERE imp.public_id = sqlc.arg(id);
+
+-- name: GetImportForUpdate :one
+SELECT
+    imp.public_id AS id,
+    imp.name,
+    imp.season,
+    imp.content_type,
+    imp.payload,
+    imp.status
+FROM imports imp
+WHERE imp.public_id = sqlc.arg(id)
+FOR UPDATE;
+
+-- name: UpdateImportValidation :exec
+UPDATE imports
+SET status = sqlc.arg(status),
+    error = sqlc.narg(error),
+    validation = sqlc.arg(validation),
+    updated_at = NOW()
+WHERE public_id = sqlc.arg(id);
+
+-- name: MarkImportApplied :exec
+UPDATE imports
+SET status = 'applied',
+    instance_id = (SELECT id FROM instances WHERE instances.public_id = sqlc.arg(instance_id)),
+    error = NULL,
+    applied_at = NOW(),
+    updated_at = NOW()
+WHERE imports.public_id = sqlc.arg(id);
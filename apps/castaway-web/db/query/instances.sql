@@ -1,15 +1,15 @@
 -- name: CreateInstance :one
-INSERT INTO instances (name, season)
-VALUES ($1, $2)
-RETURNING public_id AS id, name, season, created_at;
+INSERT INTO instances (name, season, state)
+VALUES ($1, $2, COALESCE(sqlc.narg(state), 'setup'))
+RETURNING public_id AS id, name, season, created_at, state, state_changed_at;
 
 -- name: GetInstance :one
-SELECT public_id AS id, name, season, created_at
+SELECT public_id AS id, name, season, created_at, state, state_changed_at
 FROM instances
 WHERE public_id = sqlc.arg(id);
 
 -- name: ListInstances :many
-SELECT public_id AS id, name, season, created_at
+SELECT public_id AS id, name, season, created_at, state, state_changed_at
 FROM instances
 ORDER BY created_at DESC;
 
@@ -17,8 +17,131 @@ ORDER BY created_at DESC;
 DELETE FROM instances
 WHERE name = $1 AND season = $2;
 
+-- name: DeleteInstanceActivities :exec
+DELETE FROM instance_activities a
+USING instances i
+WHERE a.instance_id = i.id
+  AND i.public_id = sqlc.arg(instance_id);
+
+-- name: DeleteInstanceAdmins :exec
+DELETE FROM instance_admins ia
+USING instances i
+WHERE ia.instance_id = i.id
+  AND i.public_id = sqlc.arg(instance_id);
+
+-- name: DeleteInstanceContestants :exec
+DELETE FROM instance_contestants ic
+USING instances i
+WHERE ic.instance_id = i.id
+  AND i.public_id = sqlc.arg(instance_id);
+
+-- name: DeleteInstanceEpisodes :exec
+DELETE FROM instance_episodes e
+USING instances i
+WHERE e.instance_id = i.id
+  AND i.public_id = sqlc.arg(instance_id);
+
+-- name: DeleteInstanceOutcomeHistory :exec
+DELETE FROM outcome_position_history h
+USING instances i
+WHERE h.instance_id = i.id
+  AND i.public_id = sqlc.arg(instance_id);
+
+-- name: DeleteInstanceOutcomes :exec
+DELETE FROM outcome_positions op
+USING instances i
+WHERE op.instance_id = i.id
+  AND i.public_id = sqlc.arg(instance_id);
+
+-- name: DeleteInstanceParticipantGroups :exec
+DELETE FROM participant_groups g
+USING instances i
+WHERE g.instance_id = i.id
+  AND i.public_id = sqlc.arg(instance_id);
+
+-- name: DeleteInstanceParticipants :exec
+DELETE FROM participants p
+USING instances i
+WHERE p.instance_id = i.id
+  AND i.public_id = sqlc.arg(instance_id);
+
+-- name: ResetInstance :exec
+UPDATE instances
+SET state = sqlc.arg(state),
+    state_changed_at = NOW(),
+    draft_deadline = NULL,
+    scoring_strategy = DEFAULT,
+    config_checksum = NULL
+WHERE public_id = sqlc.arg(id);
+
 -- name: UpdateInstanceName :one
 UPDATE instances
 SET name = $2
 WHERE public_id = $1
-RETURNING public_id AS id, name, season, created_at;
+RETURNING public_id AS id, name, season, created_at, state, state_changed_at;
+
+-- name: GetInstanceDraftDeadline :one
+SELECT draft_deadline
+FROM instances
+WHERE public_id = sqlc.arg(id);
+
+-- name: SetInstanceDraftDeadline :one
+UPDATE instances
+SET draft_deadline = sqlc.narg(draft_deadline)
+WHERE public_id = sqlc.arg(id)
+RETURNING draft_deadline;
+
+-- name: GetInstanceScoringStrategy :one
+SELECT scoring_strategy
+FROM instances
+WHERE public_id = sqlc.arg(id);
+
+-- name: SetInstanceScoringStrategy :one
+UPDATE instances
+SET scoring_strategy = sqlc.arg(scoring_strategy)
+WHERE public_id = sqlc.arg(id)
+RETURNING scoring_strategy;
+
+-- name: GetInstanceState :one
+SELECT state
+FROM instances
+WHERE public_id = sqlc.arg(id);
+
+-- name: GetInstanceStateForUpdate :one
+SELECT state
+FROM instances
+WHERE public_id = sqlc.arg(id)
+FOR UPDATE;
+
+-- name: GetInstanceStateByActivity :one
+SELECT i.public_id AS instance_id, i.state
+FROM instance_activities a
+JOIN instances i ON i.id = a.instance_id
+WHERE a.public_id = sqlc.arg(activity_id);
+
+-- name: GetInstanceStateByOccurrence :one
+SELECT i.public_id AS instance_id, i.state
+FROM activity_occurrences o
+JOIN instance_activities a ON a.id = o.activity_id
+JOIN instances i ON i.id = a.instance_id
+WHERE o.public_id = sqlc.arg(occurrence_id);
+
+-- name: SetInstanceState :one
+UPDATE instances
+SET state = sqlc.arg(state),
+    state_changed_at = NOW()
+WHERE public_id = sqlc.arg(id)
+RETURNING state, state_changed_at;
+
+-- name: GetInstanceConfigByNameSeason :one
+SELECT public_id AS id, state, config_checksum
+FROM instances
+WHERE name = sqlc.arg(name) AND season = sqlc.arg(season)
+ORDER BY created_at DESC
+LIMIT 1
+FOR UPDATE;
+
+-- name: SetInstanceConfigChecksum :exec
+UPDATE instances
+SET config_checksum = sqlc.arg(config_checksum)
+WHERE public_id = sqlc.arg(id);
@@ -9,33 +9,152 @@ WITH resolved_instance AS (
     JOIN instance_contestants ic ON ic.contestant_id = c.id
     JOIN resolved_instance ri ON ri.instance_internal_id = ic.instance_id
     WHERE c.public_id = sqlc.arg(contestant_id)
+), resolved_episode AS (
+    SELECT ie.id AS episode_internal_id, ie.public_id AS episode_id
+    FROM instance_episodes ie
+    JOIN resolved_instance ri ON ri.instance_internal_id = ie.instance_id
+    WHERE ie.public_id = sqlc.arg(episode_id)
+), previous AS (
+    SELECT op.contestant_id AS previous_contestant_internal_id
+    FROM outcome_positions op
+    JOIN resolved_instance ri ON ri.instance_internal_id = op.instance_id
+    WHERE op.position = sqlc.arg(position)
 ), upserted AS (
-    INSERT INTO outcome_positions (instance_id, position, contestant_id, updated_at)
+    INSERT INTO outcome_positions (instance_id, position, contestant_id, episode_id, reason, updated_at)
     VALUES (
         (SELECT instance_internal_id FROM resolved_instance),
         sqlc.arg(position),
         (SELECT contestant_internal_id FROM resolved_contestant),
+        (SELECT episode_internal_id FROM resolved_episode),
+        COALESCE(NULLIF(sqlc.arg(reason)::TEXT, ''), 'voted_out'),
         NOW()
     )
     ON CONFLICT (instance_id, position)
-    DO UPDATE SET contestant_id = EXCLUDED.contestant_id, updated_at = NOW()
-    RETURNING instance_id, position, contestant_id, updated_at
+    DO UPDATE SET
+        contestant_id = EXCLUDED.contestant_id,
+        episode_id = EXCLUDED.episode_id,
+        reason = EXCLUDED.reason,
+        updated_at = NOW()
+    RETURNING instance_id, position, contestant_id, episode_id, reason, updated_at
+), history AS (
+    INSERT INTO outcome_position_history (
+        instance_id,
+        position,
+        contestant_id,
+        previous_contestant_id,
+        episode_id,
+        reason,
+        note,
+        recorded_by_discord_user_id,
+        recorded_at
+    )
+    SELECT
+        upserted.instance_id,
+        upserted.position,
+        upserted.contestant_id,
+        (SELECT previous_contestant_internal_id FROM previous),
+        upserted.episode_id,
+        upserted.reason,
+        sqlc.narg(note)::TEXT,
+        sqlc.narg(recorded_by_discord_user_id)::TEXT,
+        upserted.updated_at
+    FROM upserted
 )
 SELECT
     (SELECT instance_id FROM resolved_instance) AS instance_id,
     upserted.position,
     (SELECT contestant_id FROM resolved_contestant) AS contestant_id,
+    (SELECT episode_id FROM resolved_episode) AS episode_id,
+    upserted.reason,
     upserted.updated_at
 FROM upserted;
 
+-- name: GetOutcomePosition :one
+SELECT
+    i.public_id AS instance_id,
+    op.position,
+    c.public_id AS contestant_id,
+    ie.public_id AS episode_id,
+    ie.episode_number,
+    ie.label AS episode_label,
+    op.reason,
+    op.updated_at
+FROM outcome_positions op
+JOIN instances i ON i.id = op.instance_id
+LEFT JOIN contestants c ON c.id = op.contestant_id
+LEFT JOIN instance_episodes ie ON ie.id = op.episode_id
+WHERE i.public_id = sqlc.arg(instance_id)
+  AND op.position = sqlc.arg(position);
+
 -- name: ListOutcomePositionsByInstance :many
 SELECT
     i.public_id AS instance_id,
     op.position,
     c.public_id AS contestant_id,
+    ie.public_id AS episode_id,
+    ie.episode_number,
+    ie.label AS episode_label,
+    op.reason,
     op.updated_at
 FROM outcome_positions op
 JOIN instances i ON i.id = op.instance_id
 LEFT JOIN contestants c ON c.id = op.contestant_id
+LEFT JOIN instance_episodes ie ON ie.id = op.episode_id
 WHERE i.public_id = sqlc.arg(instance_id)
 ORDER BY op.position ASC;
+
+-- name: ListOutcomePositionsAsOf :many
+-- Rebuilds the board from the correction history: for each position, the
+-- latest recorded row that had taken effect by as_of. A row takes effect when
+-- its episode aired, or when it was recorded if it has no episode.
+WITH effective AS (
+    SELECT DISTINCT ON (h.position)
+        h.instance_id,
+        h.position,
+        h.contestant_id,
+        h.episode_id,
+        h.reason,
+        h.recorded_at
+    FROM outcome_position_history h
+    JOIN instances i ON i.id = h.instance_id
+    LEFT JOIN instance_episodes ie ON ie.id = h.episode_id
+    WHERE i.public_id = sqlc.arg(instance_id)
+      AND COALESCE(ie.airs_at, h.recorded_at) <= sqlc.arg(as_of)::TIMESTAMPTZ
+    ORDER BY h.position ASC, h.recorded_at DESC, h.id DESC
+)
+SELECT
+    i.public_id AS instance_id,
+    e.position,
+    c.public_id AS contestant_id,
+    ie.public_id AS episode_id,
+    ie.episode_number,
+    ie.label AS episode_label,
+    e.reason,
+    e.recorded_at AS updated_at
+FROM effective e
+JOIN instances i ON i.id = e.instance_id
+JOIN contestants c ON c.id = e.contestant_id
+LEFT JOIN instance_episodes ie ON ie.id = e.episode_id
+ORDER BY e.position ASC;
+
+-- name: ListOutcomePositionHistoryByInstance :many
+SELECT
+    h.public_id AS id,
+    i.public_id AS instance_id,
+    h.position,
+    c.public_id AS contestant_id,
+    pc.public_id AS previous_contestant_id,
+    ie.public_id AS episode_id,
+    ie.episode_number,
+    ie.label AS episode_label,
+    h.reason,
+    h.note,
+    h.recorded_by_discord_user_id,
+    h.recorded_at
+FROM outcome_position_history h
+JOIN instances i ON i.id = h.instance_id
+LEFT JOIN contestants c ON c.id = h.contestant_id
+LEFT JOIN contestants pc ON pc.id = h.previous_contestant_id
+LEFT JOIN instance_episodes ie ON ie.id = h.episode_id
+WHERE i.public_id = sqlc.arg(instance_id)
+ORDER BY h.recorded_at ASC, h.id ASC;
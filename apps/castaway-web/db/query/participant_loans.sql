@@ -86,6 +86,33 @@ WHERE i.public_id = sqlc.arg(instance_id)
   AND p.public_id = sqlc.arg(participant_id)
   AND pl.status = 'active';
 
+-- name: GetLatestDefaultedParticipantLoanByParticipant :one
+SELECT
+    pl.public_id AS id,
+    i.public_id AS instance_id,
+    p.public_id AS participant_id,
+    ia.public_id AS activity_id,
+    pl.status,
+    pl.principal_points,
+    pl.interest_points,
+    pl.principal_repaid_points,
+    pl.interest_repaid_points,
+    pl.granted_at,
+    pl.due_at,
+    pl.settled_at,
+    pl.metadata,
+    pl.created_at,
+    pl.updated_at
+FROM participant_loans pl
+JOIN instances i ON i.id = pl.instance_id
+JOIN participants p ON p.id = pl.participant_id
+LEFT JOIN instance_activities ia ON ia.id = pl.activity_id
+WHERE i.public_id = sqlc.arg(instance_id)
+  AND p.public_id = sqlc.arg(participant_id)
+  AND pl.status = 'defaulted'
+ORDER BY pl.settled_at DESC, pl.id DESC
+LIMIT 1;
+
 -- name: UpdateParticipantLoan :one
 UPDATE participant_loans pl
 SET status = sqlc.arg(status),
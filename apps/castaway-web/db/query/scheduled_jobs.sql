@@ -0,0 +1,105 @@
+-- name: PlanEpisodeJobs :execrows
+INSERT INTO scheduled_jobs (instance_id, episode_id, job_type, run_at)
+SELECT e.instance_id, e.id, sqlc.arg(job_type)::text, e.airs_at
+FROM instance_episodes e
+JOIN instances i ON i.id = e.instance_id
+WHERE i.state = 'active'
+  AND e.airs_at > sqlc.arg(planned_after)
+ON CONFLICT (instance_id, episode_id, job_type) DO UPDATE
+SET run_at = EXCLUDED.run_at,
+    updated_at = NOW()
+WHERE scheduled_jobs.status = 'pending'
+  AND scheduled_jobs.run_at <> EXCLUDED.run_at;
+
+-- name: ClaimDueScheduledJob :one
+SELECT
+    j.public_id AS id,
+    i.public_id AS instance_id,
+    i.state AS instance_state,
+    e.public_id AS episode_id,
+    e.episode_number,
+    e.airs_at AS episode_airs_at,
+    j.job_type,
+    j.run_at,
+    j.attempts
+FROM scheduled_jobs j
+JOIN instances i ON i.id = j.instance_id
+JOIN instance_episodes e ON e.id = j.episode_id
+WHERE j.status = 'pending'
+  AND j.run_at <= sqlc.arg(now)
+ORDER BY j.run_at ASC, j.id ASC
+LIMIT 1
+FOR UPDATE OF j SKIP LOCKED;
+
+-- name: CompleteScheduledJob :exec
+UPDATE scheduled_jobs
+SET status = sqlc.arg(status),
+    attempts = attempts + 1,
+    last_error = NULL,
+    completed_at = sqlc.arg(completed_at),
+    updated_at = NOW()
+WHERE public_id = sqlc.arg(id);
+
+-- name: FailScheduledJob :exec
+UPDATE scheduled_jobs
+SET attempts = attempts + 1,
+    last_error = sqlc.arg(last_error),
+    status = CASE WHEN attempts + 1 >= sqlc.arg(max_attempts)::int THEN 'failed' ELSE 'pending' END,
+    run_at = sqlc.arg(retry_at),
+    updated_at = NOW()
+WHERE public_id = sqlc.arg(id);
+
+-- name: RetryScheduledJob :one
+UPDATE scheduled_jobs j
+SET status = 'pending',
+    attempts = 0,
+    last_error = NULL,
+    completed_at = NULL,
+    run_at = sqlc.arg(run_at),
+    updated_at = NOW()
+FROM instances i
+WHERE j.instance_id = i.id
+  AND i.public_id = sqlc.arg(instance_id)
+  AND j.public_id = sqlc.arg(id)
+  AND j.status IN ('failed', 'skipped')
+RETURNING j.public_id AS id, j.status, j.run_at;
+
+-- name: CreateScheduledJobRun :exec
+INSERT INTO scheduled_job_runs (scheduled_job_id, status, started_at, finished_at, result, error)
+SELECT j.id, sqlc.arg(status)::text, sqlc.arg(started_at)::timestamptz, sqlc.arg(finished_at)::timestamptz, sqlc.arg(result)::jsonb, sqlc.narg(error)::text
+FROM scheduled_jobs j
+WHERE j.public_id = sqlc.arg(scheduled_job_id);
+
+-- name: ListScheduledJobsByInstance :many
+SELECT
+    j.public_id AS id,
+    e.public_id AS episode_id,
+    e.episode_number,
+    j.job_type,
+    j.run_at,
+    j.status,
+    j.attempts,
+    j.last_error,
+    j.completed_at,
+    j.created_at,
+    j.updated_at
+FROM scheduled_jobs j
+JOIN instances i ON i.id = j.instance_id
+JOIN instance_episodes e ON e.id = j.episode_id
+WHERE i.public_id = sqlc.arg(instance_id)
+ORDER BY j.run_at ASC, j.job_type ASC;
+
+-- name: ListScheduledJobRuns :many
+SELECT
+    r.public_id AS id,
+    r.status,
+    r.started_at,
+    r.finished_at,
+    r.result,
+    r.error
+FROM scheduled_job_runs r
+JOIN scheduled_jobs j ON j.id = r.scheduled_job_id
+JOIN instances i ON i.id = j.instance_id
+WHERE i.public_id = sqlc.arg(instance_id)
+  AND j.public_id = sqlc.arg(scheduled_job_id)
+ORDER BY r.started_at ASC, r.id ASC;
@@ -0,0 +1,169 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (instance_id, url, secret, event_types)
+SELECT i.id, sqlc.arg(url), sqlc.arg(secret), sqlc.arg(event_types)::text[]
+FROM instances i
+WHERE i.public_id = sqlc.arg(instance_id)
+RETURNING public_id AS id, url, event_types, created_at;
+
+-- name: ListWebhookSubscriptionsByInstance :many
+SELECT
+    s.public_id AS id,
+    s.url,
+    s.event_types,
+    s.created_at
+FROM webhook_subscriptions s
+JOIN instances i ON i.id = s.instance_id
+WHERE i.public_id = sqlc.arg(instance_id)
+ORDER BY s.created_at ASC, s.id ASC;
+
+-- name: DeleteWebhookSubscription :one
+DELETE FROM webhook_subscriptions s
+USING instances i
+WHERE s.instance_id = i.id
+  AND i.public_id = sqlc.arg(instance_id)
+  AND s.public_id = sqlc.arg(id)
+RETURNING s.public_id AS id, s.url, s.event_types, s.created_at;
+
+-- name: CreateOutboxEvent :exec
+WITH event AS (
+    INSERT INTO outbox_events (instance_id, event_type, payload)
+    SELECT i.id, sqlc.arg(event_type)::text, sqlc.arg(payload)::jsonb
+    FROM instances i
+    WHERE i.public_id = sqlc.arg(instance_id)
+    RETURNING id, instance_id, event_type
+)
+INSERT INTO webhook_deliveries (outbox_event_id, subscription_id)
+SELECT event.id, s.id
+FROM event
+JOIN webhook_subscriptions s ON s.instance_id = event.instance_id
+WHERE cardinality(s.event_types) = 0
+   OR event.event_type = ANY(s.event_types);
+
+-- name: ListOutboxEventsByInstance :many
+-- Events come back oldest first. after_event_id resumes from an event a
+-- reader has already seen; since bounds a reader with no cursor yet.
+SELECT
+    e.public_id AS id,
+    e.event_type,
+    e.payload,
+    e.occurred_at
+FROM outbox_events e
+JOIN instances i ON i.id = e.instance_id
+WHERE i.public_id = sqlc.arg(instance_id)
+  AND (cardinality(sqlc.arg(event_types)::text[]) = 0 OR e.event_type = ANY(sqlc.arg(event_types)::text[]))
+  AND e.id > COALESCE((SELECT prior.id FROM outbox_events prior WHERE prior.public_id = sqlc.narg(after_event_id)), 0)
+  AND (sqlc.narg(since)::timestamptz IS NULL OR e.occurred_at > sqlc.narg(since)::timestamptz)
+ORDER BY e.id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ClaimDueWebhookDelivery :one
+SELECT
+    d.public_id AS id,
+    e.public_id AS event_id,
+    i.public_id AS instance_id,
+    e.event_type,
+    e.payload,
+    e.occurred_at,
+    s.url,
+    s.secret,
+    d.attempts
+FROM webhook_deliveries d
+JOIN outbox_events e ON e.id = d.outbox_event_id
+JOIN webhook_subscriptions s ON s.id = d.subscription_id
+JOIN instances i ON i.id = e.instance_id
+WHERE d.status = 'pending'
+  AND d.next_attempt_at <= sqlc.arg(now)
+ORDER BY d.next_attempt_at ASC, d.id ASC
+LIMIT 1
+FOR UPDATE OF d SKIP LOCKED;
+
+-- name: LeaseWebhookDelivery :exec
+-- Pushes a claimed delivery's next attempt past the time its request can
+-- take, so other dispatchers leave it alone once the claim commits.
+UPDATE webhook_deliveries
+SET next_attempt_at = sqlc.arg(lease_until),
+    updated_at = NOW()
+WHERE public_id = sqlc.arg(id)
+  AND status = 'pending';
+
+-- name: CompleteWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = 'delivered',
+    attempts = attempts + 1,
+    last_status_code = sqlc.arg(last_status_code),
+    last_error = NULL,
+    delivered_at = sqlc.arg(delivered_at),
+    updated_at = NOW()
+WHERE public_id = sqlc.arg(id);
+
+-- name: FailWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET attempts = attempts + 1,
+    last_status_code = sqlc.narg(last_status_code),
+    last_error = sqlc.arg(last_error),
+    status = CASE WHEN attempts + 1 >= sqlc.arg(max_attempts)::int THEN 'dead' ELSE 'pending' END,
+    next_attempt_at = sqlc.arg(retry_at),
+    updated_at = NOW()
+WHERE public_id = sqlc.arg(id);
+
+-- name: GetWebhookDelivery :one
+SELECT
+    d.public_id AS id,
+    s.public_id AS subscription_id,
+    s.url,
+    e.public_id AS event_id,
+    e.event_type,
+    e.payload,
+    d.status,
+    d.attempts,
+    d.next_attempt_at,
+    d.last_status_code,
+    d.last_error,
+    d.delivered_at,
+    d.created_at,
+    d.updated_at
+FROM webhook_deliveries d
+JOIN outbox_events e ON e.id = d.outbox_event_id
+JOIN webhook_subscriptions s ON s.id = d.subscription_id
+JOIN instances i ON i.id = s.instance_id
+WHERE i.public_id = sqlc.arg(instance_id)
+  AND d.public_id = sqlc.arg(id);
+
+-- name: ListWebhookDeliveriesByInstance :many
+SELECT
+    d.public_id AS id,
+    s.public_id AS subscription_id,
+    s.url,
+    e.public_id AS event_id,
+    e.event_type,
+    e.payload,
+    d.status,
+    d.attempts,
+    d.next_attempt_at,
+    d.last_status_code,
+    d.last_error,
+    d.delivered_at,
+    d.created_at,
+    d.updated_at
+FROM webhook_deliveries d
+JOIN outbox_events e ON e.id = d.outbox_event_id
+JOIN webhook_subscriptions s ON s.id = d.subscription_id
+JOIN instances i ON i.id = s.instance_id
+WHERE i.public_id = sqlc.arg(instance_id)
+  AND (sqlc.narg(status)::text IS NULL OR d.status = sqlc.narg(status)::text)
+ORDER BY d.created_at DESC, d.id DESC
+LIMIT 200;
+
+-- name: RetryWebhookDelivery :execrows
+UPDATE webhook_deliveries d
+SET status = 'pending',
+    attempts = 0,
+    last_error = NULL,
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    updated_at = NOW()
+FROM webhook_subscriptions s, instances i
+WHERE d.subscription_id = s.id
+  AND s.instance_id = i.id
+  AND i.public_id = sqlc.arg(instance_id)
+  AND d.public_id = sqlc.arg(id)
+  AND d.status = 'dead';
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/conv"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/seeddata"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Bootstrap statuses report what applying a season config did.
+const (
+	BootstrapCreated   = "created"
+	BootstrapReplaced  = "replaced"
+	BootstrapUnchanged = "unchanged"
+)
+
+// ErrBootstrapConflict is returned when a config would replace an instance
+// that has already started play.
+var ErrBootstrapConflict = errors.New("bootstrap conflict")
+
+type BootstrapResult struct {
+	Status     string
+	InstanceID pgtype.UUID
+	Checksum   string
+	Seed       SeedResult
+}
+
+// BootstrapSeason creates a full instance from a season config in one
+// transaction. Re-applying a config whose checksum matches the existing
+// instance changes nothing. A changed config replaces the instance's contents
+// in place, keeping its ID, and only while it is still in setup or drafting.
+func BootstrapSeason(ctx context.Context, pool *pgxpool.Pool, season seeddata.SeasonSeed) (BootstrapResult, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return BootstrapResult{}, fmt.Errorf("begin bootstrap tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	result, err := BootstrapSeasonTx(ctx, tx, season)
+	if err != nil {
+		return BootstrapResult{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return BootstrapResult{}, fmt.Errorf("commit bootstrap: %w", err)
+	}
+	return result, nil
+}
+
+// BootstrapSeasonTx is BootstrapSeason inside a caller-owned transaction.
+func BootstrapSeasonTx(ctx context.Context, tx pgx.Tx, season seeddata.SeasonSeed) (BootstrapResult, error) {
+	if strings.TrimSpace(season.State) == "" {
+		season.State = "setup"
+	}
+	checksum, err := seeddata.Checksum(season)
+	if err != nil {
+		return BootstrapResult{}, err
+	}
+	seasonNumber, err := conv.ToInt32(season.Season)
+	if err != nil {
+		return BootstrapResult{}, fmt.Errorf("convert season number %d: %w", season.Season, err)
+	}
+
+	q := db.New(tx)
+	status := BootstrapCreated
+	existing, err := q.GetInstanceConfigByNameSeason(ctx, db.GetInstanceConfigByNameSeasonParams{
+		Name:   season.InstanceName,
+		Season: seasonNumber,
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return BootstrapResult{}, fmt.Errorf("find existing instance: %w", err)
+	case existing.ConfigChecksum.Valid && existing.ConfigChecksum.String == checksum:
+		return BootstrapResult{Status: BootstrapUnchanged, InstanceID: existing.ID, Checksum: checksum}, nil
+	case existing.State != "setup" && existing.State != "drafting":
+		return BootstrapResult{}, fmt.Errorf("%w: %s season %d is %s; only setup or drafting instances can be replaced", ErrBootstrapConflict, season.InstanceName, season.Season, existing.State)
+	default:
+		status = BootstrapReplaced
+	}
+
+	result := BootstrapResult{Status: status, Checksum: checksum}
+	if status == BootstrapReplaced {
+		if err := resetInstanceTx(ctx, q, existing.ID, season.State); err != nil {
+			return BootstrapResult{}, err
+		}
+		result.InstanceID = existing.ID
+	} else {
+		instance, err := q.CreateInstance(ctx, db.CreateInstanceParams{
+			Name:   season.InstanceName,
+			Season: seasonNumber,
+			State:  pgtype.Text{String: season.State, Valid: true},
+		})
+		if err != nil {
+			return BootstrapResult{}, fmt.Errorf("create instance for season %d: %w", season.Season, err)
+		}
+		result.InstanceID = instance.ID
+	}
+	if err := seedSeasonContents(ctx, q, season, result.InstanceID, &result.Seed); err != nil {
+		return BootstrapResult{}, err
+	}
+	if err := q.SetInstanceConfigChecksum(ctx, db.SetInstanceConfigChecksumParams{
+		ConfigChecksum: pgtype.Text{String: checksum, Valid: true},
+		ID:             result.InstanceID,
+	}); err != nil {
+		return BootstrapResult{}, fmt.Errorf("record config checksum: %w", err)
+	}
+	return result, nil
+}
+
+// resetInstanceTx empties an instance of everything a season config defines
+// while keeping the instance row itself, so its public ID, webhook
+// subscriptions, outbox and audit history survive a replacement. Scheduled
+// jobs go with the episodes they were planned for; the scheduler plans them
+// again for the new schedule.
+func resetInstanceTx(ctx context.Context, q *db.Queries, instanceID pgtype.UUID, state string) error {
+	for _, step := range []struct {
+		what string
+		run  func(context.Context, pgtype.UUID) error
+	}{
+		{"participants", q.DeleteInstanceParticipants},
+		{"participant groups", q.DeleteInstanceParticipantGroups},
+		{"activities", q.DeleteInstanceActivities},
+		{"outcomes", q.DeleteInstanceOutcomes},
+		{"outcome history", q.DeleteInstanceOutcomeHistory},
+		{"contestants", q.DeleteInstanceContestants},
+		{"episodes", q.DeleteInstanceEpisodes},
+		{"admins", q.DeleteInstanceAdmins},
+	} {
+		if err := step.run(ctx, instanceID); err != nil {
+			return fmt.Errorf("clear instance %s: %w", step.what, err)
+		}
+	}
+	if err := q.ResetInstance(ctx, db.ResetInstanceParams{State: state, ID: instanceID}); err != nil {
+		return fmt.Errorf("reset instance: %w", err)
+	}
+	return nil
+}
+
+func BootstrapSummary(result BootstrapResult) string {
+	return "bootstrap " + result.Status +
+		" instance=" + uuid.UUID(result.InstanceID.Bytes).String() +
+		" checksum=" + result.Checksum
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -31,7 +32,7 @@ func SeedHistorical(ctx context.Context, pool *pgxpool.Pool, seasons []seeddata.
 		if err != nil {
 			return result, fmt.Errorf("begin tx for season %d: %w", seasonSeed.Season, err)
 		}
-		if err := seedSeasonTx(ctx, tx, seasonSeed, &result); err != nil {
+		if _, err := seedSeasonTx(ctx, tx, seasonSeed, &result); err != nil {
 			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && rollbackErr != pgx.ErrTxClosed {
 				return result, fmt.Errorf("rollback season %d: %w", seasonSeed.Season, rollbackErr)
 			}
@@ -45,32 +46,63 @@ func SeedHistorical(ctx context.Context, pool *pgxpool.Pool, seasons []seeddata.
 	return result, nil
 }
 
-func seedSeasonTx(ctx context.Context, tx pgx.Tx, season seeddata.SeasonSeed, result *SeedResult) error {
+func seedSeasonTx(ctx context.Context, tx pgx.Tx, season seeddata.SeasonSeed, result *SeedResult) (db.CreateInstanceRow, error) {
 	q := db.New(tx)
 	seasonNumber, err := conv.ToInt32(season.Season)
 	if err != nil {
-		return fmt.Errorf("convert season number %d: %w", season.Season, err)
+		return db.CreateInstanceRow{}, fmt.Errorf("convert season number %d: %w", season.Season, err)
 	}
 
 	if err := q.DeleteInstanceByNameSeason(ctx, db.DeleteInstanceByNameSeasonParams{
 		Name:   season.InstanceName,
 		Season: seasonNumber,
 	}); err != nil {
-		return fmt.Errorf("delete existing instance for season %d: %w", season.Season, err)
+		return db.CreateInstanceRow{}, fmt.Errorf("delete existing instance for season %d: %w", season.Season, err)
 	}
 
+	state := strings.TrimSpace(season.State)
+	if state == "" {
+		state = "active"
+	}
 	instance, err := q.CreateInstance(ctx, db.CreateInstanceParams{
 		Name:   season.InstanceName,
 		Season: seasonNumber,
+		State:  pgtype.Text{String: state, Valid: true},
 	})
 	if err != nil {
-		return fmt.Errorf("create instance for season %d: %w", season.Season, err)
+		return db.CreateInstanceRow{}, fmt.Errorf("create instance for season %d: %w", season.Season, err)
+	}
+	if err := seedSeasonContents(ctx, q, season, instance.ID, result); err != nil {
+		return db.CreateInstanceRow{}, err
+	}
+	return instance, nil
+}
+
+// seedSeasonContents fills an empty instance with the season's episodes,
+// admins, roster, picks, outcomes, groups, activities and advantages.
+func seedSeasonContents(ctx context.Context, q *db.Queries, season seeddata.SeasonSeed, instanceID pgtype.UUID, result *SeedResult) error {
+	seasonNumber, err := conv.ToInt32(season.Season)
+	if err != nil {
+		return fmt.Errorf("convert season number %d: %w", season.Season, err)
 	}
 	gameplayService := gameplay.NewService(q)
-	if err := gameplayService.CopyInstanceSchedule(ctx, instance.ID, seasonNumber); err != nil {
+	if len(season.Episodes) > 0 {
+		if err := seedEpisodes(ctx, gameplayService, season, instanceID); err != nil {
+			return fmt.Errorf("seed episodes for season %d: %w", season.Season, err)
+		}
+	} else if err := gameplayService.CopyInstanceSchedule(ctx, instanceID, seasonNumber); err != nil {
 		return fmt.Errorf("copy episode schedule for season %d: %w", season.Season, err)
 	}
 
+	for _, admin := range season.Admins {
+		if _, err := q.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{
+			DiscordUserID: strings.TrimSpace(admin),
+			InstanceID:    instanceID,
+		}); err != nil {
+			return fmt.Errorf("create instance admin %q for season %d: %w", admin, season.Season, err)
+		}
+	}
+
 	contestantIDByName := make(map[string]pgtype.UUID, len(season.Contestants))
 	participantIDByName := make(map[string]pgtype.UUID, len(season.Participants))
 	for _, name := range season.Contestants {
@@ -79,7 +111,7 @@ func seedSeasonTx(ctx context.Context, tx pgx.Tx, season seeddata.SeasonSeed, re
 			continue
 		}
 		contestant, err := q.CreateContestant(ctx, db.CreateContestantParams{
-			InstanceID: instance.ID,
+			InstanceID: instanceID,
 			Name:       trimmed,
 		})
 		if err != nil {
@@ -91,7 +123,7 @@ func seedSeasonTx(ctx context.Context, tx pgx.Tx, season seeddata.SeasonSeed, re
 	for _, participantSeed := range season.Participants {
 		participantName := strings.TrimSpace(participantSeed.Name)
 		participant, err := q.CreateParticipant(ctx, db.CreateParticipantParams{
-			InstanceID: instance.ID,
+			InstanceID: instanceID,
 			Name:       participantName,
 		})
 		if err != nil {
@@ -99,13 +131,21 @@ func seedSeasonTx(ctx context.Context, tx pgx.Tx, season seeddata.SeasonSeed, re
 		}
 		participantIDByName[normalizeSeedName(participantName)] = participant.ID
 		result.Participants++
+		if discordUserID := strings.TrimSpace(participantSeed.DiscordUserID); discordUserID != "" {
+			if _, err := q.SetParticipantDiscordUserID(ctx, db.SetParticipantDiscordUserIDParams{
+				DiscordUserID: pgtype.Text{String: discordUserID, Valid: true},
+				ID:            participant.ID,
+			}); err != nil {
+				return fmt.Errorf("link participant %q for season %d: %w", participantSeed.Name, season.Season, err)
+			}
+		}
 
 		for index, contestantName := range participantSeed.Picks {
 			trimmed := strings.TrimSpace(contestantName)
 			contestantID, ok := contestantIDByName[strings.ToLower(trimmed)]
 			if !ok && trimmed != "" {
 				contestant, createErr := q.CreateContestant(ctx, db.CreateContestantParams{
-					InstanceID: instance.ID,
+					InstanceID: instanceID,
 					Name:       trimmed,
 				})
 				if createErr != nil {
@@ -124,7 +164,7 @@ func seedSeasonTx(ctx context.Context, tx pgx.Tx, season seeddata.SeasonSeed, re
 			}
 
 			if _, err := q.CreateDraftPick(ctx, db.CreateDraftPickParams{
-				InstanceID:    instance.ID,
+				InstanceID:    instanceID,
 				ParticipantID: participant.ID,
 				ContestantID:  contestantID,
 				Position:      position,
@@ -147,7 +187,7 @@ func seedSeasonTx(ctx context.Context, tx pgx.Tx, season seeddata.SeasonSeed, re
 			contestantID, ok := contestantIDByName[strings.ToLower(trimmed)]
 			if !ok {
 				contestant, createErr := q.CreateContestant(ctx, db.CreateContestantParams{
-					InstanceID: instance.ID,
+					InstanceID: instanceID,
 					Name:       trimmed,
 				})
 				if createErr != nil {
@@ -160,7 +200,7 @@ func seedSeasonTx(ctx context.Context, tx pgx.Tx, season seeddata.SeasonSeed, re
 		}
 
 		if _, err := q.UpsertOutcomePosition(ctx, db.UpsertOutcomePositionParams{
-			InstanceID:   instance.ID,
+			InstanceID:   instanceID,
 			Position:     position,
 			ContestantID: contestantParam,
 		}); err != nil {
@@ -169,16 +209,16 @@ func seedSeasonTx(ctx context.Context, tx pgx.Tx, season seeddata.SeasonSeed, re
 		result.Outcomes++
 	}
 
-	participantGroupIDByName, err := seedParticipantGroups(ctx, q, gameplayService, season, instance.ID, participantIDByName)
+	participantGroupIDByName, err := seedParticipantGroups(ctx, q, gameplayService, season, instanceID, participantIDByName)
 	if err != nil {
 		return fmt.Errorf("seed participant groups for season %d: %w", season.Season, err)
 	}
 
-	if err := seedActivityHistory(ctx, q, gameplayService, season, instance.ID, participantIDByName, participantGroupIDByName); err != nil {
+	if err := seedActivityHistory(ctx, q, gameplayService, season, instanceID, participantIDByName, participantGroupIDByName); err != nil {
 		return fmt.Errorf("seed activities for season %d: %w", season.Season, err)
 	}
 
-	if err := seedAdvantages(ctx, q, season, instance.ID, participantIDByName); err != nil {
+	if err := seedAdvantages(ctx, q, season, instanceID, participantIDByName); err != nil {
 		return fmt.Errorf("seed advantages for season %d: %w", season.Season, err)
 	}
 
@@ -186,6 +226,31 @@ func seedSeasonTx(ctx context.Context, tx pgx.Tx, season seeddata.SeasonSeed, re
 	return nil
 }
 
+func seedEpisodes(ctx context.Context, gameplayService *gameplay.Service, season seeddata.SeasonSeed, instanceID pgtype.UUID) error {
+	for _, episodeSeed := range season.Episodes {
+		episodeNumber, err := conv.ToInt32(episodeSeed.EpisodeNumber)
+		if err != nil {
+			return fmt.Errorf("episode number %d: %w", episodeSeed.EpisodeNumber, err)
+		}
+		label := strings.TrimSpace(episodeSeed.Label)
+		if label == "" {
+			label = "Episode " + strconv.Itoa(episodeSeed.EpisodeNumber)
+			if episodeNumber == 0 {
+				label = "Preseason"
+			}
+		}
+		if _, err := gameplayService.CreateEpisode(ctx, gameplay.CreateEpisodeParams{
+			InstanceID:    instanceID,
+			EpisodeNumber: episodeNumber,
+			Label:         label,
+			AirsAt:        episodeSeed.AirsAt,
+		}); err != nil {
+			return fmt.Errorf("create episode %d: %w", episodeSeed.EpisodeNumber, err)
+		}
+	}
+	return nil
+}
+
 func seedParticipantGroups(
 	ctx context.Context,
 	q *db.Queries,
@@ -258,6 +323,11 @@ func seedActivityHistory(
 			status = "active"
 		}
 
+		metadata, err := activitySeedMetadata(activitySeed)
+		if err != nil {
+			return fmt.Errorf("activity %q metadata: %w", activitySeed.Name, err)
+		}
+
 		activity, err := q.CreateInstanceActivity(ctx, db.CreateInstanceActivityParams{
 			InstanceID:   instanceID,
 			ActivityType: strings.TrimSpace(activitySeed.ActivityType),
@@ -265,7 +335,7 @@ func seedActivityHistory(
 			Status:       status,
 			StartsAt:     timestamptz(activitySeed.StartsAt),
 			EndsAt:       optionalTimestamptz(activitySeed.EndsAt),
-			Metadata:     jsonBytesOrEmpty(activitySeed.Metadata),
+			Metadata:     metadata,
 		})
 		if err != nil {
 			return fmt.Errorf("create activity %q: %w", activitySeed.Name, err)
@@ -415,6 +485,10 @@ func seedAdvantages(
 		if status == "" {
 			status = "active"
 		}
+		visibility := strings.TrimSpace(advantageSeed.Visibility)
+		if visibility == "" {
+			visibility = "secret"
+		}
 
 		groupID := pgtype.UUID{}
 		if advantageSeed.GroupName != "" {
@@ -445,6 +519,7 @@ func seedAdvantages(
 			EffectiveAt:                timestamptz(advantageSeed.EffectiveAt),
 			EffectiveUntil:             optionalTimestamptz(advantageSeed.EffectiveUntil),
 			Metadata:                   jsonBytesOrEmpty(advantageSeed.Metadata),
+			Visibility:                 visibility,
 		}); err != nil {
 			return fmt.Errorf("create advantage %q for participant %q: %w", advantageSeed.Name, advantageSeed.ParticipantName, err)
 		}
@@ -453,6 +528,22 @@ func seedAdvantages(
 	return nil
 }
 
+// activitySeedMetadata folds reward tiers into the activity metadata, where
+// resolvers read them.
+func activitySeedMetadata(activitySeed seeddata.ActivitySeed) ([]byte, error) {
+	if len(activitySeed.RewardTiers) == 0 {
+		return jsonBytesOrEmpty(activitySeed.Metadata), nil
+	}
+	metadata := map[string]any{}
+	if len(activitySeed.Metadata) > 0 {
+		if err := json.Unmarshal(activitySeed.Metadata, &metadata); err != nil {
+			return nil, err
+		}
+	}
+	metadata["reward_tiers"] = activitySeed.RewardTiers
+	return json.Marshal(metadata)
+}
+
 func normalizeSeedName(value string) string {
 	return strings.ToLower(strings.TrimSpace(value))
 }
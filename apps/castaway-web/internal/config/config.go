@@ -3,8 +3,10 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -15,8 +17,20 @@ type Config struct {
 	ServiceAuthEnabled      bool
 	ServiceAuthBearerTokens []string
 	ServiceAuthPrincipal    string
+	ServiceAuthScopes       []string
+	SchedulerEnabled        bool
+	SchedulerInterval       time.Duration
+	SchedulerLookback       time.Duration
+	WebhooksEnabled         bool
+	WebhookInterval         time.Duration
+	WebhookPrivateTargets   bool
+	EventStreamInterval     time.Duration
 }
 
+// serviceAuthScopes are the scopes SERVICE_AUTH_SCOPES may grant the shared
+// bearer tokens, matching the API credential scopes.
+var serviceAuthScopes = []string{"read-only", "player-actions", "instance-admin", "super-admin"}
+
 func Load() (*Config, error) {
 	cfg := &Config{
 		Port:                 getEnv("PORT", "8080"),
@@ -37,13 +51,63 @@ func Load() (*Config, error) {
 	}
 	cfg.ServiceAuthEnabled = serviceAuthEnabled
 	cfg.ServiceAuthBearerTokens = parseCSV(getEnv("SERVICE_AUTH_BEARER_TOKENS", ""))
+	cfg.ServiceAuthScopes = parseCSV(getEnv("SERVICE_AUTH_SCOPES", "super-admin"))
+	for _, scope := range cfg.ServiceAuthScopes {
+		if !slices.Contains(serviceAuthScopes, scope) {
+			return nil, fmt.Errorf("SERVICE_AUTH_SCOPES: unsupported scope %q", scope)
+		}
+	}
+
+	schedulerEnabled, err := strconv.ParseBool(getEnv("SCHEDULER_ENABLED", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("parse SCHEDULER_ENABLED: %w", err)
+	}
+	cfg.SchedulerEnabled = schedulerEnabled
+	cfg.SchedulerInterval, err = time.ParseDuration(getEnv("SCHEDULER_INTERVAL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("parse SCHEDULER_INTERVAL: %w", err)
+	}
+	cfg.SchedulerLookback, err = time.ParseDuration(getEnv("SCHEDULER_LOOKBACK", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("parse SCHEDULER_LOOKBACK: %w", err)
+	}
+
+	webhooksEnabled, err := strconv.ParseBool(getEnv("WEBHOOKS_ENABLED", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("parse WEBHOOKS_ENABLED: %w", err)
+	}
+	cfg.WebhooksEnabled = webhooksEnabled
+	cfg.WebhookInterval, err = time.ParseDuration(getEnv("WEBHOOK_INTERVAL", "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("parse WEBHOOK_INTERVAL: %w", err)
+	}
+	cfg.WebhookPrivateTargets, err = strconv.ParseBool(getEnv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("parse WEBHOOK_ALLOW_PRIVATE_TARGETS: %w", err)
+	}
+	cfg.EventStreamInterval, err = time.ParseDuration(getEnv("EVENT_STREAM_INTERVAL", "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("parse EVENT_STREAM_INTERVAL: %w", err)
+	}
 
 	if cfg.DatabaseURL == "" {
 		return nil, fmt.Errorf("DATABASE_URL is required")
 	}
+	if len(cfg.ServiceAuthScopes) == 0 {
+		return nil, fmt.Errorf("SERVICE_AUTH_SCOPES must name at least one scope")
+	}
 	if cfg.ServiceAuthEnabled && len(cfg.ServiceAuthBearerTokens) == 0 {
 		return nil, fmt.Errorf("SERVICE_AUTH_BEARER_TOKENS is required when SERVICE_AUTH_ENABLED=true")
 	}
+	if cfg.SchedulerInterval <= 0 {
+		return nil, fmt.Errorf("SCHEDULER_INTERVAL must be positive")
+	}
+	if cfg.WebhookInterval <= 0 {
+		return nil, fmt.Errorf("WEBHOOK_INTERVAL must be positive")
+	}
+	if cfg.EventStreamInterval <= 0 {
+		return nil, fmt.Errorf("EVENT_STREAM_INTERVAL must be positive")
+	}
 	if cfg.ServiceAuthPrincipal == "" {
 		return nil, fmt.Errorf("SERVICE_AUTH_PRINCIPAL is required when service auth is configured")
 	}
@@ -3,6 +3,7 @@ package config
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestLoadServiceAuthDefaults(t *testing.T) {
@@ -20,6 +21,29 @@ func TestLoadServiceAuthDefaults(t *testing.T) {
 	if cfg.ServiceAuthPrincipal != "castaway-discord-bot" {
 		t.Fatalf("service auth principal = %q, want %q", cfg.ServiceAuthPrincipal, "castaway-discord-bot")
 	}
+	if !reflect.DeepEqual(cfg.ServiceAuthScopes, []string{"super-admin"}) {
+		t.Fatalf("service auth scopes = %v, want [super-admin]", cfg.ServiceAuthScopes)
+	}
+}
+
+func TestLoadServiceAuthScopes(t *testing.T) {
+	t.Setenv("SERVICE_AUTH_SCOPES", " instance-admin, player-actions ")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if want := []string{"instance-admin", "player-actions"}; !reflect.DeepEqual(cfg.ServiceAuthScopes, want) {
+		t.Fatalf("service auth scopes = %v, want %v", cfg.ServiceAuthScopes, want)
+	}
+
+	t.Setenv("SERVICE_AUTH_SCOPES", "owner")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected an unsupported scope to be rejected")
+	}
+	t.Setenv("SERVICE_AUTH_SCOPES", " , ")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected an empty scope list to be rejected")
+	}
 }
 
 func TestLoadRequiresBearerTokensWhenServiceAuthEnabled(t *testing.T) {
@@ -47,3 +71,78 @@ func TestLoadParsesBearerTokens(t *testing.T) {
 		t.Fatalf("service auth principal = %q, want %q", cfg.ServiceAuthPrincipal, "castaway-discord-bot")
 	}
 }
+
+func TestLoadSchedulerSettings(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if !cfg.SchedulerEnabled || cfg.SchedulerInterval != time.Minute || cfg.SchedulerLookback != 7*24*time.Hour {
+		t.Fatalf("unexpected scheduler defaults: %v %s %s", cfg.SchedulerEnabled, cfg.SchedulerInterval, cfg.SchedulerLookback)
+	}
+
+	t.Setenv("SCHEDULER_ENABLED", "false")
+	t.Setenv("SCHEDULER_INTERVAL", "15s")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.SchedulerEnabled || cfg.SchedulerInterval != 15*time.Second {
+		t.Fatalf("unexpected scheduler settings: %v %s", cfg.SchedulerEnabled, cfg.SchedulerInterval)
+	}
+
+	t.Setenv("SCHEDULER_INTERVAL", "0s")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for non-positive scheduler interval")
+	}
+}
+
+func TestLoadWebhookSettings(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if !cfg.WebhooksEnabled || cfg.WebhookInterval != 10*time.Second || cfg.WebhookPrivateTargets {
+		t.Fatalf("unexpected webhook defaults: %v %s %v", cfg.WebhooksEnabled, cfg.WebhookInterval, cfg.WebhookPrivateTargets)
+	}
+
+	t.Setenv("WEBHOOKS_ENABLED", "false")
+	t.Setenv("WEBHOOK_INTERVAL", "1m")
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "true")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.WebhooksEnabled || cfg.WebhookInterval != time.Minute || !cfg.WebhookPrivateTargets {
+		t.Fatalf("unexpected webhook settings: %v %s %v", cfg.WebhooksEnabled, cfg.WebhookInterval, cfg.WebhookPrivateTargets)
+	}
+
+	t.Setenv("WEBHOOK_INTERVAL", "-1s")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for non-positive webhook interval")
+	}
+}
+
+func TestLoadEventStreamInterval(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.EventStreamInterval != 2*time.Second {
+		t.Fatalf("unexpected event stream interval default: %s", cfg.EventStreamInterval)
+	}
+
+	t.Setenv("EVENT_STREAM_INTERVAL", "500ms")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.EventStreamInterval != 500*time.Millisecond {
+		t.Fatalf("unexpected event stream interval: %s", cfg.EventStreamInterval)
+	}
+
+	t.Setenv("EVENT_STREAM_INTERVAL", "0s")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for non-positive event stream interval")
+	}
+}
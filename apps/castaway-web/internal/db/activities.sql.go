@@ -277,6 +277,27 @@ func (q *Queries) CreateInstanceActivity(ctx context.Context, arg CreateInstance
 	return i, err
 }
 
+const endActivityGroupAssignment = `-- name: EndActivityGroupAssignment :execrows
+UPDATE activity_group_assignments
+SET ends_at = $1
+WHERE id = $2
+  AND starts_at < $1
+  AND (ends_at IS NULL OR ends_at > $1)
+`
+
+type EndActivityGroupAssignmentParams struct {
+	EndsAt pgtype.Timestamptz `json:"ends_at"`
+	ID     int64              `json:"id"`
+}
+
+func (q *Queries) EndActivityGroupAssignment(ctx context.Context, arg EndActivityGroupAssignmentParams) (int64, error) {
+	result, err := q.db.Exec(ctx, endActivityGroupAssignment, arg.EndsAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getInstanceActivity = `-- name: GetInstanceActivity :one
 SELECT
     ia.public_id AS id,
@@ -392,6 +413,74 @@ func (q *Queries) ListActiveActivityGroupAssignmentsAt(ctx context.Context, arg
 	return items, nil
 }
 
+const listActiveActivityGroupAssignmentsByInstanceAt = `-- name: ListActiveActivityGroupAssignmentsByInstanceAt :many
+SELECT
+    aga.id,
+    ia.public_id AS activity_id,
+    ia.name AS activity_name,
+    ia.activity_type,
+    pg.public_id AS participant_group_id,
+    pg.name AS participant_group_name,
+    aga.role,
+    aga.starts_at,
+    aga.ends_at
+FROM activity_group_assignments aga
+JOIN instance_activities ia ON ia.id = aga.activity_id
+JOIN instances i ON i.id = ia.instance_id
+JOIN participant_groups pg ON pg.id = aga.participant_group_id
+WHERE i.public_id = $1
+  AND aga.starts_at <= $2
+  AND (aga.ends_at IS NULL OR aga.ends_at > $2)
+ORDER BY ia.name ASC, pg.name ASC, aga.id ASC
+`
+
+type ListActiveActivityGroupAssignmentsByInstanceAtParams struct {
+	InstanceID pgtype.UUID        `json:"instance_id"`
+	At         pgtype.Timestamptz `json:"at"`
+}
+
+type ListActiveActivityGroupAssignmentsByInstanceAtRow struct {
+	ID                   int64              `json:"id"`
+	ActivityID           pgtype.UUID        `json:"activity_id"`
+	ActivityName         string             `json:"activity_name"`
+	ActivityType         string             `json:"activity_type"`
+	ParticipantGroupID   pgtype.UUID        `json:"participant_group_id"`
+	ParticipantGroupName string             `json:"participant_group_name"`
+	Role                 string             `json:"role"`
+	StartsAt             pgtype.Timestamptz `json:"starts_at"`
+	EndsAt               pgtype.Timestamptz `json:"ends_at"`
+}
+
+func (q *Queries) ListActiveActivityGroupAssignmentsByInstanceAt(ctx context.Context, arg ListActiveActivityGroupAssignmentsByInstanceAtParams) ([]ListActiveActivityGroupAssignmentsByInstanceAtRow, error) {
+	rows, err := q.db.Query(ctx, listActiveActivityGroupAssignmentsByInstanceAt, arg.InstanceID, arg.At)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListActiveActivityGroupAssignmentsByInstanceAtRow{}
+	for rows.Next() {
+		var i ListActiveActivityGroupAssignmentsByInstanceAtRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActivityID,
+			&i.ActivityName,
+			&i.ActivityType,
+			&i.ParticipantGroupID,
+			&i.ParticipantGroupName,
+			&i.Role,
+			&i.StartsAt,
+			&i.EndsAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listActiveActivityParticipantAssignmentsAt = `-- name: ListActiveActivityParticipantAssignmentsAt :many
 SELECT
     apa.id,
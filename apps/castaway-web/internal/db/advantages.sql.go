@@ -15,23 +15,23 @@ const createParticipantAdvantage = `-- name: CreateParticipantAdvantage :one
 WITH resolved_instance AS (
     SELECT i.id AS instance_internal_id, i.public_id AS instance_id
     FROM instances i
-    WHERE i.public_id = $8
+    WHERE i.public_id = $9
 ), resolved_participant AS (
     SELECT p.id AS participant_internal_id, p.public_id AS participant_id
     FROM participants p
     JOIN resolved_instance ri ON ri.instance_internal_id = p.instance_id
-    WHERE p.public_id = $9
+    WHERE p.public_id = $10
 ), resolved_group AS (
     SELECT pg.id AS group_internal_id, pg.public_id AS participant_group_id
     FROM participant_groups pg
     JOIN resolved_instance ri ON ri.instance_internal_id = pg.instance_id
-    WHERE pg.public_id = $10
+    WHERE pg.public_id = $11
 ), resolved_occurrence AS (
     SELECT ao.id AS occurrence_internal_id, ao.public_id AS source_activity_occurrence_id
     FROM activity_occurrences ao
     JOIN instance_activities ia ON ia.id = ao.activity_id
     JOIN resolved_instance ri ON ri.instance_internal_id = ia.instance_id
-    WHERE ao.public_id = $11
+    WHERE ao.public_id = $12
 )
 INSERT INTO participant_advantages (
     instance_id,
@@ -44,7 +44,8 @@ INSERT INTO participant_advantages (
     granted_at,
     effective_at,
     effective_until,
-    metadata
+    metadata,
+    visibility
 )
 SELECT
     ri.instance_internal_id,
@@ -57,7 +58,8 @@ SELECT
     $4,
     $5,
     $6,
-    $7
+    $7,
+    $8
 FROM resolved_instance ri
 CROSS JOIN resolved_participant rp
 LEFT JOIN resolved_group rg ON TRUE
@@ -70,6 +72,7 @@ RETURNING
     advantage_type,
     name,
     status,
+    visibility,
     (SELECT source_activity_occurrence_id FROM resolved_occurrence) AS source_activity_occurrence_id,
     granted_at,
     effective_at,
@@ -87,6 +90,7 @@ type CreateParticipantAdvantageParams struct {
 	EffectiveAt                pgtype.Timestamptz `json:"effective_at"`
 	EffectiveUntil             pgtype.Timestamptz `json:"effective_until"`
 	Metadata                   []byte             `json:"metadata"`
+	Visibility                 string             `json:"visibility"`
 	InstanceID                 pgtype.UUID        `json:"instance_id"`
 	ParticipantID              pgtype.UUID        `json:"participant_id"`
 	ParticipantGroupID         pgtype.UUID        `json:"participant_group_id"`
@@ -101,6 +105,7 @@ type CreateParticipantAdvantageRow struct {
 	AdvantageType              string             `json:"advantage_type"`
 	Name                       string             `json:"name"`
 	Status                     string             `json:"status"`
+	Visibility                 string             `json:"visibility"`
 	SourceActivityOccurrenceID pgtype.UUID        `json:"source_activity_occurrence_id"`
 	GrantedAt                  pgtype.Timestamptz `json:"granted_at"`
 	EffectiveAt                pgtype.Timestamptz `json:"effective_at"`
@@ -119,6 +124,7 @@ func (q *Queries) CreateParticipantAdvantage(ctx context.Context, arg CreatePart
 		arg.EffectiveAt,
 		arg.EffectiveUntil,
 		arg.Metadata,
+		arg.Visibility,
 		arg.InstanceID,
 		arg.ParticipantID,
 		arg.ParticipantGroupID,
@@ -133,6 +139,7 @@ func (q *Queries) CreateParticipantAdvantage(ctx context.Context, arg CreatePart
 		&i.AdvantageType,
 		&i.Name,
 		&i.Status,
+		&i.Visibility,
 		&i.SourceActivityOccurrenceID,
 		&i.GrantedAt,
 		&i.EffectiveAt,
@@ -144,6 +151,105 @@ func (q *Queries) CreateParticipantAdvantage(ctx context.Context, arg CreatePart
 	return i, err
 }
 
+const expireParticipantAdvantages = `-- name: ExpireParticipantAdvantages :execrows
+UPDATE participant_advantages pa
+SET status = 'expired', updated_at = NOW()
+FROM instances i
+WHERE i.id = pa.instance_id
+  AND i.public_id = $1
+  AND pa.status = 'active'
+  AND pa.effective_until IS NOT NULL
+  AND pa.effective_until <= $2
+`
+
+type ExpireParticipantAdvantagesParams struct {
+	InstanceID pgtype.UUID        `json:"instance_id"`
+	At         pgtype.Timestamptz `json:"at"`
+}
+
+func (q *Queries) ExpireParticipantAdvantages(ctx context.Context, arg ExpireParticipantAdvantagesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, expireParticipantAdvantages, arg.InstanceID, arg.At)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getParticipantAdvantage = `-- name: GetParticipantAdvantage :one
+SELECT
+    pa.public_id AS id,
+    i.public_id AS instance_id,
+    p.public_id AS participant_id,
+    p.name AS participant_name,
+    pg.public_id AS participant_group_id,
+    pg.name AS participant_group_name,
+    pa.advantage_type,
+    pa.name,
+    pa.status,
+    pa.visibility,
+    ao.public_id AS source_activity_occurrence_id,
+    pa.granted_at,
+    pa.effective_at,
+    pa.effective_until,
+    pa.used_at,
+    pa.metadata,
+    pa.created_at,
+    pa.updated_at
+FROM participant_advantages pa
+JOIN instances i ON i.id = pa.instance_id
+JOIN participants p ON p.id = pa.participant_id
+LEFT JOIN participant_groups pg ON pg.id = pa.participant_group_id
+LEFT JOIN activity_occurrences ao ON ao.id = pa.source_activity_occurrence_id
+WHERE pa.public_id = $1
+`
+
+type GetParticipantAdvantageRow struct {
+	ID                         pgtype.UUID        `json:"id"`
+	InstanceID                 pgtype.UUID        `json:"instance_id"`
+	ParticipantID              pgtype.UUID        `json:"participant_id"`
+	ParticipantName            string             `json:"participant_name"`
+	ParticipantGroupID         pgtype.UUID        `json:"participant_group_id"`
+	ParticipantGroupName       pgtype.Text        `json:"participant_group_name"`
+	AdvantageType              string             `json:"advantage_type"`
+	Name                       string             `json:"name"`
+	Status                     string             `json:"status"`
+	Visibility                 string             `json:"visibility"`
+	SourceActivityOccurrenceID pgtype.UUID        `json:"source_activity_occurrence_id"`
+	GrantedAt                  pgtype.Timestamptz `json:"granted_at"`
+	EffectiveAt                pgtype.Timestamptz `json:"effective_at"`
+	EffectiveUntil             pgtype.Timestamptz `json:"effective_until"`
+	UsedAt                     pgtype.Timestamptz `json:"used_at"`
+	Metadata                   []byte             `json:"metadata"`
+	CreatedAt                  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                  pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) GetParticipantAdvantage(ctx context.Context, id pgtype.UUID) (GetParticipantAdvantageRow, error) {
+	row := q.db.QueryRow(ctx, getParticipantAdvantage, id)
+	var i GetParticipantAdvantageRow
+	err := row.Scan(
+		&i.ID,
+		&i.InstanceID,
+		&i.ParticipantID,
+		&i.ParticipantName,
+		&i.ParticipantGroupID,
+		&i.ParticipantGroupName,
+		&i.AdvantageType,
+		&i.Name,
+		&i.Status,
+		&i.Visibility,
+		&i.SourceActivityOccurrenceID,
+		&i.GrantedAt,
+		&i.EffectiveAt,
+		&i.EffectiveUntil,
+		&i.UsedAt,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const listActiveAdvantagesByTypeForGroup = `-- name: ListActiveAdvantagesByTypeForGroup :many
 SELECT
     pa.public_id AS id,
@@ -324,6 +430,95 @@ func (q *Queries) ListActiveAdvantagesByTypeForParticipant(ctx context.Context,
 	return items, nil
 }
 
+const listParticipantAdvantagesByInstance = `-- name: ListParticipantAdvantagesByInstance :many
+SELECT
+    pa.public_id AS id,
+    i.public_id AS instance_id,
+    p.public_id AS participant_id,
+    p.name AS participant_name,
+    pg.public_id AS participant_group_id,
+    pg.name AS participant_group_name,
+    pa.advantage_type,
+    pa.name,
+    pa.status,
+    pa.visibility,
+    ao.public_id AS source_activity_occurrence_id,
+    pa.granted_at,
+    pa.effective_at,
+    pa.effective_until,
+    pa.used_at,
+    pa.metadata,
+    pa.created_at,
+    pa.updated_at
+FROM participant_advantages pa
+JOIN instances i ON i.id = pa.instance_id
+JOIN participants p ON p.id = pa.participant_id
+LEFT JOIN participant_groups pg ON pg.id = pa.participant_group_id
+LEFT JOIN activity_occurrences ao ON ao.id = pa.source_activity_occurrence_id
+WHERE i.public_id = $1
+ORDER BY pa.granted_at ASC, pa.id ASC
+`
+
+type ListParticipantAdvantagesByInstanceRow struct {
+	ID                         pgtype.UUID        `json:"id"`
+	InstanceID                 pgtype.UUID        `json:"instance_id"`
+	ParticipantID              pgtype.UUID        `json:"participant_id"`
+	ParticipantName            string             `json:"participant_name"`
+	ParticipantGroupID         pgtype.UUID        `json:"participant_group_id"`
+	ParticipantGroupName       pgtype.Text        `json:"participant_group_name"`
+	AdvantageType              string             `json:"advantage_type"`
+	Name                       string             `json:"name"`
+	Status                     string             `json:"status"`
+	Visibility                 string             `json:"visibility"`
+	SourceActivityOccurrenceID pgtype.UUID        `json:"source_activity_occurrence_id"`
+	GrantedAt                  pgtype.Timestamptz `json:"granted_at"`
+	EffectiveAt                pgtype.Timestamptz `json:"effective_at"`
+	EffectiveUntil             pgtype.Timestamptz `json:"effective_until"`
+	UsedAt                     pgtype.Timestamptz `json:"used_at"`
+	Metadata                   []byte             `json:"metadata"`
+	CreatedAt                  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                  pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) ListParticipantAdvantagesByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListParticipantAdvantagesByInstanceRow, error) {
+	rows, err := q.db.Query(ctx, listParticipantAdvantagesByInstance, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListParticipantAdvantagesByInstanceRow{}
+	for rows.Next() {
+		var i ListParticipantAdvantagesByInstanceRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.InstanceID,
+			&i.ParticipantID,
+			&i.ParticipantName,
+			&i.ParticipantGroupID,
+			&i.ParticipantGroupName,
+			&i.AdvantageType,
+			&i.Name,
+			&i.Status,
+			&i.Visibility,
+			&i.SourceActivityOccurrenceID,
+			&i.GrantedAt,
+			&i.EffectiveAt,
+			&i.EffectiveUntil,
+			&i.UsedAt,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const markAdvantageUsed = `-- name: MarkAdvantageUsed :exec
 UPDATE participant_advantages
 SET status = 'used', updated_at = NOW()
@@ -335,3 +530,36 @@ func (q *Queries) MarkAdvantageUsed(ctx context.Context, id pgtype.UUID) error {
 	_, err := q.db.Exec(ctx, markAdvantageUsed, id)
 	return err
 }
+
+const playParticipantAdvantage = `-- name: PlayParticipantAdvantage :execrows
+UPDATE participant_advantages
+SET status = 'used',
+    used_at = $1,
+    visibility = CASE WHEN $2::boolean AND visibility = 'secret' THEN 'revealed' ELSE visibility END,
+    metadata = metadata || $3::jsonb,
+    updated_at = NOW()
+WHERE public_id = $4
+  AND status = 'active'
+  AND effective_at <= $1
+  AND (effective_until IS NULL OR effective_until > $1)
+`
+
+type PlayParticipantAdvantageParams struct {
+	At       pgtype.Timestamptz `json:"at"`
+	Reveal   bool               `json:"reveal"`
+	Metadata []byte             `json:"metadata"`
+	ID       pgtype.UUID        `json:"id"`
+}
+
+func (q *Queries) PlayParticipantAdvantage(ctx context.Context, arg PlayParticipantAdvantageParams) (int64, error) {
+	result, err := q.db.Exec(ctx, playParticipantAdvantage,
+		arg.At,
+		arg.Reveal,
+		arg.Metadata,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -331,6 +331,69 @@ func (q *Queries) ListAllBonusPointLedgerEntriesForParticipant(ctx context.Conte
 	return items, nil
 }
 
+const listLiveBonusPointLedgerEntriesByOccurrence = `-- name: ListLiveBonusPointLedgerEntriesByOccurrence :many
+SELECT
+    bple.public_id AS id,
+    p.public_id AS participant_id,
+    p.name AS participant_name,
+    bple.entry_kind,
+    bple.points,
+    bple.visibility,
+    bple.reason,
+    bple.effective_at,
+    bple.award_key
+FROM bonus_point_ledger_entries bple
+JOIN participants p ON p.id = bple.participant_id
+JOIN activity_occurrences ao ON ao.id = bple.activity_occurrence_id
+WHERE ao.public_id = $1
+  AND bple.voided_at IS NULL
+  AND bple.reverses_entry_id IS NULL
+  AND bple.entry_kind NOT IN ('reveal', 'conversion')
+ORDER BY p.name ASC, bple.created_at ASC, bple.id ASC
+`
+
+type ListLiveBonusPointLedgerEntriesByOccurrenceRow struct {
+	ID              pgtype.UUID        `json:"id"`
+	ParticipantID   pgtype.UUID        `json:"participant_id"`
+	ParticipantName string             `json:"participant_name"`
+	EntryKind       string             `json:"entry_kind"`
+	Points          int32              `json:"points"`
+	Visibility      string             `json:"visibility"`
+	Reason          string             `json:"reason"`
+	EffectiveAt     pgtype.Timestamptz `json:"effective_at"`
+	AwardKey        pgtype.Text        `json:"award_key"`
+}
+
+func (q *Queries) ListLiveBonusPointLedgerEntriesByOccurrence(ctx context.Context, activityOccurrenceID pgtype.UUID) ([]ListLiveBonusPointLedgerEntriesByOccurrenceRow, error) {
+	rows, err := q.db.Query(ctx, listLiveBonusPointLedgerEntriesByOccurrence, activityOccurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLiveBonusPointLedgerEntriesByOccurrenceRow{}
+	for rows.Next() {
+		var i ListLiveBonusPointLedgerEntriesByOccurrenceRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParticipantID,
+			&i.ParticipantName,
+			&i.EntryKind,
+			&i.Points,
+			&i.Visibility,
+			&i.Reason,
+			&i.EffectiveAt,
+			&i.AwardKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listVisibleBonusPointLedgerEntriesByOccurrence = `-- name: ListVisibleBonusPointLedgerEntriesByOccurrence :many
 SELECT
     bple.public_id AS id,
@@ -527,3 +590,81 @@ func (q *Queries) ListVisibleBonusPointLedgerEntriesForParticipant(ctx context.C
 	}
 	return items, nil
 }
+
+const reverseBonusPointLedgerEntry = `-- name: ReverseBonusPointLedgerEntry :one
+WITH voided AS (
+    UPDATE bonus_point_ledger_entries
+    SET voided_at = $1
+    WHERE public_id = $2
+      AND voided_at IS NULL
+      AND reverses_entry_id IS NULL
+    RETURNING *
+)
+INSERT INTO bonus_point_ledger_entries (
+    instance_id,
+    participant_id,
+    activity_occurrence_id,
+    source_group_id,
+    entry_kind,
+    points,
+    visibility,
+    reason,
+    effective_at,
+    metadata,
+    reverses_entry_id
+)
+SELECT
+    v.instance_id,
+    v.participant_id,
+    v.activity_occurrence_id,
+    v.source_group_id,
+    'correction',
+    -v.points,
+    v.visibility,
+    'Reversal: ' || v.reason,
+    v.effective_at,
+    v.metadata || jsonb_build_object('reverses_entry_id', v.public_id),
+    v.id
+FROM voided v
+RETURNING
+    public_id AS id,
+    (SELECT p.public_id FROM participants p WHERE p.id = participant_id) AS participant_id,
+    entry_kind,
+    points,
+    visibility,
+    reason,
+    effective_at,
+    created_at
+`
+
+type ReverseBonusPointLedgerEntryParams struct {
+	VoidedAt pgtype.Timestamptz `json:"voided_at"`
+	ID       pgtype.UUID        `json:"id"`
+}
+
+type ReverseBonusPointLedgerEntryRow struct {
+	ID            pgtype.UUID        `json:"id"`
+	ParticipantID pgtype.UUID        `json:"participant_id"`
+	EntryKind     string             `json:"entry_kind"`
+	Points        int32              `json:"points"`
+	Visibility    string             `json:"visibility"`
+	Reason        string             `json:"reason"`
+	EffectiveAt   pgtype.Timestamptz `json:"effective_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ReverseBonusPointLedgerEntry(ctx context.Context, arg ReverseBonusPointLedgerEntryParams) (ReverseBonusPointLedgerEntryRow, error) {
+	row := q.db.QueryRow(ctx, reverseBonusPointLedgerEntry, arg.VoidedAt, arg.ID)
+	var i ReverseBonusPointLedgerEntryRow
+	err := row.Scan(
+		&i.ID,
+		&i.ParticipantID,
+		&i.EntryKind,
+		&i.Points,
+		&i.Visibility,
+		&i.Reason,
+		&i.EffectiveAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -90,6 +90,41 @@ func (q *Queries) InstanceHasContestant(ctx context.Context, arg InstanceHasCont
 	return exists, err
 }
 
+const listContestantOddsByInstance = `-- name: ListContestantOddsByInstance :many
+SELECT c.public_id AS contestant_id, c.name AS contestant_name, ic.win_odds
+FROM instance_contestants ic
+JOIN instances i ON i.id = ic.instance_id
+JOIN contestants c ON c.id = ic.contestant_id
+WHERE i.public_id = $1
+ORDER BY c.name ASC
+`
+
+type ListContestantOddsByInstanceRow struct {
+	ContestantID   pgtype.UUID   `json:"contestant_id"`
+	ContestantName string        `json:"contestant_name"`
+	WinOdds        pgtype.Float8 `json:"win_odds"`
+}
+
+func (q *Queries) ListContestantOddsByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListContestantOddsByInstanceRow, error) {
+	rows, err := q.db.Query(ctx, listContestantOddsByInstance, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListContestantOddsByInstanceRow{}
+	for rows.Next() {
+		var i ListContestantOddsByInstanceRow
+		if err := rows.Scan(&i.ContestantID, &i.ContestantName, &i.WinOdds); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listContestantsByInstance = `-- name: ListContestantsByInstance :many
 SELECT c.public_id AS id, c.name, c.created_at
 FROM contestants c
@@ -156,3 +191,33 @@ func (q *Queries) ListContestantsGlobal(ctx context.Context) ([]ListContestantsG
 	}
 	return items, nil
 }
+
+const setContestantOdds = `-- name: SetContestantOdds :one
+UPDATE instance_contestants ic
+SET win_odds = $1
+FROM instances i, contestants c
+WHERE ic.instance_id = i.id
+  AND ic.contestant_id = c.id
+  AND i.public_id = $2
+  AND c.public_id = $3
+RETURNING c.public_id AS contestant_id, c.name AS contestant_name, ic.win_odds
+`
+
+type SetContestantOddsParams struct {
+	WinOdds      pgtype.Float8 `json:"win_odds"`
+	InstanceID   pgtype.UUID   `json:"instance_id"`
+	ContestantID pgtype.UUID   `json:"contestant_id"`
+}
+
+type SetContestantOddsRow struct {
+	ContestantID   pgtype.UUID   `json:"contestant_id"`
+	ContestantName string        `json:"contestant_name"`
+	WinOdds        pgtype.Float8 `json:"win_odds"`
+}
+
+func (q *Queries) SetContestantOdds(ctx context.Context, arg SetContestantOddsParams) (SetContestantOddsRow, error) {
+	row := q.db.QueryRow(ctx, setContestantOdds, arg.WinOdds, arg.InstanceID, arg.ContestantID)
+	var i SetContestantOddsRow
+	err := row.Scan(&i.ContestantID, &i.ContestantName, &i.WinOdds)
+	return i, err
+}
@@ -11,6 +11,61 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const createDraftOverride = `-- name: CreateDraftOverride :one
+INSERT INTO draft_overrides (
+    instance_id,
+    participant_id,
+    actor_discord_user_id,
+    reason,
+    draft_deadline,
+    previous_contestant_ids,
+    contestant_ids
+)
+SELECT
+    i.id,
+    p.id,
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+FROM instances i
+JOIN participants p ON p.instance_id = i.id
+WHERE i.public_id = $6
+  AND p.public_id = $7
+RETURNING public_id AS id, created_at
+`
+
+type CreateDraftOverrideParams struct {
+	ActorDiscordUserID    string             `json:"actor_discord_user_id"`
+	Reason                string             `json:"reason"`
+	DraftDeadline         pgtype.Timestamptz `json:"draft_deadline"`
+	PreviousContestantIds []byte             `json:"previous_contestant_ids"`
+	ContestantIds         []byte             `json:"contestant_ids"`
+	InstanceID            pgtype.UUID        `json:"instance_id"`
+	ParticipantID         pgtype.UUID        `json:"participant_id"`
+}
+
+type CreateDraftOverrideRow struct {
+	ID        pgtype.UUID        `json:"id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) CreateDraftOverride(ctx context.Context, arg CreateDraftOverrideParams) (CreateDraftOverrideRow, error) {
+	row := q.db.QueryRow(ctx, createDraftOverride,
+		arg.ActorDiscordUserID,
+		arg.Reason,
+		arg.DraftDeadline,
+		arg.PreviousContestantIds,
+		arg.ContestantIds,
+		arg.InstanceID,
+		arg.ParticipantID,
+	)
+	var i CreateDraftOverrideRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
 const createDraftPick = `-- name: CreateDraftPick :one
 WITH resolved AS (
     SELECT
@@ -82,6 +137,66 @@ func (q *Queries) DeleteDraftPicksForParticipant(ctx context.Context, participan
 	return err
 }
 
+const listDraftOverridesByInstance = `-- name: ListDraftOverridesByInstance :many
+SELECT
+    o.public_id AS id,
+    p.public_id AS participant_id,
+    p.name AS participant_name,
+    o.actor_discord_user_id,
+    o.reason,
+    o.draft_deadline,
+    o.previous_contestant_ids,
+    o.contestant_ids,
+    o.created_at
+FROM draft_overrides o
+JOIN instances i ON i.id = o.instance_id
+JOIN participants p ON p.id = o.participant_id
+WHERE i.public_id = $1
+ORDER BY o.created_at DESC, o.id DESC
+`
+
+type ListDraftOverridesByInstanceRow struct {
+	ID                    pgtype.UUID        `json:"id"`
+	ParticipantID         pgtype.UUID        `json:"participant_id"`
+	ParticipantName       string             `json:"participant_name"`
+	ActorDiscordUserID    string             `json:"actor_discord_user_id"`
+	Reason                string             `json:"reason"`
+	DraftDeadline         pgtype.Timestamptz `json:"draft_deadline"`
+	PreviousContestantIds []byte             `json:"previous_contestant_ids"`
+	ContestantIds         []byte             `json:"contestant_ids"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ListDraftOverridesByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListDraftOverridesByInstanceRow, error) {
+	rows, err := q.db.Query(ctx, listDraftOverridesByInstance, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDraftOverridesByInstanceRow{}
+	for rows.Next() {
+		var i ListDraftOverridesByInstanceRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParticipantID,
+			&i.ParticipantName,
+			&i.ActorDiscordUserID,
+			&i.Reason,
+			&i.DraftDeadline,
+			&i.PreviousContestantIds,
+			&i.ContestantIds,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listDraftPicksForInstance = `-- name: ListDraftPicksForInstance :many
 SELECT
     i.public_id AS instance_id,
@@ -68,6 +68,16 @@ func (q *Queries) CreateInstanceEpisode(ctx context.Context, arg CreateInstanceE
 	return i, err
 }
 
+const deleteInstanceEpisode = `-- name: DeleteInstanceEpisode :exec
+DELETE FROM instance_episodes
+WHERE public_id = $1
+`
+
+func (q *Queries) DeleteInstanceEpisode(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteInstanceEpisode, id)
+	return err
+}
+
 const getCurrentEpisodeAt = `-- name: GetCurrentEpisodeAt :one
 SELECT
     ie.public_id AS id,
@@ -118,6 +128,129 @@ func (q *Queries) GetCurrentEpisodeAt(ctx context.Context, arg GetCurrentEpisode
 	return i, err
 }
 
+const getInstanceEpisode = `-- name: GetInstanceEpisode :one
+SELECT
+    ie.public_id AS id,
+    i.public_id AS instance_id,
+    ie.episode_number,
+    ie.label,
+    ie.airs_at,
+    ie.metadata,
+    ie.created_at,
+    ie.updated_at
+FROM instance_episodes ie
+JOIN instances i ON i.id = ie.instance_id
+WHERE i.public_id = $1
+  AND ie.public_id = $2
+`
+
+type GetInstanceEpisodeParams struct {
+	InstanceID pgtype.UUID `json:"instance_id"`
+	ID         pgtype.UUID `json:"id"`
+}
+
+type GetInstanceEpisodeRow struct {
+	ID            pgtype.UUID        `json:"id"`
+	InstanceID    pgtype.UUID        `json:"instance_id"`
+	EpisodeNumber int32              `json:"episode_number"`
+	Label         string             `json:"label"`
+	AirsAt        pgtype.Timestamptz `json:"airs_at"`
+	Metadata      []byte             `json:"metadata"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) GetInstanceEpisode(ctx context.Context, arg GetInstanceEpisodeParams) (GetInstanceEpisodeRow, error) {
+	row := q.db.QueryRow(ctx, getInstanceEpisode, arg.InstanceID, arg.ID)
+	var i GetInstanceEpisodeRow
+	err := row.Scan(
+		&i.ID,
+		&i.InstanceID,
+		&i.EpisodeNumber,
+		&i.Label,
+		&i.AirsAt,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getInstanceEpisodeUsage = `-- name: GetInstanceEpisodeUsage :one
+SELECT
+    (
+        SELECT COUNT(*)
+        FROM participant_group_membership_periods m
+        JOIN participant_groups g ON g.id = m.participant_group_id
+        WHERE g.instance_id = ie.instance_id
+          AND (m.starts_at = ie.airs_at OR m.ends_at = ie.airs_at)
+    ) AS membership_periods,
+    (
+        SELECT COUNT(*)
+        FROM activity_group_assignments aga
+        JOIN instance_activities a ON a.id = aga.activity_id
+        WHERE a.instance_id = ie.instance_id
+          AND (aga.starts_at = ie.airs_at OR aga.ends_at = ie.airs_at)
+    ) AS activity_group_assignments,
+    (
+        SELECT COUNT(*)
+        FROM activity_participant_assignments apa
+        JOIN instance_activities a ON a.id = apa.activity_id
+        WHERE a.instance_id = ie.instance_id
+          AND (apa.starts_at = ie.airs_at OR apa.ends_at = ie.airs_at)
+    ) AS activity_participant_assignments,
+    (
+        SELECT COUNT(*)
+        FROM instance_activities a
+        WHERE a.instance_id = ie.instance_id
+          AND (a.starts_at = ie.airs_at OR a.ends_at = ie.airs_at)
+    ) AS activities,
+    (
+        SELECT COUNT(*)
+        FROM activity_occurrences o
+        JOIN instance_activities a ON a.id = o.activity_id
+        WHERE a.instance_id = ie.instance_id
+          AND (o.starts_at = ie.airs_at OR o.ends_at = ie.airs_at)
+    ) AS occurrences,
+    (
+        SELECT COUNT(*)
+        FROM outcome_positions op
+        WHERE op.episode_id = ie.id
+    ) AS outcomes,
+    (
+        SELECT COUNT(*)
+        FROM outcome_position_history oph
+        WHERE oph.episode_id = ie.id
+    ) AS outcome_history
+FROM instance_episodes ie
+WHERE ie.public_id = $1
+`
+
+type GetInstanceEpisodeUsageRow struct {
+	MembershipPeriods              int64 `json:"membership_periods"`
+	ActivityGroupAssignments       int64 `json:"activity_group_assignments"`
+	ActivityParticipantAssignments int64 `json:"activity_participant_assignments"`
+	Activities                     int64 `json:"activities"`
+	Occurrences                    int64 `json:"occurrences"`
+	Outcomes                       int64 `json:"outcomes"`
+	OutcomeHistory                 int64 `json:"outcome_history"`
+}
+
+func (q *Queries) GetInstanceEpisodeUsage(ctx context.Context, id pgtype.UUID) (GetInstanceEpisodeUsageRow, error) {
+	row := q.db.QueryRow(ctx, getInstanceEpisodeUsage, id)
+	var i GetInstanceEpisodeUsageRow
+	err := row.Scan(
+		&i.MembershipPeriods,
+		&i.ActivityGroupAssignments,
+		&i.ActivityParticipantAssignments,
+		&i.Activities,
+		&i.Occurrences,
+		&i.Outcomes,
+		&i.OutcomeHistory,
+	)
+	return i, err
+}
+
 const listEpisodeBoundaryWindows = `-- name: ListEpisodeBoundaryWindows :many
 SELECT
     ie.episode_number,
@@ -220,3 +353,53 @@ func (q *Queries) ListInstanceEpisodes(ctx context.Context, instanceID pgtype.UU
 	}
 	return items, nil
 }
+
+const updateInstanceEpisode = `-- name: UpdateInstanceEpisode :one
+UPDATE instance_episodes
+SET label = $1,
+    airs_at = $2,
+    updated_at = NOW()
+WHERE public_id = $3
+RETURNING
+    public_id AS id,
+    (SELECT public_id FROM instances WHERE id = instance_episodes.instance_id) AS instance_id,
+    episode_number,
+    label,
+    airs_at,
+    metadata,
+    created_at,
+    updated_at
+`
+
+type UpdateInstanceEpisodeParams struct {
+	Label  string             `json:"label"`
+	AirsAt pgtype.Timestamptz `json:"airs_at"`
+	ID     pgtype.UUID        `json:"id"`
+}
+
+type UpdateInstanceEpisodeRow struct {
+	ID            pgtype.UUID        `json:"id"`
+	InstanceID    pgtype.UUID        `json:"instance_id"`
+	EpisodeNumber int32              `json:"episode_number"`
+	Label         string             `json:"label"`
+	AirsAt        pgtype.Timestamptz `json:"airs_at"`
+	Metadata      []byte             `json:"metadata"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) UpdateInstanceEpisode(ctx context.Context, arg UpdateInstanceEpisodeParams) (UpdateInstanceEpisodeRow, error) {
+	row := q.db.QueryRow(ctx, updateInstanceEpisode, arg.Label, arg.AirsAt, arg.ID)
+	var i UpdateInstanceEpisodeRow
+	err := row.Scan(
+		&i.ID,
+		&i.InstanceID,
+		&i.EpisodeNumber,
+		&i.Label,
+		&i.AirsAt,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -145,6 +145,73 @@ func (q *Queries) CreateParticipantGroupMembershipPeriod(ctx context.Context, ar
 	return i, err
 }
 
+const endParticipantGroupMembershipPeriods = `-- name: EndParticipantGroupMembershipPeriods :many
+UPDATE participant_group_membership_periods pgmp
+SET ends_at = $1
+FROM participant_groups pg, participants p
+WHERE pg.id = pgmp.participant_group_id
+  AND p.id = pgmp.participant_id
+  AND pg.public_id = $2
+  AND p.public_id = $3
+  AND pgmp.starts_at < $1
+  AND (pgmp.ends_at IS NULL OR pgmp.ends_at > $1)
+RETURNING
+    pgmp.id,
+    pg.public_id AS participant_group_id,
+    p.public_id AS participant_id,
+    pgmp.role,
+    pgmp.starts_at,
+    pgmp.ends_at,
+    pgmp.metadata,
+    pgmp.created_at
+`
+
+type EndParticipantGroupMembershipPeriodsParams struct {
+	EndsAt             pgtype.Timestamptz `json:"ends_at"`
+	ParticipantGroupID pgtype.UUID        `json:"participant_group_id"`
+	ParticipantID      pgtype.UUID        `json:"participant_id"`
+}
+
+type EndParticipantGroupMembershipPeriodsRow struct {
+	ID                 int64              `json:"id"`
+	ParticipantGroupID pgtype.UUID        `json:"participant_group_id"`
+	ParticipantID      pgtype.UUID        `json:"participant_id"`
+	Role               string             `json:"role"`
+	StartsAt           pgtype.Timestamptz `json:"starts_at"`
+	EndsAt             pgtype.Timestamptz `json:"ends_at"`
+	Metadata           []byte             `json:"metadata"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) EndParticipantGroupMembershipPeriods(ctx context.Context, arg EndParticipantGroupMembershipPeriodsParams) ([]EndParticipantGroupMembershipPeriodsRow, error) {
+	rows, err := q.db.Query(ctx, endParticipantGroupMembershipPeriods, arg.EndsAt, arg.ParticipantGroupID, arg.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []EndParticipantGroupMembershipPeriodsRow{}
+	for rows.Next() {
+		var i EndParticipantGroupMembershipPeriodsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParticipantGroupID,
+			&i.ParticipantID,
+			&i.Role,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Metadata,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getParticipantGroup = `-- name: GetParticipantGroup :one
 SELECT
     pg.public_id AS id,
@@ -12,35 +12,64 @@ import (
 )
 
 const createInstance = `-- name: CreateInstance :one
-INSERT INTO instances (name, season)
-VALUES ($1, $2)
-RETURNING public_id AS id, name, season, created_at
+INSERT INTO instances (name, season, state)
+VALUES ($1, $2, COALESCE($3, 'setup'))
+RETURNING public_id AS id, name, season, created_at, state, state_changed_at
 `
 
 type CreateInstanceParams struct {
-	Name   string `json:"name"`
-	Season int32  `json:"season"`
+	Name   string      `json:"name"`
+	Season int32       `json:"season"`
+	State  pgtype.Text `json:"state"`
 }
 
 type CreateInstanceRow struct {
-	ID        pgtype.UUID        `json:"id"`
-	Name      string             `json:"name"`
-	Season    int32              `json:"season"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID             pgtype.UUID        `json:"id"`
+	Name           string             `json:"name"`
+	Season         int32              `json:"season"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	State          string             `json:"state"`
+	StateChangedAt pgtype.Timestamptz `json:"state_changed_at"`
 }
 
 func (q *Queries) CreateInstance(ctx context.Context, arg CreateInstanceParams) (CreateInstanceRow, error) {
-	row := q.db.QueryRow(ctx, createInstance, arg.Name, arg.Season)
+	row := q.db.QueryRow(ctx, createInstance, arg.Name, arg.Season, arg.State)
 	var i CreateInstanceRow
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Season,
 		&i.CreatedAt,
+		&i.State,
+		&i.StateChangedAt,
 	)
 	return i, err
 }
 
+const deleteInstanceActivities = `-- name: DeleteInstanceActivities :exec
+DELETE FROM instance_activities a
+USING instances i
+WHERE a.instance_id = i.id
+  AND i.public_id = $1
+`
+
+func (q *Queries) DeleteInstanceActivities(ctx context.Context, instanceID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteInstanceActivities, instanceID)
+	return err
+}
+
+const deleteInstanceAdmins = `-- name: DeleteInstanceAdmins :exec
+DELETE FROM instance_admins ia
+USING instances i
+WHERE ia.instance_id = i.id
+  AND i.public_id = $1
+`
+
+func (q *Queries) DeleteInstanceAdmins(ctx context.Context, instanceID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteInstanceAdmins, instanceID)
+	return err
+}
+
 const deleteInstanceByNameSeason = `-- name: DeleteInstanceByNameSeason :exec
 DELETE FROM instances
 WHERE name = $1 AND season = $2
@@ -56,17 +85,91 @@ func (q *Queries) DeleteInstanceByNameSeason(ctx context.Context, arg DeleteInst
 	return err
 }
 
+const deleteInstanceContestants = `-- name: DeleteInstanceContestants :exec
+DELETE FROM instance_contestants ic
+USING instances i
+WHERE ic.instance_id = i.id
+  AND i.public_id = $1
+`
+
+func (q *Queries) DeleteInstanceContestants(ctx context.Context, instanceID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteInstanceContestants, instanceID)
+	return err
+}
+
+const deleteInstanceEpisodes = `-- name: DeleteInstanceEpisodes :exec
+DELETE FROM instance_episodes e
+USING instances i
+WHERE e.instance_id = i.id
+  AND i.public_id = $1
+`
+
+func (q *Queries) DeleteInstanceEpisodes(ctx context.Context, instanceID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteInstanceEpisodes, instanceID)
+	return err
+}
+
+const deleteInstanceOutcomeHistory = `-- name: DeleteInstanceOutcomeHistory :exec
+DELETE FROM outcome_position_history h
+USING instances i
+WHERE h.instance_id = i.id
+  AND i.public_id = $1
+`
+
+func (q *Queries) DeleteInstanceOutcomeHistory(ctx context.Context, instanceID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteInstanceOutcomeHistory, instanceID)
+	return err
+}
+
+const deleteInstanceOutcomes = `-- name: DeleteInstanceOutcomes :exec
+DELETE FROM outcome_positions op
+USING instances i
+WHERE op.instance_id = i.id
+  AND i.public_id = $1
+`
+
+func (q *Queries) DeleteInstanceOutcomes(ctx context.Context, instanceID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteInstanceOutcomes, instanceID)
+	return err
+}
+
+const deleteInstanceParticipantGroups = `-- name: DeleteInstanceParticipantGroups :exec
+DELETE FROM participant_groups g
+USING instances i
+WHERE g.instance_id = i.id
+  AND i.public_id = $1
+`
+
+func (q *Queries) DeleteInstanceParticipantGroups(ctx context.Context, instanceID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteInstanceParticipantGroups, instanceID)
+	return err
+}
+
+const deleteInstanceParticipants = `-- name: DeleteInstanceParticipants :exec
+DELETE FROM participants p
+USING instances i
+WHERE p.instance_id = i.id
+  AND i.public_id = $1
+`
+
+func (q *Queries) DeleteInstanceParticipants(ctx context.Context, instanceID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteInstanceParticipants, instanceID)
+	return err
+}
+
 const getInstance = `-- name: GetInstance :one
-SELECT public_id AS id, name, season, created_at
+SELECT public_id AS id, name, season, created_at, state, state_changed_at
 FROM instances
 WHERE public_id = $1
 `
 
 type GetInstanceRow struct {
-	ID        pgtype.UUID        `json:"id"`
-	Name      string             `json:"name"`
-	Season    int32              `json:"season"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID             pgtype.UUID        `json:"id"`
+	Name           string             `json:"name"`
+	Season         int32              `json:"season"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	State          string             `json:"state"`
+	StateChangedAt pgtype.Timestamptz `json:"state_changed_at"`
 }
 
 func (q *Queries) GetInstance(ctx context.Context, id pgtype.UUID) (GetInstanceRow, error) {
@@ -77,21 +180,144 @@ func (q *Queries) GetInstance(ctx context.Context, id pgtype.UUID) (GetInstanceR
 		&i.Name,
 		&i.Season,
 		&i.CreatedAt,
+		&i.State,
+		&i.StateChangedAt,
 	)
 	return i, err
 }
 
+const getInstanceConfigByNameSeason = `-- name: GetInstanceConfigByNameSeason :one
+SELECT public_id AS id, state, config_checksum
+FROM instances
+WHERE name = $1 AND season = $2
+ORDER BY created_at DESC
+LIMIT 1
+FOR UPDATE
+`
+
+type GetInstanceConfigByNameSeasonParams struct {
+	Name   string `json:"name"`
+	Season int32  `json:"season"`
+}
+
+type GetInstanceConfigByNameSeasonRow struct {
+	ID             pgtype.UUID `json:"id"`
+	State          string      `json:"state"`
+	ConfigChecksum pgtype.Text `json:"config_checksum"`
+}
+
+func (q *Queries) GetInstanceConfigByNameSeason(ctx context.Context, arg GetInstanceConfigByNameSeasonParams) (GetInstanceConfigByNameSeasonRow, error) {
+	row := q.db.QueryRow(ctx, getInstanceConfigByNameSeason, arg.Name, arg.Season)
+	var i GetInstanceConfigByNameSeasonRow
+	err := row.Scan(&i.ID, &i.State, &i.ConfigChecksum)
+	return i, err
+}
+
+const getInstanceDraftDeadline = `-- name: GetInstanceDraftDeadline :one
+SELECT draft_deadline
+FROM instances
+WHERE public_id = $1
+`
+
+func (q *Queries) GetInstanceDraftDeadline(ctx context.Context, id pgtype.UUID) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getInstanceDraftDeadline, id)
+	var draft_deadline pgtype.Timestamptz
+	err := row.Scan(&draft_deadline)
+	return draft_deadline, err
+}
+
+const getInstanceScoringStrategy = `-- name: GetInstanceScoringStrategy :one
+SELECT scoring_strategy
+FROM instances
+WHERE public_id = $1
+`
+
+func (q *Queries) GetInstanceScoringStrategy(ctx context.Context, id pgtype.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getInstanceScoringStrategy, id)
+	var scoring_strategy string
+	err := row.Scan(&scoring_strategy)
+	return scoring_strategy, err
+}
+
+const getInstanceState = `-- name: GetInstanceState :one
+SELECT state
+FROM instances
+WHERE public_id = $1
+`
+
+func (q *Queries) GetInstanceState(ctx context.Context, id pgtype.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getInstanceState, id)
+	var state string
+	err := row.Scan(&state)
+	return state, err
+}
+
+const getInstanceStateByActivity = `-- name: GetInstanceStateByActivity :one
+SELECT i.public_id AS instance_id, i.state
+FROM instance_activities a
+JOIN instances i ON i.id = a.instance_id
+WHERE a.public_id = $1
+`
+
+type GetInstanceStateByActivityRow struct {
+	InstanceID pgtype.UUID `json:"instance_id"`
+	State      string      `json:"state"`
+}
+
+func (q *Queries) GetInstanceStateByActivity(ctx context.Context, activityID pgtype.UUID) (GetInstanceStateByActivityRow, error) {
+	row := q.db.QueryRow(ctx, getInstanceStateByActivity, activityID)
+	var i GetInstanceStateByActivityRow
+	err := row.Scan(&i.InstanceID, &i.State)
+	return i, err
+}
+
+const getInstanceStateByOccurrence = `-- name: GetInstanceStateByOccurrence :one
+SELECT i.public_id AS instance_id, i.state
+FROM activity_occurrences o
+JOIN instance_activities a ON a.id = o.activity_id
+JOIN instances i ON i.id = a.instance_id
+WHERE o.public_id = $1
+`
+
+type GetInstanceStateByOccurrenceRow struct {
+	InstanceID pgtype.UUID `json:"instance_id"`
+	State      string      `json:"state"`
+}
+
+func (q *Queries) GetInstanceStateByOccurrence(ctx context.Context, occurrenceID pgtype.UUID) (GetInstanceStateByOccurrenceRow, error) {
+	row := q.db.QueryRow(ctx, getInstanceStateByOccurrence, occurrenceID)
+	var i GetInstanceStateByOccurrenceRow
+	err := row.Scan(&i.InstanceID, &i.State)
+	return i, err
+}
+
+const getInstanceStateForUpdate = `-- name: GetInstanceStateForUpdate :one
+SELECT state
+FROM instances
+WHERE public_id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetInstanceStateForUpdate(ctx context.Context, id pgtype.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getInstanceStateForUpdate, id)
+	var state string
+	err := row.Scan(&state)
+	return state, err
+}
+
 const listInstances = `-- name: ListInstances :many
-SELECT public_id AS id, name, season, created_at
+SELECT public_id AS id, name, season, created_at, state, state_changed_at
 FROM instances
 ORDER BY created_at DESC
 `
 
 type ListInstancesRow struct {
-	ID        pgtype.UUID        `json:"id"`
-	Name      string             `json:"name"`
-	Season    int32              `json:"season"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID             pgtype.UUID        `json:"id"`
+	Name           string             `json:"name"`
+	Season         int32              `json:"season"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	State          string             `json:"state"`
+	StateChangedAt pgtype.Timestamptz `json:"state_changed_at"`
 }
 
 func (q *Queries) ListInstances(ctx context.Context) ([]ListInstancesRow, error) {
@@ -108,6 +334,8 @@ func (q *Queries) ListInstances(ctx context.Context) ([]ListInstancesRow, error)
 			&i.Name,
 			&i.Season,
 			&i.CreatedAt,
+			&i.State,
+			&i.StateChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -119,11 +347,110 @@ func (q *Queries) ListInstances(ctx context.Context) ([]ListInstancesRow, error)
 	return items, nil
 }
 
+const resetInstance = `-- name: ResetInstance :exec
+UPDATE instances
+SET state = $1,
+    state_changed_at = NOW(),
+    draft_deadline = NULL,
+    scoring_strategy = DEFAULT,
+    config_checksum = NULL
+WHERE public_id = $2
+`
+
+type ResetInstanceParams struct {
+	State string      `json:"state"`
+	ID    pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) ResetInstance(ctx context.Context, arg ResetInstanceParams) error {
+	_, err := q.db.Exec(ctx, resetInstance, arg.State, arg.ID)
+	return err
+}
+
+const setInstanceConfigChecksum = `-- name: SetInstanceConfigChecksum :exec
+UPDATE instances
+SET config_checksum = $1
+WHERE public_id = $2
+`
+
+type SetInstanceConfigChecksumParams struct {
+	ConfigChecksum pgtype.Text `json:"config_checksum"`
+	ID             pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) SetInstanceConfigChecksum(ctx context.Context, arg SetInstanceConfigChecksumParams) error {
+	_, err := q.db.Exec(ctx, setInstanceConfigChecksum, arg.ConfigChecksum, arg.ID)
+	return err
+}
+
+const setInstanceDraftDeadline = `-- name: SetInstanceDraftDeadline :one
+UPDATE instances
+SET draft_deadline = $1
+WHERE public_id = $2
+RETURNING draft_deadline
+`
+
+type SetInstanceDraftDeadlineParams struct {
+	DraftDeadline pgtype.Timestamptz `json:"draft_deadline"`
+	ID            pgtype.UUID        `json:"id"`
+}
+
+func (q *Queries) SetInstanceDraftDeadline(ctx context.Context, arg SetInstanceDraftDeadlineParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, setInstanceDraftDeadline, arg.DraftDeadline, arg.ID)
+	var draft_deadline pgtype.Timestamptz
+	err := row.Scan(&draft_deadline)
+	return draft_deadline, err
+}
+
+const setInstanceScoringStrategy = `-- name: SetInstanceScoringStrategy :one
+UPDATE instances
+SET scoring_strategy = $1
+WHERE public_id = $2
+RETURNING scoring_strategy
+`
+
+type SetInstanceScoringStrategyParams struct {
+	ScoringStrategy string      `json:"scoring_strategy"`
+	ID              pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) SetInstanceScoringStrategy(ctx context.Context, arg SetInstanceScoringStrategyParams) (string, error) {
+	row := q.db.QueryRow(ctx, setInstanceScoringStrategy, arg.ScoringStrategy, arg.ID)
+	var scoring_strategy string
+	err := row.Scan(&scoring_strategy)
+	return scoring_strategy, err
+}
+
+const setInstanceState = `-- name: SetInstanceState :one
+UPDATE instances
+SET state = $1,
+    state_changed_at = NOW()
+WHERE public_id = $2
+RETURNING state, state_changed_at
+`
+
+type SetInstanceStateParams struct {
+	State string      `json:"state"`
+	ID    pgtype.UUID `json:"id"`
+}
+
+type SetInstanceStateRow struct {
+	State          string             `json:"state"`
+	StateChangedAt pgtype.Timestamptz `json:"state_changed_at"`
+}
+
+func (q *Queries) SetInstanceState(ctx context.Context, arg SetInstanceStateParams) (SetInstanceStateRow, error) {
+	row := q.db.QueryRow(ctx, setInstanceState, arg.State, arg.ID)
+	var i SetInstanceStateRow
+	err := row.Scan(&i.State, &i.StateChangedAt)
+	return i, err
+}
+
 const updateInstanceName = `-- name: UpdateInstanceName :one
 UPDATE instances
 SET name = $2
 WHERE public_id = $1
-RETURNING public_id AS id, name, season, created_at
+RETURNING public_id AS id, name, season, created_at, state, state_changed_at
 `
 
 type UpdateInstanceNameParams struct {
@@ -132,10 +459,12 @@ type UpdateInstanceNameParams struct {
 }
 
 type UpdateInstanceNameRow struct {
-	ID        pgtype.UUID        `json:"id"`
-	Name      string             `json:"name"`
-	Season    int32              `json:"season"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID             pgtype.UUID        `json:"id"`
+	Name           string             `json:"name"`
+	Season         int32              `json:"season"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	State          string             `json:"state"`
+	StateChangedAt pgtype.Timestamptz `json:"state_changed_at"`
 }
 
 func (q *Queries) UpdateInstanceName(ctx context.Context, arg UpdateInstanceNameParams) (UpdateInstanceNameRow, error) {
@@ -146,6 +475,8 @@ func (q *Queries) UpdateInstanceName(ctx context.Context, arg UpdateInstanceName
 		&i.Name,
 		&i.Season,
 		&i.CreatedAt,
+		&i.State,
+		&i.StateChangedAt,
 	)
 	return i, err
 }
@@ -68,6 +68,39 @@ type ActivityParticipantAssignment struct {
 	CreatedAt          pgtype.Timestamptz `json:"created_at"`
 }
 
+type ApiCredential struct {
+	ID                     int64              `json:"id"`
+	PublicID               pgtype.UUID        `json:"public_id"`
+	Name                   string             `json:"name"`
+	TokenPrefix            string             `json:"token_prefix"`
+	TokenHash              string             `json:"token_hash"`
+	PreviousTokenHash      pgtype.Text        `json:"previous_token_hash"`
+	PreviousTokenExpiresAt pgtype.Timestamptz `json:"previous_token_expires_at"`
+	Scopes                 []string           `json:"scopes"`
+	InstanceIds            []pgtype.UUID      `json:"instance_ids"`
+	ExpiresAt              pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt              pgtype.Timestamptz `json:"revoked_at"`
+	LastUsedAt             pgtype.Timestamptz `json:"last_used_at"`
+	RotatedAt              pgtype.Timestamptz `json:"rotated_at"`
+	CreatedAt              pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt              pgtype.Timestamptz `json:"updated_at"`
+	DiscordUserID          pgtype.Text        `json:"discord_user_id"`
+}
+
+type AuditEvent struct {
+	ID                 int64              `json:"id"`
+	PublicID           pgtype.UUID        `json:"public_id"`
+	InstanceID         pgtype.Int8        `json:"instance_id"`
+	Action             string             `json:"action"`
+	ServicePrincipal   pgtype.Text        `json:"service_principal"`
+	ActorDiscordUserID pgtype.Text        `json:"actor_discord_user_id"`
+	Method             string             `json:"method"`
+	Route              string             `json:"route"`
+	Before             []byte             `json:"before"`
+	After              []byte             `json:"after"`
+	OccurredAt         pgtype.Timestamptz `json:"occurred_at"`
+}
+
 type BonusPointLedgerEntry struct {
 	ID                   int64              `json:"id"`
 	PublicID             pgtype.UUID        `json:"public_id"`
@@ -83,6 +116,8 @@ type BonusPointLedgerEntry struct {
 	AwardKey             pgtype.Text        `json:"award_key"`
 	Metadata             []byte             `json:"metadata"`
 	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	ReversesEntryID      pgtype.Int8        `json:"reverses_entry_id"`
+	VoidedAt             pgtype.Timestamptz `json:"voided_at"`
 }
 
 type Contestant struct {
@@ -100,6 +135,31 @@ type DraftPick struct {
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 }
 
+type DraftOverride struct {
+	ID                    int64              `json:"id"`
+	PublicID              pgtype.UUID        `json:"public_id"`
+	InstanceID            int64              `json:"instance_id"`
+	ParticipantID         int64              `json:"participant_id"`
+	ActorDiscordUserID    string             `json:"actor_discord_user_id"`
+	Reason                string             `json:"reason"`
+	DraftDeadline         pgtype.Timestamptz `json:"draft_deadline"`
+	PreviousContestantIds []byte             `json:"previous_contestant_ids"`
+	ContestantIds         []byte             `json:"contestant_ids"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	ID                 int64              `json:"id"`
+	InstanceID         int64              `json:"instance_id"`
+	IdempotencyKey     string             `json:"idempotency_key"`
+	RequestFingerprint string             `json:"request_fingerprint"`
+	StatusCode         pgtype.Int4        `json:"status_code"`
+	ResponseBody       []byte             `json:"response_body"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	CompletedAt        pgtype.Timestamptz `json:"completed_at"`
+	ExpiresAt          pgtype.Timestamptz `json:"expires_at"`
+}
+
 type Import struct {
 	ID               int64              `json:"id"`
 	PublicID         pgtype.UUID        `json:"public_id"`
@@ -113,14 +173,21 @@ type Import struct {
 	SubmissionsCount int32              `json:"submissions_count"`
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	Validation       []byte             `json:"validation"`
+	AppliedAt        pgtype.Timestamptz `json:"applied_at"`
 }
 
 type Instance struct {
-	ID        int64              `json:"id"`
-	PublicID  pgtype.UUID        `json:"public_id"`
-	Name      string             `json:"name"`
-	Season    int32              `json:"season"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID              int64              `json:"id"`
+	PublicID        pgtype.UUID        `json:"public_id"`
+	Name            string             `json:"name"`
+	Season          int32              `json:"season"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	DraftDeadline   pgtype.Timestamptz `json:"draft_deadline"`
+	ScoringStrategy string             `json:"scoring_strategy"`
+	State           string             `json:"state"`
+	StateChangedAt  pgtype.Timestamptz `json:"state_changed_at"`
+	ConfigChecksum  pgtype.Text        `json:"config_checksum"`
 }
 
 type InstanceActivity struct {
@@ -148,6 +215,7 @@ type InstanceContestant struct {
 	InstanceID   int64              `json:"instance_id"`
 	ContestantID int64              `json:"contestant_id"`
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	WinOdds      pgtype.Float8      `json:"win_odds"`
 }
 
 type InstanceEpisode struct {
@@ -162,11 +230,36 @@ type InstanceEpisode struct {
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
 }
 
+type OutboxEvent struct {
+	ID         int64              `json:"id"`
+	PublicID   pgtype.UUID        `json:"public_id"`
+	InstanceID int64              `json:"instance_id"`
+	EventType  string             `json:"event_type"`
+	Payload    []byte             `json:"payload"`
+	OccurredAt pgtype.Timestamptz `json:"occurred_at"`
+}
+
 type OutcomePosition struct {
 	InstanceID   int64              `json:"instance_id"`
 	Position     int32              `json:"position"`
 	ContestantID pgtype.Int8        `json:"contestant_id"`
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	EpisodeID    pgtype.Int8        `json:"episode_id"`
+	Reason       string             `json:"reason"`
+}
+
+type OutcomePositionHistory struct {
+	ID                      int64              `json:"id"`
+	PublicID                pgtype.UUID        `json:"public_id"`
+	InstanceID              int64              `json:"instance_id"`
+	Position                int32              `json:"position"`
+	ContestantID            pgtype.Int8        `json:"contestant_id"`
+	PreviousContestantID    pgtype.Int8        `json:"previous_contestant_id"`
+	EpisodeID               pgtype.Int8        `json:"episode_id"`
+	Reason                  string             `json:"reason"`
+	Note                    pgtype.Text        `json:"note"`
+	RecordedByDiscordUserID pgtype.Text        `json:"recorded_by_discord_user_id"`
+	RecordedAt              pgtype.Timestamptz `json:"recorded_at"`
 }
 
 type Participant struct {
@@ -194,6 +287,8 @@ type ParticipantAdvantage struct {
 	Metadata                   []byte             `json:"metadata"`
 	CreatedAt                  pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt                  pgtype.Timestamptz `json:"updated_at"`
+	Visibility                 string             `json:"visibility"`
+	UsedAt                     pgtype.Timestamptz `json:"used_at"`
 }
 
 type ParticipantGroup struct {
@@ -251,3 +346,54 @@ type ParticipantPonyOwnership struct {
 	CreatedAt                  pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt                  pgtype.Timestamptz `json:"updated_at"`
 }
+
+type ScheduledJob struct {
+	ID          int64              `json:"id"`
+	PublicID    pgtype.UUID        `json:"public_id"`
+	InstanceID  int64              `json:"instance_id"`
+	EpisodeID   int64              `json:"episode_id"`
+	JobType     string             `json:"job_type"`
+	RunAt       pgtype.Timestamptz `json:"run_at"`
+	Status      string             `json:"status"`
+	Attempts    int32              `json:"attempts"`
+	LastError   pgtype.Text        `json:"last_error"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ScheduledJobRun struct {
+	ID             int64              `json:"id"`
+	PublicID       pgtype.UUID        `json:"public_id"`
+	ScheduledJobID int64              `json:"scheduled_job_id"`
+	Status         string             `json:"status"`
+	StartedAt      pgtype.Timestamptz `json:"started_at"`
+	FinishedAt     pgtype.Timestamptz `json:"finished_at"`
+	Result         []byte             `json:"result"`
+	Error          pgtype.Text        `json:"error"`
+}
+
+type WebhookDelivery struct {
+	ID             int64              `json:"id"`
+	PublicID       pgtype.UUID        `json:"public_id"`
+	OutboxEventID  int64              `json:"outbox_event_id"`
+	SubscriptionID int64              `json:"subscription_id"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	NextAttemptAt  pgtype.Timestamptz `json:"next_attempt_at"`
+	LastStatusCode pgtype.Int4        `json:"last_status_code"`
+	LastError      pgtype.Text        `json:"last_error"`
+	DeliveredAt    pgtype.Timestamptz `json:"delivered_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type WebhookSubscription struct {
+	ID         int64              `json:"id"`
+	PublicID   pgtype.UUID        `json:"public_id"`
+	InstanceID int64              `json:"instance_id"`
+	Url        string             `json:"url"`
+	Secret     string             `json:"secret"`
+	EventTypes []string           `json:"event_types"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
@@ -11,24 +11,234 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const getOutcomePosition = `-- name: GetOutcomePosition :one
+SELECT
+    i.public_id AS instance_id,
+    op.position,
+    c.public_id AS contestant_id,
+    ie.public_id AS episode_id,
+    ie.episode_number,
+    ie.label AS episode_label,
+    op.reason,
+    op.updated_at
+FROM outcome_positions op
+JOIN instances i ON i.id = op.instance_id
+LEFT JOIN contestants c ON c.id = op.contestant_id
+LEFT JOIN instance_episodes ie ON ie.id = op.episode_id
+WHERE i.public_id = $1
+  AND op.position = $2
+`
+
+type GetOutcomePositionParams struct {
+	InstanceID pgtype.UUID `json:"instance_id"`
+	Position   int32       `json:"position"`
+}
+
+type GetOutcomePositionRow struct {
+	InstanceID    pgtype.UUID        `json:"instance_id"`
+	Position      int32              `json:"position"`
+	ContestantID  pgtype.UUID        `json:"contestant_id"`
+	EpisodeID     pgtype.UUID        `json:"episode_id"`
+	EpisodeNumber pgtype.Int4        `json:"episode_number"`
+	EpisodeLabel  pgtype.Text        `json:"episode_label"`
+	Reason        string             `json:"reason"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) GetOutcomePosition(ctx context.Context, arg GetOutcomePositionParams) (GetOutcomePositionRow, error) {
+	row := q.db.QueryRow(ctx, getOutcomePosition, arg.InstanceID, arg.Position)
+	var i GetOutcomePositionRow
+	err := row.Scan(
+		&i.InstanceID,
+		&i.Position,
+		&i.ContestantID,
+		&i.EpisodeID,
+		&i.EpisodeNumber,
+		&i.EpisodeLabel,
+		&i.Reason,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listOutcomePositionHistoryByInstance = `-- name: ListOutcomePositionHistoryByInstance :many
+SELECT
+    h.public_id AS id,
+    i.public_id AS instance_id,
+    h.position,
+    c.public_id AS contestant_id,
+    pc.public_id AS previous_contestant_id,
+    ie.public_id AS episode_id,
+    ie.episode_number,
+    ie.label AS episode_label,
+    h.reason,
+    h.note,
+    h.recorded_by_discord_user_id,
+    h.recorded_at
+FROM outcome_position_history h
+JOIN instances i ON i.id = h.instance_id
+LEFT JOIN contestants c ON c.id = h.contestant_id
+LEFT JOIN contestants pc ON pc.id = h.previous_contestant_id
+LEFT JOIN instance_episodes ie ON ie.id = h.episode_id
+WHERE i.public_id = $1
+ORDER BY h.recorded_at ASC, h.id ASC
+`
+
+type ListOutcomePositionHistoryByInstanceRow struct {
+	ID                      pgtype.UUID        `json:"id"`
+	InstanceID              pgtype.UUID        `json:"instance_id"`
+	Position                int32              `json:"position"`
+	ContestantID            pgtype.UUID        `json:"contestant_id"`
+	PreviousContestantID    pgtype.UUID        `json:"previous_contestant_id"`
+	EpisodeID               pgtype.UUID        `json:"episode_id"`
+	EpisodeNumber           pgtype.Int4        `json:"episode_number"`
+	EpisodeLabel            pgtype.Text        `json:"episode_label"`
+	Reason                  string             `json:"reason"`
+	Note                    pgtype.Text        `json:"note"`
+	RecordedByDiscordUserID pgtype.Text        `json:"recorded_by_discord_user_id"`
+	RecordedAt              pgtype.Timestamptz `json:"recorded_at"`
+}
+
+func (q *Queries) ListOutcomePositionHistoryByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListOutcomePositionHistoryByInstanceRow, error) {
+	rows, err := q.db.Query(ctx, listOutcomePositionHistoryByInstance, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOutcomePositionHistoryByInstanceRow{}
+	for rows.Next() {
+		var i ListOutcomePositionHistoryByInstanceRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.InstanceID,
+			&i.Position,
+			&i.ContestantID,
+			&i.PreviousContestantID,
+			&i.EpisodeID,
+			&i.EpisodeNumber,
+			&i.EpisodeLabel,
+			&i.Reason,
+			&i.Note,
+			&i.RecordedByDiscordUserID,
+			&i.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOutcomePositionsAsOf = `-- name: ListOutcomePositionsAsOf :many
+WITH effective AS (
+    SELECT DISTINCT ON (h.position)
+        h.instance_id,
+        h.position,
+        h.contestant_id,
+        h.episode_id,
+        h.reason,
+        h.recorded_at
+    FROM outcome_position_history h
+    JOIN instances i ON i.id = h.instance_id
+    LEFT JOIN instance_episodes ie ON ie.id = h.episode_id
+    WHERE i.public_id = $1
+      AND COALESCE(ie.airs_at, h.recorded_at) <= $2::TIMESTAMPTZ
+    ORDER BY h.position ASC, h.recorded_at DESC, h.id DESC
+)
+SELECT
+    i.public_id AS instance_id,
+    e.position,
+    c.public_id AS contestant_id,
+    ie.public_id AS episode_id,
+    ie.episode_number,
+    ie.label AS episode_label,
+    e.reason,
+    e.recorded_at AS updated_at
+FROM effective e
+JOIN instances i ON i.id = e.instance_id
+JOIN contestants c ON c.id = e.contestant_id
+LEFT JOIN instance_episodes ie ON ie.id = e.episode_id
+ORDER BY e.position ASC
+`
+
+type ListOutcomePositionsAsOfParams struct {
+	InstanceID pgtype.UUID        `json:"instance_id"`
+	AsOf       pgtype.Timestamptz `json:"as_of"`
+}
+
+type ListOutcomePositionsAsOfRow struct {
+	InstanceID    pgtype.UUID        `json:"instance_id"`
+	Position      int32              `json:"position"`
+	ContestantID  pgtype.UUID        `json:"contestant_id"`
+	EpisodeID     pgtype.UUID        `json:"episode_id"`
+	EpisodeNumber pgtype.Int4        `json:"episode_number"`
+	EpisodeLabel  pgtype.Text        `json:"episode_label"`
+	Reason        string             `json:"reason"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Rebuilds the board from the correction history: for each position, the
+// latest recorded row that had taken effect by as_of. A row takes effect when
+// its episode aired, or when it was recorded if it has no episode.
+func (q *Queries) ListOutcomePositionsAsOf(ctx context.Context, arg ListOutcomePositionsAsOfParams) ([]ListOutcomePositionsAsOfRow, error) {
+	rows, err := q.db.Query(ctx, listOutcomePositionsAsOf, arg.InstanceID, arg.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOutcomePositionsAsOfRow{}
+	for rows.Next() {
+		var i ListOutcomePositionsAsOfRow
+		if err := rows.Scan(
+			&i.InstanceID,
+			&i.Position,
+			&i.ContestantID,
+			&i.EpisodeID,
+			&i.EpisodeNumber,
+			&i.EpisodeLabel,
+			&i.Reason,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listOutcomePositionsByInstance = `-- name: ListOutcomePositionsByInstance :many
 SELECT
     i.public_id AS instance_id,
     op.position,
     c.public_id AS contestant_id,
+    ie.public_id AS episode_id,
+    ie.episode_number,
+    ie.label AS episode_label,
+    op.reason,
     op.updated_at
 FROM outcome_positions op
 JOIN instances i ON i.id = op.instance_id
 LEFT JOIN contestants c ON c.id = op.contestant_id
+LEFT JOIN instance_episodes ie ON ie.id = op.episode_id
 WHERE i.public_id = $1
 ORDER BY op.position ASC
 `
 
 type ListOutcomePositionsByInstanceRow struct {
-	InstanceID   pgtype.UUID        `json:"instance_id"`
-	Position     int32              `json:"position"`
-	ContestantID pgtype.UUID        `json:"contestant_id"`
-	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	InstanceID    pgtype.UUID        `json:"instance_id"`
+	Position      int32              `json:"position"`
+	ContestantID  pgtype.UUID        `json:"contestant_id"`
+	EpisodeID     pgtype.UUID        `json:"episode_id"`
+	EpisodeNumber pgtype.Int4        `json:"episode_number"`
+	EpisodeLabel  pgtype.Text        `json:"episode_label"`
+	Reason        string             `json:"reason"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
 }
 
 func (q *Queries) ListOutcomePositionsByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListOutcomePositionsByInstanceRow, error) {
@@ -44,6 +254,10 @@ func (q *Queries) ListOutcomePositionsByInstance(ctx context.Context, instanceID
 			&i.InstanceID,
 			&i.Position,
 			&i.ContestantID,
+			&i.EpisodeID,
+			&i.EpisodeNumber,
+			&i.EpisodeLabel,
+			&i.Reason,
 			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
@@ -67,46 +281,103 @@ WITH resolved_instance AS (
     JOIN instance_contestants ic ON ic.contestant_id = c.id
     JOIN resolved_instance ri ON ri.instance_internal_id = ic.instance_id
     WHERE c.public_id = $2
+), resolved_episode AS (
+    SELECT ie.id AS episode_internal_id, ie.public_id AS episode_id
+    FROM instance_episodes ie
+    JOIN resolved_instance ri ON ri.instance_internal_id = ie.instance_id
+    WHERE ie.public_id = $3
+), previous AS (
+    SELECT op.contestant_id AS previous_contestant_internal_id
+    FROM outcome_positions op
+    JOIN resolved_instance ri ON ri.instance_internal_id = op.instance_id
+    WHERE op.position = $4
 ), upserted AS (
-    INSERT INTO outcome_positions (instance_id, position, contestant_id, updated_at)
+    INSERT INTO outcome_positions (instance_id, position, contestant_id, episode_id, reason, updated_at)
     VALUES (
         (SELECT instance_internal_id FROM resolved_instance),
-        $3,
+        $4,
         (SELECT contestant_internal_id FROM resolved_contestant),
+        (SELECT episode_internal_id FROM resolved_episode),
+        COALESCE(NULLIF($5::TEXT, ''), 'voted_out'),
         NOW()
     )
     ON CONFLICT (instance_id, position)
-    DO UPDATE SET contestant_id = EXCLUDED.contestant_id, updated_at = NOW()
-    RETURNING instance_id, position, contestant_id, updated_at
+    DO UPDATE SET
+        contestant_id = EXCLUDED.contestant_id,
+        episode_id = EXCLUDED.episode_id,
+        reason = EXCLUDED.reason,
+        updated_at = NOW()
+    RETURNING instance_id, position, contestant_id, episode_id, reason, updated_at
+), history AS (
+    INSERT INTO outcome_position_history (
+        instance_id,
+        position,
+        contestant_id,
+        previous_contestant_id,
+        episode_id,
+        reason,
+        note,
+        recorded_by_discord_user_id,
+        recorded_at
+    )
+    SELECT
+        upserted.instance_id,
+        upserted.position,
+        upserted.contestant_id,
+        (SELECT previous_contestant_internal_id FROM previous),
+        upserted.episode_id,
+        upserted.reason,
+        $6::TEXT,
+        $7::TEXT,
+        upserted.updated_at
+    FROM upserted
 )
 SELECT
     (SELECT instance_id FROM resolved_instance) AS instance_id,
     upserted.position,
     (SELECT contestant_id FROM resolved_contestant) AS contestant_id,
+    (SELECT episode_id FROM resolved_episode) AS episode_id,
+    upserted.reason,
     upserted.updated_at
 FROM upserted
 `
 
 type UpsertOutcomePositionParams struct {
-	InstanceID   pgtype.UUID `json:"instance_id"`
-	ContestantID pgtype.UUID `json:"contestant_id"`
-	Position     int32       `json:"position"`
+	InstanceID              pgtype.UUID `json:"instance_id"`
+	ContestantID            pgtype.UUID `json:"contestant_id"`
+	EpisodeID               pgtype.UUID `json:"episode_id"`
+	Position                int32       `json:"position"`
+	Reason                  string      `json:"reason"`
+	Note                    pgtype.Text `json:"note"`
+	RecordedByDiscordUserID pgtype.Text `json:"recorded_by_discord_user_id"`
 }
 
 type UpsertOutcomePositionRow struct {
 	InstanceID   pgtype.UUID        `json:"instance_id"`
 	Position     int32              `json:"position"`
 	ContestantID pgtype.UUID        `json:"contestant_id"`
+	EpisodeID    pgtype.UUID        `json:"episode_id"`
+	Reason       string             `json:"reason"`
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
 }
 
 func (q *Queries) UpsertOutcomePosition(ctx context.Context, arg UpsertOutcomePositionParams) (UpsertOutcomePositionRow, error) {
-	row := q.db.QueryRow(ctx, upsertOutcomePosition, arg.InstanceID, arg.ContestantID, arg.Position)
+	row := q.db.QueryRow(ctx, upsertOutcomePosition,
+		arg.InstanceID,
+		arg.ContestantID,
+		arg.EpisodeID,
+		arg.Position,
+		arg.Reason,
+		arg.Note,
+		arg.RecordedByDiscordUserID,
+	)
 	var i UpsertOutcomePositionRow
 	err := row.Scan(
 		&i.InstanceID,
 		&i.Position,
 		&i.ContestantID,
+		&i.EpisodeID,
+		&i.Reason,
 		&i.UpdatedAt,
 	)
 	return i, err
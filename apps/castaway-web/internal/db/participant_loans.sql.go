@@ -216,6 +216,80 @@ func (q *Queries) GetActiveParticipantLoanByParticipant(ctx context.Context, arg
 	return i, err
 }
 
+const getLatestDefaultedParticipantLoanByParticipant = `-- name: GetLatestDefaultedParticipantLoanByParticipant :one
+SELECT
+    pl.public_id AS id,
+    i.public_id AS instance_id,
+    p.public_id AS participant_id,
+    ia.public_id AS activity_id,
+    pl.status,
+    pl.principal_points,
+    pl.interest_points,
+    pl.principal_repaid_points,
+    pl.interest_repaid_points,
+    pl.granted_at,
+    pl.due_at,
+    pl.settled_at,
+    pl.metadata,
+    pl.created_at,
+    pl.updated_at
+FROM participant_loans pl
+JOIN instances i ON i.id = pl.instance_id
+JOIN participants p ON p.id = pl.participant_id
+LEFT JOIN instance_activities ia ON ia.id = pl.activity_id
+WHERE i.public_id = $1
+  AND p.public_id = $2
+  AND pl.status = 'defaulted'
+ORDER BY pl.settled_at DESC, pl.id DESC
+LIMIT 1
+`
+
+type GetLatestDefaultedParticipantLoanByParticipantParams struct {
+	InstanceID    pgtype.UUID `json:"instance_id"`
+	ParticipantID pgtype.UUID `json:"participant_id"`
+}
+
+type GetLatestDefaultedParticipantLoanByParticipantRow struct {
+	ID                    pgtype.UUID        `json:"id"`
+	InstanceID            pgtype.UUID        `json:"instance_id"`
+	ParticipantID         pgtype.UUID        `json:"participant_id"`
+	ActivityID            pgtype.UUID        `json:"activity_id"`
+	Status                string             `json:"status"`
+	PrincipalPoints       int32              `json:"principal_points"`
+	InterestPoints        int32              `json:"interest_points"`
+	PrincipalRepaidPoints int32              `json:"principal_repaid_points"`
+	InterestRepaidPoints  int32              `json:"interest_repaid_points"`
+	GrantedAt             pgtype.Timestamptz `json:"granted_at"`
+	DueAt                 pgtype.Timestamptz `json:"due_at"`
+	SettledAt             pgtype.Timestamptz `json:"settled_at"`
+	Metadata              []byte             `json:"metadata"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) GetLatestDefaultedParticipantLoanByParticipant(ctx context.Context, arg GetLatestDefaultedParticipantLoanByParticipantParams) (GetLatestDefaultedParticipantLoanByParticipantRow, error) {
+	row := q.db.QueryRow(ctx, getLatestDefaultedParticipantLoanByParticipant, arg.InstanceID, arg.ParticipantID)
+	var i GetLatestDefaultedParticipantLoanByParticipantRow
+	err := row.Scan(
+		&i.ID,
+		&i.InstanceID,
+		&i.ParticipantID,
+		&i.ActivityID,
+		&i.Status,
+		&i.PrincipalPoints,
+		&i.InterestPoints,
+		&i.PrincipalRepaidPoints,
+		&i.InterestRepaidPoints,
+		&i.GrantedAt,
+		&i.DueAt,
+		&i.SettledAt,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const listActiveParticipantLoansByInstance = `-- name: ListActiveParticipantLoansByInstance :many
 SELECT
     pl.public_id AS id,
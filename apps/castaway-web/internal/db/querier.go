@@ -11,46 +11,100 @@ import (
 )
 
 type Querier interface {
+	ClaimDueScheduledJob(ctx context.Context, now pgtype.Timestamptz) (ClaimDueScheduledJobRow, error)
+	ClaimDueWebhookDelivery(ctx context.Context, now pgtype.Timestamptz) (ClaimDueWebhookDeliveryRow, error)
+	ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (int64, error)
 	ClearParticipantDiscordUserID(ctx context.Context, id pgtype.UUID) (ClearParticipantDiscordUserIDRow, error)
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error
+	CompleteScheduledJob(ctx context.Context, arg CompleteScheduledJobParams) error
+	CompleteWebhookDelivery(ctx context.Context, arg CompleteWebhookDeliveryParams) error
 	CountInstanceAdmins(ctx context.Context, instanceID pgtype.UUID) (int64, error)
+	CountInstancesByPublicIDs(ctx context.Context, instanceIds []pgtype.UUID) (int64, error)
+	CreateAPICredential(ctx context.Context, arg CreateAPICredentialParams) (CreateAPICredentialRow, error)
 	CreateActivityGroupAssignment(ctx context.Context, arg CreateActivityGroupAssignmentParams) (CreateActivityGroupAssignmentRow, error)
 	CreateActivityOccurrence(ctx context.Context, arg CreateActivityOccurrenceParams) (CreateActivityOccurrenceRow, error)
 	CreateActivityOccurrenceGroup(ctx context.Context, arg CreateActivityOccurrenceGroupParams) (CreateActivityOccurrenceGroupRow, error)
 	CreateActivityOccurrenceParticipant(ctx context.Context, arg CreateActivityOccurrenceParticipantParams) (CreateActivityOccurrenceParticipantRow, error)
 	CreateActivityParticipantAssignment(ctx context.Context, arg CreateActivityParticipantAssignmentParams) (CreateActivityParticipantAssignmentRow, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error
 	CreateBonusPointLedgerEntry(ctx context.Context, arg CreateBonusPointLedgerEntryParams) (CreateBonusPointLedgerEntryRow, error)
 	CreateContestant(ctx context.Context, arg CreateContestantParams) (CreateContestantRow, error)
+	CreateDraftOverride(ctx context.Context, arg CreateDraftOverrideParams) (CreateDraftOverrideRow, error)
 	CreateDraftPick(ctx context.Context, arg CreateDraftPickParams) (CreateDraftPickRow, error)
+	CreateGlobalAuditEvent(ctx context.Context, arg CreateGlobalAuditEventParams) error
+	CreateImport(ctx context.Context, arg CreateImportParams) (CreateImportRow, error)
 	CreateInstance(ctx context.Context, arg CreateInstanceParams) (CreateInstanceRow, error)
 	CreateInstanceActivity(ctx context.Context, arg CreateInstanceActivityParams) (CreateInstanceActivityRow, error)
 	CreateInstanceAdmin(ctx context.Context, arg CreateInstanceAdminParams) (InstanceAdmin, error)
 	CreateInstanceEpisode(ctx context.Context, arg CreateInstanceEpisodeParams) (CreateInstanceEpisodeRow, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) error
 	CreateParticipant(ctx context.Context, arg CreateParticipantParams) (CreateParticipantRow, error)
 	CreateParticipantAdvantage(ctx context.Context, arg CreateParticipantAdvantageParams) (CreateParticipantAdvantageRow, error)
 	CreateParticipantGroup(ctx context.Context, arg CreateParticipantGroupParams) (CreateParticipantGroupRow, error)
 	CreateParticipantGroupMembershipPeriod(ctx context.Context, arg CreateParticipantGroupMembershipPeriodParams) (CreateParticipantGroupMembershipPeriodRow, error)
 	CreateParticipantLoan(ctx context.Context, arg CreateParticipantLoanParams) (CreateParticipantLoanRow, error)
 	CreateParticipantPonyOwnership(ctx context.Context, arg CreateParticipantPonyOwnershipParams) (CreateParticipantPonyOwnershipRow, error)
+	CreateScheduledJobRun(ctx context.Context, arg CreateScheduledJobRunParams) error
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (CreateWebhookSubscriptionRow, error)
 	DeleteDraftPicksForParticipant(ctx context.Context, participantID pgtype.UUID) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now pgtype.Timestamptz) error
+	DeleteIdempotencyKey(ctx context.Context, id int64) error
+	DeleteInstanceActivities(ctx context.Context, instanceID pgtype.UUID) error
 	DeleteInstanceAdmin(ctx context.Context, arg DeleteInstanceAdminParams) error
+	DeleteInstanceAdmins(ctx context.Context, instanceID pgtype.UUID) error
 	DeleteInstanceByNameSeason(ctx context.Context, arg DeleteInstanceByNameSeasonParams) error
+	DeleteInstanceContestants(ctx context.Context, instanceID pgtype.UUID) error
+	DeleteInstanceEpisode(ctx context.Context, id pgtype.UUID) error
+	DeleteInstanceEpisodes(ctx context.Context, instanceID pgtype.UUID) error
+	DeleteInstanceOutcomeHistory(ctx context.Context, instanceID pgtype.UUID) error
+	DeleteInstanceOutcomes(ctx context.Context, instanceID pgtype.UUID) error
+	DeleteInstanceParticipantGroups(ctx context.Context, instanceID pgtype.UUID) error
+	DeleteInstanceParticipants(ctx context.Context, instanceID pgtype.UUID) error
+	DeleteWebhookSubscription(ctx context.Context, arg DeleteWebhookSubscriptionParams) (DeleteWebhookSubscriptionRow, error)
+	EndActivityGroupAssignment(ctx context.Context, arg EndActivityGroupAssignmentParams) (int64, error)
+	EndParticipantGroupMembershipPeriods(ctx context.Context, arg EndParticipantGroupMembershipPeriodsParams) ([]EndParticipantGroupMembershipPeriodsRow, error)
+	ExpireParticipantAdvantages(ctx context.Context, arg ExpireParticipantAdvantagesParams) (int64, error)
+	FailScheduledJob(ctx context.Context, arg FailScheduledJobParams) error
+	FailWebhookDelivery(ctx context.Context, arg FailWebhookDeliveryParams) error
+	GetActiveAPICredentialByTokenHash(ctx context.Context, arg GetActiveAPICredentialByTokenHashParams) (GetActiveAPICredentialByTokenHashRow, error)
 	GetActiveParticipantLoanByParticipant(ctx context.Context, arg GetActiveParticipantLoanByParticipantParams) (GetActiveParticipantLoanByParticipantRow, error)
 	GetActivityOccurrence(ctx context.Context, id pgtype.UUID) (GetActivityOccurrenceRow, error)
 	GetActivityOccurrenceParticipant(ctx context.Context, arg GetActivityOccurrenceParticipantParams) (GetActivityOccurrenceParticipantRow, error)
 	GetAvailableSecretBalanceByParticipant(ctx context.Context, arg GetAvailableSecretBalanceByParticipantParams) (int32, error)
 	GetContestant(ctx context.Context, id pgtype.UUID) (GetContestantRow, error)
 	GetCurrentEpisodeAt(ctx context.Context, arg GetCurrentEpisodeAtParams) (GetCurrentEpisodeAtRow, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (GetIdempotencyKeyRow, error)
+	GetImport(ctx context.Context, id pgtype.UUID) (GetImportRow, error)
+	GetImportForUpdate(ctx context.Context, id pgtype.UUID) (GetImportForUpdateRow, error)
 	GetInstance(ctx context.Context, id pgtype.UUID) (GetInstanceRow, error)
 	GetInstanceActivity(ctx context.Context, id pgtype.UUID) (GetInstanceActivityRow, error)
+	GetInstanceConfigByNameSeason(ctx context.Context, arg GetInstanceConfigByNameSeasonParams) (GetInstanceConfigByNameSeasonRow, error)
+	GetInstanceDraftDeadline(ctx context.Context, id pgtype.UUID) (pgtype.Timestamptz, error)
+	GetInstanceEpisode(ctx context.Context, arg GetInstanceEpisodeParams) (GetInstanceEpisodeRow, error)
+	GetInstanceEpisodeUsage(ctx context.Context, id pgtype.UUID) (GetInstanceEpisodeUsageRow, error)
+	GetInstanceScoringStrategy(ctx context.Context, id pgtype.UUID) (string, error)
+	GetInstanceState(ctx context.Context, id pgtype.UUID) (string, error)
+	GetInstanceStateByActivity(ctx context.Context, activityID pgtype.UUID) (GetInstanceStateByActivityRow, error)
+	GetInstanceStateByOccurrence(ctx context.Context, occurrenceID pgtype.UUID) (GetInstanceStateByOccurrenceRow, error)
+	GetInstanceStateForUpdate(ctx context.Context, id pgtype.UUID) (string, error)
+	GetLatestDefaultedParticipantLoanByParticipant(ctx context.Context, arg GetLatestDefaultedParticipantLoanByParticipantParams) (GetLatestDefaultedParticipantLoanByParticipantRow, error)
+	GetOutcomePosition(ctx context.Context, arg GetOutcomePositionParams) (GetOutcomePositionRow, error)
 	GetParticipant(ctx context.Context, id pgtype.UUID) (GetParticipantRow, error)
+	GetParticipantAdvantage(ctx context.Context, id pgtype.UUID) (GetParticipantAdvantageRow, error)
 	GetParticipantByDiscordUserID(ctx context.Context, arg GetParticipantByDiscordUserIDParams) (GetParticipantByDiscordUserIDRow, error)
 	GetParticipantGroup(ctx context.Context, id pgtype.UUID) (GetParticipantGroupRow, error)
 	GetSecretBonusTotalByParticipant(ctx context.Context, arg GetSecretBonusTotalByParticipantParams) (int32, error)
 	GetVisibleBonusTotalByParticipant(ctx context.Context, arg GetVisibleBonusTotalByParticipantParams) (int32, error)
 	GetVisibleBonusTotalByParticipantAsOf(ctx context.Context, arg GetVisibleBonusTotalByParticipantAsOfParams) (int32, error)
+	GetWebhookDelivery(ctx context.Context, arg GetWebhookDeliveryParams) (GetWebhookDeliveryRow, error)
 	InstanceHasContestant(ctx context.Context, arg InstanceHasContestantParams) (bool, error)
 	IsInstanceAdmin(ctx context.Context, arg IsInstanceAdminParams) (bool, error)
+	// Pushes a claimed delivery's next attempt past the time its request can
+	// take, so other dispatchers leave it alone once the claim commits.
+	LeaseWebhookDelivery(ctx context.Context, arg LeaseWebhookDeliveryParams) error
+	ListAPICredentials(ctx context.Context) ([]ListAPICredentialsRow, error)
 	ListActiveActivityGroupAssignmentsAt(ctx context.Context, arg ListActiveActivityGroupAssignmentsAtParams) ([]ListActiveActivityGroupAssignmentsAtRow, error)
+	ListActiveActivityGroupAssignmentsByInstanceAt(ctx context.Context, arg ListActiveActivityGroupAssignmentsByInstanceAtParams) ([]ListActiveActivityGroupAssignmentsByInstanceAtRow, error)
 	ListActiveActivityParticipantAssignmentsAt(ctx context.Context, arg ListActiveActivityParticipantAssignmentsAtParams) ([]ListActiveActivityParticipantAssignmentsAtRow, error)
 	ListActiveAdvantagesByTypeForGroup(ctx context.Context, arg ListActiveAdvantagesByTypeForGroupParams) ([]ListActiveAdvantagesByTypeForGroupRow, error)
 	ListActiveAdvantagesByTypeForParticipant(ctx context.Context, arg ListActiveAdvantagesByTypeForParticipantParams) ([]ListActiveAdvantagesByTypeForParticipantRow, error)
@@ -66,8 +120,11 @@ type Querier interface {
 	ListActivityOccurrencesByActivityAndStatus(ctx context.Context, arg ListActivityOccurrencesByActivityAndStatusParams) ([]ListActivityOccurrencesByActivityAndStatusRow, error)
 	ListActivityParticipantAssignments(ctx context.Context, activityID pgtype.UUID) ([]ListActivityParticipantAssignmentsRow, error)
 	ListAllBonusPointLedgerEntriesForParticipant(ctx context.Context, arg ListAllBonusPointLedgerEntriesForParticipantParams) ([]ListAllBonusPointLedgerEntriesForParticipantRow, error)
+	ListAuditEventsByInstance(ctx context.Context, arg ListAuditEventsByInstanceParams) ([]ListAuditEventsByInstanceRow, error)
+	ListContestantOddsByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListContestantOddsByInstanceRow, error)
 	ListContestantsByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListContestantsByInstanceRow, error)
 	ListContestantsGlobal(ctx context.Context) ([]ListContestantsGlobalRow, error)
+	ListDraftOverridesByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListDraftOverridesByInstanceRow, error)
 	ListDraftPicksForInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListDraftPicksForInstanceRow, error)
 	ListDraftPicksForParticipant(ctx context.Context, participantID pgtype.UUID) ([]ListDraftPicksForParticipantRow, error)
 	ListEpisodeBoundaryWindows(ctx context.Context, instanceID pgtype.UUID) ([]ListEpisodeBoundaryWindowsRow, error)
@@ -76,16 +133,47 @@ type Querier interface {
 	ListInstanceAdmins(ctx context.Context, instanceID pgtype.UUID) ([]ListInstanceAdminsRow, error)
 	ListInstanceEpisodes(ctx context.Context, instanceID pgtype.UUID) ([]ListInstanceEpisodesRow, error)
 	ListInstances(ctx context.Context) ([]ListInstancesRow, error)
+	ListLiveBonusPointLedgerEntriesByOccurrence(ctx context.Context, activityOccurrenceID pgtype.UUID) ([]ListLiveBonusPointLedgerEntriesByOccurrenceRow, error)
+	// Events come back oldest first. after_event_id resumes from an event a
+	// reader has already seen; since bounds a reader with no cursor yet.
+	ListOutboxEventsByInstance(ctx context.Context, arg ListOutboxEventsByInstanceParams) ([]ListOutboxEventsByInstanceRow, error)
+	ListOutcomePositionHistoryByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListOutcomePositionHistoryByInstanceRow, error)
+	// Rebuilds the board from the correction history: for each position, the
+	// latest recorded row that had taken effect by as_of. A row takes effect when
+	// its episode aired, or when it was recorded if it has no episode.
+	ListOutcomePositionsAsOf(ctx context.Context, arg ListOutcomePositionsAsOfParams) ([]ListOutcomePositionsAsOfRow, error)
 	ListOutcomePositionsByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListOutcomePositionsByInstanceRow, error)
+	ListParticipantAdvantagesByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListParticipantAdvantagesByInstanceRow, error)
 	ListParticipantGroupMembershipPeriods(ctx context.Context, participantGroupID pgtype.UUID) ([]ListParticipantGroupMembershipPeriodsRow, error)
 	ListParticipantGroupsByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListParticipantGroupsByInstanceRow, error)
 	ListParticipantOccurrenceInvolvementByInstance(ctx context.Context, arg ListParticipantOccurrenceInvolvementByInstanceParams) ([]ListParticipantOccurrenceInvolvementByInstanceRow, error)
 	ListParticipantsByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListParticipantsByInstanceRow, error)
+	ListScheduledJobRuns(ctx context.Context, arg ListScheduledJobRunsParams) ([]ListScheduledJobRunsRow, error)
+	ListScheduledJobsByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListScheduledJobsByInstanceRow, error)
 	ListVisibleBonusPointLedgerEntriesByOccurrence(ctx context.Context, activityOccurrenceID pgtype.UUID) ([]ListVisibleBonusPointLedgerEntriesByOccurrenceRow, error)
 	ListVisibleBonusPointLedgerEntriesForParticipant(ctx context.Context, arg ListVisibleBonusPointLedgerEntriesForParticipantParams) ([]ListVisibleBonusPointLedgerEntriesForParticipantRow, error)
+	ListWebhookDeliveriesByInstance(ctx context.Context, arg ListWebhookDeliveriesByInstanceParams) ([]ListWebhookDeliveriesByInstanceRow, error)
+	ListWebhookSubscriptionsByInstance(ctx context.Context, instanceID pgtype.UUID) ([]ListWebhookSubscriptionsByInstanceRow, error)
 	MarkAdvantageUsed(ctx context.Context, id pgtype.UUID) error
+	MarkImportApplied(ctx context.Context, arg MarkImportAppliedParams) error
+	PlanEpisodeJobs(ctx context.Context, arg PlanEpisodeJobsParams) (int64, error)
+	PlayParticipantAdvantage(ctx context.Context, arg PlayParticipantAdvantageParams) (int64, error)
+	ResetInstance(ctx context.Context, arg ResetInstanceParams) error
+	RetryScheduledJob(ctx context.Context, arg RetryScheduledJobParams) (RetryScheduledJobRow, error)
+	RetryWebhookDelivery(ctx context.Context, arg RetryWebhookDeliveryParams) (int64, error)
+	ReverseBonusPointLedgerEntry(ctx context.Context, arg ReverseBonusPointLedgerEntryParams) (ReverseBonusPointLedgerEntryRow, error)
+	RevokeAPICredential(ctx context.Context, arg RevokeAPICredentialParams) (RevokeAPICredentialRow, error)
+	RotateAPICredential(ctx context.Context, arg RotateAPICredentialParams) (RotateAPICredentialRow, error)
+	SetContestantOdds(ctx context.Context, arg SetContestantOddsParams) (SetContestantOddsRow, error)
+	SetInstanceConfigChecksum(ctx context.Context, arg SetInstanceConfigChecksumParams) error
+	SetInstanceDraftDeadline(ctx context.Context, arg SetInstanceDraftDeadlineParams) (pgtype.Timestamptz, error)
+	SetInstanceScoringStrategy(ctx context.Context, arg SetInstanceScoringStrategyParams) (string, error)
+	SetInstanceState(ctx context.Context, arg SetInstanceStateParams) (SetInstanceStateRow, error)
 	SetParticipantDiscordUserID(ctx context.Context, arg SetParticipantDiscordUserIDParams) (SetParticipantDiscordUserIDRow, error)
+	TouchAPICredential(ctx context.Context, arg TouchAPICredentialParams) error
 	UpdateActivityOccurrenceStatusAndMetadata(ctx context.Context, arg UpdateActivityOccurrenceStatusAndMetadataParams) (UpdateActivityOccurrenceStatusAndMetadataRow, error)
+	UpdateImportValidation(ctx context.Context, arg UpdateImportValidationParams) error
+	UpdateInstanceEpisode(ctx context.Context, arg UpdateInstanceEpisodeParams) (UpdateInstanceEpisodeRow, error)
 	UpdateInstanceName(ctx context.Context, arg UpdateInstanceNameParams) (UpdateInstanceNameRow, error)
 	UpdateParticipantLoan(ctx context.Context, arg UpdateParticipantLoanParams) (UpdateParticipantLoanRow, error)
 	UpsertActivityOccurrenceParticipant(ctx context.Context, arg UpsertActivityOccurrenceParticipantParams) (UpsertActivityOccurrenceParticipantRow, error)
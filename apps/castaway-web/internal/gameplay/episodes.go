@@ -0,0 +1,180 @@
+package gameplay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrEpisodeConflict is returned when an episode change would break the
+// schedule or records that depend on it.
+var ErrEpisodeConflict = errors.New("episode conflict")
+
+type CreateEpisodeParams struct {
+	InstanceID    pgtype.UUID
+	EpisodeNumber int32
+	Label         string
+	AirsAt        time.Time
+	Metadata      []byte
+}
+
+// CreateEpisode adds an episode to an instance schedule. Episode numbers must
+// air in order, so the new episode has to fall between its neighbours.
+func (s *Service) CreateEpisode(ctx context.Context, params CreateEpisodeParams) (db.CreateInstanceEpisodeRow, error) {
+	episodes, err := s.queries.ListInstanceEpisodes(ctx, params.InstanceID)
+	if err != nil {
+		return db.CreateInstanceEpisodeRow{}, fmt.Errorf("list instance episodes: %w", err)
+	}
+	schedule := episodeSchedule(episodes, pgtype.UUID{})
+	schedule = append(schedule, EpisodeTemplate{EpisodeNumber: params.EpisodeNumber, Label: params.Label, AirsAt: params.AirsAt})
+	if err := requireOrderedSchedule(schedule); err != nil {
+		return db.CreateInstanceEpisodeRow{}, err
+	}
+
+	return s.queries.CreateInstanceEpisode(ctx, db.CreateInstanceEpisodeParams{
+		EpisodeNumber: params.EpisodeNumber,
+		Label:         params.Label,
+		AirsAt:        timestamptz(params.AirsAt),
+		Metadata:      jsonbOrEmpty(params.Metadata),
+		InstanceID:    params.InstanceID,
+	})
+}
+
+type UpdateEpisodeParams struct {
+	InstanceID pgtype.UUID
+	EpisodeID  pgtype.UUID
+	Label      *string
+	AirsAt     *time.Time
+}
+
+// UpdateEpisode relabels or reschedules an episode. Rescheduling is refused
+// while memberships, assignments, activities or occurrences start or end at
+// the episode's current air time, since they would no longer sit on an
+// episode boundary.
+func (s *Service) UpdateEpisode(ctx context.Context, params UpdateEpisodeParams) (db.UpdateInstanceEpisodeRow, error) {
+	episode, err := s.queries.GetInstanceEpisode(ctx, db.GetInstanceEpisodeParams{
+		InstanceID: params.InstanceID,
+		ID:         params.EpisodeID,
+	})
+	if err != nil {
+		return db.UpdateInstanceEpisodeRow{}, fmt.Errorf("get instance episode: %w", err)
+	}
+
+	label := episode.Label
+	if params.Label != nil {
+		label = *params.Label
+	}
+	airsAt := episode.AirsAt.Time
+	if params.AirsAt != nil && !params.AirsAt.Equal(airsAt) {
+		airsAt = *params.AirsAt
+		usage, err := s.queries.GetInstanceEpisodeUsage(ctx, params.EpisodeID)
+		if err != nil {
+			return db.UpdateInstanceEpisodeRow{}, fmt.Errorf("get episode usage: %w", err)
+		}
+		if dependents := boundaryDependents(usage); len(dependents) > 0 {
+			return db.UpdateInstanceEpisodeRow{}, fmt.Errorf("%w: episode %d cannot be rescheduled while %s start or end when it airs", ErrEpisodeConflict, episode.EpisodeNumber, strings.Join(dependents, ", "))
+		}
+
+		episodes, err := s.queries.ListInstanceEpisodes(ctx, params.InstanceID)
+		if err != nil {
+			return db.UpdateInstanceEpisodeRow{}, fmt.Errorf("list instance episodes: %w", err)
+		}
+		schedule := episodeSchedule(episodes, params.EpisodeID)
+		schedule = append(schedule, EpisodeTemplate{EpisodeNumber: episode.EpisodeNumber, Label: label, AirsAt: airsAt})
+		if err := requireOrderedSchedule(schedule); err != nil {
+			return db.UpdateInstanceEpisodeRow{}, err
+		}
+	}
+
+	return s.queries.UpdateInstanceEpisode(ctx, db.UpdateInstanceEpisodeParams{
+		Label:  label,
+		AirsAt: timestamptz(airsAt),
+		ID:     params.EpisodeID,
+	})
+}
+
+// DeleteEpisode removes an episode nothing depends on and returns it.
+// Episodes that anchor a boundary or have outcomes recorded against them are
+// kept.
+func (s *Service) DeleteEpisode(ctx context.Context, instanceID, episodeID pgtype.UUID) (db.GetInstanceEpisodeRow, error) {
+	episode, err := s.queries.GetInstanceEpisode(ctx, db.GetInstanceEpisodeParams{
+		InstanceID: instanceID,
+		ID:         episodeID,
+	})
+	if err != nil {
+		return db.GetInstanceEpisodeRow{}, fmt.Errorf("get instance episode: %w", err)
+	}
+	usage, err := s.queries.GetInstanceEpisodeUsage(ctx, episodeID)
+	if err != nil {
+		return db.GetInstanceEpisodeRow{}, fmt.Errorf("get episode usage: %w", err)
+	}
+	dependents := boundaryDependents(usage)
+	dependents = appendUsage(dependents, usage.Outcomes+usage.OutcomeHistory, "outcome record", "outcome records")
+	if len(dependents) > 0 {
+		return db.GetInstanceEpisodeRow{}, fmt.Errorf("%w: episode %d cannot be deleted while it is used by %s", ErrEpisodeConflict, episode.EpisodeNumber, strings.Join(dependents, ", "))
+	}
+	if err := s.queries.DeleteInstanceEpisode(ctx, episodeID); err != nil {
+		return db.GetInstanceEpisodeRow{}, err
+	}
+	return episode, nil
+}
+
+// episodeSchedule converts episodes to templates, leaving out the episode
+// being changed so its new values can be appended.
+func episodeSchedule(episodes []db.ListInstanceEpisodesRow, skip pgtype.UUID) []EpisodeTemplate {
+	schedule := make([]EpisodeTemplate, 0, len(episodes)+1)
+	for _, episode := range episodes {
+		if skip.Valid && episode.ID == skip {
+			continue
+		}
+		schedule = append(schedule, EpisodeTemplate{
+			EpisodeNumber: episode.EpisodeNumber,
+			Label:         episode.Label,
+			AirsAt:        episode.AirsAt.Time,
+		})
+	}
+	return schedule
+}
+
+func requireOrderedSchedule(schedule []EpisodeTemplate) error {
+	sort.Slice(schedule, func(i, j int) bool {
+		return schedule[i].EpisodeNumber < schedule[j].EpisodeNumber
+	})
+	for index := 1; index < len(schedule); index++ {
+		previous, current := schedule[index-1], schedule[index]
+		if previous.EpisodeNumber == current.EpisodeNumber {
+			return fmt.Errorf("%w: episode %d already exists", ErrEpisodeConflict, current.EpisodeNumber)
+		}
+		if !current.AirsAt.After(previous.AirsAt) {
+			return fmt.Errorf("%w: episode %d must air after episode %d", ErrEpisodeConflict, current.EpisodeNumber, previous.EpisodeNumber)
+		}
+	}
+	return nil
+}
+
+func boundaryDependents(usage db.GetInstanceEpisodeUsageRow) []string {
+	dependents := []string{}
+	dependents = appendUsage(dependents, usage.MembershipPeriods, "group membership", "group memberships")
+	dependents = appendUsage(dependents, usage.ActivityGroupAssignments, "activity group assignment", "activity group assignments")
+	dependents = appendUsage(dependents, usage.ActivityParticipantAssignments, "activity participant assignment", "activity participant assignments")
+	dependents = appendUsage(dependents, usage.Activities, "activity", "activities")
+	dependents = appendUsage(dependents, usage.Occurrences, "occurrence", "occurrences")
+	return dependents
+}
+
+func appendUsage(dependents []string, count int64, singular, plural string) []string {
+	switch {
+	case count == 1:
+		return append(dependents, "1 "+singular)
+	case count > 1:
+		return append(dependents, fmt.Sprintf("%d %s", count, plural))
+	default:
+		return dependents
+	}
+}
@@ -0,0 +1,46 @@
+package gameplay
+
+import (
+	"context"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PreviewActivityOccurrence runs the occurrence's resolver exactly as
+// ResolveActivityOccurrence does and returns the ledger entries it would
+// create, without writing the ledger entries or marking anything resolved.
+// The returned rows have no ID or created_at because they were never
+// inserted. Other writes a resolver makes still reach the service's querier,
+// so callers bind it to a transaction they always roll back.
+func (s *Service) PreviewActivityOccurrence(ctx context.Context, occurrenceID pgtype.UUID) ([]db.CreateBonusPointLedgerEntryRow, error) {
+	preview := &Service{queries: previewQuerier{serviceQuerier: s.queries}}
+	return preview.ResolveActivityOccurrence(ctx, occurrenceID)
+}
+
+// previewQuerier passes reads through and drops the ledger and status writes
+// the resolvers make, echoing ledger entries back as if they had been
+// inserted.
+type previewQuerier struct {
+	serviceQuerier
+}
+
+func (previewQuerier) CreateBonusPointLedgerEntry(_ context.Context, arg db.CreateBonusPointLedgerEntryParams) (db.CreateBonusPointLedgerEntryRow, error) {
+	return db.CreateBonusPointLedgerEntryRow{
+		InstanceID:           arg.InstanceID,
+		ParticipantID:        arg.ParticipantID,
+		ActivityOccurrenceID: arg.ActivityOccurrenceID,
+		SourceGroupID:        arg.SourceGroupID,
+		EntryKind:            arg.EntryKind,
+		Points:               arg.Points,
+		Visibility:           arg.Visibility,
+		Reason:               arg.Reason,
+		EffectiveAt:          arg.EffectiveAt,
+		AwardKey:             arg.AwardKey,
+		Metadata:             arg.Metadata,
+	}, nil
+}
+
+func (previewQuerier) UpdateActivityOccurrenceStatusAndMetadata(_ context.Context, arg db.UpdateActivityOccurrenceStatusAndMetadataParams) (db.UpdateActivityOccurrenceStatusAndMetadataRow, error) {
+	return db.UpdateActivityOccurrenceStatusAndMetadataRow{ID: arg.ID, Status: arg.Status}, nil
+}
@@ -0,0 +1,75 @@
+package gameplay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+)
+
+func TestPreviewActivityOccurrenceReturnsEntriesWithoutWriting(t *testing.T) {
+	instanceID := testUUID()
+	activityID := testUUID()
+	occurrenceID := testUUID()
+	stirThePotActivityID := testUUID()
+	stirThePotRoundID := testUUID()
+	winningGroupID := testUUID()
+	aliceID := testUUID()
+	effectiveAt := time.Date(2026, time.March, 25, 20, 0, 0, 0, time.UTC)
+
+	fake := &fakeQuerier{
+		activityOccurrence: db.GetActivityOccurrenceRow{
+			ID:             occurrenceID,
+			ActivityID:     activityID,
+			OccurrenceType: "immunity_result",
+			Name:           "Episode 4 Immunity",
+			EffectiveAt:    timestamptz(effectiveAt),
+			Metadata:       []byte(`{"winning_survivor_tribes":["vatu"]}`),
+		},
+		instanceActivity: db.GetInstanceActivityRow{
+			ID:           activityID,
+			InstanceID:   instanceID,
+			ActivityType: "tribal_pony",
+			Name:         "Pony Tribes",
+		},
+		currentEpisode: db.GetCurrentEpisodeAtRow{ID: testUUID(), InstanceID: instanceID, EpisodeNumber: 5, AirsAt: timestamptz(effectiveAt.Add(-time.Hour))},
+		activeActivityGroupAssignments: []db.ListActiveActivityGroupAssignmentsAtRow{{
+			ActivityID:           activityID,
+			ParticipantGroupID:   winningGroupID,
+			ParticipantGroupName: "Lotus",
+			Role:                 "tribe",
+			Configuration:        []byte(`{"pony_survivor_tribe":"vatu"}`),
+		}},
+		activeMembershipsByGroup: map[[16]byte][]db.ListActiveParticipantGroupMembershipsAtRow{
+			winningGroupID.Bytes: {{ParticipantGroupID: winningGroupID, ParticipantID: aliceID, ParticipantName: "Alice"}},
+		},
+		instanceActivitiesByType: []db.ListInstanceActivitiesByTypeRow{{ID: stirThePotActivityID, InstanceID: instanceID, ActivityType: "stir_the_pot", Name: "Stir the Pot"}},
+		occurrencesByStatus: []db.ListActivityOccurrencesByActivityAndStatusRow{{
+			ID:             stirThePotRoundID,
+			ActivityID:     stirThePotActivityID,
+			OccurrenceType: "stir_the_pot_round",
+			Name:           "Round 1",
+			EffectiveAt:    timestamptz(effectiveAt.Add(-time.Hour)),
+			Status:         "recorded",
+			Metadata:       []byte(`{"reward_tiers":[{"contributions":2,"bonus":1},{"contributions":5,"bonus":2}],"target_episode":{"episode_number":5}}`),
+		}},
+		occurrenceParticipantsByOccurrence: map[[16]byte][]db.ListActivityOccurrenceParticipantsRow{
+			stirThePotRoundID.Bytes: {{ParticipantID: testUUID(), ParticipantName: "Contributor", ParticipantGroupID: winningGroupID, ParticipantGroupName: textValue("Lotus"), Role: "contributor", Metadata: []byte(`{"contribution":5}`)}},
+		},
+	}
+
+	entries, err := NewService(fake).PreviewActivityOccurrence(context.Background(), occurrenceID)
+	if err != nil {
+		t.Fatalf("preview activity occurrence: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ParticipantID != aliceID || entries[0].Points != 3 || entries[0].Visibility != bonusVisibilityPublic {
+		t.Fatalf("unexpected preview entries: %+v", entries)
+	}
+	if entries[0].ID.Valid {
+		t.Fatal("expected previewed entries to have no id")
+	}
+	if len(fake.createdBonusLedgerEntries) != 0 || len(fake.updatedOccurrences) != 0 {
+		t.Fatalf("expected no writes, got %d entries and %d occurrence updates", len(fake.createdBonusLedgerEntries), len(fake.updatedOccurrences))
+	}
+}
@@ -0,0 +1,177 @@
+package gameplay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+type resolverFunc func(s *Service, ctx context.Context, resolverCtx resolverContext) ([]resolvedLedgerEntry, error)
+
+// ActivityType is a registered kind of activity: the schemas its metadata is
+// checked against on write and, for scored activities, the resolver that
+// turns an occurrence into ledger entries.
+type ActivityType struct {
+	Name        string
+	Description string
+	Schemas     ActivityMetadataSchemas
+	resolve     resolverFunc
+}
+
+// ActivityMetadataSchemas holds one schema per place an activity type keeps
+// metadata. A nil schema accepts any JSON object.
+type ActivityMetadataSchemas struct {
+	Activity    *MetadataSchema `json:"activity"`
+	Assignment  *MetadataSchema `json:"assignment"`
+	Occurrence  *MetadataSchema `json:"occurrence"`
+	Participant *MetadataSchema `json:"participant"`
+}
+
+// Resolvable reports whether occurrences of this type can be resolved into
+// ledger entries through ResolveActivityOccurrence.
+func (t ActivityType) Resolvable() bool {
+	return t.resolve != nil
+}
+
+var activityTypes = map[string]ActivityType{}
+
+func registerActivityType(activityType ActivityType) {
+	if _, exists := activityTypes[activityType.Name]; exists {
+		panic(fmt.Sprintf("activity type %q registered twice", activityType.Name))
+	}
+	activityTypes[activityType.Name] = activityType
+}
+
+// LookupActivityType returns the registered activity type with name.
+func LookupActivityType(name string) (ActivityType, bool) {
+	activityType, ok := activityTypes[name]
+	return activityType, ok
+}
+
+// ActivityTypes lists every registered activity type by name.
+func ActivityTypes() []ActivityType {
+	types := make([]ActivityType, 0, len(activityTypes))
+	for _, activityType := range activityTypes {
+		types = append(types, activityType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// validateAssignmentConfiguration checks assignment configuration against the
+// activity's registered schema. Activities of unregistered types predate the
+// registry and are left unchecked.
+func validateAssignmentConfiguration(activityTypeName string, configuration []byte) error {
+	activityType, ok := LookupActivityType(activityTypeName)
+	if !ok {
+		return nil
+	}
+	return activityType.Schemas.Assignment.Validate(configuration)
+}
+
+func init() {
+	visibility := &MetadataSchema{Type: "string", Enum: []string{bonusVisibilityPublic, bonusVisibilitySecret, bonusVisibilityRevealed}}
+	guessCount := minimumIntegerSchema("Number of guesses the participant needed.", 1)
+
+	registerActivityType(ActivityType{
+		Name:        "tribal_pony",
+		Description: "Each tribe backs a Survivor tribe; members of tribes whose pony wins immunity earn a point.",
+		Schemas: ActivityMetadataSchemas{
+			Assignment: objectSchema([]string{"pony_survivor_tribe"}, map[string]*MetadataSchema{
+				"pony_survivor_tribe": stringSchema("Survivor tribe this group backs."),
+			}),
+			Occurrence: objectSchema([]string{"winning_survivor_tribes"}, map[string]*MetadataSchema{
+				"winning_survivor_tribes": {Type: "array", Description: "Survivor tribes that won immunity.", Items: &MetadataSchema{Type: "string"}},
+			}),
+		},
+		resolve: (*Service).resolveTribalPony,
+	})
+	registerActivityType(ActivityType{
+		Name:        "tribe_wordle",
+		Description: "Tribes compare their best three Wordle guess counts; the lowest total wins a point per member.",
+		Schemas: ActivityMetadataSchemas{
+			Participant: objectSchema([]string{"guess_count"}, map[string]*MetadataSchema{
+				"guess_count": guessCount,
+			}),
+		},
+		resolve: (*Service).resolveTribeWordle,
+	})
+	registerActivityType(ActivityType{
+		Name:        "journey",
+		Description: "Delegates attend a journey, choose SHARE or STEAL for their tribe, and may risk secret points on a guess.",
+		Schemas: ActivityMetadataSchemas{
+			Participant: objectSchema(nil, map[string]*MetadataSchema{
+				"choice":      stringSchema("SHARE or STEAL, when the result column is not used."),
+				"guess_count": guessCount,
+			}),
+		},
+		resolve: (*Service).resolveJourney,
+	})
+	registerActivityType(ActivityType{
+		Name:        "manual_adjustment",
+		Description: "Admin-entered point adjustments, one ledger entry per participant.",
+		Schemas: ActivityMetadataSchemas{
+			Participant: objectSchema([]string{"points"}, map[string]*MetadataSchema{
+				"points":     integerSchema("Non-zero points to add or remove."),
+				"visibility": visibility,
+				"reason":     stringSchema("Ledger reason; defaults to the occurrence name."),
+				"entry_kind": {Type: "string", Enum: []string{bonusEntryKindAward, bonusEntryKindCorrection, bonusEntryKindSpend}},
+				"award_key":  stringSchema("Idempotency key for the entry."),
+			}),
+		},
+		resolve: func(s *Service, _ context.Context, resolverCtx resolverContext) ([]resolvedLedgerEntry, error) {
+			return s.resolveManualAdjustment(resolverCtx)
+		},
+	})
+	registerActivityType(ActivityType{
+		Name:        "stir_the_pot",
+		Description: "Tribe members pool bonus points; the pot pays out per member at the tribe's exchange rate.",
+		Schemas: ActivityMetadataSchemas{
+			Occurrence: objectSchema(nil, map[string]*MetadataSchema{
+				"reward_tiers": {Type: "array", Description: "Contribution thresholds and the bonus each unlocks.", Items: objectSchema([]string{"contributions", "bonus"}, map[string]*MetadataSchema{
+					"contributions": minimumIntegerSchema("Total tribe contribution needed.", 0),
+					"bonus":         minimumIntegerSchema("Bonus for reaching the threshold.", 0),
+				})},
+			}),
+			Participant: objectSchema(nil, map[string]*MetadataSchema{
+				"contribution": minimumIntegerSchema("Points the participant put in.", 0),
+			}),
+		},
+		resolve: (*Service).resolveStirThePot,
+	})
+	registerActivityType(ActivityType{
+		Name:        "individual_pony",
+		Description: "Owners of a contestant's pony earn points when that contestant wins individual immunity.",
+		Schemas: ActivityMetadataSchemas{
+			Occurrence: objectSchema([]string{"winning_contestant_id"}, map[string]*MetadataSchema{
+				"winning_contestant_id": {Type: "string", Format: "uuid", Description: "Contestant who won individual immunity."},
+			}),
+		},
+		resolve: (*Service).resolveIndividualPony,
+	})
+	registerActivityType(ActivityType{
+		Name:        "individual_pony_auction",
+		Description: "Auction lots for individual pony ownership, settled as bids close.",
+	})
+	registerActivityType(ActivityType{
+		Name:        "loan_shark",
+		Description: "Bonus point loans with interest, repaid or defaulted by the scheduler.",
+		Schemas: ActivityMetadataSchemas{
+			Activity: objectSchema(nil, map[string]*MetadataSchema{
+				"default_penalty": objectSchema(nil, map[string]*MetadataSchema{
+					"shortfall_multiplier": minimumIntegerSchema("Multiplier on unpaid points; defaults to 1.", 0),
+					"flat_points":          minimumIntegerSchema("Flat charge added to every default.", 0),
+					"max_points":           minimumIntegerSchema("Cap on the penalty; 0 means no cap.", 0),
+				}),
+			}),
+		},
+	})
+	registerActivityType(ActivityType{
+		Name:        "merge_auction",
+		Description: "Merge Auction results recorded by an admin.",
+	})
+	registerActivityType(ActivityType{
+		Name:        "finale_bingo",
+		Description: "Finale Bingo scores and Loan Shark assignments.",
+	})
+}
@@ -0,0 +1,99 @@
+package gameplay
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+)
+
+func TestActivityTypeSchemasValidateMetadata(t *testing.T) {
+	cases := []struct {
+		name     string
+		schema   func(ActivityType) *MetadataSchema
+		typeName string
+		metadata string
+		wantErr  string
+	}{
+		{name: "tribal pony occurrence", typeName: "tribal_pony", schema: occurrenceSchema, metadata: `{"winning_survivor_tribes":["vatu"],"note":"extra fields are fine"}`},
+		{name: "tribal pony missing winners", typeName: "tribal_pony", schema: occurrenceSchema, metadata: `{}`, wantErr: "metadata.winning_survivor_tribes is required"},
+		{name: "tribal pony winners not strings", typeName: "tribal_pony", schema: occurrenceSchema, metadata: `{"winning_survivor_tribes":[1]}`, wantErr: "metadata.winning_survivor_tribes[0] must be a string"},
+		{name: "tribal pony assignment", typeName: "tribal_pony", schema: assignmentSchema, metadata: `{}`, wantErr: "metadata.pony_survivor_tribe is required"},
+		{name: "wordle guess count", typeName: "tribe_wordle", schema: participantSchema, metadata: `{"guess_count":3}`},
+		{name: "wordle zero guesses", typeName: "tribe_wordle", schema: participantSchema, metadata: `{"guess_count":0}`, wantErr: "metadata.guess_count must be at least 1"},
+		{name: "wordle fractional guesses", typeName: "tribe_wordle", schema: participantSchema, metadata: `{"guess_count":2.5}`, wantErr: "metadata.guess_count must be an integer"},
+		{name: "manual adjustment visibility", typeName: "manual_adjustment", schema: participantSchema, metadata: `{"points":2,"visibility":"hidden"}`, wantErr: "metadata.visibility must be one of public, secret, revealed"},
+		{name: "stir the pot tiers", typeName: "stir_the_pot", schema: occurrenceSchema, metadata: `{"reward_tiers":[{"contributions":2}]}`, wantErr: "metadata.reward_tiers[0].bonus is required"},
+		{name: "individual pony winner", typeName: "individual_pony", schema: occurrenceSchema, metadata: `{"winning_contestant_id":"not-a-uuid"}`, wantErr: "metadata.winning_contestant_id must be a UUID"},
+		{name: "metadata must be an object", typeName: "merge_auction", schema: activitySchema, metadata: `[]`, wantErr: "metadata must be an object"},
+		{name: "empty metadata", typeName: "journey", schema: participantSchema, metadata: ``},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			activityType, ok := LookupActivityType(tc.typeName)
+			if !ok {
+				t.Fatalf("activity type %q is not registered", tc.typeName)
+			}
+			err := tc.schema(activityType).Validate([]byte(tc.metadata))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidMetadata) || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestActivityTypesListsResolversInOrder(t *testing.T) {
+	resolvable := map[string]bool{}
+	types := ActivityTypes()
+	for i, activityType := range types {
+		if i > 0 && types[i-1].Name >= activityType.Name {
+			t.Fatalf("activity types out of order: %q before %q", types[i-1].Name, activityType.Name)
+		}
+		resolvable[activityType.Name] = activityType.Resolvable()
+	}
+	for _, name := range []string{"tribal_pony", "tribe_wordle", "journey", "manual_adjustment", "stir_the_pot", "individual_pony"} {
+		if !resolvable[name] {
+			t.Fatalf("expected %q to be resolvable", name)
+		}
+	}
+	if resolvable["loan_shark"] {
+		t.Fatal("expected loan_shark to have no resolver")
+	}
+}
+
+func TestCreateActivityGroupAssignmentValidatesConfiguration(t *testing.T) {
+	activityID := testUUID()
+	boundary := time.Date(2026, time.March, 4, 20, 0, 0, 0, time.UTC)
+	fake := &fakeQuerier{
+		instanceActivity: db.GetInstanceActivityRow{ID: activityID, InstanceID: testUUID(), ActivityType: "tribal_pony"},
+		episodes:         []db.ListInstanceEpisodesRow{{EpisodeNumber: 0, AirsAt: timestamptz(boundary)}},
+	}
+
+	_, err := NewService(fake).CreateActivityGroupAssignment(context.Background(), CreateActivityGroupAssignmentParams{
+		ActivityID:         activityID,
+		ParticipantGroupID: testUUID(),
+		Role:               "tribe",
+		StartsAt:           boundary,
+		Configuration:      []byte(`{"pony_survivor_tribe":7}`),
+	})
+	if !errors.Is(err, ErrInvalidMetadata) {
+		t.Fatalf("expected ErrInvalidMetadata, got %v", err)
+	}
+	if len(fake.createdActivityGroupAssignments) != 0 {
+		t.Fatal("expected no assignment to be written")
+	}
+}
+
+func activitySchema(t ActivityType) *MetadataSchema    { return t.Schemas.Activity }
+func assignmentSchema(t ActivityType) *MetadataSchema  { return t.Schemas.Assignment }
+func occurrenceSchema(t ActivityType) *MetadataSchema  { return t.Schemas.Occurrence }
+func participantSchema(t ActivityType) *MetadataSchema { return t.Schemas.Participant }
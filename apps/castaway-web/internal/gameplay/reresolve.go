@@ -0,0 +1,183 @@
+package gameplay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrOccurrenceNotResolved is returned when re-resolving an occurrence that
+// has not been resolved yet.
+var ErrOccurrenceNotResolved = errors.New("occurrence not resolved")
+
+// ErrNegativeBalance is returned when re-resolving would take a
+// participant's bonus balance below zero and the caller did not allow it.
+var ErrNegativeBalance = errors.New("re-resolving would leave a negative bonus balance")
+
+// NegativeBalance is a participant whose bonus balance re-resolving takes
+// below zero.
+type NegativeBalance struct {
+	ParticipantID pgtype.UUID
+	BalanceBefore int32
+	BalanceAfter  int32
+}
+
+// NegativeBalanceError names the participants behind ErrNegativeBalance.
+type NegativeBalanceError struct {
+	Balances []NegativeBalance
+}
+
+func (e *NegativeBalanceError) Error() string {
+	return fmt.Sprintf("%s for %d participant(s)", ErrNegativeBalance, len(e.Balances))
+}
+
+func (e *NegativeBalanceError) Unwrap() error {
+	return ErrNegativeBalance
+}
+
+// ReresolveResult is what re-resolving an occurrence changed: the correction
+// entries that voided the old ledger rows, the rows the resolver wrote on the
+// current inputs, and the net change per participant.
+type ReresolveResult struct {
+	Reversed         []db.ReverseBonusPointLedgerEntryRow
+	Created          []db.CreateBonusPointLedgerEntryRow
+	Diff             []ParticipantPointsDiff
+	NegativeBalances []NegativeBalance
+}
+
+// ParticipantPointsDiff is one participant's points from an occurrence before
+// and after it was re-resolved.
+type ParticipantPointsDiff struct {
+	ParticipantID pgtype.UUID
+	BeforePoints  int32
+	AfterPoints   int32
+	NetPoints     int32
+}
+
+// ReresolveActivityOccurrence voids the occurrence's live ledger entries with
+// linked corrections and runs its resolver again on the current inputs.
+// Reveal and conversion rows record secret points being disclosed rather than
+// scored, so they are left alone.
+//
+// Balances are read before anything is reversed. If the net change would take
+// a participant below zero, it returns a *NegativeBalanceError unless
+// allowNegativeBalance is set, and the caller must roll back its transaction.
+// When it is set, those participants are reported in NegativeBalances.
+func (s *Service) ReresolveActivityOccurrence(ctx context.Context, occurrenceID pgtype.UUID, at time.Time, allowNegativeBalance bool) (ReresolveResult, error) {
+	occurrence, err := s.queries.GetActivityOccurrence(ctx, occurrenceID)
+	if err != nil {
+		return ReresolveResult{}, fmt.Errorf("get activity occurrence: %w", err)
+	}
+	if occurrence.Status != "resolved" {
+		return ReresolveResult{}, fmt.Errorf("%w: occurrence %q is %s", ErrOccurrenceNotResolved, occurrence.Name, occurrence.Status)
+	}
+	activity, err := s.queries.GetInstanceActivity(ctx, occurrence.ActivityID)
+	if err != nil {
+		return ReresolveResult{}, fmt.Errorf("get instance activity: %w", err)
+	}
+	if activityType, ok := LookupActivityType(activity.ActivityType); !ok || !activityType.Resolvable() {
+		return ReresolveResult{}, fmt.Errorf("unsupported activity type %q", activity.ActivityType)
+	}
+
+	live, err := s.queries.ListLiveBonusPointLedgerEntriesByOccurrence(ctx, occurrence.ID)
+	if err != nil {
+		return ReresolveResult{}, fmt.Errorf("list occurrence ledger entries: %w", err)
+	}
+	balances := make(map[pgtype.UUID]int32, len(live))
+	for _, entry := range live {
+		if _, ok := balances[entry.ParticipantID]; ok {
+			continue
+		}
+		balance, err := s.bonusBalance(ctx, activity.InstanceID, entry.ParticipantID)
+		if err != nil {
+			return ReresolveResult{}, err
+		}
+		balances[entry.ParticipantID] = balance
+	}
+	reversed := make([]db.ReverseBonusPointLedgerEntryRow, 0, len(live))
+	for _, entry := range live {
+		reversal, err := s.queries.ReverseBonusPointLedgerEntry(ctx, db.ReverseBonusPointLedgerEntryParams{
+			VoidedAt: timestamptz(at),
+			ID:       entry.ID,
+		})
+		if err != nil {
+			return ReresolveResult{}, fmt.Errorf("reverse ledger entry %s: %w", pgUUIDString(entry.ID), err)
+		}
+		reversed = append(reversed, reversal)
+	}
+
+	created, err := s.ResolveActivityOccurrence(ctx, occurrence.ID)
+	if err != nil {
+		return ReresolveResult{}, err
+	}
+	diff := participantPointsDiff(reversed, created)
+	negative := make([]NegativeBalance, 0)
+	for _, change := range diff {
+		balance, ok := balances[change.ParticipantID]
+		if !ok || change.NetPoints >= 0 || balance+change.NetPoints >= 0 {
+			continue
+		}
+		negative = append(negative, NegativeBalance{
+			ParticipantID: change.ParticipantID,
+			BalanceBefore: balance,
+			BalanceAfter:  balance + change.NetPoints,
+		})
+	}
+	if len(negative) > 0 && !allowNegativeBalance {
+		return ReresolveResult{}, &NegativeBalanceError{Balances: negative}
+	}
+	return ReresolveResult{
+		Reversed:         reversed,
+		Created:          created,
+		Diff:             diff,
+		NegativeBalances: negative,
+	}, nil
+}
+
+// bonusBalance is the participant's visible plus secret bonus points.
+func (s *Service) bonusBalance(ctx context.Context, instanceID, participantID pgtype.UUID) (int32, error) {
+	visible, err := s.VisibleBonusTotalByParticipant(ctx, instanceID, participantID)
+	if err != nil {
+		return 0, fmt.Errorf("get visible bonus total: %w", err)
+	}
+	secret, err := s.SecretBonusTotalByParticipant(ctx, instanceID, participantID)
+	if err != nil {
+		return 0, fmt.Errorf("get secret bonus total: %w", err)
+	}
+	return visible + secret, nil
+}
+
+// participantPointsDiff nets reversals against new entries. A reversal carries
+// the negated points of the entry it voided, so the before total is its
+// negation.
+func participantPointsDiff(reversed []db.ReverseBonusPointLedgerEntryRow, created []db.CreateBonusPointLedgerEntryRow) []ParticipantPointsDiff {
+	order := make([]pgtype.UUID, 0)
+	byParticipant := make(map[pgtype.UUID]*ParticipantPointsDiff)
+	diffFor := func(participantID pgtype.UUID) *ParticipantPointsDiff {
+		diff, ok := byParticipant[participantID]
+		if !ok {
+			diff = &ParticipantPointsDiff{ParticipantID: participantID}
+			byParticipant[participantID] = diff
+			order = append(order, participantID)
+		}
+		return diff
+	}
+	for _, reversal := range reversed {
+		diffFor(reversal.ParticipantID).BeforePoints -= reversal.Points
+	}
+	for _, entry := range created {
+		diffFor(entry.ParticipantID).AfterPoints += entry.Points
+	}
+
+	diffs := make([]ParticipantPointsDiff, 0, len(order))
+	for _, participantID := range order {
+		diff := *byParticipant[participantID]
+		diff.NetPoints = diff.AfterPoints - diff.BeforePoints
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
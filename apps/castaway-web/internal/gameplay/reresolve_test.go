@@ -0,0 +1,120 @@
+package gameplay
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+)
+
+func TestReresolveActivityOccurrenceReversesThenReportsNetDiff(t *testing.T) {
+	instanceID := testUUID()
+	activityID := testUUID()
+	occurrenceID := testUUID()
+	aliceID := testUUID()
+	bobID := testUUID()
+	caraID := testUUID()
+	effectiveAt := time.Date(2026, time.March, 22, 9, 0, 0, 0, time.UTC)
+
+	fake := &fakeQuerier{
+		activityOccurrence: db.GetActivityOccurrenceRow{
+			ID:             occurrenceID,
+			ActivityID:     activityID,
+			OccurrenceType: "manual_correction",
+			Name:           "Manual Correction",
+			Status:         "resolved",
+			EffectiveAt:    timestamptz(effectiveAt),
+		},
+		instanceActivity: db.GetInstanceActivityRow{
+			ID:           activityID,
+			InstanceID:   instanceID,
+			ActivityType: "manual_adjustment",
+			Name:         "Manual Adjustments",
+		},
+		liveBonusLedgerEntries: []db.ListLiveBonusPointLedgerEntriesByOccurrenceRow{
+			{ID: testUUID(), ParticipantID: aliceID, ParticipantName: "Alice", EntryKind: bonusEntryKindCorrection, Points: 2, Visibility: bonusVisibilityPublic},
+			{ID: testUUID(), ParticipantID: bobID, ParticipantName: "Bob", EntryKind: bonusEntryKindCorrection, Points: 1, Visibility: bonusVisibilitySecret},
+		},
+		occurrenceParticipants: []db.ListActivityOccurrenceParticipantsRow{
+			{ParticipantID: aliceID, ParticipantName: "Alice", Metadata: []byte(`{"points":3,"visibility":"public","reason":"fixed"}`)},
+			{ParticipantID: caraID, ParticipantName: "Cara", Metadata: []byte(`{"points":1,"visibility":"public","reason":"fixed"}`)},
+		},
+		visibleTotal: 4,
+	}
+
+	result, err := NewService(fake).ReresolveActivityOccurrence(context.Background(), occurrenceID, effectiveAt.Add(time.Hour), false)
+	if err != nil {
+		t.Fatalf("re-resolve activity occurrence: %v", err)
+	}
+	if len(fake.reversedBonusLedgerEntries) != 2 || len(result.Reversed) != 2 || len(result.Created) != 2 {
+		t.Fatalf("unexpected reversals %+v and created %+v", fake.reversedBonusLedgerEntries, result.Created)
+	}
+	want := map[[16]byte]ParticipantPointsDiff{
+		aliceID.Bytes: {BeforePoints: 2, AfterPoints: 3, NetPoints: 1},
+		bobID.Bytes:   {BeforePoints: 1, AfterPoints: 0, NetPoints: -1},
+		caraID.Bytes:  {BeforePoints: 0, AfterPoints: 1, NetPoints: 1},
+	}
+	if len(result.Diff) != len(want) {
+		t.Fatalf("unexpected diff: %+v", result.Diff)
+	}
+	for _, diff := range result.Diff {
+		expected := want[diff.ParticipantID.Bytes]
+		if diff.BeforePoints != expected.BeforePoints || diff.AfterPoints != expected.AfterPoints || diff.NetPoints != expected.NetPoints {
+			t.Fatalf("unexpected diff for %v: %+v", diff.ParticipantID, diff)
+		}
+	}
+}
+
+func TestReresolveActivityOccurrenceRequiresResolvedOccurrence(t *testing.T) {
+	fake := &fakeQuerier{
+		activityOccurrence: db.GetActivityOccurrenceRow{ID: testUUID(), Name: "Week 2 Wordle", Status: "recorded"},
+		instanceActivity:   db.GetInstanceActivityRow{ActivityType: "tribe_wordle"},
+	}
+
+	_, err := NewService(fake).ReresolveActivityOccurrence(context.Background(), fake.activityOccurrence.ID, time.Now(), false)
+	if !errors.Is(err, ErrOccurrenceNotResolved) {
+		t.Fatalf("expected ErrOccurrenceNotResolved, got %v", err)
+	}
+	if len(fake.reversedBonusLedgerEntries) != 0 || len(fake.createdBonusLedgerEntries) != 0 {
+		t.Fatal("expected no ledger writes")
+	}
+}
+
+func TestReresolveActivityOccurrenceRejectsNegativeBalanceUnlessAllowed(t *testing.T) {
+	occurrenceID := testUUID()
+	activityID := testUUID()
+	aliceID := testUUID()
+	newFake := func() *fakeQuerier {
+		return &fakeQuerier{
+			activityOccurrence: db.GetActivityOccurrenceRow{ID: occurrenceID, ActivityID: activityID, OccurrenceType: "manual_correction", Name: "Manual Correction", Status: "resolved"},
+			instanceActivity:   db.GetInstanceActivityRow{ID: activityID, InstanceID: testUUID(), ActivityType: "manual_adjustment", Name: "Manual Adjustments"},
+			liveBonusLedgerEntries: []db.ListLiveBonusPointLedgerEntriesByOccurrenceRow{
+				{ID: testUUID(), ParticipantID: aliceID, ParticipantName: "Alice", EntryKind: bonusEntryKindCorrection, Points: 5, Visibility: bonusVisibilityPublic},
+			},
+			occurrenceParticipants: []db.ListActivityOccurrenceParticipantsRow{
+				{ParticipantID: aliceID, ParticipantName: "Alice", Metadata: []byte(`{"points":1,"visibility":"public","reason":"fixed"}`)},
+			},
+			// Alice has spent 3 of the 5 points this occurrence gave her.
+			visibleTotal: 2,
+		}
+	}
+
+	_, err := NewService(newFake()).ReresolveActivityOccurrence(context.Background(), occurrenceID, time.Now(), false)
+	var negativeErr *NegativeBalanceError
+	if !errors.As(err, &negativeErr) || !errors.Is(err, ErrNegativeBalance) {
+		t.Fatalf("expected a NegativeBalanceError, got %v", err)
+	}
+	if len(negativeErr.Balances) != 1 || negativeErr.Balances[0].ParticipantID != aliceID || negativeErr.Balances[0].BalanceBefore != 2 || negativeErr.Balances[0].BalanceAfter != -2 {
+		t.Fatalf("unexpected negative balances: %+v", negativeErr.Balances)
+	}
+
+	result, err := NewService(newFake()).ReresolveActivityOccurrence(context.Background(), occurrenceID, time.Now(), true)
+	if err != nil {
+		t.Fatalf("re-resolve with negative balances allowed: %v", err)
+	}
+	if len(result.NegativeBalances) != 1 || result.NegativeBalances[0].BalanceAfter != -2 {
+		t.Fatalf("expected the negative balance to be surfaced, got %+v", result.NegativeBalances)
+	}
+}
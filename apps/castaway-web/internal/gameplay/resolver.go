@@ -131,23 +131,11 @@ func (s *Service) ResolveActivityOccurrence(ctx context.Context, occurrenceID pg
 		occurrenceParticipants: occurrenceParticipants,
 	}
 
-	var entries []resolvedLedgerEntry
-	switch activity.ActivityType {
-	case "tribal_pony":
-		entries, err = s.resolveTribalPony(ctx, resolverCtx)
-	case "tribe_wordle":
-		entries, err = s.resolveTribeWordle(ctx, resolverCtx)
-	case "journey":
-		entries, err = s.resolveJourney(ctx, resolverCtx)
-	case "manual_adjustment":
-		entries, err = s.resolveManualAdjustment(resolverCtx)
-	case "stir_the_pot":
-		entries, err = s.resolveStirThePot(ctx, resolverCtx)
-	case "individual_pony":
-		entries, err = s.resolveIndividualPony(ctx, resolverCtx)
-	default:
+	activityType, ok := LookupActivityType(activity.ActivityType)
+	if !ok || !activityType.Resolvable() {
 		return nil, fmt.Errorf("unsupported activity type %q", activity.ActivityType)
 	}
+	entries, err := activityType.resolve(s, ctx, resolverCtx)
 	if err != nil {
 		return nil, err
 	}
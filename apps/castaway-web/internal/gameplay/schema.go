@@ -0,0 +1,149 @@
+package gameplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidMetadata is returned when metadata does not match the schema its
+// activity type declares.
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// MetadataSchema is the subset of JSON Schema the activity registry uses to
+// describe metadata. It marshals to a valid JSON Schema document so clients
+// can build forms from it. Properties that are not declared are allowed.
+type MetadataSchema struct {
+	Type        string                     `json:"type"`
+	Description string                     `json:"description,omitempty"`
+	Format      string                     `json:"format,omitempty"`
+	Enum        []string                   `json:"enum,omitempty"`
+	Minimum     *int64                     `json:"minimum,omitempty"`
+	Items       *MetadataSchema            `json:"items,omitempty"`
+	Properties  map[string]*MetadataSchema `json:"properties,omitempty"`
+	Required    []string                   `json:"required,omitempty"`
+}
+
+// Validate checks raw against the schema. Empty input is treated as an empty
+// object, matching how metadata columns default.
+func (schema *MetadataSchema) Validate(raw []byte) error {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		raw = emptyJSONB
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	if schema == nil {
+		schema = &MetadataSchema{Type: "object"}
+	}
+	return schema.validate("metadata", value)
+}
+
+func (schema *MetadataSchema) validate(path string, value any) error {
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return invalidMetadata(path, "must be an object")
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return invalidMetadata(path+"."+name, "is required")
+			}
+		}
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			field, ok := object[name]
+			if !ok || field == nil {
+				continue
+			}
+			if err := schema.Properties[name].validate(path+"."+name, field); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return invalidMetadata(path, "must be an array")
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				if err := schema.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return invalidMetadata(path, "must be a string")
+		}
+		if schema.Format == "uuid" {
+			if _, err := uuid.Parse(strings.TrimSpace(text)); err != nil {
+				return invalidMetadata(path, "must be a UUID")
+			}
+		}
+		if len(schema.Enum) > 0 && !containsString(schema.Enum, text) {
+			return invalidMetadata(path, "must be one of "+strings.Join(schema.Enum, ", "))
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return invalidMetadata(path, "must be an integer")
+		}
+		parsed, err := strconv.ParseInt(number.String(), 10, 32)
+		if err != nil {
+			return invalidMetadata(path, "must be an integer")
+		}
+		if schema.Minimum != nil && parsed < *schema.Minimum {
+			return invalidMetadata(path, fmt.Sprintf("must be at least %d", *schema.Minimum))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return invalidMetadata(path, "must be a boolean")
+		}
+	}
+	return nil
+}
+
+func invalidMetadata(path, problem string) error {
+	return fmt.Errorf("%w: %s %s", ErrInvalidMetadata, path, problem)
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func objectSchema(required []string, properties map[string]*MetadataSchema) *MetadataSchema {
+	return &MetadataSchema{Type: "object", Properties: properties, Required: required}
+}
+
+func integerSchema(description string) *MetadataSchema {
+	return &MetadataSchema{Type: "integer", Description: description}
+}
+
+func minimumIntegerSchema(description string, minimum int64) *MetadataSchema {
+	return &MetadataSchema{Type: "integer", Description: description, Minimum: &minimum}
+}
+
+func stringSchema(description string) *MetadataSchema {
+	return &MetadataSchema{Type: "string", Description: description}
+}
@@ -2,23 +2,34 @@ package gameplay
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"time"
 
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 var emptyJSONB = []byte("{}")
 
+// ErrEpisodeBoundary is returned when a membership or assignment would start
+// or end between episodes.
+var ErrEpisodeBoundary = errors.New("episode boundary")
+
 type serviceQuerier interface {
 	CreateInstanceEpisode(ctx context.Context, arg db.CreateInstanceEpisodeParams) (db.CreateInstanceEpisodeRow, error)
 	ListInstanceEpisodes(ctx context.Context, instanceID pgtype.UUID) ([]db.ListInstanceEpisodesRow, error)
 	GetCurrentEpisodeAt(ctx context.Context, arg db.GetCurrentEpisodeAtParams) (db.GetCurrentEpisodeAtRow, error)
 	ListEpisodeBoundaryWindows(ctx context.Context, instanceID pgtype.UUID) ([]db.ListEpisodeBoundaryWindowsRow, error)
+	GetInstanceEpisode(ctx context.Context, arg db.GetInstanceEpisodeParams) (db.GetInstanceEpisodeRow, error)
+	GetInstanceEpisodeUsage(ctx context.Context, id pgtype.UUID) (db.GetInstanceEpisodeUsageRow, error)
+	UpdateInstanceEpisode(ctx context.Context, arg db.UpdateInstanceEpisodeParams) (db.UpdateInstanceEpisodeRow, error)
+	DeleteInstanceEpisode(ctx context.Context, id pgtype.UUID) error
 	GetParticipantGroup(ctx context.Context, id pgtype.UUID) (db.GetParticipantGroupRow, error)
 	CreateParticipantGroupMembershipPeriod(ctx context.Context, arg db.CreateParticipantGroupMembershipPeriodParams) (db.CreateParticipantGroupMembershipPeriodRow, error)
+	EndParticipantGroupMembershipPeriods(ctx context.Context, arg db.EndParticipantGroupMembershipPeriodsParams) ([]db.EndParticipantGroupMembershipPeriodsRow, error)
 	ListActiveParticipantGroupMembershipsAt(ctx context.Context, arg db.ListActiveParticipantGroupMembershipsAtParams) ([]db.ListActiveParticipantGroupMembershipsAtRow, error)
 	GetActivityOccurrence(ctx context.Context, id pgtype.UUID) (db.GetActivityOccurrenceRow, error)
 	GetInstanceActivity(ctx context.Context, id pgtype.UUID) (db.GetInstanceActivityRow, error)
@@ -32,6 +43,8 @@ type serviceQuerier interface {
 	UpdateActivityOccurrenceStatusAndMetadata(ctx context.Context, arg db.UpdateActivityOccurrenceStatusAndMetadataParams) (db.UpdateActivityOccurrenceStatusAndMetadataRow, error)
 	ListInstanceActivitiesByType(ctx context.Context, arg db.ListInstanceActivitiesByTypeParams) ([]db.ListInstanceActivitiesByTypeRow, error)
 	CreateBonusPointLedgerEntry(ctx context.Context, arg db.CreateBonusPointLedgerEntryParams) (db.CreateBonusPointLedgerEntryRow, error)
+	ListLiveBonusPointLedgerEntriesByOccurrence(ctx context.Context, activityOccurrenceID pgtype.UUID) ([]db.ListLiveBonusPointLedgerEntriesByOccurrenceRow, error)
+	ReverseBonusPointLedgerEntry(ctx context.Context, arg db.ReverseBonusPointLedgerEntryParams) (db.ReverseBonusPointLedgerEntryRow, error)
 	GetVisibleBonusTotalByParticipant(ctx context.Context, arg db.GetVisibleBonusTotalByParticipantParams) (int32, error)
 	GetSecretBonusTotalByParticipant(ctx context.Context, arg db.GetSecretBonusTotalByParticipantParams) (int32, error)
 	GetVisibleBonusTotalByParticipantAsOf(ctx context.Context, arg db.GetVisibleBonusTotalByParticipantAsOfParams) (int32, error)
@@ -58,6 +71,9 @@ type EpisodeTemplate struct {
 	AirsAt        time.Time
 }
 
+// DefaultEpisodeScheduleForSeason is the schedule copied into a new instance.
+// Only season 50 has a known air schedule; other seasons start with a
+// placeholder preseason and are scheduled through the episode API.
 func DefaultEpisodeScheduleForSeason(season int32) []EpisodeTemplate {
 	if season == 50 {
 		location, err := time.LoadLocation("America/New_York")
@@ -147,6 +163,41 @@ func (s *Service) CreateMembershipPeriod(ctx context.Context, params CreateMembe
 	})
 }
 
+type EndMembershipPeriodParams struct {
+	ParticipantGroupID pgtype.UUID
+	ParticipantID      pgtype.UUID
+	EndsAt             time.Time
+}
+
+// EndMembershipPeriod ends the participant's membership periods in the group
+// that are still open at EndsAt. It returns pgx.ErrNoRows when none are.
+func (s *Service) EndMembershipPeriod(ctx context.Context, params EndMembershipPeriodParams) ([]db.EndParticipantGroupMembershipPeriodsRow, error) {
+	group, err := s.queries.GetParticipantGroup(ctx, params.ParticipantGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("get participant group: %w", err)
+	}
+	boundaries, err := s.episodeBoundaries(ctx, group.InstanceID)
+	if err != nil {
+		return nil, err
+	}
+	if !matchesBoundary(boundaries, params.EndsAt) {
+		return nil, fmt.Errorf("%w: ends_at must match an explicit episode boundary", ErrEpisodeBoundary)
+	}
+
+	ended, err := s.queries.EndParticipantGroupMembershipPeriods(ctx, db.EndParticipantGroupMembershipPeriodsParams{
+		EndsAt:             timestamptz(params.EndsAt),
+		ParticipantGroupID: params.ParticipantGroupID,
+		ParticipantID:      params.ParticipantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ended) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return ended, nil
+}
+
 func (s *Service) ActiveGroupMembershipsAt(ctx context.Context, participantGroupID pgtype.UUID, at time.Time) ([]db.ListActiveParticipantGroupMembershipsAtRow, error) {
 	return s.queries.ListActiveParticipantGroupMembershipsAt(ctx, db.ListActiveParticipantGroupMembershipsAtParams{
 		ParticipantGroupID: participantGroupID,
@@ -168,6 +219,9 @@ func (s *Service) CreateActivityGroupAssignment(ctx context.Context, params Crea
 	if err != nil {
 		return db.CreateActivityGroupAssignmentRow{}, fmt.Errorf("get instance activity: %w", err)
 	}
+	if err := validateAssignmentConfiguration(activity.ActivityType, params.Configuration); err != nil {
+		return db.CreateActivityGroupAssignmentRow{}, err
+	}
 	if err := s.requireEpisodeBoundaries(ctx, activity.InstanceID, params.StartsAt, params.EndsAt); err != nil {
 		return db.CreateActivityGroupAssignmentRow{}, err
 	}
@@ -205,6 +259,9 @@ func (s *Service) CreateParticipantAssignment(ctx context.Context, params Create
 	if err != nil {
 		return db.CreateActivityParticipantAssignmentRow{}, fmt.Errorf("get instance activity: %w", err)
 	}
+	if err := validateAssignmentConfiguration(activity.ActivityType, params.Configuration); err != nil {
+		return db.CreateActivityParticipantAssignmentRow{}, err
+	}
 	if err := s.requireEpisodeBoundaries(ctx, activity.InstanceID, params.StartsAt, params.EndsAt); err != nil {
 		return db.CreateActivityParticipantAssignmentRow{}, err
 	}
@@ -261,10 +318,37 @@ func (s *Service) AvailableSecretBalanceByParticipant(ctx context.Context, insta
 	})
 }
 
+// RequireEpisodeBoundary returns ErrEpisodeBoundary unless one of the
+// instance's episodes airs at exactly at.
+func (s *Service) RequireEpisodeBoundary(ctx context.Context, instanceID pgtype.UUID, at time.Time) error {
+	boundaries, err := s.episodeBoundaries(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	if !matchesBoundary(boundaries, at) {
+		return fmt.Errorf("%w: %s is not an explicit episode boundary", ErrEpisodeBoundary, at.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
 func (s *Service) requireEpisodeBoundaries(ctx context.Context, instanceID pgtype.UUID, startsAt time.Time, endsAt *time.Time) error {
+	boundaries, err := s.episodeBoundaries(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	if !matchesBoundary(boundaries, startsAt) {
+		return fmt.Errorf("%w: starts_at must match an explicit episode boundary", ErrEpisodeBoundary)
+	}
+	if endsAt != nil && !matchesBoundary(boundaries, *endsAt) {
+		return fmt.Errorf("%w: ends_at must match an explicit episode boundary", ErrEpisodeBoundary)
+	}
+	return nil
+}
+
+func (s *Service) episodeBoundaries(ctx context.Context, instanceID pgtype.UUID) ([]time.Time, error) {
 	episodes, err := s.queries.ListInstanceEpisodes(ctx, instanceID)
 	if err != nil {
-		return fmt.Errorf("list instance episodes: %w", err)
+		return nil, fmt.Errorf("list instance episodes: %w", err)
 	}
 
 	boundaries := make([]time.Time, 0, len(episodes))
@@ -274,14 +358,7 @@ func (s *Service) requireEpisodeBoundaries(ctx context.Context, instanceID pgtyp
 	sort.Slice(boundaries, func(i, j int) bool {
 		return boundaries[i].Before(boundaries[j])
 	})
-
-	if !matchesBoundary(boundaries, startsAt) {
-		return fmt.Errorf("starts_at must match an explicit episode boundary")
-	}
-	if endsAt != nil && !matchesBoundary(boundaries, *endsAt) {
-		return fmt.Errorf("ends_at must match an explicit episode boundary")
-	}
-	return nil
+	return boundaries, nil
 }
 
 func matchesBoundary(boundaries []time.Time, candidate time.Time) bool {
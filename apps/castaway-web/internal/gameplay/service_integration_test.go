@@ -351,7 +351,7 @@ func resetDatabase(t *testing.T, ctx context.Context, pool *pgxpool.Pool) {
 
 func createInstanceForTest(t *testing.T, ctx context.Context, queries *db.Queries, name string, season int32) db.CreateInstanceRow {
 	t.Helper()
-	instance, err := queries.CreateInstance(ctx, db.CreateInstanceParams{Name: name, Season: season})
+	instance, err := queries.CreateInstance(ctx, db.CreateInstanceParams{Name: name, Season: season, State: pgtype.Text{String: "active", Valid: true}})
 	if err != nil {
 		t.Fatalf("create instance: %v", err)
 	}
@@ -3,11 +3,13 @@ package gameplay
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -48,6 +50,85 @@ func TestCopyInstanceSchedule(t *testing.T) {
 	}
 }
 
+func TestCreateEpisodeRequiresAiringOrder(t *testing.T) {
+	instanceID := testUUID()
+	episodeOne := time.Date(2026, time.March, 4, 20, 0, 0, 0, time.UTC)
+	fake := &fakeQuerier{episodes: []db.ListInstanceEpisodesRow{
+		{ID: testUUID(), EpisodeNumber: 0, AirsAt: timestamptz(episodeOne.AddDate(0, 0, -7))},
+		{ID: testUUID(), EpisodeNumber: 1, AirsAt: timestamptz(episodeOne)},
+		{ID: testUUID(), EpisodeNumber: 3, AirsAt: timestamptz(episodeOne.AddDate(0, 0, 14))},
+	}}
+	service := NewService(fake)
+
+	if _, err := service.CreateEpisode(context.Background(), CreateEpisodeParams{InstanceID: instanceID, EpisodeNumber: 2, Label: "Episode 2", AirsAt: episodeOne.AddDate(0, 0, 7)}); err != nil {
+		t.Fatalf("expected episode between neighbours to succeed, got %v", err)
+	}
+	for _, params := range []CreateEpisodeParams{
+		{InstanceID: instanceID, EpisodeNumber: 2, Label: "Too late", AirsAt: episodeOne.AddDate(0, 0, 21)},
+		{InstanceID: instanceID, EpisodeNumber: 1, Label: "Duplicate", AirsAt: episodeOne.AddDate(0, 0, 1)},
+	} {
+		if _, err := service.CreateEpisode(context.Background(), params); !errors.Is(err, ErrEpisodeConflict) {
+			t.Fatalf("expected %q to conflict, got %v", params.Label, err)
+		}
+	}
+	if len(fake.createdEpisodes) != 1 {
+		t.Fatalf("expected only the ordered episode to be created, got %d", len(fake.createdEpisodes))
+	}
+}
+
+func TestUpdateEpisodeRefusesToMoveUsedBoundary(t *testing.T) {
+	instanceID := testUUID()
+	episodeID := testUUID()
+	episodeOne := time.Date(2026, time.March, 4, 20, 0, 0, 0, time.UTC)
+	moved := episodeOne.Add(time.Hour)
+	label := "Premiere"
+	fake := &fakeQuerier{
+		episodes: []db.ListInstanceEpisodesRow{
+			{ID: testUUID(), EpisodeNumber: 0, AirsAt: timestamptz(episodeOne.AddDate(0, 0, -7))},
+			{ID: episodeID, EpisodeNumber: 1, Label: "Episode 1", AirsAt: timestamptz(episodeOne)},
+		},
+		episodeUsage: db.GetInstanceEpisodeUsageRow{MembershipPeriods: 2, Activities: 1},
+	}
+	service := NewService(fake)
+
+	if _, err := service.UpdateEpisode(context.Background(), UpdateEpisodeParams{InstanceID: instanceID, EpisodeID: episodeID, Label: &label}); err != nil {
+		t.Fatalf("expected relabel to succeed, got %v", err)
+	}
+	_, err := service.UpdateEpisode(context.Background(), UpdateEpisodeParams{InstanceID: instanceID, EpisodeID: episodeID, AirsAt: &moved})
+	if !errors.Is(err, ErrEpisodeConflict) || !strings.Contains(err.Error(), "2 group memberships, 1 activity") {
+		t.Fatalf("expected reschedule to be refused with dependents, got %v", err)
+	}
+
+	fake.episodeUsage = db.GetInstanceEpisodeUsageRow{}
+	if _, err := service.UpdateEpisode(context.Background(), UpdateEpisodeParams{InstanceID: instanceID, EpisodeID: episodeID, AirsAt: &moved}); err != nil {
+		t.Fatalf("expected unused episode to reschedule, got %v", err)
+	}
+	if len(fake.updatedEpisodes) != 2 || fake.updatedEpisodes[0].Label != label || !fake.updatedEpisodes[1].AirsAt.Time.Equal(moved) {
+		t.Fatalf("unexpected episode updates: %+v", fake.updatedEpisodes)
+	}
+}
+
+func TestDeleteEpisodeKeepsEpisodesWithOutcomes(t *testing.T) {
+	instanceID := testUUID()
+	episodeID := testUUID()
+	fake := &fakeQuerier{
+		episodes:     []db.ListInstanceEpisodesRow{{ID: episodeID, EpisodeNumber: 4}},
+		episodeUsage: db.GetInstanceEpisodeUsageRow{OutcomeHistory: 1},
+	}
+	service := NewService(fake)
+
+	if _, err := service.DeleteEpisode(context.Background(), instanceID, episodeID); !errors.Is(err, ErrEpisodeConflict) {
+		t.Fatalf("expected delete to be refused, got %v", err)
+	}
+	fake.episodeUsage = db.GetInstanceEpisodeUsageRow{}
+	if _, err := service.DeleteEpisode(context.Background(), instanceID, episodeID); err != nil {
+		t.Fatalf("expected unused episode to delete, got %v", err)
+	}
+	if len(fake.deletedEpisodes) != 1 {
+		t.Fatalf("expected one deleted episode, got %d", len(fake.deletedEpisodes))
+	}
+}
+
 func TestCreateMembershipPeriodRequiresEpisodeBoundary(t *testing.T) {
 	groupID := testUUID()
 	participantID := testUUID()
@@ -90,6 +171,43 @@ func TestCreateMembershipPeriodRequiresEpisodeBoundary(t *testing.T) {
 	}
 }
 
+func TestEndMembershipPeriodRequiresEpisodeBoundary(t *testing.T) {
+	groupID := testUUID()
+	participantID := testUUID()
+	boundary0 := time.Date(2026, time.March, 4, 20, 0, 0, 0, time.UTC)
+	boundary1 := boundary0.Add(7 * 24 * time.Hour)
+
+	fake := &fakeQuerier{
+		participantGroup: db.GetParticipantGroupRow{ID: groupID, InstanceID: testUUID()},
+		episodes: []db.ListInstanceEpisodesRow{
+			{EpisodeNumber: 1, AirsAt: timestamptz(boundary0)},
+			{EpisodeNumber: 2, AirsAt: timestamptz(boundary1)},
+		},
+	}
+	service := NewService(fake)
+	params := EndMembershipPeriodParams{ParticipantGroupID: groupID, ParticipantID: participantID, EndsAt: boundary1.Add(time.Hour)}
+
+	if _, err := service.EndMembershipPeriod(context.Background(), params); !errors.Is(err, ErrEpisodeBoundary) {
+		t.Fatalf("expected boundary error, got %v", err)
+	}
+	if len(fake.endedMemberships) != 0 {
+		t.Fatalf("expected no membership update, got %d", len(fake.endedMemberships))
+	}
+
+	params.EndsAt = boundary1
+	if _, err := service.EndMembershipPeriod(context.Background(), params); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected no open membership to end, got %v", err)
+	}
+	fake.endedMembershipRows = []db.EndParticipantGroupMembershipPeriodsRow{{ParticipantGroupID: groupID, ParticipantID: participantID, EndsAt: timestamptz(boundary1)}}
+	ended, err := service.EndMembershipPeriod(context.Background(), params)
+	if err != nil || len(ended) != 1 {
+		t.Fatalf("expected one ended membership, got %d, %v", len(ended), err)
+	}
+	if !fake.endedMemberships[1].EndsAt.Time.Equal(boundary1) {
+		t.Fatalf("expected ends_at %s, got %+v", boundary1, fake.endedMemberships[1])
+	}
+}
+
 func TestCreateActivityGroupAssignmentRequiresEpisodeBoundary(t *testing.T) {
 	activityID := testUUID()
 	groupID := testUUID()
@@ -198,6 +316,8 @@ type fakeQuerier struct {
 	participantGroup                      db.GetParticipantGroupRow
 	membershipRow                         db.CreateParticipantGroupMembershipPeriodRow
 	createdMemberships                    []db.CreateParticipantGroupMembershipPeriodParams
+	endedMemberships                      []db.EndParticipantGroupMembershipPeriodsParams
+	endedMembershipRows                   []db.EndParticipantGroupMembershipPeriodsRow
 	activeMembershipsByGroup              map[[16]byte][]db.ListActiveParticipantGroupMembershipsAtRow
 	activityOccurrence                    db.GetActivityOccurrenceRow
 	occurrenceGroups                      []db.ListActivityOccurrenceGroupsRow
@@ -214,6 +334,8 @@ type fakeQuerier struct {
 	instanceActivitiesByType              []db.ListInstanceActivitiesByTypeRow
 	updatedOccurrences                    []db.UpdateActivityOccurrenceStatusAndMetadataParams
 	createdBonusLedgerEntries             []db.CreateBonusPointLedgerEntryParams
+	liveBonusLedgerEntries                []db.ListLiveBonusPointLedgerEntriesByOccurrenceRow
+	reversedBonusLedgerEntries            []db.ReverseBonusPointLedgerEntryParams
 	visibleTotal                          int32
 	secretTotal                           int32
 	visibleTotalAsOf                      int32
@@ -223,6 +345,9 @@ type fakeQuerier struct {
 	createdAdvantages                     []db.CreateParticipantAdvantageParams
 	createdPonyOwnerships                 []db.CreateParticipantPonyOwnershipParams
 	activePonyOwnershipsByContestant      []db.ListActiveParticipantPonyOwnershipsByContestantAtRow
+	episodeUsage                          db.GetInstanceEpisodeUsageRow
+	updatedEpisodes                       []db.UpdateInstanceEpisodeParams
+	deletedEpisodes                       []pgtype.UUID
 }
 
 func (f *fakeQuerier) CreateInstanceEpisode(_ context.Context, arg db.CreateInstanceEpisodeParams) (db.CreateInstanceEpisodeRow, error) {
@@ -245,6 +370,29 @@ func (f *fakeQuerier) ListEpisodeBoundaryWindows(context.Context, pgtype.UUID) (
 	return nil, errors.New("unexpected call")
 }
 
+func (f *fakeQuerier) GetInstanceEpisode(_ context.Context, arg db.GetInstanceEpisodeParams) (db.GetInstanceEpisodeRow, error) {
+	for _, episode := range f.episodes {
+		if episode.ID == arg.ID {
+			return db.GetInstanceEpisodeRow(episode), nil
+		}
+	}
+	return db.GetInstanceEpisodeRow{}, errors.New("episode not found")
+}
+
+func (f *fakeQuerier) GetInstanceEpisodeUsage(context.Context, pgtype.UUID) (db.GetInstanceEpisodeUsageRow, error) {
+	return f.episodeUsage, nil
+}
+
+func (f *fakeQuerier) UpdateInstanceEpisode(_ context.Context, arg db.UpdateInstanceEpisodeParams) (db.UpdateInstanceEpisodeRow, error) {
+	f.updatedEpisodes = append(f.updatedEpisodes, arg)
+	return db.UpdateInstanceEpisodeRow{ID: arg.ID, Label: arg.Label, AirsAt: arg.AirsAt}, nil
+}
+
+func (f *fakeQuerier) DeleteInstanceEpisode(_ context.Context, id pgtype.UUID) error {
+	f.deletedEpisodes = append(f.deletedEpisodes, id)
+	return nil
+}
+
 func (f *fakeQuerier) GetParticipantGroup(context.Context, pgtype.UUID) (db.GetParticipantGroupRow, error) {
 	return f.participantGroup, nil
 }
@@ -254,6 +402,11 @@ func (f *fakeQuerier) CreateParticipantGroupMembershipPeriod(_ context.Context,
 	return f.membershipRow, nil
 }
 
+func (f *fakeQuerier) EndParticipantGroupMembershipPeriods(_ context.Context, arg db.EndParticipantGroupMembershipPeriodsParams) ([]db.EndParticipantGroupMembershipPeriodsRow, error) {
+	f.endedMemberships = append(f.endedMemberships, arg)
+	return f.endedMembershipRows, nil
+}
+
 func (f *fakeQuerier) ListActiveParticipantGroupMembershipsAt(_ context.Context, arg db.ListActiveParticipantGroupMembershipsAtParams) ([]db.ListActiveParticipantGroupMembershipsAtRow, error) {
 	if f.activeMembershipsByGroup == nil {
 		return nil, errors.New("unexpected call")
@@ -336,6 +489,20 @@ func (f *fakeQuerier) CreateBonusPointLedgerEntry(_ context.Context, arg db.Crea
 	}, nil
 }
 
+func (f *fakeQuerier) ListLiveBonusPointLedgerEntriesByOccurrence(context.Context, pgtype.UUID) ([]db.ListLiveBonusPointLedgerEntriesByOccurrenceRow, error) {
+	return f.liveBonusLedgerEntries, nil
+}
+
+func (f *fakeQuerier) ReverseBonusPointLedgerEntry(_ context.Context, arg db.ReverseBonusPointLedgerEntryParams) (db.ReverseBonusPointLedgerEntryRow, error) {
+	f.reversedBonusLedgerEntries = append(f.reversedBonusLedgerEntries, arg)
+	for _, entry := range f.liveBonusLedgerEntries {
+		if entry.ID == arg.ID {
+			return db.ReverseBonusPointLedgerEntryRow{ParticipantID: entry.ParticipantID, EntryKind: bonusEntryKindCorrection, Points: -entry.Points, Visibility: entry.Visibility}, nil
+		}
+	}
+	return db.ReverseBonusPointLedgerEntryRow{}, errors.New("unexpected reversal")
+}
+
 func (f *fakeQuerier) GetVisibleBonusTotalByParticipant(context.Context, db.GetVisibleBonusTotalByParticipantParams) (int32, error) {
 	return f.visibleTotal, nil
 }
@@ -0,0 +1,72 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/gameplay"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// listActivityTypes reports every registered activity type with the JSON
+// schemas its metadata is checked against, so clients can build forms.
+func (s *Server) listActivityTypes(c *gin.Context) {
+	types := gameplay.ActivityTypes()
+	response := make([]gin.H, 0, len(types))
+	for _, activityType := range types {
+		response = append(response, gin.H{
+			"activity_type": activityType.Name,
+			"description":   activityType.Description,
+			"resolvable":    activityType.Resolvable(),
+			"schemas": gin.H{
+				"activity":    metadataSchemaOrObject(activityType.Schemas.Activity),
+				"assignment":  metadataSchemaOrObject(activityType.Schemas.Assignment),
+				"occurrence":  metadataSchemaOrObject(activityType.Schemas.Occurrence),
+				"participant": metadataSchemaOrObject(activityType.Schemas.Participant),
+			},
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"activity_types": response})
+}
+
+func metadataSchemaOrObject(schema *gameplay.MetadataSchema) *gameplay.MetadataSchema {
+	if schema == nil {
+		return &gameplay.MetadataSchema{Type: "object"}
+	}
+	return schema
+}
+
+// registeredActivityType returns the registered type of an activity. ok is
+// false for activities whose type predates the registry, which are written
+// without schema checks.
+func (s *Server) registeredActivityType(ctx context.Context, activityID pgtype.UUID) (activityType gameplay.ActivityType, ok bool, err error) {
+	activity, err := s.queries.GetInstanceActivity(ctx, activityID)
+	if err != nil {
+		return gameplay.ActivityType{}, false, err
+	}
+	activityType, ok = gameplay.LookupActivityType(activity.ActivityType)
+	return activityType, ok, nil
+}
+
+// validateMetadataRequest responds 400 and returns false when metadata does
+// not match schema.
+func validateMetadataRequest(c *gin.Context, schema *gameplay.MetadataSchema, metadata []byte) bool {
+	if err := schema.Validate(metadata); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return false
+	}
+	return true
+}
+
+// respondActivityLookupError maps a failed activity or occurrence lookup to a
+// response.
+func respondActivityLookupError(c *gin.Context, err error, notFound string) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, errorResponse{Error: notFound})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,392 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/scheduler"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	advantageStatusActive  = "active"
+	advantageStatusUsed    = "used"
+	advantageStatusExpired = "expired"
+
+	// Secret advantages are shown only to their holder and instance admins,
+	// like secret ledger entries. Playing one with reveal set makes it
+	// revealed, which everyone can see.
+	advantageVisibilityPublic = "public"
+	advantageVisibilitySecret = "secret"
+
+	jobTypeExpireAdvantages = "expire_advantages"
+)
+
+type grantAdvantageRequest struct {
+	ParticipantID              string           `json:"participant_id" binding:"required"`
+	ParticipantGroupID         string           `json:"participant_group_id"`
+	AdvantageType              string           `json:"advantage_type" binding:"required"`
+	Name                       string           `json:"name" binding:"required"`
+	Visibility                 string           `json:"visibility"`
+	SourceActivityOccurrenceID string           `json:"source_activity_occurrence_id"`
+	EffectiveAt                *time.Time       `json:"effective_at"`
+	EffectiveUntil             *time.Time       `json:"effective_until"`
+	Metadata                   *json.RawMessage `json:"metadata"`
+}
+
+type playAdvantageRequest struct {
+	Reveal bool   `json:"reveal"`
+	Note   string `json:"note"`
+}
+
+func advantageToJSON(row db.ListParticipantAdvantagesByInstanceRow) gin.H {
+	return gin.H{
+		"id":                            pgUUIDString(row.ID),
+		"participant_id":                pgUUIDString(row.ParticipantID),
+		"participant_name":              row.ParticipantName,
+		"participant_group_id":          pgUUIDPointer(row.ParticipantGroupID),
+		"participant_group_name":        pgTextPointer(row.ParticipantGroupName),
+		"advantage_type":                row.AdvantageType,
+		"name":                          row.Name,
+		"status":                        row.Status,
+		"visibility":                    row.Visibility,
+		"source_activity_occurrence_id": pgUUIDPointer(row.SourceActivityOccurrenceID),
+		"granted_at":                    formatTimestamp(row.GrantedAt),
+		"effective_at":                  formatTimestamp(row.EffectiveAt),
+		"effective_until":               formatNullableTimestamp(row.EffectiveUntil),
+		"used_at":                       formatNullableTimestamp(row.UsedAt),
+		"metadata":                      json.RawMessage(nonEmptyMetadata(row.Metadata)),
+		"created_at":                    formatTimestamp(row.CreatedAt),
+		"updated_at":                    formatTimestamp(row.UpdatedAt),
+	}
+}
+
+// parseOptionalUUIDField parses an optional UUID from a request body, writing
+// a 400 when it is present but malformed.
+func parseOptionalUUIDField(c *gin.Context, field, raw string) (pgtype.UUID, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return pgtype.UUID{}, true
+	}
+	parsed, err := uuid.Parse(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: field + " must be a UUID"})
+		return pgtype.UUID{}, false
+	}
+	return toPGUUID(parsed), true
+}
+
+// expireAdvantages marks active advantages whose effective_until has passed
+// as expired, so reads and plays never see a stale active status.
+func expireAdvantages(ctx context.Context, q *db.Queries, instanceID pgtype.UUID, at time.Time) (int64, error) {
+	return q.ExpireParticipantAdvantages(ctx, db.ExpireParticipantAdvantagesParams{
+		InstanceID: instanceID,
+		At:         optionalTime(at),
+	})
+}
+
+// expireAdvantagesJob sweeps lapsed advantages when an episode airs.
+func (s *Server) expireAdvantagesJob(ctx context.Context, tx pgx.Tx, job scheduler.Job) (scheduler.Result, error) {
+	expired, err := expireAdvantages(ctx, s.queries.WithTx(tx), job.InstanceID, job.Now)
+	if err != nil {
+		return scheduler.Result{}, err
+	}
+	if expired == 0 {
+		return scheduler.Result{Skipped: true, Detail: gin.H{"reason": "no advantages lapsed"}}, nil
+	}
+	return scheduler.Result{Detail: gin.H{"expired_advantages": expired}}, nil
+}
+
+func (s *Server) grantAdvantage(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+
+	var req grantAdvantageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	participantID, ok := parseOptionalUUIDField(c, "participant_id", req.ParticipantID)
+	if !ok {
+		return
+	}
+	groupID, ok := parseOptionalUUIDField(c, "participant_group_id", req.ParticipantGroupID)
+	if !ok {
+		return
+	}
+	occurrenceID, ok := parseOptionalUUIDField(c, "source_activity_occurrence_id", req.SourceActivityOccurrenceID)
+	if !ok {
+		return
+	}
+	advantageType := strings.TrimSpace(req.AdvantageType)
+	name := strings.TrimSpace(req.Name)
+	if advantageType == "" || name == "" {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "advantage_type and name are required"})
+		return
+	}
+	visibility := strings.ToLower(strings.TrimSpace(req.Visibility))
+	if visibility == "" {
+		visibility = advantageVisibilitySecret
+	}
+	if visibility != advantageVisibilityPublic && visibility != advantageVisibilitySecret {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "visibility must be public or secret"})
+		return
+	}
+	now := time.Now().UTC()
+	effectiveAt := now
+	if req.EffectiveAt != nil {
+		effectiveAt = req.EffectiveAt.UTC()
+	}
+	if req.EffectiveUntil != nil && !req.EffectiveUntil.After(effectiveAt) {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "effective_until must be after effective_at"})
+		return
+	}
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	created, err := qtx.CreateParticipantAdvantage(ctx, db.CreateParticipantAdvantageParams{
+		AdvantageType:              advantageType,
+		Name:                       name,
+		Status:                     advantageStatusActive,
+		GrantedAt:                  optionalTime(now),
+		EffectiveAt:                optionalTime(effectiveAt),
+		EffectiveUntil:             optionalTimePtr(req.EffectiveUntil),
+		Metadata:                   defaultJSONB(req.Metadata),
+		Visibility:                 visibility,
+		InstanceID:                 toPGUUID(instanceID),
+		ParticipantID:              participantID,
+		ParticipantGroupID:         groupID,
+		SourceActivityOccurrenceID: occurrenceID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "participant not found"})
+			return
+		}
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	advantage, err := qtx.GetParticipantAdvantage(ctx, created.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	response := advantageToJSON(db.ListParticipantAdvantagesByInstanceRow(advantage))
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionAdvantageGrant, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"advantage": response})
+}
+
+// listAdvantages lists an instance's advantages. Secret ones are included only
+// for their holder and instance admins.
+func (s *Server) listAdvantages(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	participantID, ok := parseOptionalUUIDField(c, "participant_id", c.Query("participant_id"))
+	if !ok {
+		return
+	}
+	s.writeAdvantages(c, instanceID, participantID)
+}
+
+func (s *Server) listParticipantAdvantages(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	participantID, ok := parseUUIDPath(c, "participantID")
+	if !ok {
+		return
+	}
+	participant, err := s.queries.GetParticipant(c.Request.Context(), toPGUUID(participantID))
+	if err != nil || participant.InstanceID != toPGUUID(instanceID) {
+		if err == nil || errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "participant not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	s.writeAdvantages(c, instanceID, participant.ID)
+}
+
+func (s *Server) writeAdvantages(c *gin.Context, instanceID uuid.UUID, participantID pgtype.UUID) {
+	status := strings.ToLower(strings.TrimSpace(c.Query("status")))
+	switch status {
+	case "", advantageStatusActive, advantageStatusUsed, advantageStatusExpired:
+	default:
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "status must be active, used, or expired"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := expireAdvantages(ctx, s.queries, toPGUUID(instanceID), time.Now().UTC()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	rows, err := s.queries.ListParticipantAdvantagesByInstance(ctx, toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	discordUserID := discordUserIDFromRequest(c.Request)
+	isAdmin, err := s.isInstanceAdmin(ctx, toPGUUID(instanceID), discordUserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	var viewerID pgtype.UUID
+	if discordUserID != "" {
+		viewer, err := s.queries.GetParticipantByDiscordUserID(ctx, db.GetParticipantByDiscordUserIDParams{
+			InstanceID:    toPGUUID(instanceID),
+			DiscordUserID: pgtype.Text{String: discordUserID, Valid: true},
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		viewerID = viewer.ID
+	}
+
+	advantages := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		if participantID.Valid && row.ParticipantID != participantID {
+			continue
+		}
+		if status != "" && row.Status != status {
+			continue
+		}
+		if row.Visibility == advantageVisibilitySecret && !isAdmin && (!viewerID.Valid || row.ParticipantID != viewerID) {
+			continue
+		}
+		advantages = append(advantages, advantageToJSON(row))
+	}
+	c.JSON(http.StatusOK, gin.H{"advantages": advantages})
+}
+
+// playAdvantage uses one of the caller's active advantages. Admins may play an
+// advantage on a participant's behalf.
+func (s *Server) playAdvantage(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	advantageID, ok := parseUUIDPath(c, "advantageID")
+	if !ok {
+		return
+	}
+	var req playAdvantageRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	now := time.Now().UTC()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	advantage, err := qtx.GetParticipantAdvantage(ctx, toPGUUID(advantageID))
+	if err != nil || advantage.InstanceID != toPGUUID(instanceID) {
+		if err == nil || errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "advantage not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	participant, err := qtx.GetParticipant(ctx, advantage.ParticipantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	discordUserID := discordUserIDFromRequest(c.Request)
+	allowed, err := s.canViewSecretParticipantData(ctx, toPGUUID(instanceID), discordUserID, participant)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, errorResponse{Error: "only the advantage holder or an instance admin can play it"})
+		return
+	}
+
+	if _, err := expireAdvantages(ctx, qtx, toPGUUID(instanceID), now); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	metadata, err := json.Marshal(gin.H{"played_by_discord_user_id": discordUserID, "play_note": strings.TrimSpace(req.Note)})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	played, err := qtx.PlayParticipantAdvantage(ctx, db.PlayParticipantAdvantageParams{
+		At:       optionalTime(now),
+		Reveal:   req.Reveal,
+		Metadata: metadata,
+		ID:       advantage.ID,
+	})
+	if err != nil {
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	if played == 0 {
+		current, err := qtx.GetParticipantAdvantage(ctx, advantage.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		message := fmt.Sprintf("advantage is %s", current.Status)
+		if current.Status == advantageStatusActive {
+			message = "advantage cannot be played until " + formatTimestamp(current.EffectiveAt)
+		}
+		c.JSON(http.StatusConflict, errorResponse{Error: message})
+		return
+	}
+	updated, err := qtx.GetParticipantAdvantage(ctx, advantage.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"advantage": advantageToJSON(db.ListParticipantAdvantagesByInstanceRow(updated))})
+}
@@ -0,0 +1,223 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Audit actions recorded for admin mutations.
+const (
+	auditActionOutcomeUpsert            = "outcome.upsert"
+	auditActionMergeAuctionRecord       = "merge_auction.record"
+	auditActionIndividualPonyImmunity   = "individual_pony.immunity"
+	auditActionStirThePotClose          = "stir_the_pot.close"
+	auditActionParticipantDiscordLink   = "participant.discord_link"
+	auditActionParticipantDiscordUnlink = "participant.discord_unlink"
+	auditActionOccurrenceReresolve      = "occurrence.reresolve"
+	auditActionOccurrenceResolve        = "occurrence.resolve"
+	auditActionInstanceState            = "instance.state"
+	auditActionInstanceBootstrap        = "instance.bootstrap"
+	auditActionInstanceImport           = "instance.import"
+	auditActionEpisodeCreate            = "episode.create"
+	auditActionEpisodeUpdate            = "episode.update"
+	auditActionEpisodeDelete            = "episode.delete"
+	auditActionDraftDeadlineSet         = "draft_deadline.set"
+	auditActionScoringStrategySet       = "scoring_strategy.set"
+	auditActionContestantOddsSet        = "contestant_odds.set"
+	auditActionGroupRealignment         = "group.realignment"
+	auditActionGroupCreate              = "group.create"
+	auditActionGroupMembershipCreate    = "group_membership.create"
+	auditActionGroupMembershipEnd       = "group_membership.end"
+	auditActionAdvantageGrant           = "advantage.grant"
+	auditActionFinaleBingoLoanSharks    = "finale_bingo.loan_sharks"
+	auditActionFinaleBingoScores        = "finale_bingo.scores"
+	auditActionAuctionLotStart          = "auction_lot.start"
+	auditActionAuctionLotStop           = "auction_lot.stop"
+	auditActionStirThePotStart          = "stir_the_pot.start"
+	auditActionWebhookCreate            = "webhook.create"
+	auditActionWebhookDelete            = "webhook.delete"
+	auditActionScheduledJobRetry        = "scheduled_job.retry"
+	auditActionWebhookDeliveryRetry     = "webhook_delivery.retry"
+	auditActionCredentialCreate         = "credential.create"
+	auditActionCredentialRotate         = "credential.rotate"
+	auditActionCredentialRevoke         = "credential.revoke"
+)
+
+const (
+	defaultAuditEventLimit = 100
+	maxAuditEventLimit     = 500
+)
+
+// recordAuditEvent appends an audit event for the request's instance through
+// q, which should be the transaction the mutation itself runs in so the
+// event commits or rolls back with it. before and after are marshalled to
+// JSON; nil is stored as NULL. Mutations that are not tied to an instance,
+// such as credential changes, pass an invalid instanceID and are read back
+// from GET /audit.
+func recordAuditEvent(c *gin.Context, q *db.Queries, instanceID pgtype.UUID, action string, before, after any) error {
+	beforeJSON, err := auditPayload(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := auditPayload(after)
+	if err != nil {
+		return err
+	}
+	principal, _ := ServicePrincipal(c.Request.Context())
+	if !instanceID.Valid {
+		return q.CreateGlobalAuditEvent(c.Request.Context(), db.CreateGlobalAuditEventParams{
+			Action:             action,
+			ServicePrincipal:   auditText(principal),
+			ActorDiscordUserID: auditText(discordUserIDFromRequest(c.Request)),
+			Method:             c.Request.Method,
+			Route:              c.FullPath(),
+			Before:             beforeJSON,
+			After:              afterJSON,
+		})
+	}
+	return q.CreateAuditEvent(c.Request.Context(), db.CreateAuditEventParams{
+		Action:             action,
+		ServicePrincipal:   auditText(principal),
+		ActorDiscordUserID: auditText(discordUserIDFromRequest(c.Request)),
+		Method:             c.Request.Method,
+		Route:              c.FullPath(),
+		Before:             beforeJSON,
+		After:              afterJSON,
+		InstanceID:         instanceID,
+	})
+}
+
+func auditPayload(value any) ([]byte, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
+
+// listAuditEvents answers GET /instances/:instanceID/audit for instance
+// admins, newest first. It filters on action, actor (a Discord user ID),
+// and an occurred_at window of since (inclusive) and until (exclusive).
+func (s *Server) listAuditEvents(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+
+	params, err := auditEventFilter(c.Request.URL.Query().Get)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	params.InstanceID = toPGUUID(instanceID)
+
+	events, err := s.queries.ListAuditEventsByInstance(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"events": auditEventsToJSON(events)})
+}
+
+// listGlobalAuditEvents answers GET /audit with the events that are not tied
+// to an instance, such as credential changes. It takes the same filters as
+// listAuditEvents.
+func (s *Server) listGlobalAuditEvents(c *gin.Context) {
+	params, err := auditEventFilter(c.Request.URL.Query().Get)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	rows, err := s.queries.ListGlobalAuditEvents(c.Request.Context(), db.ListGlobalAuditEventsParams{
+		Action:             params.Action,
+		ActorDiscordUserID: params.ActorDiscordUserID,
+		Since:              params.Since,
+		Until:              params.Until,
+		RowLimit:           params.RowLimit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	events := make([]db.ListAuditEventsByInstanceRow, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, db.ListAuditEventsByInstanceRow(row))
+	}
+	c.JSON(http.StatusOK, gin.H{"events": auditEventsToJSON(events)})
+}
+
+func auditEventsToJSON(events []db.ListAuditEventsByInstanceRow) []gin.H {
+	response := make([]gin.H, 0, len(events))
+	for _, event := range events {
+		response = append(response, gin.H{
+			"id":                    pgUUIDString(event.ID),
+			"action":                event.Action,
+			"service_principal":     pgTextPointer(event.ServicePrincipal),
+			"actor_discord_user_id": pgTextPointer(event.ActorDiscordUserID),
+			"method":                event.Method,
+			"route":                 event.Route,
+			"before":                auditPayloadJSON(event.Before),
+			"after":                 auditPayloadJSON(event.After),
+			"occurred_at":           formatTimestamp(event.OccurredAt),
+		})
+	}
+	return response
+}
+
+// auditEventFilter builds list params from query values looked up by get.
+func auditEventFilter(get func(string) string) (db.ListAuditEventsByInstanceParams, error) {
+	params := db.ListAuditEventsByInstanceParams{RowLimit: defaultAuditEventLimit}
+	params.Action = auditText(get("action"))
+	params.ActorDiscordUserID = auditText(get("actor"))
+	for _, bound := range []struct {
+		name   string
+		target *pgtype.Timestamptz
+	}{
+		{"since", &params.Since},
+		{"until", &params.Until},
+	} {
+		raw := strings.TrimSpace(get(bound.name))
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return params, errors.New(bound.name + " must be an RFC3339 timestamp")
+		}
+		*bound.target = optionalTime(parsed)
+	}
+	if raw := strings.TrimSpace(get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxAuditEventLimit {
+			return params, errors.New("limit must be between 1 and " + strconv.Itoa(maxAuditEventLimit))
+		}
+		params.RowLimit = int32(limit)
+	}
+	return params, nil
+}
+
+// auditText stores blank values as NULL so filters on them stay simple.
+func auditText(value string) pgtype.Text {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: value, Valid: true}
+}
+
+func auditPayloadJSON(payload []byte) any {
+	if len(payload) == 0 {
+		return nil
+	}
+	return json.RawMessage(payload)
+}
@@ -0,0 +1,57 @@
+package httpapi
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestAuditEventFilterParsesQuery(t *testing.T) {
+	query := url.Values{
+		"action": {" outcome.upsert "},
+		"actor":  {"admin-discord"},
+		"since":  {"2026-03-01T00:00:00Z"},
+		"limit":  {"25"},
+	}
+	params, err := auditEventFilter(query.Get)
+	if err != nil {
+		t.Fatalf("auditEventFilter returned error: %v", err)
+	}
+	if params.Action.String != "outcome.upsert" || !params.Action.Valid {
+		t.Fatalf("unexpected action filter: %+v", params.Action)
+	}
+	if params.ActorDiscordUserID.String != "admin-discord" {
+		t.Fatalf("unexpected actor filter: %+v", params.ActorDiscordUserID)
+	}
+	if !params.Since.Valid || !params.Since.Time.Equal(time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected since filter: %+v", params.Since)
+	}
+	if params.Until.Valid {
+		t.Fatalf("expected no until filter, got %+v", params.Until)
+	}
+	if params.RowLimit != 25 {
+		t.Fatalf("RowLimit = %d, want 25", params.RowLimit)
+	}
+
+	defaults, err := auditEventFilter(url.Values{}.Get)
+	if err != nil {
+		t.Fatalf("auditEventFilter returned error: %v", err)
+	}
+	if defaults.Action.Valid || defaults.ActorDiscordUserID.Valid || defaults.RowLimit != defaultAuditEventLimit {
+		t.Fatalf("unexpected default filter: %+v", defaults)
+	}
+}
+
+func TestAuditEventFilterRejectsBadValues(t *testing.T) {
+	for _, query := range []url.Values{
+		{"since": {"yesterday"}},
+		{"until": {"2026-03-01"}},
+		{"limit": {"0"}},
+		{"limit": {"501"}},
+		{"limit": {"ten"}},
+	} {
+		if _, err := auditEventFilter(query.Get); err == nil {
+			t.Fatalf("expected error for %v", query)
+		}
+	}
+}
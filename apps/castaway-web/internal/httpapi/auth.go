@@ -2,6 +2,7 @@ package httpapi
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -15,14 +16,23 @@ const (
 	discordUserIDHeader     = "X-Discord-User-ID"
 )
 
+// ServiceAuthConfig configures the shared bearer tokens. Scopes is what those
+// tokens may do, checked against the same route table as API credentials; it
+// defaults to super-admin. Per-caller API credentials are preferred over
+// shared tokens.
 type ServiceAuthConfig struct {
 	Enabled      bool
 	BearerTokens []string
 	Principal    string
+	Scopes       []string
 }
 
 type serviceAuthContextKey struct{}
 
+// sharedServiceTokenContextKey marks requests authenticated with one of the
+// SERVICE_AUTH_BEARER_TOKENS rather than an API credential.
+type sharedServiceTokenContextKey struct{}
+
 func ServicePrincipal(ctx context.Context) (string, bool) {
 	principal, ok := ctx.Value(serviceAuthContextKey{}).(string)
 	if !ok || strings.TrimSpace(principal) == "" {
@@ -51,6 +61,9 @@ func normalizeServiceAuthConfig(cfg ServiceAuthConfig) ServiceAuthConfig {
 		tokens = append(tokens, trimmed)
 	}
 	cfg.BearerTokens = tokens
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{scopeSuperAdmin}
+	}
 
 	return cfg
 }
@@ -73,14 +86,36 @@ func (s *Server) requireServiceAuth() gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{Error: "unauthorized"})
 			return
 		}
-		if _, ok := s.serviceAuthBearerTokens[token]; !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{Error: "unauthorized"})
+		if _, ok := s.serviceAuthBearerTokens[token]; ok {
+			required := credentialRouteScope(c.Request.Method, c.FullPath(), discordUserIDFromRequest(c.Request) != "")
+			if !scopesInclude(s.serviceAuth.Scopes, required) {
+				c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{Error: fmt.Sprintf("service token lacks the %s scope", required)})
+				return
+			}
+			ctx := context.WithValue(c.Request.Context(), serviceAuthContextKey{}, s.serviceAuth.Principal)
+			ctx = context.WithValue(ctx, sharedServiceTokenContextKey{}, true)
+			c.Request = c.Request.WithContext(ctx)
+			c.Set("service_principal", s.serviceAuth.Principal)
+			c.Next()
 			return
 		}
 
-		ctx := context.WithValue(c.Request.Context(), serviceAuthContextKey{}, s.serviceAuth.Principal)
+		credential, ok, err := s.lookupAPICredential(c.Request.Context(), token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{Error: "unauthorized"})
+			return
+		}
+		ctx := context.WithValue(c.Request.Context(), serviceAuthContextKey{}, credential.name)
+		ctx = context.WithValue(ctx, apiCredentialContextKey{}, credential)
 		c.Request = c.Request.WithContext(ctx)
-		c.Set("service_principal", s.serviceAuth.Principal)
+		c.Set("service_principal", credential.name)
+		if !s.authorizeAPICredential(c, credential) {
+			return
+		}
 		c.Next()
 	}
 }
@@ -92,16 +127,47 @@ func discordUserIDFromRequest(r *http.Request) string {
 	return strings.TrimSpace(r.Header.Get(discordUserIDHeader))
 }
 
+// isInstanceAdmin reports whether the caller may act as an admin of the
+// instance. A super-admin credential is one on its own; other credentials
+// need the instance-admin scope and a Discord user who admins the instance.
 func (s *Server) isInstanceAdmin(ctx context.Context, instanceID pgtype.UUID, discordUserID string) (bool, error) {
+	return s.isInstanceAdminTx(ctx, s.queries, instanceID, discordUserID)
+}
+
+// isInstanceAdminTx is isInstanceAdmin reading the admin list through q, so a
+// caller can check against the transaction it is about to write in.
+func (s *Server) isInstanceAdminTx(ctx context.Context, q *db.Queries, instanceID pgtype.UUID, discordUserID string) (bool, error) {
+	if credential, ok := apiCredentialFromContext(ctx); ok {
+		if !credential.allowsInstance(instanceID) {
+			return false, nil
+		}
+		if credential.hasScope(scopeSuperAdmin) {
+			return true, nil
+		}
+		if !credential.hasScope(scopeInstanceAdmin) {
+			return false, nil
+		}
+	}
 	if strings.TrimSpace(discordUserID) == "" {
 		return false, nil
 	}
-	return s.queries.IsInstanceAdmin(ctx, db.IsInstanceAdminParams{
+	return q.IsInstanceAdmin(ctx, db.IsInstanceAdminParams{
 		InstanceID:    instanceID,
 		DiscordUserID: strings.TrimSpace(discordUserID),
 	})
 }
 
+// isSuperAdminService reports whether the caller is a service with the
+// super-admin scope for the instance: a super-admin API credential that may
+// reach it, or a shared service token configured with super-admin.
+func (s *Server) isSuperAdminService(ctx context.Context, instanceID pgtype.UUID) bool {
+	if credential, ok := apiCredentialFromContext(ctx); ok {
+		return credential.allowsInstance(instanceID) && credential.hasScope(scopeSuperAdmin)
+	}
+	shared, _ := ctx.Value(sharedServiceTokenContextKey{}).(bool)
+	return shared && scopesInclude(s.serviceAuth.Scopes, scopeSuperAdmin)
+}
+
 func (s *Server) canViewSecretParticipantData(ctx context.Context, instanceID pgtype.UUID, discordUserID string, participant db.GetParticipantRow) (bool, error) {
 	if participant.DiscordUserID.Valid {
 		if strings.TrimSpace(participant.DiscordUserID.String) != "" && participant.DiscordUserID.String == strings.TrimSpace(discordUserID) {
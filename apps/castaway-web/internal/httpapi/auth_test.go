@@ -1,9 +1,16 @@
 package httpapi
 
 import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -88,3 +95,201 @@ func TestRouterHealthzRemainsUnauthenticated(t *testing.T) {
 		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
 	}
 }
+
+func TestCredentialRouteScope(t *testing.T) {
+	tests := []struct {
+		method             string
+		route              string
+		actsForDiscordUser bool
+		want               string
+	}{
+		{http.MethodGet, "/instances/:instanceID/leaderboard", false, scopeReadOnly},
+		{http.MethodGet, "/instances/:instanceID/participants/me", true, scopePlayerActions},
+		{http.MethodPost, "/instances/:instanceID/loan-shark/me/borrow", true, scopePlayerActions},
+		{http.MethodPost, "/instances/:instanceID/advantages/:advantageID/play", true, scopePlayerActions},
+		{http.MethodPut, "/instances/:instanceID/outcomes/:position", false, scopeInstanceAdmin},
+		{http.MethodPost, "/occurrences/:occurrenceID/reresolve", true, scopeInstanceAdmin},
+		{http.MethodPost, "/instances", false, scopeSuperAdmin},
+		{http.MethodGet, "/credentials", false, scopeSuperAdmin},
+		{http.MethodDelete, "/credentials/:credentialID", false, scopeSuperAdmin},
+	}
+	for _, tt := range tests {
+		if got := credentialRouteScope(tt.method, tt.route, tt.actsForDiscordUser); got != tt.want {
+			t.Fatalf("credentialRouteScope(%s %s) = %q, want %q", tt.method, tt.route, got, tt.want)
+		}
+	}
+}
+
+func TestAPICredentialScopesAreHierarchical(t *testing.T) {
+	credential := &apiCredential{name: "bot", scopes: []string{scopeInstanceAdmin}}
+	for _, scope := range []string{scopeReadOnly, scopePlayerActions, scopeInstanceAdmin} {
+		if !credential.hasScope(scope) {
+			t.Fatalf("expected instance-admin to include %s", scope)
+		}
+	}
+	if credential.hasScope(scopeSuperAdmin) {
+		t.Fatal("expected instance-admin not to include super-admin")
+	}
+}
+
+func TestAPICredentialDiscordUserBinding(t *testing.T) {
+	tests := []struct {
+		credential    apiCredential
+		discordUserID string
+		want          bool
+	}{
+		{apiCredential{scopes: []string{scopePlayerActions}}, "alice-discord", false},
+		{apiCredential{scopes: []string{scopePlayerActions}, discordUserID: "alice-discord"}, "alice-discord", true},
+		{apiCredential{scopes: []string{scopePlayerActions}, discordUserID: "alice-discord"}, "bob-discord", false},
+		{apiCredential{scopes: []string{scopeInstanceAdmin}}, "bob-discord", true},
+		{apiCredential{scopes: []string{scopeSuperAdmin}, discordUserID: "alice-discord"}, "bob-discord", false},
+	}
+	for _, tt := range tests {
+		if got := tt.credential.allowsDiscordUser(tt.discordUserID); got != tt.want {
+			t.Fatalf("allowsDiscordUser(%q) for %+v = %v, want %v", tt.discordUserID, tt.credential, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeCredentialScopes(t *testing.T) {
+	scopes, err := normalizeCredentialScopes([]string{" super-admin", "read-only", "read-only"})
+	if err != nil {
+		t.Fatalf("normalizeCredentialScopes: %v", err)
+	}
+	if len(scopes) != 2 || scopes[0] != scopeReadOnly || scopes[1] != scopeSuperAdmin {
+		t.Fatalf("scopes = %v, want [read-only super-admin]", scopes)
+	}
+	if _, err := normalizeCredentialScopes([]string{"owner"}); err == nil {
+		t.Fatal("expected unsupported scope error")
+	}
+	if _, err := normalizeCredentialScopes(nil); err == nil {
+		t.Fatal("expected missing scope error")
+	}
+}
+
+func TestAdminRouteTablesMatchRegisteredRoutes(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, route := range New(nil).Router().Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for _, table := range []map[string]bool{superAdminRoutes, instanceAdminRoutes} {
+		for route := range table {
+			if !registered[route] {
+				t.Fatalf("admin route %s is not registered", route)
+			}
+		}
+	}
+}
+
+// TestInstanceAdminHandlersAreInAdminRouteTables keeps the route tables in
+// step with the handlers: a route whose handler requires an instance admin
+// must not be reachable with a player credential.
+func TestInstanceAdminHandlersAreInAdminRouteTables(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(currentDir(t), "*.go"))
+	if err != nil {
+		t.Fatalf("list package files: %v", err)
+	}
+	checksAdmin := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", file, err)
+		}
+		for _, decl := range parsed.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			ast.Inspect(fn.Body, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				if selector, ok := call.Fun.(*ast.SelectorExpr); ok {
+					switch selector.Sel.Name {
+					case "requireInstanceAdminRequest", "requireInstanceAdminTx":
+						checksAdmin[fn.Name.Name] = true
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	for _, route := range New(nil).Router().Routes() {
+		handler := strings.TrimSuffix(route.Handler[strings.LastIndex(route.Handler, ".")+1:], "-fm")
+		key := route.Method + " " + route.Path
+		if checksAdmin[handler] && !instanceAdminRoutes[key] && !superAdminRoutes[key] {
+			t.Errorf("%s requires an instance admin but is missing from instanceAdminRoutes", key)
+		}
+	}
+}
+
+func TestPlayerScopedServiceTokenIsRefusedOnAdminRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := New(nil, WithServiceAuth(ServiceAuthConfig{
+		Enabled:      true,
+		BearerTokens: []string{"player-token"},
+		Scopes:       []string{scopePlayerActions},
+	})).Router()
+	const id = "7b8c3a52-2f0d-4a8e-9c35-3f6c2f1b2d11"
+	for route := range instanceAdminRoutes {
+		method, pattern, _ := strings.Cut(route, " ")
+		path := pattern
+		for _, param := range []string{":instanceID", ":episodeID", ":jobID", ":webhookID", ":deliveryID", ":groupID", ":participantID", ":contestantID", ":activityID", ":occurrenceID"} {
+			path = strings.ReplaceAll(path, param, id)
+		}
+		path = strings.ReplaceAll(path, ":position", "1")
+
+		req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+		req.Header.Set("Authorization", "Bearer player-token")
+		req.Header.Set(discordUserIDHeader, "admin-discord")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusForbidden || !strings.Contains(recorder.Body.String(), scopeInstanceAdmin) {
+			t.Fatalf("%s status = %d, body = %s, want 403 for a player-actions token", route, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func TestServiceTokensDefaultToSuperAdmin(t *testing.T) {
+	server := New(nil, WithServiceAuth(ServiceAuthConfig{Enabled: true, BearerTokens: []string{"test-token"}}))
+	if !scopesInclude(server.serviceAuth.Scopes, scopeSuperAdmin) {
+		t.Fatalf("expected shared tokens to default to super-admin, got %v", server.serviceAuth.Scopes)
+	}
+	if scopesInclude([]string{"owner"}, scopeReadOnly) {
+		t.Fatal("expected an unknown scope to grant nothing")
+	}
+}
+
+func TestDraftOverrideActorFallsBackToServicePrincipal(t *testing.T) {
+	ctx := context.WithValue(context.Background(), serviceAuthContextKey{}, "league-admin")
+	if got := draftOverrideActor(ctx, "admin-discord"); got != "admin-discord" {
+		t.Fatalf("draftOverrideActor with a Discord user = %q, want admin-discord", got)
+	}
+	if got := draftOverrideActor(ctx, ""); got != "league-admin" {
+		t.Fatalf("draftOverrideActor without a Discord user = %q, want league-admin", got)
+	}
+}
+
+func TestCredentialTouchesAreThrottled(t *testing.T) {
+	touches := newCredentialTouches()
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	if !touches.due(1, now) {
+		t.Fatal("expected the first use to be touched")
+	}
+	if touches.due(1, now.Add(30*time.Second)) {
+		t.Fatal("expected a use within the interval to skip the touch")
+	}
+	if !touches.due(2, now.Add(30*time.Second)) {
+		t.Fatal("expected another credential to be touched")
+	}
+	if !touches.due(1, now.Add(credentialTouchInterval)) {
+		t.Fatal("expected a use after the interval to be touched")
+	}
+}
@@ -0,0 +1,115 @@
+package httpapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/app"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/conv"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/gameplay"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/seeddata"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// bootstrapInstance creates an instance from a YAML or JSON season config.
+// Replacing an existing instance with the same name and season requires an
+// admin of that instance and keeps its ID, and an unchanged config is a no-op.
+// The admin check, the bootstrap and its audit event share one transaction.
+func (s *Server) bootstrapInstance(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	season, err := seeddata.ParseSeasonConfig(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	seasonNumber, err := conv.ToInt32(season.Season)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	var before any
+	existing, err := qtx.GetInstanceConfigByNameSeason(ctx, db.GetInstanceConfigByNameSeasonParams{
+		Name:   season.InstanceName,
+		Season: seasonNumber,
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	default:
+		if !s.requireInstanceAdminTx(c, qtx, uuid.UUID(existing.ID.Bytes)) {
+			return
+		}
+		before = gin.H{"state": existing.State, "checksum": existing.ConfigChecksum.String}
+	}
+
+	result, err := app.BootstrapSeasonTx(ctx, tx, season)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrBootstrapConflict):
+			c.JSON(http.StatusConflict, errorResponse{Error: err.Error()})
+		case errors.Is(err, gameplay.ErrEpisodeConflict):
+			c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		default:
+			c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	instance, err := qtx.GetInstance(ctx, result.InstanceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if result.Status != app.BootstrapUnchanged {
+		if err := recordAuditEvent(c, qtx, result.InstanceID, auditActionInstanceBootstrap, before, gin.H{
+			"status":       result.Status,
+			"state":        instance.State,
+			"checksum":     result.Checksum,
+			"participants": result.Seed.Participants,
+			"draft_picks":  result.Seed.DraftPicks,
+			"outcomes":     result.Seed.Outcomes,
+		}); err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	status := http.StatusCreated
+	if result.Status == app.BootstrapUnchanged {
+		status = http.StatusOK
+	}
+	c.JSON(status, gin.H{
+		"bootstrap": gin.H{
+			"status":       result.Status,
+			"checksum":     result.Checksum,
+			"participants": result.Seed.Participants,
+			"draft_picks":  result.Seed.DraftPicks,
+			"outcomes":     result.Seed.Outcomes,
+		},
+		"instance": toInstanceResponse(instance.ID, instance.Name, instance.Season, instance.CreatedAt, instance.State),
+	})
+}
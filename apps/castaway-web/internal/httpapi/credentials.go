@@ -0,0 +1,642 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Credential scopes, from least to most trusted. Each scope includes the
+// ones before it.
+const (
+	scopeReadOnly      = "read-only"
+	scopePlayerActions = "player-actions"
+	scopeInstanceAdmin = "instance-admin"
+	scopeSuperAdmin    = "super-admin"
+)
+
+const (
+	apiCredentialTokenPrefix = "cak_"
+	maxCredentialGracePeriod = 7 * 24 * time.Hour
+)
+
+var credentialScopeRanks = map[string]int{
+	scopeReadOnly:      1,
+	scopePlayerActions: 2,
+	scopeInstanceAdmin: 3,
+	scopeSuperAdmin:    4,
+}
+
+// superAdminRoutes change or read data across every instance, so only
+// unrestricted super-admin credentials may call them.
+var superAdminRoutes = map[string]bool{
+	"POST /instances":                        true,
+	"POST /instances/import":                 true,
+	"POST /instances/bootstrap":              true,
+	"GET /imports/:importID":                 true,
+	"POST /imports/:importID/apply":          true,
+	"GET /credentials":                       true,
+	"POST /credentials":                      true,
+	"POST /credentials/:credentialID/rotate": true,
+	"DELETE /credentials/:credentialID":      true,
+	"GET /audit":                             true,
+}
+
+// instanceAdminRoutes change how an instance is set up or scored, or read
+// what only its admins may see, so a credential needs at least the
+// instance-admin scope to call them. Handlers that also check isInstanceAdmin
+// still require an admin Discord user. Every handler that calls
+// requireInstanceAdminRequest belongs here; a test keeps the two in step.
+var instanceAdminRoutes = map[string]bool{
+	"GET /instances/:instanceID/audit":                                           true,
+	"GET /instances/:instanceID/outbox":                                          true,
+	"GET /instances/:instanceID/scheduled-jobs":                                  true,
+	"GET /instances/:instanceID/scheduled-jobs/:jobID/runs":                      true,
+	"GET /instances/:instanceID/webhooks":                                        true,
+	"GET /instances/:instanceID/webhooks/deliveries":                             true,
+	"GET /instances/:instanceID/draft-overrides":                                 true,
+	"GET /instances/:instanceID/stir-the-pot/tribes/show":                        true,
+	"PUT /instances/:instanceID/state":                                           true,
+	"POST /instances/:instanceID/episodes":                                       true,
+	"PATCH /instances/:instanceID/episodes/:episodeID":                           true,
+	"DELETE /instances/:instanceID/episodes/:episodeID":                          true,
+	"POST /instances/:instanceID/scheduled-jobs/:jobID/retry":                    true,
+	"POST /instances/:instanceID/webhooks":                                       true,
+	"DELETE /instances/:instanceID/webhooks/:webhookID":                          true,
+	"POST /instances/:instanceID/webhooks/deliveries/:deliveryID/retry":          true,
+	"POST /instances/:instanceID/contestants":                                    true,
+	"POST /instances/:instanceID/participants":                                   true,
+	"POST /instances/:instanceID/advantages":                                     true,
+	"POST /instances/:instanceID/groups":                                         true,
+	"POST /instances/:instanceID/groups/realignments/preview":                    true,
+	"POST /instances/:instanceID/groups/realignments":                            true,
+	"POST /instances/:instanceID/groups/:groupID/memberships":                    true,
+	"POST /instances/:instanceID/groups/:groupID/memberships/:participantID/end": true,
+	"POST /instances/:instanceID/stir-the-pot/start":                             true,
+	"POST /instances/:instanceID/stir-the-pot/close":                             true,
+	"POST /instances/:instanceID/auction/lots/start":                             true,
+	"POST /instances/:instanceID/auction/lots/:contestantID/stop":                true,
+	"POST /instances/:instanceID/individual-pony/immunity":                       true,
+	"POST /instances/:instanceID/merge-auction/record":                           true,
+	"POST /instances/:instanceID/finale-bingo/loan-sharks":                       true,
+	"POST /instances/:instanceID/finale-bingo/scores/preview":                    true,
+	"POST /instances/:instanceID/finale-bingo/scores":                            true,
+	"PUT /instances/:instanceID/drafts/:participantID":                           true,
+	"PUT /instances/:instanceID/draft-deadline":                                  true,
+	"PUT /instances/:instanceID/outcomes/:position":                              true,
+	"PUT /instances/:instanceID/contestant-odds":                                 true,
+	"PUT /instances/:instanceID/scoring-strategy":                                true,
+	"POST /instances/:instanceID/activities":                                     true,
+	"POST /activities/:activityID/occurrences":                                   true,
+	"POST /occurrences/:occurrenceID/participants":                               true,
+	"POST /occurrences/:occurrenceID/groups":                                     true,
+	"POST /occurrences/:occurrenceID/resolve":                                    true,
+	"POST /occurrences/:occurrenceID/reresolve":                                  true,
+}
+
+type apiCredentialContextKey struct{}
+
+// apiCredential is the stored credential a request authenticated with.
+// Requests using a token from SERVICE_AUTH_BEARER_TOKENS have none; their
+// routes are checked against ServiceAuthConfig.Scopes instead.
+type apiCredential struct {
+	id            int64
+	name          string
+	scopes        []string
+	instanceIDs   []pgtype.UUID
+	discordUserID string
+}
+
+type createAPICredentialRequest struct {
+	Name          string     `json:"name" binding:"required"`
+	Scopes        []string   `json:"scopes" binding:"required"`
+	InstanceIDs   []string   `json:"instance_ids"`
+	DiscordUserID string     `json:"discord_user_id"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+type rotateAPICredentialRequest struct {
+	GracePeriodSeconds int64      `json:"grace_period_seconds"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+}
+
+func apiCredentialFromContext(ctx context.Context) (*apiCredential, bool) {
+	credential, ok := ctx.Value(apiCredentialContextKey{}).(*apiCredential)
+	return credential, ok && credential != nil
+}
+
+func (credential *apiCredential) hasScope(required string) bool {
+	return scopesInclude(credential.scopes, required)
+}
+
+// scopesInclude reports whether any of scopes grants required. Unknown scopes
+// grant nothing.
+func scopesInclude(scopes []string, required string) bool {
+	for _, scope := range scopes {
+		if rank, ok := credentialScopeRanks[scope]; ok && rank >= credentialScopeRanks[required] {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsDiscordUser reports whether credential may act for discordUserID. A
+// credential bound to a Discord user may only act for that user; an unbound
+// one needs the instance-admin scope to act for anyone, since it is trusted
+// to relay whoever is using it, such as the Discord bot.
+func (credential *apiCredential) allowsDiscordUser(discordUserID string) bool {
+	if credential.discordUserID != "" {
+		return credential.discordUserID == discordUserID
+	}
+	return credential.hasScope(scopeInstanceAdmin)
+}
+
+func (credential *apiCredential) limitedToInstances() bool {
+	return len(credential.instanceIDs) > 0
+}
+
+func (credential *apiCredential) allowsInstance(instanceID pgtype.UUID) bool {
+	return !credential.limitedToInstances() || slices.Contains(credential.instanceIDs, instanceID)
+}
+
+// credentialRouteScope is the least scope a credential needs to call route.
+// Handlers may still check isInstanceAdmin for the acting Discord user.
+func credentialRouteScope(method, route string, actsForDiscordUser bool) string {
+	switch {
+	case superAdminRoutes[method+" "+route]:
+		return scopeSuperAdmin
+	case instanceAdminRoutes[method+" "+route]:
+		return scopeInstanceAdmin
+	case method != http.MethodGet && method != http.MethodHead:
+		return scopePlayerActions
+	case actsForDiscordUser:
+		// Acting as a Discord user can reveal that user's secret data.
+		return scopePlayerActions
+	default:
+		return scopeReadOnly
+	}
+}
+
+// lookupAPICredential finds the active credential for token, accepting the
+// previous token of a recently rotated credential until its grace period
+// ends.
+func (s *Server) lookupAPICredential(ctx context.Context, token string) (*apiCredential, bool, error) {
+	if s.pool == nil || !strings.HasPrefix(token, apiCredentialTokenPrefix) {
+		return nil, false, nil
+	}
+	now := time.Now().UTC()
+	row, err := s.queries.GetActiveAPICredentialByTokenHash(ctx, db.GetActiveAPICredentialByTokenHashParams{
+		Now:       optionalTime(now),
+		TokenHash: hashAPICredentialToken(token),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if s.credentialTouches.due(row.ID, now) {
+		s.touchAPICredential(ctx, row.ID, now)
+	}
+	return &apiCredential{
+		id:          row.ID,
+		name:        row.Name,
+		scopes:      row.Scopes,
+		instanceIDs: row.InstanceIds,
+		// Stored trimmed and non-empty, so an unbound credential reads as "".
+		discordUserID: row.DiscordUserID.String,
+	}, true, nil
+}
+
+// credentialTouchInterval is how often a credential's last_used_at is
+// written. Requests in between skip the write.
+const credentialTouchInterval = time.Minute
+
+// credentialTouchTimeout caps how long a request waits on that write.
+const credentialTouchTimeout = 250 * time.Millisecond
+
+// credentialTouches remembers when each credential was last touched, so
+// last_used_at is written at most once per interval rather than on every
+// request.
+type credentialTouches struct {
+	mu   sync.Mutex
+	last map[int64]time.Time
+}
+
+func newCredentialTouches() *credentialTouches {
+	return &credentialTouches{last: make(map[int64]time.Time)}
+}
+
+// due reports whether credential id should be touched at now, and if so
+// records now as its latest touch.
+func (t *credentialTouches) due(id int64, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if last, ok := t.last[id]; ok && now.Sub(last) < credentialTouchInterval {
+		return false
+	}
+	t.last[id] = now
+	return true
+}
+
+// touchAPICredential records a credential's use on the request that used
+// it, giving up after credentialTouchTimeout so a slow write cannot hold the
+// request up. A failure only leaves last_used_at stale, so it is logged
+// rather than returned.
+func (s *Server) touchAPICredential(ctx context.Context, id int64, now time.Time) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), credentialTouchTimeout)
+	defer cancel()
+	if err := s.queries.TouchAPICredential(ctx, db.TouchAPICredentialParams{Now: optionalTime(now), ID: id}); err != nil {
+		slog.Warn("touch api credential failed", slog.String("service", "castaway-web"), slog.Int64("credential_id", id), slog.String("error", err.Error()))
+	}
+}
+
+// authorizeAPICredential checks credential's scopes, instance limits and
+// Discord user binding against the matched route, writing the error response
+// when it is refused.
+func (s *Server) authorizeAPICredential(c *gin.Context, credential *apiCredential) bool {
+	route := c.FullPath()
+	discordUserID := discordUserIDFromRequest(c.Request)
+	required := credentialRouteScope(c.Request.Method, route, discordUserID != "")
+	if !credential.hasScope(required) {
+		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{Error: fmt.Sprintf("credential %q lacks the %s scope", credential.name, required)})
+		return false
+	}
+	if discordUserID != "" && !credential.allowsDiscordUser(discordUserID) {
+		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{Error: fmt.Sprintf("credential %q cannot act for this Discord user", credential.name)})
+		return false
+	}
+	if !credential.limitedToInstances() {
+		return true
+	}
+	instanceID, ok := s.lookupRequestInstanceID(c)
+	if !ok {
+		c.Abort()
+		return false
+	}
+	if !instanceID.Valid {
+		if required == scopeSuperAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{Error: fmt.Sprintf("credential %q is limited to specific instances", credential.name)})
+			return false
+		}
+		return true
+	}
+	if !credential.allowsInstance(instanceID) {
+		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{Error: fmt.Sprintf("credential %q cannot access this instance", credential.name)})
+		return false
+	}
+	return true
+}
+
+// lookupRequestInstanceID returns the instance a route acts on, or an
+// invalid UUID for routes that are not tied to one instance.
+func (s *Server) lookupRequestInstanceID(c *gin.Context) (pgtype.UUID, bool) {
+	ctx := c.Request.Context()
+	switch {
+	case c.Param("instanceID") != "":
+		instanceID, ok := parseUUIDPath(c, "instanceID")
+		if !ok {
+			return pgtype.UUID{}, false
+		}
+		return toPGUUID(instanceID), true
+	case c.Param("activityID") != "":
+		activityID, ok := parseUUIDPath(c, "activityID")
+		if !ok {
+			return pgtype.UUID{}, false
+		}
+		row, err := s.queries.GetInstanceStateByActivity(ctx, toPGUUID(activityID))
+		return requestInstanceIDResult(c, row.InstanceID, err, "activity not found")
+	case c.Param("occurrenceID") != "":
+		occurrenceID, ok := parseUUIDPath(c, "occurrenceID")
+		if !ok {
+			return pgtype.UUID{}, false
+		}
+		row, err := s.queries.GetInstanceStateByOccurrence(ctx, toPGUUID(occurrenceID))
+		return requestInstanceIDResult(c, row.InstanceID, err, "occurrence not found")
+	default:
+		return pgtype.UUID{}, true
+	}
+}
+
+func requestInstanceIDResult(c *gin.Context, instanceID pgtype.UUID, err error, notFound string) (pgtype.UUID, bool) {
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: notFound})
+			return pgtype.UUID{}, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return pgtype.UUID{}, false
+	}
+	return instanceID, true
+}
+
+func (s *Server) listAPICredentials(c *gin.Context) {
+	credentials, err := s.queries.ListAPICredentials(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	response := make([]gin.H, 0, len(credentials))
+	for _, credential := range credentials {
+		response = append(response, apiCredentialToJSON(db.CreateAPICredentialRow(credential)))
+	}
+	c.JSON(http.StatusOK, gin.H{"credentials": response})
+}
+
+// createAPICredential issues a named credential. The token is only returned
+// in this response; the server keeps its hash. Credential changes are audited
+// without an instance and read back from GET /audit.
+func (s *Server) createAPICredential(c *gin.Context) {
+	var req createAPICredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "name is required"})
+		return
+	}
+	scopes, err := normalizeCredentialScopes(req.Scopes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	now := time.Now().UTC()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "expires_at must be in the future"})
+		return
+	}
+	instanceIDs, err := parseCredentialInstanceIDs(req.InstanceIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	discordUserID := strings.TrimSpace(req.DiscordUserID)
+	if req.DiscordUserID != "" && discordUserID == "" {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "discord_user_id must not be blank"})
+		return
+	}
+	if len(instanceIDs) > 0 {
+		count, err := s.queries.CountInstancesByPublicIDs(c.Request.Context(), instanceIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		if count != int64(len(instanceIDs)) {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: "instance_ids contains an unknown instance"})
+			return
+		}
+	}
+	token, err := newAPICredentialToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	tx, err := s.pool.Begin(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	credential, err := qtx.CreateAPICredential(c.Request.Context(), db.CreateAPICredentialParams{
+		Name:          name,
+		TokenPrefix:   apiCredentialTokenDisplayPrefix(token),
+		TokenHash:     hashAPICredentialToken(token),
+		Scopes:        scopes,
+		InstanceIds:   instanceIDs,
+		DiscordUserID: auditText(discordUserID),
+		ExpiresAt:     optionalTimePtr(req.ExpiresAt),
+	})
+	if err != nil {
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	response := apiCredentialToJSON(credential)
+	if err := recordAuditEvent(c, qtx, pgtype.UUID{}, auditActionCredentialCreate, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"credential": response, "token": token})
+}
+
+// rotateAPICredential replaces a credential's token. With a grace period the
+// old token keeps working until it ends, so callers can switch over.
+func (s *Server) rotateAPICredential(c *gin.Context) {
+	credentialID, ok := parseUUIDPath(c, "credentialID")
+	if !ok {
+		return
+	}
+	var req rotateAPICredentialRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+			return
+		}
+	}
+	gracePeriod := time.Duration(req.GracePeriodSeconds) * time.Second
+	if gracePeriod < 0 || gracePeriod > maxCredentialGracePeriod {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "grace_period_seconds must be between 0 and 604800"})
+		return
+	}
+	now := time.Now().UTC()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "expires_at must be in the future"})
+		return
+	}
+	previousTokenExpiresAt := pgtype.Timestamptz{}
+	if gracePeriod > 0 {
+		previousTokenExpiresAt = optionalTime(now.Add(gracePeriod))
+	}
+	token, err := newAPICredentialToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	tx, err := s.pool.Begin(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	credential, err := qtx.RotateAPICredential(c.Request.Context(), db.RotateAPICredentialParams{
+		PreviousTokenExpiresAt: previousTokenExpiresAt,
+		TokenPrefix:            apiCredentialTokenDisplayPrefix(token),
+		TokenHash:              hashAPICredentialToken(token),
+		ExpiresAt:              optionalTimePtr(req.ExpiresAt),
+		RotatedAt:              optionalTime(now),
+		ID:                     toPGUUID(credentialID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "active credential not found"})
+			return
+		}
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	response := apiCredentialToJSON(db.CreateAPICredentialRow(credential))
+	if err := recordAuditEvent(c, qtx, pgtype.UUID{}, auditActionCredentialRotate, nil, gin.H{
+		"credential":                response,
+		"previous_token_expires_at": formatNullableTimestamp(previousTokenExpiresAt),
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"credential":                response,
+		"token":                     token,
+		"previous_token_expires_at": formatNullableTimestamp(previousTokenExpiresAt),
+	})
+}
+
+// revokeAPICredential stops a credential, and any token still in a rotation
+// grace period, from authenticating. The record is kept for reference.
+func (s *Server) revokeAPICredential(c *gin.Context) {
+	credentialID, ok := parseUUIDPath(c, "credentialID")
+	if !ok {
+		return
+	}
+	tx, err := s.pool.Begin(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	credential, err := qtx.RevokeAPICredential(c.Request.Context(), db.RevokeAPICredentialParams{
+		RevokedAt: optionalTime(time.Now().UTC()),
+		ID:        toPGUUID(credentialID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "credential not found"})
+			return
+		}
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	response := apiCredentialToJSON(db.CreateAPICredentialRow(credential))
+	if err := recordAuditEvent(c, qtx, pgtype.UUID{}, auditActionCredentialRevoke, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"credential": response})
+}
+
+func credentialScopes() []string {
+	return []string{scopeReadOnly, scopePlayerActions, scopeInstanceAdmin, scopeSuperAdmin}
+}
+
+// normalizeCredentialScopes trims and dedupes scopes, keeping them in
+// least-to-most trusted order.
+func normalizeCredentialScopes(raw []string) ([]string, error) {
+	requested := make(map[string]struct{}, len(raw))
+	for _, scope := range raw {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if _, ok := credentialScopeRanks[scope]; !ok {
+			return nil, fmt.Errorf("unsupported scope %q", scope)
+		}
+		requested[scope] = struct{}{}
+	}
+	scopes := make([]string, 0, len(requested))
+	for _, scope := range credentialScopes() {
+		if _, ok := requested[scope]; ok {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	return scopes, nil
+}
+
+func parseCredentialInstanceIDs(raw []string) ([]pgtype.UUID, error) {
+	instanceIDs := make([]pgtype.UUID, 0, len(raw))
+	for _, value := range raw {
+		parsed, err := uuid.Parse(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid instance id %q", value)
+		}
+		if id := toPGUUID(parsed); !slices.Contains(instanceIDs, id) {
+			instanceIDs = append(instanceIDs, id)
+		}
+	}
+	return instanceIDs, nil
+}
+
+func newAPICredentialToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return apiCredentialTokenPrefix + hex.EncodeToString(raw), nil
+}
+
+func hashAPICredentialToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiCredentialTokenDisplayPrefix is the part of a token kept in clear so
+// admins can tell credentials apart.
+func apiCredentialTokenDisplayPrefix(token string) string {
+	return token[:len(apiCredentialTokenPrefix)+8]
+}
+
+func apiCredentialToJSON(credential db.CreateAPICredentialRow) gin.H {
+	instanceIDs := make([]string, 0, len(credential.InstanceIds))
+	for _, instanceID := range credential.InstanceIds {
+		instanceIDs = append(instanceIDs, pgUUIDString(instanceID))
+	}
+	return gin.H{
+		"id":              pgUUIDString(credential.ID),
+		"name":            credential.Name,
+		"token_prefix":    credential.TokenPrefix,
+		"scopes":          credential.Scopes,
+		"instance_ids":    instanceIDs,
+		"discord_user_id": pgTextPointer(credential.DiscordUserID),
+		"expires_at":      formatNullableTimestamp(credential.ExpiresAt),
+		"revoked_at":      formatNullableTimestamp(credential.RevokedAt),
+		"last_used_at":    formatNullableTimestamp(credential.LastUsedAt),
+		"rotated_at":      formatNullableTimestamp(credential.RotatedAt),
+		"created_at":      formatTimestamp(credential.CreatedAt),
+	}
+}
@@ -0,0 +1,223 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	draftDeadlineSourceInstance     = "instance"
+	draftDeadlineSourceFirstEpisode = "first_episode"
+	draftDeadlineSourceNone         = "none"
+)
+
+// draftDeadline is the moment after which only instance admins may change
+// drafts. An explicit instance deadline wins over the first aired episode.
+type draftDeadline struct {
+	at     time.Time
+	source string
+	set    bool
+}
+
+func (d draftDeadline) lockedAt(now time.Time) bool {
+	return d.set && !now.Before(d.at)
+}
+
+func (d draftDeadline) response(now time.Time) gin.H {
+	var deadline any
+	if d.set {
+		deadline = formatTimestamp(optionalTime(d.at))
+	}
+	return gin.H{
+		"draft_deadline": deadline,
+		"source":         d.source,
+		"locked":         d.lockedAt(now),
+	}
+}
+
+// draftOverrideActor names who made a draft override: the acting Discord user,
+// or the credential itself when a super-admin caller sent none.
+func draftOverrideActor(ctx context.Context, discordUserID string) string {
+	if discordUserID != "" {
+		return discordUserID
+	}
+	principal, _ := ServicePrincipal(ctx)
+	return principal
+}
+
+func (s *Server) resolveDraftDeadline(ctx context.Context, q *db.Queries, instanceID pgtype.UUID) (draftDeadline, error) {
+	explicit, err := q.GetInstanceDraftDeadline(ctx, instanceID)
+	if err != nil {
+		return draftDeadline{}, err
+	}
+	if explicit.Valid {
+		return draftDeadline{at: explicit.Time.UTC(), source: draftDeadlineSourceInstance, set: true}, nil
+	}
+
+	episodes, err := q.ListInstanceEpisodes(ctx, instanceID)
+	if err != nil {
+		return draftDeadline{}, err
+	}
+	deadline := draftDeadline{source: draftDeadlineSourceNone}
+	for _, episode := range episodes {
+		if episode.EpisodeNumber <= 0 || !episode.AirsAt.Valid {
+			continue
+		}
+		if !deadline.set || episode.AirsAt.Time.Before(deadline.at) {
+			deadline = draftDeadline{at: episode.AirsAt.Time.UTC(), source: draftDeadlineSourceFirstEpisode, set: true}
+		}
+	}
+	return deadline, nil
+}
+
+func (s *Server) getDraftDeadline(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+
+	deadline, err := s.resolveDraftDeadline(c.Request.Context(), s.queries, toPGUUID(instanceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deadline.response(time.Now().UTC()))
+}
+
+type setDraftDeadlineRequest struct {
+	DraftDeadline *string `json:"draft_deadline"`
+}
+
+func (s *Server) setDraftDeadline(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+
+	var req setDraftDeadlineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	// A null deadline clears the override so the first episode applies again.
+	var value pgtype.Timestamptz
+	if req.DraftDeadline != nil {
+		parsed, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(*req.DraftDeadline))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: "draft_deadline must be an RFC3339 timestamp"})
+			return
+		}
+		value = optionalTime(parsed.UTC())
+	}
+
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	now := time.Now().UTC()
+	before, err := s.resolveDraftDeadline(ctx, qtx, toPGUUID(instanceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if _, err := qtx.SetInstanceDraftDeadline(ctx, db.SetInstanceDraftDeadlineParams{
+		DraftDeadline: value,
+		ID:            toPGUUID(instanceID),
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	deadline, err := s.resolveDraftDeadline(ctx, qtx, toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	response := deadline.response(now)
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionDraftDeadlineSet, before.response(now), response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (s *Server) listDraftOverrides(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+
+	overrides, err := s.queries.ListDraftOverridesByInstance(c.Request.Context(), toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(overrides))
+	for _, override := range overrides {
+		previousContestantIDs := []string{}
+		contestantIDs := []string{}
+		if err := json.Unmarshal(override.PreviousContestantIds, &previousContestantIDs); err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		if err := json.Unmarshal(override.ContestantIds, &contestantIDs); err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		response = append(response, gin.H{
+			"id":                      pgUUIDString(override.ID),
+			"participant_id":          pgUUIDString(override.ParticipantID),
+			"participant_name":        override.ParticipantName,
+			"actor_discord_user_id":   override.ActorDiscordUserID,
+			"reason":                  override.Reason,
+			"draft_deadline":          formatTimestamp(override.DraftDeadline),
+			"previous_contestant_ids": previousContestantIDs,
+			"contestant_ids":          contestantIDs,
+			"created_at":              formatTimestamp(override.CreatedAt),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"overrides": response})
+}
@@ -0,0 +1,270 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/gameplay"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func episodeToJSON(id pgtype.UUID, episodeNumber int32, label string, airsAt pgtype.Timestamptz) gin.H {
+	return gin.H{
+		"id":             pgUUIDString(id),
+		"episode_number": episodeNumber,
+		"label":          label,
+		"airs_at":        formatTimestamp(airsAt),
+	}
+}
+
+// episodeErrorStatus maps gameplay episode errors to HTTP statuses.
+func episodeErrorStatus(err error) int {
+	if errors.Is(err, gameplay.ErrEpisodeConflict) {
+		return http.StatusConflict
+	}
+	return statusFromPg(err)
+}
+
+func (s *Server) listEpisodes(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+
+	if _, err := s.queries.GetInstance(c.Request.Context(), toPGUUID(instanceID)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	rows, err := s.queries.ListInstanceEpisodes(c.Request.Context(), toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	response := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, episodeToJSON(row.ID, row.EpisodeNumber, row.Label, row.AirsAt))
+	}
+	c.JSON(http.StatusOK, gin.H{"episodes": response})
+}
+
+type createEpisodeRequest struct {
+	EpisodeNumber *int32           `json:"episode_number" binding:"required"`
+	Label         string           `json:"label"`
+	AirsAt        time.Time        `json:"airs_at" binding:"required"`
+	Metadata      *json.RawMessage `json:"metadata"`
+}
+
+// createEpisode adds an episode to the instance schedule. Episodes must air
+// in episode-number order.
+func (s *Server) createEpisode(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+
+	var req createEpisodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	if *req.EpisodeNumber < 0 {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "episode_number must not be negative"})
+		return
+	}
+	label := strings.TrimSpace(req.Label)
+	if label == "" {
+		label = defaultEpisodeLabel(*req.EpisodeNumber)
+	}
+
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+
+	qtx := s.queries.WithTx(tx)
+	episode, err := gameplay.NewService(qtx).CreateEpisode(ctx, gameplay.CreateEpisodeParams{
+		InstanceID:    toPGUUID(instanceID),
+		EpisodeNumber: *req.EpisodeNumber,
+		Label:         label,
+		AirsAt:        req.AirsAt.UTC(),
+		Metadata:      defaultJSONB(req.Metadata),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(episodeErrorStatus(err), errorResponse{Error: err.Error()})
+		return
+	}
+	response := episodeToJSON(episode.ID, episode.EpisodeNumber, episode.Label, episode.AirsAt)
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionEpisodeCreate, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"episode": response})
+}
+
+type updateEpisodeRequest struct {
+	Label  *string    `json:"label"`
+	AirsAt *time.Time `json:"airs_at"`
+}
+
+// updateEpisode relabels or reschedules an episode. Omitted fields are left
+// unchanged.
+func (s *Server) updateEpisode(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	episodeID, ok := parseUUIDPath(c, "episodeID")
+	if !ok {
+		return
+	}
+
+	var req updateEpisodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	if req.Label == nil && req.AirsAt == nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "label or airs_at is required"})
+		return
+	}
+	params := gameplay.UpdateEpisodeParams{
+		InstanceID: toPGUUID(instanceID),
+		EpisodeID:  toPGUUID(episodeID),
+	}
+	if req.Label != nil {
+		label := strings.TrimSpace(*req.Label)
+		if label == "" {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: "label cannot be empty"})
+			return
+		}
+		params.Label = &label
+	}
+	if req.AirsAt != nil {
+		airsAt := req.AirsAt.UTC()
+		params.AirsAt = &airsAt
+	}
+
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+
+	qtx := s.queries.WithTx(tx)
+	before, err := qtx.GetInstanceEpisode(ctx, db.GetInstanceEpisodeParams{InstanceID: params.InstanceID, ID: params.EpisodeID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "episode not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	episode, err := gameplay.NewService(qtx).UpdateEpisode(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "episode not found"})
+			return
+		}
+		c.JSON(episodeErrorStatus(err), errorResponse{Error: err.Error()})
+		return
+	}
+	response := episodeToJSON(episode.ID, episode.EpisodeNumber, episode.Label, episode.AirsAt)
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionEpisodeUpdate,
+		episodeToJSON(before.ID, before.EpisodeNumber, before.Label, before.AirsAt), response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"episode": response})
+}
+
+func (s *Server) deleteEpisode(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	episodeID, ok := parseUUIDPath(c, "episodeID")
+	if !ok {
+		return
+	}
+
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+
+	qtx := s.queries.WithTx(tx)
+	episode, err := gameplay.NewService(qtx).DeleteEpisode(ctx, toPGUUID(instanceID), toPGUUID(episodeID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "episode not found"})
+			return
+		}
+		c.JSON(episodeErrorStatus(err), errorResponse{Error: err.Error()})
+		return
+	}
+	response := episodeToJSON(episode.ID, episode.EpisodeNumber, episode.Label, episode.AirsAt)
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionEpisodeDelete, response, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"episode": response})
+}
+
+func defaultEpisodeLabel(episodeNumber int32) string {
+	if episodeNumber == 0 {
+		return "Preseason"
+	}
+	return "Episode " + strconv.FormatInt(int64(episodeNumber), 10)
+}
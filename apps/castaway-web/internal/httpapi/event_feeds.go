@@ -0,0 +1,161 @@
+package httpapi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// WithShutdown ends every open event stream once done is closed. Streams never
+// finish on their own, so without it a graceful shutdown waits out its whole
+// timeout.
+func WithShutdown(done <-chan struct{}) Option {
+	return func(s *Server) {
+		s.done = done
+	}
+}
+
+// instanceFeeds shares one poll loop per instance between all of its open
+// event streams, so database load grows with the instances being watched
+// rather than with the clients watching them.
+type instanceFeeds struct {
+	mu    sync.Mutex
+	feeds map[uuid.UUID]*instanceFeed
+}
+
+type instanceFeed struct {
+	instanceID  pgtype.UUID
+	subscribers map[*streamSubscription]struct{}
+	wake        chan struct{}
+	stop        context.CancelFunc
+}
+
+// streamSubscription receives the latest state of an instance as seen by one
+// viewer. Updates are coalesced: a slow stream skips intermediate states
+// instead of holding up the feed.
+type streamSubscription struct {
+	viewer  *db.GetParticipantRow
+	updates chan streamUpdate
+}
+
+type streamUpdate struct {
+	state instanceStreamState
+	err   error
+}
+
+func newInstanceFeeds() *instanceFeeds {
+	return &instanceFeeds{feeds: make(map[uuid.UUID]*instanceFeed)}
+}
+
+// subscribeInstanceStream joins the instance's feed, starting it if this is
+// the first stream, and asks for an immediate poll so the new stream gets its
+// snapshot without waiting for the next tick.
+func (s *Server) subscribeInstanceStream(instanceID uuid.UUID, viewer *db.GetParticipantRow) *streamSubscription {
+	subscription := &streamSubscription{viewer: viewer, updates: make(chan streamUpdate, 1)}
+	s.streams.mu.Lock()
+	defer s.streams.mu.Unlock()
+	feed, ok := s.streams.feeds[instanceID]
+	if !ok {
+		ctx, stop := context.WithCancel(context.Background())
+		feed = &instanceFeed{
+			instanceID:  toPGUUID(instanceID),
+			subscribers: make(map[*streamSubscription]struct{}),
+			wake:        make(chan struct{}, 1),
+			stop:        stop,
+		}
+		s.streams.feeds[instanceID] = feed
+		go s.runInstanceFeed(ctx, feed)
+	}
+	feed.subscribers[subscription] = struct{}{}
+	select {
+	case feed.wake <- struct{}{}:
+	default:
+	}
+	return subscription
+}
+
+// unsubscribeInstanceStream leaves the instance's feed and stops it once no
+// streams are left.
+func (s *Server) unsubscribeInstanceStream(instanceID uuid.UUID, subscription *streamSubscription) {
+	s.streams.mu.Lock()
+	defer s.streams.mu.Unlock()
+	feed, ok := s.streams.feeds[instanceID]
+	if !ok {
+		return
+	}
+	delete(feed.subscribers, subscription)
+	if len(feed.subscribers) == 0 {
+		feed.stop()
+		delete(s.streams.feeds, instanceID)
+	}
+}
+
+func (s *Server) runInstanceFeed(ctx context.Context, feed *instanceFeed) {
+	ticker := time.NewTicker(s.eventStreamInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.done:
+			return
+		case <-feed.wake:
+		case <-ticker.C:
+		}
+		s.pollInstanceFeed(ctx, feed)
+	}
+}
+
+// pollInstanceFeed reads the shared instance state once, adds each distinct
+// viewer's own balance, and hands every subscriber its view.
+func (s *Server) pollInstanceFeed(ctx context.Context, feed *instanceFeed) {
+	s.streams.mu.Lock()
+	subscriptions := make([]*streamSubscription, 0, len(feed.subscribers))
+	for subscription := range feed.subscribers {
+		subscriptions = append(subscriptions, subscription)
+	}
+	s.streams.mu.Unlock()
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	shared, err := s.loadInstanceStreamState(ctx, feed.instanceID)
+	if ctx.Err() != nil {
+		return
+	}
+	balances := make(map[pgtype.UUID]*streamBalance)
+	for _, subscription := range subscriptions {
+		update := streamUpdate{state: shared, err: err}
+		if err == nil && subscription.viewer != nil {
+			balance, ok := balances[subscription.viewer.ID]
+			if !ok {
+				balance, update.err = s.loadStreamBalance(ctx, feed.instanceID, subscription.viewer)
+				if update.err == nil {
+					balances[subscription.viewer.ID] = balance
+				}
+			}
+			update.state.Balance = balance
+		}
+		subscription.deliver(update)
+	}
+}
+
+// deliver replaces any update the stream has not read yet. Only the feed
+// sends, so the loop settles after at most one retry.
+func (subscription *streamSubscription) deliver(update streamUpdate) {
+	for {
+		select {
+		case subscription.updates <- update:
+			return
+		default:
+		}
+		select {
+		case <-subscription.updates:
+		default:
+		}
+	}
+}
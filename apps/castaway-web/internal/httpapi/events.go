@@ -0,0 +1,280 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/scoring"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Event names sent on the instance event stream.
+const (
+	streamEventSnapshot           = "snapshot"
+	streamEventLeaderboardUpdated = "leaderboard.updated"
+	streamEventAuctionLotOpened   = "auction_lot.opened"
+	streamEventAuctionLotClosed   = "auction_lot.closed"
+	streamEventStirThePotOpened   = "stir_the_pot.opened"
+	streamEventStirThePotClosed   = "stir_the_pot.closed"
+	streamEventBalanceUpdated     = "balance.updated"
+)
+
+const (
+	defaultEventStreamInterval = 2 * time.Second
+	eventStreamHeartbeat       = 15 * time.Second
+)
+
+// WithEventStreamInterval sets how often each instance's feed re-reads its
+// state looking for changes.
+func WithEventStreamInterval(interval time.Duration) Option {
+	return func(s *Server) {
+		if interval > 0 {
+			s.eventStreamInterval = interval
+		}
+	}
+}
+
+type streamLeaderboardRow struct {
+	ParticipantID   string `json:"participant_id"`
+	ParticipantName string `json:"participant_name"`
+	Rank            int    `json:"rank"`
+	DraftPoints     int    `json:"draft_points"`
+	BonusPoints     int    `json:"bonus_points"`
+	TotalPoints     int    `json:"total_points"`
+}
+
+type streamAuctionLot struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	ContestantID   string `json:"contestant_id"`
+	ContestantName string `json:"contestant_name"`
+}
+
+type streamStirThePotRound struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type streamBalance struct {
+	ParticipantID        string `json:"participant_id"`
+	BonusPointsAvailable int32  `json:"bonus_points_available"`
+}
+
+// instanceStreamState is what one subscriber can see of an instance. Balance
+// includes secret points, so it is only set for the subscriber's own linked
+// participant.
+type instanceStreamState struct {
+	Leaderboard     []streamLeaderboardRow `json:"leaderboard"`
+	AuctionLots     []streamAuctionLot     `json:"auction_lots"`
+	StirThePotRound *streamStirThePotRound `json:"stir_the_pot_round"`
+	Balance         *streamBalance         `json:"balance"`
+}
+
+type streamEvent struct {
+	Name string
+	Data any
+}
+
+// streamInstanceEvents streams live instance changes as Server-Sent Events.
+// The first event is a snapshot of everything the caller can see; later
+// events describe what changed since the previous read. Reads come from the
+// instance's shared feed, and the stream ends when the server shuts down.
+func (s *Server) streamInstanceEvents(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	if _, err := s.queries.GetInstance(ctx, toPGUUID(instanceID)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	viewer, err := s.streamViewer(ctx, toPGUUID(instanceID), discordUserIDFromRequest(c.Request))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	subscription := s.subscribeInstanceStream(instanceID, viewer)
+	defer s.unsubscribeInstanceStream(instanceID, subscription)
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+	var previous *instanceStreamState
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.done:
+			return
+		case <-heartbeat.C:
+			_, _ = c.Writer.WriteString(": keep-alive\n\n")
+			c.Writer.Flush()
+		case update := <-subscription.updates:
+			if update.err != nil {
+				slog.Error("instance event stream failed", slog.String("service", "castaway-web"), slog.String("instance_id", instanceID.String()), slog.String("error", update.err.Error()))
+				c.SSEvent("error", errorResponse{Error: update.err.Error()})
+				c.Writer.Flush()
+				return
+			}
+			events := diffInstanceStreamStates(previous, update.state)
+			for _, event := range events {
+				c.SSEvent(event.Name, event.Data)
+			}
+			if len(events) > 0 {
+				c.Writer.Flush()
+				heartbeat.Reset(eventStreamHeartbeat)
+			}
+			state := update.state
+			previous = &state
+		}
+	}
+}
+
+// streamViewer returns the caller's linked participant when the caller may
+// see that participant's secret totals.
+func (s *Server) streamViewer(ctx context.Context, instanceID pgtype.UUID, discordUserID string) (*db.GetParticipantRow, error) {
+	if discordUserID == "" {
+		return nil, nil
+	}
+	linked, err := s.queries.GetParticipantByDiscordUserID(ctx, db.GetParticipantByDiscordUserIDParams{
+		InstanceID:    instanceID,
+		DiscordUserID: pgtype.Text{String: discordUserID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	participant := linkedParticipantRowToParticipant(linked)
+	allowed, err := s.canViewSecretParticipantData(ctx, instanceID, discordUserID, participant)
+	if err != nil || !allowed {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// loadInstanceStreamState reads the parts of the stream state every viewer
+// shares. Balance is left for loadStreamBalance.
+func (s *Server) loadInstanceStreamState(ctx context.Context, instanceID pgtype.UUID) (instanceStreamState, error) {
+	inputs, err := s.loadLeaderboardInputs(ctx, instanceID)
+	if err != nil {
+		return instanceStreamState{}, err
+	}
+	leaderboard, err := s.scoreLeaderboardAt(ctx, inputs, nil)
+	if err != nil {
+		return instanceStreamState{}, err
+	}
+	ranks := scoring.Ranks(leaderboard)
+	state := instanceStreamState{
+		Leaderboard: make([]streamLeaderboardRow, 0, len(leaderboard)),
+		AuctionLots: []streamAuctionLot{},
+	}
+	for _, row := range leaderboard {
+		state.Leaderboard = append(state.Leaderboard, streamLeaderboardRow{
+			ParticipantID:   row.ParticipantID,
+			ParticipantName: row.ParticipantName,
+			Rank:            ranks[row.ParticipantID],
+			DraftPoints:     row.DraftPoints,
+			BonusPoints:     row.BonusPoints,
+			TotalPoints:     row.TotalPoints,
+		})
+	}
+
+	_, lots, err := s.listOpenAuctionLots(ctx, s.queries, instanceID)
+	if err != nil {
+		return instanceStreamState{}, err
+	}
+	for _, lot := range lots {
+		state.AuctionLots = append(state.AuctionLots, streamAuctionLot{
+			ID:             pgUUIDString(lot.Occurrence.ID),
+			Name:           lot.Occurrence.Name,
+			ContestantID:   lot.ContestantID.String(),
+			ContestantName: lot.ContestantName,
+		})
+	}
+	sort.Slice(state.AuctionLots, func(i, j int) bool {
+		return state.AuctionLots[i].ID < state.AuctionLots[j].ID
+	})
+
+	round, _, found, err := s.findOpenStirThePotRound(ctx, s.queries, instanceID, time.Now().UTC())
+	if err != nil {
+		return instanceStreamState{}, err
+	}
+	if found {
+		state.StirThePotRound = &streamStirThePotRound{ID: pgUUIDString(round.ID), Name: round.Name}
+	}
+
+	return state, nil
+}
+
+func (s *Server) loadStreamBalance(ctx context.Context, instanceID pgtype.UUID, viewer *db.GetParticipantRow) (*streamBalance, error) {
+	balance, err := s.currentBonusBalance(ctx, s.queries, instanceID, viewer.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &streamBalance{
+		ParticipantID:        uuid.UUID(viewer.ID.Bytes).String(),
+		BonusPointsAvailable: balance,
+	}, nil
+}
+
+// diffInstanceStreamStates lists the events that turn previous into next. A
+// nil previous yields a single snapshot.
+func diffInstanceStreamStates(previous *instanceStreamState, next instanceStreamState) []streamEvent {
+	if previous == nil {
+		return []streamEvent{{Name: streamEventSnapshot, Data: next}}
+	}
+
+	var events []streamEvent
+	if !slices.Equal(previous.Leaderboard, next.Leaderboard) {
+		events = append(events, streamEvent{Name: streamEventLeaderboardUpdated, Data: gin.H{"leaderboard": next.Leaderboard}})
+	}
+	for _, lot := range next.AuctionLots {
+		if !containsStreamAuctionLot(previous.AuctionLots, lot.ID) {
+			events = append(events, streamEvent{Name: streamEventAuctionLotOpened, Data: gin.H{"lot": lot}})
+		}
+	}
+	for _, lot := range previous.AuctionLots {
+		if !containsStreamAuctionLot(next.AuctionLots, lot.ID) {
+			events = append(events, streamEvent{Name: streamEventAuctionLotClosed, Data: gin.H{"lot": lot}})
+		}
+	}
+	previousRound, nextRound := previous.StirThePotRound, next.StirThePotRound
+	if previousRound != nil && (nextRound == nil || nextRound.ID != previousRound.ID) {
+		events = append(events, streamEvent{Name: streamEventStirThePotClosed, Data: gin.H{"round": previousRound}})
+	}
+	if nextRound != nil && (previousRound == nil || previousRound.ID != nextRound.ID) {
+		events = append(events, streamEvent{Name: streamEventStirThePotOpened, Data: gin.H{"round": nextRound}})
+	}
+	if next.Balance != nil && (previous.Balance == nil || *previous.Balance != *next.Balance) {
+		events = append(events, streamEvent{Name: streamEventBalanceUpdated, Data: next.Balance})
+	}
+	return events
+}
+
+func containsStreamAuctionLot(lots []streamAuctionLot, id string) bool {
+	return slices.ContainsFunc(lots, func(lot streamAuctionLot) bool {
+		return lot.ID == id
+	})
+}
@@ -0,0 +1,69 @@
+package httpapi
+
+import "testing"
+
+func TestDiffInstanceStreamStatesStartsWithSnapshot(t *testing.T) {
+	state := instanceStreamState{AuctionLots: []streamAuctionLot{{ID: "lot-1"}}}
+	events := diffInstanceStreamStates(nil, state)
+	if len(events) != 1 || events[0].Name != streamEventSnapshot {
+		t.Fatalf("expected a single snapshot event, got %+v", events)
+	}
+	if len(diffInstanceStreamStates(&state, state)) != 0 {
+		t.Fatal("expected no events for an unchanged state")
+	}
+}
+
+func TestDiffInstanceStreamStatesReportsChanges(t *testing.T) {
+	previous := instanceStreamState{
+		Leaderboard:     []streamLeaderboardRow{{ParticipantID: "alice", Rank: 1, TotalPoints: 10}},
+		AuctionLots:     []streamAuctionLot{{ID: "lot-1", ContestantName: "Kenzie"}},
+		StirThePotRound: &streamStirThePotRound{ID: "round-1"},
+		Balance:         &streamBalance{ParticipantID: "alice", BonusPointsAvailable: 7},
+	}
+	next := instanceStreamState{
+		Leaderboard: []streamLeaderboardRow{{ParticipantID: "alice", Rank: 1, TotalPoints: 12}},
+		AuctionLots: []streamAuctionLot{{ID: "lot-2", ContestantName: "Ben"}},
+		Balance:     &streamBalance{ParticipantID: "alice", BonusPointsAvailable: 4},
+	}
+
+	events := diffInstanceStreamStates(&previous, next)
+	want := []string{
+		streamEventLeaderboardUpdated,
+		streamEventAuctionLotOpened,
+		streamEventAuctionLotClosed,
+		streamEventStirThePotClosed,
+		streamEventBalanceUpdated,
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, name := range want {
+		if events[i].Name != name {
+			t.Fatalf("event %d = %q, want %q", i, events[i].Name, name)
+		}
+	}
+
+	reopened := diffInstanceStreamStates(&next, instanceStreamState{
+		Leaderboard:     next.Leaderboard,
+		AuctionLots:     next.AuctionLots,
+		StirThePotRound: &streamStirThePotRound{ID: "round-2"},
+	})
+	if len(reopened) != 1 || reopened[0].Name != streamEventStirThePotOpened {
+		t.Fatalf("expected only a round opened event, got %+v", reopened)
+	}
+}
+
+func TestStreamSubscriptionKeepsOnlyTheLatestUpdate(t *testing.T) {
+	subscription := &streamSubscription{updates: make(chan streamUpdate, 1)}
+	subscription.deliver(streamUpdate{state: instanceStreamState{AuctionLots: []streamAuctionLot{{ID: "lot-1"}}}})
+	subscription.deliver(streamUpdate{state: instanceStreamState{AuctionLots: []streamAuctionLot{{ID: "lot-2"}}}})
+	update := <-subscription.updates
+	if len(update.state.AuctionLots) != 1 || update.state.AuctionLots[0].ID != "lot-2" {
+		t.Fatalf("expected the newest update, got %+v", update.state)
+	}
+	select {
+	case stale := <-subscription.updates:
+		t.Fatalf("expected the older update to be dropped, got %+v", stale.state)
+	default:
+	}
+}
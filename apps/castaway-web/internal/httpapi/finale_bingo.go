@@ -135,11 +135,16 @@ func (s *Server) recordFinaleBingoLoanSharks(c *gin.Context) {
 		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
 		return
 	}
+	response := gin.H{"occurrence_id": pgUUIDString(occurrence.ID), "assignments": assignments}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionFinaleBingoLoanSharks, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
 	if err := tx.Commit(c.Request.Context()); err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"occurrence_id": pgUUIDString(occurrence.ID), "assignments": assignments})
+	c.JSON(http.StatusOK, response)
 }
 
 func (s *Server) previewFinaleBingoScores(c *gin.Context) {
@@ -270,15 +275,20 @@ func (s *Server) handleFinaleBingoScores(c *gin.Context, write bool) {
 			created = append(created, entry)
 		}
 	}
-	if err := tx.Commit(c.Request.Context()); err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
-		return
-	}
 	entries := make([]gin.H, 0, len(created))
 	for _, entry := range created {
 		entries = append(entries, gin.H{"id": pgUUIDString(entry.ID), "participant_id": pgUUIDString(entry.ParticipantID), "points": entry.Points, "reason": entry.Reason})
 	}
-	c.JSON(http.StatusOK, gin.H{"occurrence_id": pgUUIDString(occurrence.ID), "scores": calculated, "loan_sharks": loanSharks, "created_entries": entries})
+	response := gin.H{"occurrence_id": pgUUIDString(occurrence.ID), "scores": calculated, "loan_sharks": loanSharks, "created_entries": entries}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionFinaleBingoScores, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 func (s *Server) finaleBingoParticipants(c *gin.Context, instanceID uuid.UUID) (map[string]db.ListParticipantsByInstanceRow, []string, bool) {
@@ -0,0 +1,480 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/gameplay"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Realignment modes: a swap deals participants into a new layout of groups,
+// a merge moves everyone into one group.
+const (
+	realignmentModeSwap  = "swap"
+	realignmentModeMerge = "merge"
+)
+
+// Actions taken on activity group assignments held by realigned groups.
+const (
+	realignmentAssignmentKeep = "keep"
+	realignmentAssignmentEnd  = "end"
+)
+
+type groupLayoutEntry struct {
+	Group        string   `json:"group"`
+	Participants []string `json:"participants"`
+}
+
+type groupRealignmentRequest struct {
+	EffectiveAt time.Time          `json:"effective_at" binding:"required"`
+	Kind        string             `json:"kind"`
+	Layout      []groupLayoutEntry `json:"layout"`
+	MergeInto   string             `json:"merge_into"`
+}
+
+// realignmentGroupState is an existing group of the realigned kind and the
+// participants in it at the effective time.
+type realignmentGroupState struct {
+	ID      pgtype.UUID
+	Name    string
+	Members []pgtype.UUID
+}
+
+type realignmentGroup struct {
+	GroupID string   `json:"group_id,omitempty"`
+	Name    string   `json:"name"`
+	Created bool     `json:"created"`
+	Members []string `json:"members"`
+
+	id pgtype.UUID
+}
+
+type realignmentMove struct {
+	ParticipantID   string   `json:"participant_id"`
+	ParticipantName string   `json:"participant_name"`
+	From            []string `json:"from"`
+	To              string   `json:"to"`
+
+	participantID pgtype.UUID
+	fromGroupIDs  []pgtype.UUID
+	toGroup       int
+}
+
+type realignmentAssignment struct {
+	ActivityID           string `json:"activity_id"`
+	ActivityName         string `json:"activity_name"`
+	ActivityType         string `json:"activity_type"`
+	ParticipantGroupID   string `json:"participant_group_id"`
+	ParticipantGroupName string `json:"participant_group_name"`
+	Role                 string `json:"role"`
+	StartsAt             string `json:"starts_at"`
+	Action               string `json:"action"`
+
+	id int64
+}
+
+// groupRealignmentPlan is both the preview and the record of an applied swap
+// or merge. Participants whose group does not change are left untouched.
+type groupRealignmentPlan struct {
+	Mode          string                  `json:"mode"`
+	Kind          string                  `json:"kind"`
+	EffectiveAt   string                  `json:"effective_at"`
+	Groups        []realignmentGroup      `json:"groups"`
+	Moves         []realignmentMove       `json:"moves"`
+	Unchanged     []string                `json:"unchanged"`
+	EmptiedGroups []string                `json:"emptied_groups"`
+	Assignments   []realignmentAssignment `json:"assignments"`
+
+	changedGroups map[string]bool
+	emptiedGroups map[string]bool
+}
+
+// planGroupRealignment works out who moves where. A swap layout must place
+// every participant currently in a group of the kind exactly once; groups it
+// names that do not exist yet are created on apply.
+func planGroupRealignment(req groupRealignmentRequest, kind string, groups []realignmentGroupState, participants []db.ListParticipantsByInstanceRow) (groupRealignmentPlan, error) {
+	mergeInto := strings.TrimSpace(req.MergeInto)
+	mode := ""
+	switch {
+	case len(req.Layout) > 0 && mergeInto == "":
+		mode = realignmentModeSwap
+	case len(req.Layout) == 0 && mergeInto != "":
+		mode = realignmentModeMerge
+	default:
+		return groupRealignmentPlan{}, errors.New("provide exactly one of layout or merge_into")
+	}
+
+	names := make(map[string]string, len(participants))
+	for _, participant := range participants {
+		names[pgUUIDString(participant.ID)] = participant.Name
+	}
+	existing := make(map[string]realignmentGroupState, len(groups))
+	current := make(map[string][]realignmentGroupState)
+	var currentOrder []pgtype.UUID
+	for _, group := range groups {
+		existing[strings.ToLower(strings.TrimSpace(group.Name))] = group
+		for _, member := range group.Members {
+			key := pgUUIDString(member)
+			if _, seen := current[key]; !seen {
+				currentOrder = append(currentOrder, member)
+			}
+			current[key] = append(current[key], group)
+		}
+	}
+
+	plan := groupRealignmentPlan{
+		Mode:          mode,
+		Kind:          kind,
+		EffectiveAt:   req.EffectiveAt.UTC().Format(time.RFC3339),
+		Groups:        []realignmentGroup{},
+		Moves:         []realignmentMove{},
+		Unchanged:     []string{},
+		EmptiedGroups: []string{},
+		Assignments:   []realignmentAssignment{},
+		changedGroups: map[string]bool{},
+		emptiedGroups: map[string]bool{},
+	}
+	addGroup := func(name string) (int, error) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return 0, errors.New("layout group name is required")
+		}
+		for _, group := range plan.Groups {
+			if strings.EqualFold(group.Name, name) {
+				return 0, fmt.Errorf("group %s appears more than once in the layout", name)
+			}
+		}
+		group := realignmentGroup{Name: name, Created: true, Members: []string{}}
+		if state, ok := existing[strings.ToLower(name)]; ok {
+			group = realignmentGroup{GroupID: pgUUIDString(state.ID), Name: state.Name, Members: []string{}, id: state.ID}
+		}
+		plan.Groups = append(plan.Groups, group)
+		return len(plan.Groups) - 1, nil
+	}
+
+	type placement struct {
+		participantID pgtype.UUID
+		group         int
+	}
+	var placements []placement
+	placed := make(map[string]bool)
+	if mode == realignmentModeMerge {
+		target, err := addGroup(mergeInto)
+		if err != nil {
+			return groupRealignmentPlan{}, err
+		}
+		for _, participantID := range currentOrder {
+			placements = append(placements, placement{participantID: participantID, group: target})
+		}
+	} else {
+		for _, entry := range req.Layout {
+			target, err := addGroup(entry.Group)
+			if err != nil {
+				return groupRealignmentPlan{}, err
+			}
+			if len(entry.Participants) == 0 {
+				return groupRealignmentPlan{}, fmt.Errorf("group %s has no participants", plan.Groups[target].Name)
+			}
+			for _, ref := range entry.Participants {
+				participantID, err := resolveRealignmentParticipant(participants, ref)
+				if err != nil {
+					return groupRealignmentPlan{}, err
+				}
+				key := pgUUIDString(participantID)
+				if placed[key] {
+					return groupRealignmentPlan{}, fmt.Errorf("%s is placed more than once", names[key])
+				}
+				placed[key] = true
+				placements = append(placements, placement{participantID: participantID, group: target})
+			}
+		}
+		var missing []string
+		for _, participantID := range currentOrder {
+			if key := pgUUIDString(participantID); !placed[key] {
+				missing = append(missing, names[key])
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return groupRealignmentPlan{}, fmt.Errorf("layout must place every current member; missing %s", strings.Join(missing, ", "))
+		}
+	}
+
+	for _, p := range placements {
+		key := pgUUIDString(p.participantID)
+		target := &plan.Groups[p.group]
+		target.Members = append(target.Members, names[key])
+		from := current[key]
+		if len(from) == 1 && target.id.Valid && from[0].ID == target.id {
+			plan.Unchanged = append(plan.Unchanged, names[key])
+			continue
+		}
+		move := realignmentMove{
+			ParticipantID:   key,
+			ParticipantName: names[key],
+			From:            []string{},
+			To:              target.Name,
+			participantID:   p.participantID,
+			toGroup:         p.group,
+		}
+		for _, group := range from {
+			move.From = append(move.From, group.Name)
+			move.fromGroupIDs = append(move.fromGroupIDs, group.ID)
+			plan.changedGroups[pgUUIDString(group.ID)] = true
+		}
+		if target.id.Valid {
+			plan.changedGroups[pgUUIDString(target.id)] = true
+		}
+		plan.Moves = append(plan.Moves, move)
+	}
+
+	for _, group := range groups {
+		if len(group.Members) == 0 {
+			continue
+		}
+		kept := false
+		for _, target := range plan.Groups {
+			if target.id == group.ID {
+				kept = len(target.Members) > 0
+			}
+		}
+		if !kept {
+			plan.EmptiedGroups = append(plan.EmptiedGroups, group.Name)
+			plan.emptiedGroups[pgUUIDString(group.ID)] = true
+		}
+	}
+	return plan, nil
+}
+
+// addAssignments records the activity group assignments held by groups whose
+// membership changes. Assignments of groups left empty are ended.
+func (p *groupRealignmentPlan) addAssignments(rows []db.ListActiveActivityGroupAssignmentsByInstanceAtRow) {
+	for _, row := range rows {
+		groupID := pgUUIDString(row.ParticipantGroupID)
+		if !p.changedGroups[groupID] {
+			continue
+		}
+		action := realignmentAssignmentKeep
+		if p.emptiedGroups[groupID] {
+			action = realignmentAssignmentEnd
+		}
+		p.Assignments = append(p.Assignments, realignmentAssignment{
+			ActivityID:           pgUUIDString(row.ActivityID),
+			ActivityName:         row.ActivityName,
+			ActivityType:         row.ActivityType,
+			ParticipantGroupID:   groupID,
+			ParticipantGroupName: row.ParticipantGroupName,
+			Role:                 row.Role,
+			StartsAt:             formatTimestamp(row.StartsAt),
+			Action:               action,
+			id:                   row.ID,
+		})
+	}
+}
+
+func resolveRealignmentParticipant(participants []db.ListParticipantsByInstanceRow, ref string) (pgtype.UUID, error) {
+	ref = strings.TrimSpace(ref)
+	if parsed, err := uuid.Parse(ref); err == nil {
+		for _, participant := range participants {
+			if participant.ID == toPGUUID(parsed) {
+				return participant.ID, nil
+			}
+		}
+		return pgtype.UUID{}, fmt.Errorf("participant %s not found", ref)
+	}
+	var matches []pgtype.UUID
+	for _, participant := range participants {
+		if strings.EqualFold(strings.TrimSpace(participant.Name), ref) {
+			matches = append(matches, participant.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return pgtype.UUID{}, fmt.Errorf("participant %q not found", ref)
+	case 1:
+		return matches[0], nil
+	}
+	return pgtype.UUID{}, fmt.Errorf("participant name %q is ambiguous; use the participant id", ref)
+}
+
+func (s *Server) previewGroupRealignment(c *gin.Context) {
+	s.handleGroupRealignment(c, false)
+}
+
+func (s *Server) applyGroupRealignment(c *gin.Context) {
+	s.handleGroupRealignment(c, true)
+}
+
+// handleGroupRealignment plans a swap or merge at an episode boundary and,
+// when write is set, applies it in one transaction.
+func (s *Server) handleGroupRealignment(c *gin.Context, write bool) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+	var req groupRealignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	kind := strings.ToLower(strings.TrimSpace(req.Kind))
+	if kind == "" {
+		kind = groupKindTribe
+	}
+	if kind != groupKindTribe && kind != groupKindAlliance {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "kind must be tribe or alliance"})
+		return
+	}
+	effectiveAt := req.EffectiveAt.UTC()
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	if err := gameplay.NewService(qtx).RequireEpisodeBoundary(ctx, toPGUUID(instanceID), effectiveAt); err != nil {
+		c.JSON(membershipErrorStatus(err), errorResponse{Error: err.Error()})
+		return
+	}
+	groups, err := qtx.ListParticipantGroupsByInstance(ctx, toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	states := make([]realignmentGroupState, 0, len(groups))
+	for _, group := range groups {
+		if group.Kind != kind {
+			continue
+		}
+		periods, err := qtx.ListParticipantGroupMembershipPeriods(ctx, group.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		state := realignmentGroupState{ID: group.ID, Name: group.Name}
+		seen := make(map[pgtype.UUID]bool)
+		for _, period := range periods {
+			if !period.StartsAt.Time.Before(effectiveAt) {
+				c.JSON(http.StatusConflict, errorResponse{Error: fmt.Sprintf("%s already has membership changes at or after effective_at", group.Name)})
+				return
+			}
+			if (!period.EndsAt.Valid || period.EndsAt.Time.After(effectiveAt)) && !seen[period.ParticipantID] {
+				seen[period.ParticipantID] = true
+				state.Members = append(state.Members, period.ParticipantID)
+			}
+		}
+		states = append(states, state)
+	}
+	participants, err := qtx.ListParticipantsByInstance(ctx, toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	plan, err := planGroupRealignment(req, kind, states, participants)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	assignments, err := qtx.ListActiveActivityGroupAssignmentsByInstanceAt(ctx, db.ListActiveActivityGroupAssignmentsByInstanceAtParams{
+		InstanceID: toPGUUID(instanceID),
+		At:         pgtype.Timestamptz{Time: effectiveAt, Valid: true},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	plan.addAssignments(assignments)
+
+	if !write {
+		c.JSON(http.StatusOK, gin.H{"applied": false, "realignment": plan})
+		return
+	}
+	if err := applyGroupRealignmentPlan(ctx, qtx, toPGUUID(instanceID), &plan, effectiveAt); err != nil {
+		c.JSON(membershipErrorStatus(err), errorResponse{Error: err.Error()})
+		return
+	}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionGroupRealignment, nil, plan); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"applied": true, "realignment": plan})
+}
+
+// applyGroupRealignmentPlan creates the plan's new groups, closes each mover's
+// current periods and opens their new one at effectiveAt, and ends the
+// assignments of groups left empty.
+func applyGroupRealignmentPlan(ctx context.Context, qtx *db.Queries, instanceID pgtype.UUID, plan *groupRealignmentPlan, effectiveAt time.Time) error {
+	service := gameplay.NewService(qtx)
+	for i := range plan.Groups {
+		group := &plan.Groups[i]
+		if !group.Created {
+			continue
+		}
+		created, err := qtx.CreateParticipantGroup(ctx, db.CreateParticipantGroupParams{
+			Name:       group.Name,
+			Kind:       plan.Kind,
+			Metadata:   []byte("{}"),
+			InstanceID: instanceID,
+		})
+		if err != nil {
+			return fmt.Errorf("create group %s: %w", group.Name, err)
+		}
+		group.id = created.ID
+		group.GroupID = pgUUIDString(created.ID)
+	}
+
+	metadata := []byte(fmt.Sprintf(`{"realignment":%q}`, plan.Mode))
+	for _, move := range plan.Moves {
+		for _, fromGroupID := range move.fromGroupIDs {
+			if _, err := service.EndMembershipPeriod(ctx, gameplay.EndMembershipPeriodParams{
+				ParticipantGroupID: fromGroupID,
+				ParticipantID:      move.participantID,
+				EndsAt:             effectiveAt,
+			}); err != nil {
+				return fmt.Errorf("end %s membership for %s: %w", strings.Join(move.From, ", "), move.ParticipantName, err)
+			}
+		}
+		if _, err := service.CreateMembershipPeriod(ctx, gameplay.CreateMembershipPeriodParams{
+			ParticipantGroupID: plan.Groups[move.toGroup].id,
+			ParticipantID:      move.participantID,
+			StartsAt:           effectiveAt,
+			Metadata:           metadata,
+		}); err != nil {
+			return fmt.Errorf("add %s to %s: %w", move.ParticipantName, move.To, err)
+		}
+	}
+
+	for _, assignment := range plan.Assignments {
+		if assignment.Action != realignmentAssignmentEnd {
+			continue
+		}
+		if _, err := qtx.EndActivityGroupAssignment(ctx, db.EndActivityGroupAssignmentParams{
+			EndsAt: pgtype.Timestamptz{Time: effectiveAt, Valid: true},
+			ID:     assignment.id,
+		}); err != nil {
+			return fmt.Errorf("end %s assignment for %s: %w", assignment.ActivityName, assignment.ParticipantGroupName, err)
+		}
+	}
+	return nil
+}
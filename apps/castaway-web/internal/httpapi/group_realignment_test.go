@@ -0,0 +1,120 @@
+package httpapi
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func realignmentParticipants(names ...string) []db.ListParticipantsByInstanceRow {
+	participants := make([]db.ListParticipantsByInstanceRow, 0, len(names))
+	for _, name := range names {
+		participants = append(participants, db.ListParticipantsByInstanceRow{
+			ID:   pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			Name: name,
+		})
+	}
+	return participants
+}
+
+func realignmentGroupFixture(name string, members ...db.ListParticipantsByInstanceRow) realignmentGroupState {
+	group := realignmentGroupState{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Name: name}
+	for _, member := range members {
+		group.Members = append(group.Members, member.ID)
+	}
+	return group
+}
+
+func TestPlanGroupRealignmentSwapsAndReportsAssignments(t *testing.T) {
+	participants := realignmentParticipants("Alice", "Bob", "Cara", "Dan")
+	alice, bob, cara, dan := participants[0], participants[1], participants[2], participants[3]
+	lavo := realignmentGroupFixture("Lavo", alice, bob)
+	gata := realignmentGroupFixture("Gata", cara, dan)
+	effectiveAt := time.Date(2026, time.March, 12, 1, 0, 0, 0, time.UTC)
+
+	plan, err := planGroupRealignment(groupRealignmentRequest{
+		EffectiveAt: effectiveAt,
+		Layout: []groupLayoutEntry{
+			{Group: "lavo", Participants: []string{"Alice", "cara"}},
+			{Group: "Tuku", Participants: []string{uuid.UUID(bob.ID.Bytes).String(), " Dan "}},
+		},
+	}, groupKindTribe, []realignmentGroupState{lavo, gata}, participants)
+	if err != nil {
+		t.Fatalf("plan swap: %v", err)
+	}
+	if plan.Mode != realignmentModeSwap || len(plan.Groups) != 2 || plan.Groups[0].Name != "Lavo" || plan.Groups[0].Created || !plan.Groups[1].Created {
+		t.Fatalf("unexpected groups: %+v", plan.Groups)
+	}
+	if len(plan.Unchanged) != 1 || plan.Unchanged[0] != "Alice" {
+		t.Fatalf("expected Alice to stay in Lavo, got %+v", plan.Unchanged)
+	}
+	if len(plan.Moves) != 3 || plan.Moves[0].ParticipantName != "Cara" || plan.Moves[0].From[0] != "Gata" || plan.Moves[0].To != "Lavo" {
+		t.Fatalf("unexpected moves: %+v", plan.Moves)
+	}
+	if len(plan.EmptiedGroups) != 1 || plan.EmptiedGroups[0] != "Gata" {
+		t.Fatalf("expected Gata to be emptied, got %+v", plan.EmptiedGroups)
+	}
+
+	other := realignmentGroupFixture("Alliance", alice)
+	plan.addAssignments([]db.ListActiveActivityGroupAssignmentsByInstanceAtRow{
+		{ID: 1, ActivityName: "Tribal Pony", ParticipantGroupID: lavo.ID, ParticipantGroupName: "Lavo"},
+		{ID: 2, ActivityName: "Tribal Pony", ParticipantGroupID: gata.ID, ParticipantGroupName: "Gata"},
+		{ID: 3, ActivityName: "Wordle", ParticipantGroupID: other.ID, ParticipantGroupName: "Alliance"},
+	})
+	if len(plan.Assignments) != 2 || plan.Assignments[0].Action != realignmentAssignmentKeep || plan.Assignments[1].Action != realignmentAssignmentEnd {
+		t.Fatalf("unexpected assignments: %+v", plan.Assignments)
+	}
+}
+
+func TestPlanGroupRealignmentMergesEveryone(t *testing.T) {
+	participants := realignmentParticipants("Alice", "Bob", "Cara")
+	lavo := realignmentGroupFixture("Lavo", participants[0], participants[1])
+	gata := realignmentGroupFixture("Gata", participants[2])
+
+	plan, err := planGroupRealignment(groupRealignmentRequest{
+		EffectiveAt: time.Date(2026, time.April, 2, 0, 0, 0, 0, time.UTC),
+		MergeInto:   "Merge",
+	}, groupKindTribe, []realignmentGroupState{lavo, gata}, participants)
+	if err != nil {
+		t.Fatalf("plan merge: %v", err)
+	}
+	if len(plan.Groups) != 1 || !plan.Groups[0].Created || len(plan.Groups[0].Members) != 3 {
+		t.Fatalf("unexpected merge group: %+v", plan.Groups)
+	}
+	if len(plan.Moves) != 3 || len(plan.Unchanged) != 0 || len(plan.EmptiedGroups) != 2 {
+		t.Fatalf("unexpected merge plan: %+v", plan)
+	}
+}
+
+func TestPlanGroupRealignmentRejectsInvalidLayouts(t *testing.T) {
+	participants := realignmentParticipants("Alice", "Bob", "Cara")
+	groups := []realignmentGroupState{realignmentGroupFixture("Lavo", participants[0], participants[1])}
+	effectiveAt := time.Date(2026, time.March, 12, 1, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name string
+		req  groupRealignmentRequest
+		want string
+	}{
+		{name: "neither", req: groupRealignmentRequest{}, want: "exactly one of"},
+		{name: "both", req: groupRealignmentRequest{MergeInto: "Merge", Layout: []groupLayoutEntry{{Group: "A", Participants: []string{"Alice"}}}}, want: "exactly one of"},
+		{name: "missing member", req: groupRealignmentRequest{Layout: []groupLayoutEntry{{Group: "A", Participants: []string{"Alice"}}}}, want: "missing Bob"},
+		{name: "placed twice", req: groupRealignmentRequest{Layout: []groupLayoutEntry{{Group: "A", Participants: []string{"Alice", "Bob"}}, {Group: "B", Participants: []string{"alice"}}}}, want: "more than once"},
+		{name: "duplicate group", req: groupRealignmentRequest{Layout: []groupLayoutEntry{{Group: "A", Participants: []string{"Alice"}}, {Group: "a", Participants: []string{"Bob"}}}}, want: "appears more than once"},
+		{name: "empty group", req: groupRealignmentRequest{Layout: []groupLayoutEntry{{Group: "A", Participants: []string{"Alice", "Bob"}}, {Group: "B"}}}, want: "no participants"},
+		{name: "unknown participant", req: groupRealignmentRequest{Layout: []groupLayoutEntry{{Group: "A", Participants: []string{"Alice", "Bob", "Zed"}}}}, want: "not found"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.req.EffectiveAt = effectiveAt
+			_, err := planGroupRealignment(tc.req, groupKindTribe, groups, participants)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,440 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/gameplay"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Participant group kinds accepted by the groups API.
+const (
+	groupKindTribe    = "tribe"
+	groupKindAlliance = "alliance"
+)
+
+type createParticipantGroupRequest struct {
+	Name     string           `json:"name" binding:"required"`
+	Kind     string           `json:"kind"`
+	Metadata *json.RawMessage `json:"metadata"`
+}
+
+type createMembershipRequest struct {
+	ParticipantID string           `json:"participant_id" binding:"required"`
+	Role          string           `json:"role"`
+	StartsAt      time.Time        `json:"starts_at" binding:"required"`
+	EndsAt        *time.Time       `json:"ends_at"`
+	Metadata      *json.RawMessage `json:"metadata"`
+}
+
+type endMembershipRequest struct {
+	EndsAt time.Time `json:"ends_at" binding:"required"`
+}
+
+func participantGroupToJSON(id pgtype.UUID, name, kind string, metadata []byte, createdAt, updatedAt pgtype.Timestamptz) gin.H {
+	return gin.H{
+		"id":         pgUUIDString(id),
+		"name":       name,
+		"kind":       kind,
+		"metadata":   json.RawMessage(nonEmptyMetadata(metadata)),
+		"created_at": formatTimestamp(createdAt),
+		"updated_at": formatTimestamp(updatedAt),
+	}
+}
+
+func membershipToJSON(groupID, participantID pgtype.UUID, participantName, role string, startsAt, endsAt pgtype.Timestamptz) gin.H {
+	return gin.H{
+		"participant_group_id": pgUUIDString(groupID),
+		"participant_id":       pgUUIDString(participantID),
+		"participant_name":     participantName,
+		"role":                 role,
+		"starts_at":            formatTimestamp(startsAt),
+		"ends_at":              formatNullableTimestamp(endsAt),
+	}
+}
+
+// membershipErrorStatus maps gameplay membership errors to HTTP statuses.
+func membershipErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gameplay.ErrEpisodeBoundary):
+		return http.StatusBadRequest
+	case errors.Is(err, pgx.ErrNoRows):
+		return http.StatusNotFound
+	}
+	return statusFromPg(err)
+}
+
+// requireInstanceGroup loads groupID and checks it belongs to instanceID,
+// writing a 404 when it does not.
+func (s *Server) requireInstanceGroup(c *gin.Context, instanceID, groupID uuid.UUID) (db.GetParticipantGroupRow, bool) {
+	group, err := s.queries.GetParticipantGroup(c.Request.Context(), toPGUUID(groupID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "group not found"})
+			return db.GetParticipantGroupRow{}, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return db.GetParticipantGroupRow{}, false
+	}
+	if group.InstanceID != toPGUUID(instanceID) {
+		c.JSON(http.StatusNotFound, errorResponse{Error: "group not found"})
+		return db.GetParticipantGroupRow{}, false
+	}
+	return group, true
+}
+
+// listParticipantGroups lists an instance's tribes and alliances with the
+// members each had at as_of, or now.
+func (s *Server) listParticipantGroups(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	asOf, ok := parseOptionalAsOfQuery(c)
+	if !ok {
+		return
+	}
+	kindFilter := strings.ToLower(strings.TrimSpace(c.Query("kind")))
+	at := time.Now().UTC()
+	if asOf != nil {
+		at = *asOf
+	}
+
+	ctx := c.Request.Context()
+	if _, err := s.queries.GetInstance(ctx, toPGUUID(instanceID)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	groups, err := s.queries.ListParticipantGroupsByInstance(ctx, toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	service := gameplay.NewService(s.queries)
+	response := make([]gin.H, 0, len(groups))
+	for _, group := range groups {
+		if kindFilter != "" && !strings.EqualFold(group.Kind, kindFilter) {
+			continue
+		}
+		memberships, err := service.ActiveGroupMembershipsAt(ctx, group.ID, at)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		members := make([]gin.H, 0, len(memberships))
+		for _, membership := range memberships {
+			members = append(members, membershipToJSON(membership.ParticipantGroupID, membership.ParticipantID, membership.ParticipantName, membership.Role, membership.StartsAt, membership.EndsAt))
+		}
+		item := participantGroupToJSON(group.ID, group.Name, group.Kind, group.Metadata, group.CreatedAt, group.UpdatedAt)
+		item["members"] = members
+		response = append(response, item)
+	}
+	c.JSON(http.StatusOK, gin.H{"as_of": at.Format(time.RFC3339Nano), "groups": response})
+}
+
+func (s *Server) createParticipantGroup(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+
+	var req createParticipantGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	kind := strings.ToLower(strings.TrimSpace(req.Kind))
+	if kind == "" {
+		kind = groupKindTribe
+	}
+	if name == "" {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "name is required"})
+		return
+	}
+	if kind != groupKindTribe && kind != groupKindAlliance {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "kind must be tribe or alliance"})
+		return
+	}
+	metadata := []byte("{}")
+	if req.Metadata != nil {
+		metadata = *req.Metadata
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	group, err := qtx.CreateParticipantGroup(ctx, db.CreateParticipantGroupParams{
+		InstanceID: toPGUUID(instanceID),
+		Name:       name,
+		Kind:       kind,
+		Metadata:   metadata,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	response := participantGroupToJSON(group.ID, group.Name, group.Kind, group.Metadata, group.CreatedAt, group.UpdatedAt)
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionGroupCreate, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"group": response})
+}
+
+// getParticipantGroup returns a group with its members at as_of, or now,
+// and every membership period it has had.
+func (s *Server) getParticipantGroup(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	groupID, ok := parseUUIDPath(c, "groupID")
+	if !ok {
+		return
+	}
+	asOf, ok := parseOptionalAsOfQuery(c)
+	if !ok {
+		return
+	}
+	at := time.Now().UTC()
+	if asOf != nil {
+		at = *asOf
+	}
+	group, ok := s.requireInstanceGroup(c, instanceID, groupID)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	participants, err := s.queries.ListParticipantsByInstance(ctx, toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	participantNames := make(map[string]string, len(participants))
+	for _, participant := range participants {
+		participantNames[pgUUIDString(participant.ID)] = participant.Name
+	}
+
+	periods, err := s.queries.ListParticipantGroupMembershipPeriods(ctx, group.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	members := make([]gin.H, 0)
+	memberships := make([]gin.H, 0, len(periods))
+	for _, period := range periods {
+		row := membershipToJSON(period.ParticipantGroupID, period.ParticipantID, participantNames[pgUUIDString(period.ParticipantID)], period.Role, period.StartsAt, period.EndsAt)
+		memberships = append(memberships, row)
+		if !period.StartsAt.Time.After(at) && (!period.EndsAt.Valid || period.EndsAt.Time.After(at)) {
+			members = append(members, row)
+		}
+	}
+
+	response := participantGroupToJSON(group.ID, group.Name, group.Kind, group.Metadata, group.CreatedAt, group.UpdatedAt)
+	response["members"] = members
+	response["memberships"] = memberships
+	c.JSON(http.StatusOK, gin.H{"as_of": at.Format(time.RFC3339Nano), "group": response})
+}
+
+// createGroupMembership adds a participant to a group from one episode
+// boundary, optionally until another. A participant cannot hold overlapping
+// periods in the same group.
+func (s *Server) createGroupMembership(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	groupID, ok := parseUUIDPath(c, "groupID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+	group, ok := s.requireInstanceGroup(c, instanceID, groupID)
+	if !ok {
+		return
+	}
+
+	var req createMembershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	participantID, err := uuid.Parse(strings.TrimSpace(req.ParticipantID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "participant_id must be a UUID"})
+		return
+	}
+	if req.EndsAt != nil && !req.EndsAt.After(req.StartsAt) {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "ends_at must be after starts_at"})
+		return
+	}
+	var metadata []byte
+	if req.Metadata != nil {
+		metadata = *req.Metadata
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	periods, err := qtx.ListParticipantGroupMembershipPeriods(ctx, group.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	for _, period := range periods {
+		if period.ParticipantID != toPGUUID(participantID) {
+			continue
+		}
+		startsBeforeNewEnds := req.EndsAt == nil || period.StartsAt.Time.Before(*req.EndsAt)
+		endsAfterNewStarts := !period.EndsAt.Valid || period.EndsAt.Time.After(req.StartsAt)
+		if startsBeforeNewEnds && endsAfterNewStarts {
+			c.JSON(http.StatusConflict, errorResponse{Error: fmt.Sprintf("participant already has a %s membership from %s", group.Name, formatTimestamp(period.StartsAt))})
+			return
+		}
+	}
+
+	membership, err := gameplay.NewService(qtx).CreateMembershipPeriod(ctx, gameplay.CreateMembershipPeriodParams{
+		ParticipantGroupID: group.ID,
+		ParticipantID:      toPGUUID(participantID),
+		Role:               strings.TrimSpace(req.Role),
+		StartsAt:           req.StartsAt,
+		EndsAt:             req.EndsAt,
+		Metadata:           metadata,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "participant not found"})
+			return
+		}
+		c.JSON(membershipErrorStatus(err), errorResponse{Error: err.Error()})
+		return
+	}
+	participant, err := qtx.GetParticipant(ctx, membership.ParticipantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	response := membershipToJSON(membership.ParticipantGroupID, membership.ParticipantID, participant.Name, membership.Role, membership.StartsAt, membership.EndsAt)
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionGroupMembershipCreate, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"membership": response})
+}
+
+// endGroupMembership ends a participant's open membership in a group at an
+// episode boundary.
+func (s *Server) endGroupMembership(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	groupID, ok := parseUUIDPath(c, "groupID")
+	if !ok {
+		return
+	}
+	participantID, ok := parseUUIDPath(c, "participantID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+	group, ok := s.requireInstanceGroup(c, instanceID, groupID)
+	if !ok {
+		return
+	}
+
+	var req endMembershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	ended, err := gameplay.NewService(qtx).EndMembershipPeriod(ctx, gameplay.EndMembershipPeriodParams{
+		ParticipantGroupID: group.ID,
+		ParticipantID:      toPGUUID(participantID),
+		EndsAt:             req.EndsAt,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "participant has no open membership in this group at ends_at"})
+			return
+		}
+		c.JSON(membershipErrorStatus(err), errorResponse{Error: err.Error()})
+		return
+	}
+	participant, err := qtx.GetParticipant(ctx, toPGUUID(participantID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	memberships := make([]gin.H, 0, len(ended))
+	for _, membership := range ended {
+		memberships = append(memberships, membershipToJSON(membership.ParticipantGroupID, membership.ParticipantID, participant.Name, membership.Role, membership.StartsAt, membership.EndsAt))
+	}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionGroupMembershipEnd, nil, memberships); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"memberships": memberships})
+}
@@ -0,0 +1,176 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	idempotencyKeyTTL         = 24 * time.Hour
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+// idempotencyRecorder keeps a copy of the response body so it can be stored
+// against the request's Idempotency-Key.
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// requireIdempotency makes a write route safe to retry. Requests without an
+// Idempotency-Key header run as usual. The first request with a key claims it
+// and stores its response; a later request with the same key and the same
+// fingerprint gets that response back without running again. Reusing a key
+// for a different request is rejected, as is a replay that arrives while the
+// original is still running. Server errors release the key so the caller can
+// retry. Bodies over 1MB are rejected with 413, since they could not be
+// fingerprinted whole. Expired keys are swept by the scheduler.
+//
+// Routes list requireIdempotency ahead of requireInstanceState, so a retry of
+// a write that already succeeded is replayed even after the instance has
+// moved to a state that would now refuse it.
+func (s *Server) requireIdempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(idempotencyKeyHeader))
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{Error: "Idempotency-Key must be at most 255 characters"})
+			return
+		}
+		instanceID, err := uuid.Parse(c.Param("instanceID"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{Error: "invalid instanceID"})
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentRequestBytes))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, errorResponse{Error: "request body must be at most 1MB"})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		now := time.Now().UTC()
+		fingerprint := idempotencyFingerprint(c.Request.Method, c.FullPath(), c.Request.URL.Path, discordUserIDFromRequest(c.Request), body)
+		claimedID, err := s.queries.ClaimIdempotencyKey(ctx, db.ClaimIdempotencyKeyParams{
+			IdempotencyKey:     key,
+			RequestFingerprint: fingerprint,
+			ExpiresAt:          optionalTime(now.Add(idempotencyKeyTTL)),
+			InstanceID:         toPGUUID(instanceID),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.replayIdempotentResponse(c, toPGUUID(instanceID), key, fingerprint)
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(statusFromPg(err), errorResponse{Error: err.Error()})
+			return
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		// Storing the outcome must survive the caller hanging up, or the key
+		// would stay claimed with no response until it expires.
+		storeCtx := context.WithoutCancel(ctx)
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := s.queries.DeleteIdempotencyKey(storeCtx, claimedID); err != nil {
+				slog.Error("release idempotency key failed", slog.String("service", "castaway-web"), slog.String("error", err.Error()))
+			}
+		}()
+
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		if err := s.queries.CompleteIdempotencyKey(storeCtx, db.CompleteIdempotencyKeyParams{
+			StatusCode:   pgtype.Int4{Int32: int32(status), Valid: true},
+			ResponseBody: recorder.body.Bytes(),
+			ID:           claimedID,
+		}); err != nil {
+			slog.Error("store idempotent response failed", slog.String("service", "castaway-web"), slog.String("error", err.Error()))
+			return
+		}
+		completed = true
+	}
+}
+
+func (s *Server) replayIdempotentResponse(c *gin.Context, instanceID pgtype.UUID, key, fingerprint string) {
+	stored, err := s.queries.GetIdempotencyKey(c.Request.Context(), db.GetIdempotencyKeyParams{
+		InstanceID:     instanceID,
+		IdempotencyKey: key,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Nothing was claimed because the instance does not exist; let the
+		// handler report it.
+		c.Next()
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if stored.RequestFingerprint != fingerprint {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, errorResponse{Error: "Idempotency-Key was already used for a different request"})
+		return
+	}
+	if !stored.StatusCode.Valid {
+		c.AbortWithStatusJSON(http.StatusConflict, errorResponse{Error: "a request with this Idempotency-Key is still in progress"})
+		return
+	}
+	c.Header(idempotentReplayedHeader, "true")
+	c.Data(int(stored.StatusCode.Int32), "application/json; charset=utf-8", stored.ResponseBody)
+	c.Abort()
+}
+
+// idempotencyFingerprint identifies what a request asked for, so a key reused
+// by another caller, route or body is not mistaken for a retry.
+func idempotencyFingerprint(method, route, path, discordUserID string, body []byte) string {
+	hash := sha256.New()
+	for _, part := range []string{method, route, path, strings.TrimSpace(discordUserID)} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
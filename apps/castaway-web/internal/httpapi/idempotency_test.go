@@ -0,0 +1,23 @@
+package httpapi
+
+import "testing"
+
+func TestIdempotencyFingerprintDistinguishesRequests(t *testing.T) {
+	route := "/instances/:instanceID/loan-shark/me/borrow"
+	path := "/instances/7b8c/loan-shark/me/borrow"
+	base := idempotencyFingerprint("POST", route, path, "alice-discord", []byte(`{"points":2}`))
+	if again := idempotencyFingerprint("POST", route, path, " alice-discord ", []byte(`{"points":2}`)); again != base {
+		t.Fatalf("expected identical requests to share a fingerprint")
+	}
+
+	variants := map[string]string{
+		"body":   idempotencyFingerprint("POST", route, path, "alice-discord", []byte(`{"points":3}`)),
+		"caller": idempotencyFingerprint("POST", route, path, "bob-discord", []byte(`{"points":2}`)),
+		"route":  idempotencyFingerprint("POST", "/instances/:instanceID/loan-shark/me/repay", "/instances/7b8c/loan-shark/me/repay", "alice-discord", []byte(`{"points":2}`)),
+	}
+	for name, fingerprint := range variants {
+		if fingerprint == base {
+			t.Fatalf("expected a different %s to change the fingerprint", name)
+		}
+	}
+}
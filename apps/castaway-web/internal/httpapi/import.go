@@ -1,20 +1,25 @@
 package httpapi
 
 import (
+	"bytes"
+	"context"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/conv"
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/gameplay"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type importSubmission struct {
@@ -28,63 +33,629 @@ type importInstanceRequest struct {
 	Submissions []importSubmission `json:"submissions"`
 }
 
+// importIssue is a single validation finding for a staged import. Submission
+// is the 1-based position of the offending submission in the payload.
+type importIssue struct {
+	Submission      int    `json:"submission,omitempty"`
+	ParticipantName string `json:"participant_name,omitempty"`
+	Message         string `json:"message"`
+}
+
+type importNameMapping struct {
+	Raw      string `json:"raw"`
+	Resolved string `json:"resolved"`
+}
+
+type importPreviewParticipant struct {
+	Name  string   `json:"name"`
+	Picks []string `json:"picks"`
+}
+
+type importPreview struct {
+	Name               string                     `json:"name"`
+	Season             int32                      `json:"season"`
+	ReplacesInstanceID string                     `json:"replaces_instance_id,omitempty"`
+	Contestants        []string                   `json:"contestants"`
+	Participants       []importPreviewParticipant `json:"participants"`
+	NormalizedNames    []importNameMapping        `json:"normalized_names"`
+}
+
+// importValidation is persisted on the imports row so reviewers can see what
+// an apply would do before it happens.
+type importValidation struct {
+	Errors   []importIssue  `json:"errors"`
+	Warnings []importIssue  `json:"warnings"`
+	Preview  *importPreview `json:"preview,omitempty"`
+}
+
+type applyImportRequest struct {
+	Mode           string `json:"mode"`
+	OverrideReason string `json:"override_reason"`
+}
+
+// importDraftOverride is who is applying a merge and why. Merges that change
+// drafts after the instance's draft deadline need an instance admin and a
+// reason, as replaceDraft does, and record a draft override for each change.
+type importDraftOverride struct {
+	actorDiscordUserID string
+	reason             string
+}
+
+var (
+	errImportDraftDeadlinePassed = errors.New("draft deadline has passed")
+	errImportDraftOverrideReason = errors.New("override_reason is required after the draft deadline")
+)
+
+const (
+	importModeReplace = "replace"
+	importModeMerge   = "merge"
+)
+
+type importPick struct {
+	Position       int32  `json:"position"`
+	ContestantName string `json:"contestant_name"`
+}
+
+type importPickChange struct {
+	Position int32  `json:"position"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+}
+
+type importParticipantDiff struct {
+	ParticipantID   string             `json:"participant_id"`
+	ParticipantName string             `json:"participant_name"`
+	Created         bool               `json:"created"`
+	Added           []importPick       `json:"added"`
+	Removed         []importPick       `json:"removed"`
+	Changed         []importPickChange `json:"changed"`
+}
+
+// importMergeDiff reports what a merge apply changed. Participants whose drafts
+// already matched the import are listed by name only.
+type importMergeDiff struct {
+	InstanceCreated       bool                    `json:"instance_created"`
+	ContestantsAdded      []string                `json:"contestants_added"`
+	Participants          []importParticipantDiff `json:"participants"`
+	UnchangedParticipants []string                `json:"unchanged_participants"`
+}
+
 func (s *Server) importInstance(c *gin.Context) {
-	payload, err := s.parseImportPayload(c)
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	payload, err := parseImportPayload(c.ContentType(), body, c.Query("season"), c.Query("name"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
 		return
 	}
 
-	if payload.Name == "" {
-		payload.Name = fmt.Sprintf("Season %d", payload.Season)
+	submissionsCount, err := conv.ToInt32(len(payload.Submissions))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	staged, err := s.queries.CreateImport(c.Request.Context(), db.CreateImportParams{
+		Name:             payload.Name,
+		Season:           payload.Season,
+		ContentType:      c.ContentType(),
+		Payload:          string(body),
+		SubmissionsCount: submissionsCount,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
 	}
 
-	tx, err := s.pool.Begin(c.Request.Context())
+	validation, err := validateImport(c.Request.Context(), s.queries, payload)
 	if err != nil {
+		// Leave the row failed rather than accepted, so it is not mistaken for
+		// an import still waiting on validation.
+		if markErr := s.queries.UpdateImportValidation(c.Request.Context(), db.UpdateImportValidationParams{
+			Status:     "failed",
+			Error:      pgtype.Text{String: err.Error(), Valid: true},
+			Validation: []byte("{}"),
+			ID:         staged.ID,
+		}); markErr != nil {
+			err = errors.Join(err, markErr)
+		}
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
-	defer func() {
-		rollbackErr := tx.Rollback(c.Request.Context())
-		if rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
-			if ginErr := c.Error(rollbackErr); ginErr != nil {
-				ginErr.Type = gin.ErrorTypePrivate
-			}
+	validationJSON, err := json.Marshal(validation)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	status, importErr := "validated", pgtype.Text{}
+	if len(validation.Errors) > 0 {
+		status = "failed"
+		importErr = pgtype.Text{String: fmt.Sprintf("import has %d validation error(s)", len(validation.Errors)), Valid: true}
+	}
+	if err := s.queries.UpdateImportValidation(c.Request.Context(), db.UpdateImportValidationParams{
+		Status:     status,
+		Error:      importErr,
+		Validation: validationJSON,
+		ID:         staged.ID,
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	row, err := s.queries.GetImport(c.Request.Context(), staged.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"import": importToJSON(row)})
+}
+
+func (s *Server) getImport(c *gin.Context) {
+	importID, ok := parseUUIDPath(c, "importID")
+	if !ok {
+		return
+	}
+
+	row, err := s.queries.GetImport(c.Request.Context(), toPGUUID(importID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "import not found"})
+			return
 		}
-	}()
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"import": importToJSON(row)})
+}
 
-	qtx := s.queries.WithTx(tx)
-	if err := qtx.DeleteInstanceByNameSeason(c.Request.Context(), db.DeleteInstanceByNameSeasonParams{
-		Name:   payload.Name,
-		Season: payload.Season,
-	}); err != nil {
+func (s *Server) applyImport(c *gin.Context) {
+	importID, ok := parseUUIDPath(c, "importID")
+	if !ok {
+		return
+	}
+
+	var req applyImportRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+			return
+		}
+	}
+	// Replace deletes the existing instance and its gameplay data, so it has
+	// to be asked for explicitly.
+	mode := strings.TrimSpace(req.Mode)
+	if mode == "" {
+		mode = importModeMerge
+	}
+	if mode != importModeReplace && mode != importModeMerge {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "mode must be replace or merge"})
+		return
+	}
+
+	tx, err := s.pool.Begin(c.Request.Context())
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
 
-	instance, err := qtx.CreateInstance(c.Request.Context(), db.CreateInstanceParams{
-		Name:   payload.Name,
-		Season: payload.Season,
-	})
+	staged, err := qtx.GetImportForUpdate(c.Request.Context(), toPGUUID(importID))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "import not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
+	switch staged.Status {
+	case "validated":
+	case "applied":
+		c.JSON(http.StatusConflict, errorResponse{Error: "import has already been applied"})
+		return
+	default:
+		c.JSON(http.StatusConflict, errorResponse{Error: fmt.Sprintf("import is %s and cannot be applied", staged.Status)})
+		return
+	}
 
-	if err := gameplay.NewService(qtx).CopyInstanceSchedule(c.Request.Context(), instance.ID, instance.Season); err != nil {
+	payload, err := decodeStagedImport(staged)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
+	// Contestant names can change between staging and apply, so re-run the
+	// validation against the current catalog rather than trusting the stored
+	// preview.
+	validation, err := validateImport(c.Request.Context(), qtx, payload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if len(validation.Errors) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "import no longer validates", "errors": validation.Errors})
+		return
+	}
+
+	if err := ensureImportTargetConfigurable(c.Request.Context(), qtx, validation.Preview.Name, validation.Preview.Season); err != nil {
+		c.JSON(http.StatusConflict, errorResponse{Error: err.Error()})
+		return
+	}
 
-	globals, err := qtx.ListContestantsGlobal(c.Request.Context())
+	var instance db.CreateInstanceRow
+	var diff *importMergeDiff
+	if mode == importModeMerge {
+		var merged importMergeDiff
+		instance, merged, err = s.mergeImportPreview(c.Request.Context(), qtx, *validation.Preview, importDraftOverride{
+			actorDiscordUserID: discordUserIDFromRequest(c.Request),
+			reason:             strings.TrimSpace(req.OverrideReason),
+		})
+		diff = &merged
+	} else {
+		instance, err = applyImportPreview(c.Request.Context(), qtx, *validation.Preview)
+	}
+	switch {
+	case errors.Is(err, errImportDraftDeadlinePassed):
+		c.JSON(http.StatusForbidden, errorResponse{Error: err.Error()})
+		return
+	case errors.Is(err, errImportDraftOverrideReason):
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	case err != nil:
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	if err := qtx.MarkImportApplied(c.Request.Context(), db.MarkImportAppliedParams{
+		InstanceID: instance.ID,
+		ID:         staged.ID,
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	row, err := qtx.GetImport(c.Request.Context(), staged.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
+	// A merge records what it changed; a replace rebuilt the instance, so it
+	// records everything it wrote.
+	after := gin.H{"import_id": pgUUIDString(staged.ID), "mode": mode}
+	if diff != nil {
+		after["diff"] = diff
+	} else {
+		after["contestants"] = validation.Preview.Contestants
+		after["participants"] = validation.Preview.Participants
+	}
+	if err := recordAuditEvent(c, qtx, instance.ID, auditActionInstanceImport, nil, after); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"mode":     mode,
+		"import":   importToJSON(row),
+		"instance": toInstanceResponse(instance.ID, instance.Name, instance.Season, instance.CreatedAt, instance.State),
+	}
+	if diff != nil {
+		response["diff"] = diff
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// applyImportPreview replaces any instance with the same name and season with
+// one built from the validated preview.
+func applyImportPreview(ctx context.Context, qtx *db.Queries, preview importPreview) (db.CreateInstanceRow, error) {
+	if err := qtx.DeleteInstanceByNameSeason(ctx, db.DeleteInstanceByNameSeasonParams{
+		Name:   preview.Name,
+		Season: preview.Season,
+	}); err != nil {
+		return db.CreateInstanceRow{}, err
+	}
+
+	instance, err := qtx.CreateInstance(ctx, db.CreateInstanceParams{
+		Name:   preview.Name,
+		Season: preview.Season,
+	})
+	if err != nil {
+		return db.CreateInstanceRow{}, err
+	}
+	if err := gameplay.NewService(qtx).CopyInstanceSchedule(ctx, instance.ID, instance.Season); err != nil {
+		return db.CreateInstanceRow{}, err
+	}
+
+	contestantIDByName := make(map[string]pgtype.UUID, len(preview.Contestants))
+	for _, name := range preview.Contestants {
+		contestant, err := qtx.CreateContestant(ctx, db.CreateContestantParams{
+			InstanceID: instance.ID,
+			Name:       name,
+		})
+		if err != nil {
+			return db.CreateInstanceRow{}, err
+		}
+		contestantIDByName[name] = contestant.ID
+	}
+
+	for _, submission := range preview.Participants {
+		participant, err := qtx.CreateParticipant(ctx, db.CreateParticipantParams{
+			InstanceID: instance.ID,
+			Name:       submission.Name,
+		})
+		if err != nil {
+			return db.CreateInstanceRow{}, err
+		}
+		for index, contestantName := range submission.Picks {
+			position, err := conv.ToInt32(index + 1)
+			if err != nil {
+				return db.CreateInstanceRow{}, err
+			}
+			if _, err := qtx.CreateDraftPick(ctx, db.CreateDraftPickParams{
+				InstanceID:    instance.ID,
+				ParticipantID: participant.ID,
+				ContestantID:  contestantIDByName[contestantName],
+				Position:      position,
+			}); err != nil {
+				return db.CreateInstanceRow{}, err
+			}
+		}
+	}
+
+	return instance, nil
+}
+
+// mergeImportPreview applies an import on top of an existing instance without
+// deleting anything: missing contestants and participants are added and only
+// drafts that differ from the import are rewritten. Episodes, activities, the
+// bonus ledger, pony ownerships and loans are left alone. Drafts rewritten
+// after the draft deadline are recorded as overrides by override's actor.
+func (s *Server) mergeImportPreview(ctx context.Context, qtx *db.Queries, preview importPreview, override importDraftOverride) (db.CreateInstanceRow, importMergeDiff, error) {
+	diff := importMergeDiff{
+		ContestantsAdded:      []string{},
+		Participants:          []importParticipantDiff{},
+		UnchangedParticipants: []string{},
+	}
+
+	instances, err := qtx.ListInstances(ctx)
+	if err != nil {
+		return db.CreateInstanceRow{}, importMergeDiff{}, err
+	}
+	var instance db.CreateInstanceRow
+	found := false
+	for _, existing := range instances {
+		if existing.Name == preview.Name && existing.Season == preview.Season {
+			instance = db.CreateInstanceRow(existing)
+			found = true
+			break
+		}
+	}
+	if !found {
+		instance, err = qtx.CreateInstance(ctx, db.CreateInstanceParams{Name: preview.Name, Season: preview.Season})
+		if err != nil {
+			return db.CreateInstanceRow{}, importMergeDiff{}, err
+		}
+		if err := gameplay.NewService(qtx).CopyInstanceSchedule(ctx, instance.ID, instance.Season); err != nil {
+			return db.CreateInstanceRow{}, importMergeDiff{}, err
+		}
+		diff.InstanceCreated = true
+	}
+	// A new instance has no drafts yet, so importing them is not an override.
+	var deadline draftDeadline
+	if found {
+		deadline, err = s.resolveDraftDeadline(ctx, qtx, instance.ID)
+		if err != nil {
+			return db.CreateInstanceRow{}, importMergeDiff{}, err
+		}
+	}
+	locked := found && deadline.lockedAt(time.Now().UTC())
+	overrideAllowed := false
+
+	contestants, err := qtx.ListContestantsByInstance(ctx, instance.ID)
+	if err != nil {
+		return db.CreateInstanceRow{}, importMergeDiff{}, err
+	}
+	contestantIDByName := make(map[string]pgtype.UUID, len(contestants))
+	contestantNameByID := make(map[pgtype.UUID]string, len(contestants))
+	for _, contestant := range contestants {
+		contestantIDByName[contestant.Name] = contestant.ID
+		contestantNameByID[contestant.ID] = contestant.Name
+	}
+	for _, name := range preview.Contestants {
+		if _, ok := contestantIDByName[name]; ok {
+			continue
+		}
+		contestant, err := qtx.CreateContestant(ctx, db.CreateContestantParams{InstanceID: instance.ID, Name: name})
+		if err != nil {
+			return db.CreateInstanceRow{}, importMergeDiff{}, err
+		}
+		contestantIDByName[name] = contestant.ID
+		contestantNameByID[contestant.ID] = name
+		diff.ContestantsAdded = append(diff.ContestantsAdded, name)
+	}
+
+	participants, err := qtx.ListParticipantsByInstance(ctx, instance.ID)
+	if err != nil {
+		return db.CreateInstanceRow{}, importMergeDiff{}, err
+	}
+	participantIDByName := make(map[string]pgtype.UUID, len(participants))
+	for _, participant := range participants {
+		participantIDByName[strings.ToLower(participant.Name)] = participant.ID
+	}
+	draftPicks, err := qtx.ListDraftPicksForInstance(ctx, instance.ID)
+	if err != nil {
+		return db.CreateInstanceRow{}, importMergeDiff{}, err
+	}
+	existingPicks := make(map[pgtype.UUID]map[int32]string, len(participants))
+	existingContestantIDs := make(map[pgtype.UUID][]string, len(participants))
+	for _, pick := range draftPicks {
+		if existingPicks[pick.ParticipantID] == nil {
+			existingPicks[pick.ParticipantID] = map[int32]string{}
+		}
+		existingPicks[pick.ParticipantID][pick.Position] = contestantNameByID[pick.ContestantID]
+	}
+	sort.Slice(draftPicks, func(i, j int) bool { return draftPicks[i].Position < draftPicks[j].Position })
+	for _, pick := range draftPicks {
+		existingContestantIDs[pick.ParticipantID] = append(existingContestantIDs[pick.ParticipantID], pgUUIDString(pick.ContestantID))
+	}
+
+	for _, submission := range preview.Participants {
+		participantID, exists := participantIDByName[strings.ToLower(submission.Name)]
+		if !exists {
+			participant, err := qtx.CreateParticipant(ctx, db.CreateParticipantParams{InstanceID: instance.ID, Name: submission.Name})
+			if err != nil {
+				return db.CreateInstanceRow{}, importMergeDiff{}, err
+			}
+			participantID = participant.ID
+		}
+
+		participantDiff := diffImportDraft(existingPicks[participantID], submission.Picks)
+		if exists && len(participantDiff.Added) == 0 && len(participantDiff.Removed) == 0 && len(participantDiff.Changed) == 0 {
+			diff.UnchangedParticipants = append(diff.UnchangedParticipants, submission.Name)
+			continue
+		}
+		participantDiff.ParticipantID = pgUUIDString(participantID)
+		participantDiff.ParticipantName = submission.Name
+		participantDiff.Created = !exists
+
+		if locked && !overrideAllowed {
+			isAdmin, err := s.isInstanceAdmin(ctx, instance.ID, override.actorDiscordUserID)
+			if err != nil {
+				return db.CreateInstanceRow{}, importMergeDiff{}, err
+			}
+			if !isAdmin {
+				return db.CreateInstanceRow{}, importMergeDiff{}, errImportDraftDeadlinePassed
+			}
+			if override.reason == "" {
+				return db.CreateInstanceRow{}, importMergeDiff{}, errImportDraftOverrideReason
+			}
+			overrideAllowed = true
+		}
+
+		if err := qtx.DeleteDraftPicksForParticipant(ctx, participantID); err != nil {
+			return db.CreateInstanceRow{}, importMergeDiff{}, err
+		}
+		contestantIDs := make([]string, 0, len(submission.Picks))
+		for index, contestantName := range submission.Picks {
+			position, err := conv.ToInt32(index + 1)
+			if err != nil {
+				return db.CreateInstanceRow{}, importMergeDiff{}, err
+			}
+			if _, err := qtx.CreateDraftPick(ctx, db.CreateDraftPickParams{
+				InstanceID:    instance.ID,
+				ParticipantID: participantID,
+				ContestantID:  contestantIDByName[contestantName],
+				Position:      position,
+			}); err != nil {
+				return db.CreateInstanceRow{}, importMergeDiff{}, err
+			}
+			contestantIDs = append(contestantIDs, pgUUIDString(contestantIDByName[contestantName]))
+		}
+		if locked {
+			previousJSON, err := json.Marshal(append([]string{}, existingContestantIDs[participantID]...))
+			if err != nil {
+				return db.CreateInstanceRow{}, importMergeDiff{}, err
+			}
+			contestantJSON, err := json.Marshal(contestantIDs)
+			if err != nil {
+				return db.CreateInstanceRow{}, importMergeDiff{}, err
+			}
+			if _, err := qtx.CreateDraftOverride(ctx, db.CreateDraftOverrideParams{
+				ActorDiscordUserID:    draftOverrideActor(ctx, override.actorDiscordUserID),
+				Reason:                override.reason,
+				DraftDeadline:         optionalTime(deadline.at),
+				PreviousContestantIds: previousJSON,
+				ContestantIds:         contestantJSON,
+				InstanceID:            instance.ID,
+				ParticipantID:         participantID,
+			}); err != nil {
+				return db.CreateInstanceRow{}, importMergeDiff{}, err
+			}
+		}
+		diff.Participants = append(diff.Participants, participantDiff)
+	}
+
+	return instance, diff, nil
+}
+
+// diffImportDraft compares stored picks (by position) with an imported
+// ranking and reports per-position additions, removals and changes.
+func diffImportDraft(existing map[int32]string, picks []string) importParticipantDiff {
+	diff := importParticipantDiff{
+		Added:   []importPick{},
+		Removed: []importPick{},
+		Changed: []importPickChange{},
+	}
+	imported := make(map[int32]string, len(picks))
+	nextPosition := int32(0)
+	for _, name := range picks {
+		nextPosition++
+		imported[nextPosition] = name
+	}
+
+	positions := make([]int32, 0, len(existing)+len(imported))
+	for position := range existing {
+		positions = append(positions, position)
+	}
+	for position := range imported {
+		if _, ok := existing[position]; !ok {
+			positions = append(positions, position)
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+
+	for _, position := range positions {
+		before, hadBefore := existing[position]
+		after, hasAfter := imported[position]
+		switch {
+		case hadBefore && !hasAfter:
+			diff.Removed = append(diff.Removed, importPick{Position: position, ContestantName: before})
+		case !hadBefore && hasAfter:
+			diff.Added = append(diff.Added, importPick{Position: position, ContestantName: after})
+		case before != after:
+			diff.Changed = append(diff.Changed, importPickChange{Position: position, From: before, To: after})
+		}
+	}
+	return diff
+}
+
+func validateImport(ctx context.Context, queries *db.Queries, payload importInstanceRequest) (importValidation, error) {
+	globals, err := queries.ListContestantsGlobal(ctx)
+	if err != nil {
+		return importValidation{}, err
+	}
+	globalNames := make([]string, 0, len(globals))
+	for _, contestant := range globals {
+		globalNames = append(globalNames, contestant.Name)
+	}
+
+	validation := buildImportValidation(payload, globalNames)
+
+	instances, err := queries.ListInstances(ctx)
+	if err != nil {
+		return importValidation{}, err
+	}
+	for _, instance := range instances {
+		if instance.Name == payload.Name && instance.Season == payload.Season {
+			validation.Preview.ReplacesInstanceID = pgUUIDString(instance.ID)
+			validation.Warnings = append(validation.Warnings, importIssue{Message: fmt.Sprintf("an instance named %q already exists for season %d; merge mode (the default) updates it in place, replace mode deletes it and its gameplay data", instance.Name, instance.Season)})
+			break
+		}
+	}
+	return validation, nil
+}
+
+// buildImportValidation normalizes participant and contestant names the same
+// way apply does and reports anything that would make the import ambiguous.
+func buildImportValidation(payload importInstanceRequest, globalContestantNames []string) importValidation {
 	globalByExact := map[string]string{}
 	globalByFirst := map[string]string{}
-	for _, contestant := range globals {
-		name := strings.TrimSpace(contestant.Name)
+	for _, rawName := range globalContestantNames {
+		name := strings.TrimSpace(rawName)
 		if name == "" {
 			continue
 		}
@@ -94,81 +665,149 @@ func (s *Server) importInstance(c *gin.Context) {
 			globalByFirst[first] = name
 		}
 	}
+	knownContestants := make(map[string]struct{}, len(globalByExact))
+	for _, name := range globalByExact {
+		knownContestants[name] = struct{}{}
+	}
 
-	contestantIDByName := map[string]uuid.UUID{}
-	for _, submission := range payload.Submissions {
-		for _, raw := range submission.Rankings {
-			resolved := normalizeContestantName(raw, globalByExact, globalByFirst)
-			if resolved == "" {
-				continue
-			}
-			if _, ok := contestantIDByName[resolved]; ok {
-				continue
-			}
-			contestant, createErr := qtx.CreateContestant(c.Request.Context(), db.CreateContestantParams{
-				InstanceID: instance.ID,
-				Name:       resolved,
-			})
-			if createErr != nil {
-				c.JSON(statusFromPg(createErr), errorResponse{Error: createErr.Error()})
-				return
-			}
-			contestantIDByName[resolved] = uuid.UUID(contestant.ID.Bytes)
+	validation := importValidation{
+		Errors:   []importIssue{},
+		Warnings: []importIssue{},
+		Preview: &importPreview{
+			Name:            payload.Name,
+			Season:          payload.Season,
+			Contestants:     []string{},
+			Participants:    []importPreviewParticipant{},
+			NormalizedNames: []importNameMapping{},
+		},
+	}
+	preview := validation.Preview
+
+	seenMappings := map[importNameMapping]struct{}{}
+	recordMapping := func(raw, resolved string) {
+		mapping := importNameMapping{Raw: strings.TrimSpace(raw), Resolved: resolved}
+		if mapping.Raw == resolved {
+			return
+		}
+		if _, ok := seenMappings[mapping]; ok {
+			return
 		}
+		seenMappings[mapping] = struct{}{}
+		preview.NormalizedNames = append(preview.NormalizedNames, mapping)
 	}
 
-	for _, submission := range payload.Submissions {
+	contestantSeen := map[string]struct{}{}
+	unknownWarned := map[string]struct{}{}
+	participantSubmission := map[string]int{}
+	for index, submission := range payload.Submissions {
+		submissionNumber := index + 1
 		participantName := normalizeParticipantName(submission.ParticipantName)
 		if participantName == "" {
+			validation.Errors = append(validation.Errors, importIssue{Submission: submissionNumber, Message: "participant name is empty"})
 			continue
 		}
-		participant, createErr := qtx.CreateParticipant(c.Request.Context(), db.CreateParticipantParams{
-			InstanceID: instance.ID,
-			Name:       participantName,
-		})
-		if createErr != nil {
-			c.JSON(statusFromPg(createErr), errorResponse{Error: createErr.Error()})
-			return
+		recordMapping(submission.ParticipantName, participantName)
+		if previous, ok := participantSubmission[strings.ToLower(participantName)]; ok {
+			validation.Errors = append(validation.Errors, importIssue{
+				Submission:      submissionNumber,
+				ParticipantName: participantName,
+				Message:         fmt.Sprintf("duplicate participant; submission %d already uses this name", previous),
+			})
+			continue
 		}
+		participantSubmission[strings.ToLower(participantName)] = submissionNumber
 
-		for index, rawContestant := range submission.Rankings {
-			resolvedContestant := normalizeContestantName(rawContestant, globalByExact, globalByFirst)
-			contestantID, ok := contestantIDByName[resolvedContestant]
-			if !ok {
+		picks := make([]string, 0, len(submission.Rankings))
+		pickSeen := map[string]struct{}{}
+		for _, raw := range submission.Rankings {
+			resolved := normalizeContestantName(raw, globalByExact, globalByFirst)
+			if resolved == "" {
+				continue
+			}
+			recordMapping(raw, resolved)
+			if _, ok := pickSeen[resolved]; ok {
+				validation.Errors = append(validation.Errors, importIssue{
+					Submission:      submissionNumber,
+					ParticipantName: participantName,
+					Message:         fmt.Sprintf("contestant %q is ranked more than once", resolved),
+				})
 				continue
 			}
-			position, convErr := conv.ToInt32(index + 1)
-			if convErr != nil {
-				c.JSON(http.StatusBadRequest, errorResponse{Error: convErr.Error()})
-				return
+			pickSeen[resolved] = struct{}{}
+			picks = append(picks, resolved)
+
+			if _, ok := contestantSeen[resolved]; !ok {
+				contestantSeen[resolved] = struct{}{}
+				preview.Contestants = append(preview.Contestants, resolved)
 			}
-			_, createPickErr := qtx.CreateDraftPick(c.Request.Context(), db.CreateDraftPickParams{
-				InstanceID:    instance.ID,
-				ParticipantID: participant.ID,
-				ContestantID:  toPGUUID(contestantID),
-				Position:      position,
-			})
-			if createPickErr != nil {
-				c.JSON(statusFromPg(createPickErr), errorResponse{Error: createPickErr.Error()})
-				return
+			if _, known := knownContestants[resolved]; !known {
+				if _, warned := unknownWarned[resolved]; !warned {
+					unknownWarned[resolved] = struct{}{}
+					validation.Warnings = append(validation.Warnings, importIssue{
+						Submission:      submissionNumber,
+						ParticipantName: participantName,
+						Message:         fmt.Sprintf("contestant %q does not match a known contestant and will be created", resolved),
+					})
+				}
 			}
 		}
+		if len(picks) == 0 {
+			validation.Errors = append(validation.Errors, importIssue{Submission: submissionNumber, ParticipantName: participantName, Message: "submission has no rankings"})
+			continue
+		}
+		preview.Participants = append(preview.Participants, importPreviewParticipant{Name: participantName, Picks: picks})
 	}
 
-	if err := tx.Commit(c.Request.Context()); err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
-		return
+	for _, participant := range preview.Participants {
+		if len(participant.Picks) != len(preview.Contestants) {
+			validation.Warnings = append(validation.Warnings, importIssue{
+				Submission:      participantSubmission[strings.ToLower(participant.Name)],
+				ParticipantName: participant.Name,
+				Message:         fmt.Sprintf("ranks %d of %d contestants", len(participant.Picks), len(preview.Contestants)),
+			})
+		}
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"instance": toInstanceResponse(instance.ID, instance.Name, instance.Season, instance.CreatedAt)})
+	return validation
 }
 
-func (s *Server) parseImportPayload(c *gin.Context) (importInstanceRequest, error) {
-	contentType := c.ContentType()
+func importToJSON(row db.GetImportRow) gin.H {
+	var validation importValidation
+	if len(row.Validation) > 0 {
+		if err := json.Unmarshal(row.Validation, &validation); err != nil {
+			validation = importValidation{}
+		}
+	}
+	if validation.Errors == nil {
+		validation.Errors = []importIssue{}
+	}
+	if validation.Warnings == nil {
+		validation.Warnings = []importIssue{}
+	}
+
+	return gin.H{
+		"id":                pgUUIDString(row.ID),
+		"instance_id":       pgUUIDPointer(row.InstanceID),
+		"name":              row.Name,
+		"season":            row.Season,
+		"content_type":      row.ContentType,
+		"status":            row.Status,
+		"error":             pgTextPointer(row.Error),
+		"submissions_count": row.SubmissionsCount,
+		"errors":            validation.Errors,
+		"warnings":          validation.Warnings,
+		"preview":           validation.Preview,
+		"applied_at":        formatNullableTimestamp(row.AppliedAt),
+		"created_at":        formatTimestamp(row.CreatedAt),
+		"updated_at":        formatTimestamp(row.UpdatedAt),
+	}
+}
+
+func parseImportPayload(contentType string, body []byte, seasonRaw string, name string) (importInstanceRequest, error) {
 	switch contentType {
 	case "application/json":
 		var payload importInstanceRequest
-		if err := c.ShouldBindJSON(&payload); err != nil {
+		if err := json.Unmarshal(body, &payload); err != nil {
 			return importInstanceRequest{}, err
 		}
 		if payload.Season <= 0 {
@@ -177,9 +816,11 @@ func (s *Server) parseImportPayload(c *gin.Context) (importInstanceRequest, erro
 		if len(payload.Submissions) == 0 {
 			return importInstanceRequest{}, fmt.Errorf("submissions cannot be empty")
 		}
+		if strings.TrimSpace(payload.Name) == "" {
+			payload.Name = fmt.Sprintf("Season %d", payload.Season)
+		}
 		return payload, nil
 	case "text/csv":
-		seasonRaw := c.Query("season")
 		if seasonRaw == "" {
 			return importInstanceRequest{}, fmt.Errorf("season query parameter is required for text/csv")
 		}
@@ -187,11 +828,10 @@ func (s *Server) parseImportPayload(c *gin.Context) (importInstanceRequest, erro
 		if err != nil || season <= 0 {
 			return importInstanceRequest{}, fmt.Errorf("invalid season query parameter")
 		}
-		submissions, err := parseCSVSubmissions(c.Request.Body)
+		submissions, err := parseCSVSubmissions(bytes.NewReader(body))
 		if err != nil {
 			return importInstanceRequest{}, err
 		}
-		name := c.Query("name")
 		if name == "" {
 			name = fmt.Sprintf("Season %d", season)
 		}
@@ -209,6 +849,18 @@ func (s *Server) parseImportPayload(c *gin.Context) (importInstanceRequest, erro
 	}
 }
 
+// decodeStagedImport re-parses a stored payload. Name and season come from the
+// imports row because CSV payloads carry them as query parameters.
+func decodeStagedImport(staged db.GetImportForUpdateRow) (importInstanceRequest, error) {
+	payload, err := parseImportPayload(staged.ContentType, []byte(staged.Payload), strconv.Itoa(int(staged.Season)), staged.Name)
+	if err != nil {
+		return importInstanceRequest{}, fmt.Errorf("decode staged import: %w", err)
+	}
+	payload.Name = staged.Name
+	payload.Season = staged.Season
+	return payload, nil
+}
+
 func parseCSVSubmissions(reader io.Reader) ([]importSubmission, error) {
 	r := csv.NewReader(reader)
 	r.FieldsPerRecord = -1
@@ -0,0 +1,93 @@
+package httpapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildImportValidationNormalizesNamesAndPreviewsPicks(t *testing.T) {
+	payload := importInstanceRequest{
+		Season: 50,
+		Name:   "Season 50",
+		Submissions: []importSubmission{
+			{ParticipantName: "brain", Rankings: []string{"Rizgod", "Joe Hunter", "Newcomer Person"}},
+			{ParticipantName: "Keith (KB)", Rankings: []string{"Joe", "Rizo"}},
+		},
+	}
+
+	validation := buildImportValidation(payload, []string{"Rizo Velovic", "Rizo", "Joe Hunter"})
+	if len(validation.Errors) != 0 {
+		t.Fatalf("expected no errors, got %+v", validation.Errors)
+	}
+	preview := validation.Preview
+	if len(preview.Participants) != 2 || preview.Participants[0].Name != "Bryan" {
+		t.Fatalf("expected normalized participants, got %+v", preview.Participants)
+	}
+	if got := strings.Join(preview.Participants[0].Picks, ","); got != "Rizo,Joe Hunter,Newcomer" {
+		t.Fatalf("unexpected normalized picks: %s", got)
+	}
+	if got := strings.Join(preview.Contestants, ","); got != "Rizo,Joe Hunter,Newcomer" {
+		t.Fatalf("unexpected contestants: %s", got)
+	}
+
+	var unknownWarning, partialWarning bool
+	for _, warning := range validation.Warnings {
+		if strings.Contains(warning.Message, `"Newcomer" does not match`) {
+			unknownWarning = true
+		}
+		if warning.ParticipantName == "Keith" && warning.Message == "ranks 2 of 3 contestants" {
+			partialWarning = true
+		}
+	}
+	if !unknownWarning || !partialWarning {
+		t.Fatalf("expected unknown contestant and partial ranking warnings, got %+v", validation.Warnings)
+	}
+}
+
+func TestBuildImportValidationRejectsDuplicates(t *testing.T) {
+	payload := importInstanceRequest{
+		Season: 50,
+		Name:   "Season 50",
+		Submissions: []importSubmission{
+			{ParticipantName: "Bryan", Rankings: []string{"Rizo", "Joe"}},
+			{ParticipantName: "brain", Rankings: []string{"Joe", "Rizo"}},
+			{ParticipantName: "Keith", Rankings: []string{"Joe Hunter", "Joe"}},
+			{ParticipantName: "   ", Rankings: []string{"Joe"}},
+		},
+	}
+
+	validation := buildImportValidation(payload, []string{"Rizo", "Joe Hunter"})
+	if len(validation.Errors) != 3 {
+		t.Fatalf("expected three errors, got %+v", validation.Errors)
+	}
+	if validation.Errors[0].Submission != 2 || !strings.Contains(validation.Errors[0].Message, "duplicate participant") {
+		t.Fatalf("expected duplicate participant error for submission 2, got %+v", validation.Errors[0])
+	}
+	if validation.Errors[1].Submission != 3 || !strings.Contains(validation.Errors[1].Message, `"Joe Hunter" is ranked more than once`) {
+		t.Fatalf("expected duplicate pick error for submission 3, got %+v", validation.Errors[1])
+	}
+	if validation.Errors[2].Submission != 4 || validation.Errors[2].Message != "participant name is empty" {
+		t.Fatalf("expected empty participant error for submission 4, got %+v", validation.Errors[2])
+	}
+}
+
+func TestDiffImportDraftReportsAddedRemovedAndChangedPicks(t *testing.T) {
+	existing := map[int32]string{1: "Rizo", 2: "Joe", 3: "Kyle", 4: "Savannah"}
+
+	diff := diffImportDraft(existing, []string{"Rizo", "Kyle", "Joe"})
+	if len(diff.Added) != 0 {
+		t.Fatalf("expected no added picks, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0] != (importPick{Position: 4, ContestantName: "Savannah"}) {
+		t.Fatalf("expected position 4 removed, got %+v", diff.Removed)
+	}
+	expectedChanged := []importPickChange{{Position: 2, From: "Joe", To: "Kyle"}, {Position: 3, From: "Kyle", To: "Joe"}}
+	if len(diff.Changed) != len(expectedChanged) || diff.Changed[0] != expectedChanged[0] || diff.Changed[1] != expectedChanged[1] {
+		t.Fatalf("unexpected changed picks: %+v", diff.Changed)
+	}
+
+	created := diffImportDraft(nil, []string{"Rizo"})
+	if len(created.Added) != 1 || created.Added[0] != (importPick{Position: 1, ContestantName: "Rizo"}) {
+		t.Fatalf("expected new draft to be reported as added, got %+v", created)
+	}
+}
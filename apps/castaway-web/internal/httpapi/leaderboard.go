@@ -0,0 +1,316 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/gameplay"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/scoring"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// leaderboardInputs holds the parts of a leaderboard that do not depend on the
+// point in time being scored.
+type leaderboardInputs struct {
+	instanceID          pgtype.UUID
+	strategy            scoring.Strategy
+	totalPositions      int
+	contestants         []db.ListContestantsByInstanceRow
+	participants        []db.ListParticipantsByInstanceRow
+	participantNames    map[string]string
+	draftsByParticipant map[string][]scoring.DraftPick
+}
+
+func (s *Server) leaderboard(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+
+	participantFilter, ok := parseOptionalParticipantIDQuery(c)
+	if !ok {
+		return
+	}
+	asOf, ok := parseOptionalAsOfQuery(c)
+	if !ok {
+		return
+	}
+	episodeNumber, ok := parseOptionalEpisodeQuery(c)
+	if !ok {
+		return
+	}
+	if asOf != nil && episodeNumber != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "as_of and episode cannot be combined"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	episodes, err := s.queries.ListInstanceEpisodes(ctx, toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	now := time.Now().UTC()
+	if episodeNumber != nil {
+		windowEnd, found := episodeWindowEnd(episodes, *episodeNumber, now)
+		if !found {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "episode not found"})
+			return
+		}
+		asOf = &windowEnd
+	}
+
+	inputs, err := s.loadLeaderboardInputs(ctx, toPGUUID(instanceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	leaderboard, err := s.scoreLeaderboardAt(ctx, inputs, asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	ranks := scoring.Ranks(leaderboard)
+
+	leaderboardAt := now
+	if asOf != nil {
+		leaderboardAt = *asOf
+	}
+	currentEpisodeIndex := episodeIndexAt(episodes, leaderboardAt)
+
+	// Rank change compares against the board as it stood at the end of the
+	// previous episode, so there is nothing to compare before the second one.
+	var previousRanks map[string]int
+	if currentEpisodeIndex > 0 {
+		previousCutoff := episodes[currentEpisodeIndex].AirsAt.Time.Add(-time.Microsecond)
+		previousLeaderboard, err := s.scoreLeaderboardAt(ctx, inputs, &previousCutoff)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		previousRanks = scoring.Ranks(previousLeaderboard)
+	}
+
+	participantDiscordUserIDs := make(map[string]string, len(inputs.participants))
+	currentTribeNames := make(map[string]string, len(inputs.participants))
+	for _, participant := range inputs.participants {
+		participantID := uuid.UUID(participant.ID.Bytes).String()
+		if participant.DiscordUserID.Valid {
+			participantDiscordUserIDs[participantID] = strings.TrimSpace(participant.DiscordUserID.String)
+		}
+		tribeName, err := s.currentTribeName(ctx, participant.ID, leaderboardAt)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		currentTribeNames[participantID] = tribeName
+	}
+
+	response := make([]gin.H, 0, len(leaderboard))
+	for _, row := range leaderboard {
+		if participantFilter != nil && row.ParticipantID != participantFilter.String() {
+			continue
+		}
+		var previousRank, rankChange any
+		if rank, ok := previousRanks[row.ParticipantID]; ok {
+			previousRank = rank
+			rankChange = rank - ranks[row.ParticipantID]
+		}
+		response = append(response, gin.H{
+			"participant_id":              row.ParticipantID,
+			"participant_name":            row.ParticipantName,
+			"participant_discord_user_id": participantDiscordUserIDs[row.ParticipantID],
+			"current_tribe_name":          currentTribeNames[row.ParticipantID],
+			"rank":                        ranks[row.ParticipantID],
+			"previous_rank":               previousRank,
+			"rank_change":                 rankChange,
+			"score":                       row.Score,
+			"draft_points":                row.DraftPoints,
+			"bonus_points":                row.BonusPoints,
+			"total_points":                row.TotalPoints,
+			"points_available":            row.PointsAvailable,
+			"max_possible_points":         row.MaxPossiblePoints,
+			"eliminated_on_current_bonus": row.EliminatedOnCurrentBonus,
+			"clinched_on_current_bonus":   row.ClinchedOnCurrentBonus,
+		})
+	}
+
+	var episode *instanceEpisodeBrief
+	if currentEpisodeIndex >= 0 {
+		episode = toInstanceEpisodeBrief(db.GetCurrentEpisodeAtRow(episodes[currentEpisodeIndex]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"as_of":            leaderboardAt.Format(time.RFC3339Nano),
+		"episode":          episode,
+		"scoring_strategy": inputs.strategy.Name(),
+		"leaderboard":      response,
+	})
+}
+
+func (s *Server) loadLeaderboardInputs(ctx context.Context, instanceID pgtype.UUID) (leaderboardInputs, error) {
+	strategyName, err := s.queries.GetInstanceScoringStrategy(ctx, instanceID)
+	if err != nil {
+		return leaderboardInputs{}, err
+	}
+	strategy, ok := scoring.StrategyByName(strategyName)
+	if !ok {
+		return leaderboardInputs{}, fmt.Errorf("instance uses unknown scoring strategy %q", strategyName)
+	}
+	contestants, err := s.queries.ListContestantsByInstance(ctx, instanceID)
+	if err != nil {
+		return leaderboardInputs{}, err
+	}
+	participants, err := s.queries.ListParticipantsByInstance(ctx, instanceID)
+	if err != nil {
+		return leaderboardInputs{}, err
+	}
+	draftPicks, err := s.queries.ListDraftPicksForInstance(ctx, instanceID)
+	if err != nil {
+		return leaderboardInputs{}, err
+	}
+
+	participantNames := make(map[string]string, len(participants))
+	for _, participant := range participants {
+		participantNames[uuid.UUID(participant.ID.Bytes).String()] = participant.Name
+	}
+
+	draftsByParticipant := make(map[string][]scoring.DraftPick, len(participants))
+	for _, pick := range draftPicks {
+		participantID := uuid.UUID(pick.ParticipantID.Bytes).String()
+		draftsByParticipant[participantID] = append(draftsByParticipant[participantID], scoring.DraftPick{
+			Position:     int(pick.Position),
+			ContestantID: uuid.UUID(pick.ContestantID.Bytes).String(),
+		})
+	}
+	for participantID := range draftsByParticipant {
+		sort.Slice(draftsByParticipant[participantID], func(i, j int) bool {
+			return draftsByParticipant[participantID][i].Position < draftsByParticipant[participantID][j].Position
+		})
+	}
+
+	return leaderboardInputs{
+		instanceID:          instanceID,
+		strategy:            strategy,
+		totalPositions:      len(contestants),
+		contestants:         contestants,
+		participants:        participants,
+		participantNames:    participantNames,
+		draftsByParticipant: draftsByParticipant,
+	}, nil
+}
+
+// scoreLeaderboardAt scores the leaderboard from outcomes and visible bonus
+// points recorded up to asOf. A nil asOf scores the live state.
+func (s *Server) scoreLeaderboardAt(ctx context.Context, inputs leaderboardInputs, asOf *time.Time) ([]scoring.LeaderboardEntry, error) {
+	finalPositions, err := s.finalPositionsAt(ctx, inputs, asOf)
+	if err != nil {
+		return nil, err
+	}
+	visibleBonusByParticipant, err := s.visibleBonusAt(ctx, inputs, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return scoring.CalculateLeaderboard(inputs.strategy, inputs.totalPositions, inputs.participantNames, inputs.draftsByParticipant, finalPositions, visibleBonusByParticipant), nil
+}
+
+// finalPositionsAt maps contestant ids to the final position recorded for them
+// up to asOf. A nil asOf reads the live outcomes.
+func (s *Server) finalPositionsAt(ctx context.Context, inputs leaderboardInputs, asOf *time.Time) (map[string]int, error) {
+	var outcomes []db.ListOutcomePositionsByInstanceRow
+	if asOf != nil {
+		rows, err := s.queries.ListOutcomePositionsAsOf(ctx, db.ListOutcomePositionsAsOfParams{
+			InstanceID: inputs.instanceID,
+			AsOf:       optionalTime(*asOf),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			outcomes = append(outcomes, db.ListOutcomePositionsByInstanceRow(row))
+		}
+	} else {
+		rows, err := s.queries.ListOutcomePositionsByInstance(ctx, inputs.instanceID)
+		if err != nil {
+			return nil, err
+		}
+		outcomes = rows
+	}
+
+	finalPositions := map[string]int{}
+	for _, outcome := range outcomes {
+		if !outcome.ContestantID.Valid {
+			continue
+		}
+		finalPositions[uuid.UUID(outcome.ContestantID.Bytes).String()] = int(outcome.Position)
+	}
+	return finalPositions, nil
+}
+
+// visibleBonusAt totals each participant's visible bonus points up to asOf. A
+// nil asOf reads the live ledger.
+func (s *Server) visibleBonusAt(ctx context.Context, inputs leaderboardInputs, asOf *time.Time) (map[string]int, error) {
+	visibleBonusByParticipant := make(map[string]int, len(inputs.participants))
+	gameplayService := gameplay.NewService(s.queries)
+	for _, participant := range inputs.participants {
+		var bonusPoints int32
+		var err error
+		if asOf != nil {
+			bonusPoints, err = gameplayService.VisibleBonusTotalByParticipantAsOf(ctx, inputs.instanceID, participant.ID, *asOf)
+		} else {
+			bonusPoints, err = gameplayService.VisibleBonusTotalByParticipant(ctx, inputs.instanceID, participant.ID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		visibleBonusByParticipant[uuid.UUID(participant.ID.Bytes).String()] = int(bonusPoints)
+	}
+	return visibleBonusByParticipant, nil
+}
+
+// episodeWindowEnd returns the last instant that still belongs to the given
+// episode: just before the next episode airs, or now for the latest episode.
+func episodeWindowEnd(episodes []db.ListInstanceEpisodesRow, episodeNumber int32, now time.Time) (time.Time, bool) {
+	for index, episode := range episodes {
+		if episode.EpisodeNumber != episodeNumber {
+			continue
+		}
+		if index+1 < len(episodes) {
+			nextStart := episodes[index+1].AirsAt.Time.Add(-time.Microsecond)
+			if nextStart.Before(now) {
+				return nextStart.UTC(), true
+			}
+		}
+		return now, true
+	}
+	return time.Time{}, false
+}
+
+// episodeIndexAt returns the index of the latest episode that aired at or
+// before at, or -1 when none has.
+func episodeIndexAt(episodes []db.ListInstanceEpisodesRow, at time.Time) int {
+	current := -1
+	for index, episode := range episodes {
+		if episode.AirsAt.Time.After(at) {
+			continue
+		}
+		if current == -1 || !episode.AirsAt.Time.Before(episodes[current].AirsAt.Time) {
+			current = index
+		}
+	}
+	return current
+}
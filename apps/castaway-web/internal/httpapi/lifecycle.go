@@ -0,0 +1,312 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Instance lifecycle states, in the order a season normally moves through
+// them.
+const (
+	instanceStateSetup     = "setup"
+	instanceStateDrafting  = "drafting"
+	instanceStateActive    = "active"
+	instanceStateCompleted = "completed"
+	instanceStateArchived  = "archived"
+)
+
+// instanceAction groups mutating routes by the kind of change they make so
+// each lifecycle state can allow or refuse them together.
+type instanceAction string
+
+const (
+	// instanceActionConfigure covers roster, schedule and settings changes.
+	instanceActionConfigure instanceAction = "configure"
+	// instanceActionLink covers linking participants to Discord users.
+	instanceActionLink instanceAction = "link"
+	// instanceActionDraft covers submitting or replacing drafts.
+	instanceActionDraft instanceAction = "draft"
+	// instanceActionPlay covers player and admin gameplay moves such as bids,
+	// loans and stir-the-pot rounds.
+	instanceActionPlay instanceAction = "play"
+	// instanceActionScore covers recording outcomes and resolving results.
+	instanceActionScore instanceAction = "score"
+	// instanceActionOperate covers operator upkeep such as webhook
+	// subscriptions and retrying failed jobs or deliveries.
+	instanceActionOperate instanceAction = "operate"
+)
+
+var instanceStateActions = map[string]map[instanceAction]bool{
+	instanceStateSetup: {
+		instanceActionConfigure: true,
+		instanceActionLink:      true,
+		instanceActionDraft:     true,
+		instanceActionOperate:   true,
+	},
+	instanceStateDrafting: {
+		instanceActionConfigure: true,
+		instanceActionLink:      true,
+		instanceActionDraft:     true,
+		instanceActionOperate:   true,
+	},
+	instanceStateActive: {
+		instanceActionConfigure: true,
+		instanceActionLink:      true,
+		instanceActionDraft:     true,
+		instanceActionPlay:      true,
+		instanceActionScore:     true,
+		instanceActionOperate:   true,
+	},
+	// A completed season can still have late corrections scored and accounts
+	// linked, but no more gameplay.
+	instanceStateCompleted: {
+		instanceActionLink:    true,
+		instanceActionScore:   true,
+		instanceActionOperate: true,
+	},
+	instanceStateArchived: {},
+}
+
+// instanceStateTransitions lists the states an admin may move an instance to
+// from each state. Steps back are allowed so mistakes can be undone, but an
+// archived season must be reopened as completed before anything else.
+var instanceStateTransitions = map[string][]string{
+	instanceStateSetup:     {instanceStateDrafting},
+	instanceStateDrafting:  {instanceStateSetup, instanceStateActive},
+	instanceStateActive:    {instanceStateCompleted},
+	instanceStateCompleted: {instanceStateActive, instanceStateArchived},
+	instanceStateArchived:  {instanceStateCompleted},
+}
+
+// instanceStateExemptRoutes are the mutating routes that deliberately skip
+// requireInstanceState. Every other non-GET route must be guarded.
+var instanceStateExemptRoutes = map[string]string{
+	"POST /instances":                                         "creates a new instance",
+	"POST /instances/import":                                  "stages an import without touching any instance",
+	"POST /instances/bootstrap":                               "checks the target instance's state itself",
+	"POST /imports/:importID/apply":                           "checks the target instance's state itself",
+	"PUT /instances/:instanceID/state":                        "is how an instance leaves a locked state",
+	"POST /instances/:instanceID/groups/realignments/preview": "is read-only",
+	"POST /instances/:instanceID/finale-bingo/scores/preview": "is read-only",
+	"POST /instances/:instanceID/leaderboard/what-if":         "is read-only",
+	"POST /credentials":                                       "is not tied to an instance",
+	"POST /credentials/:credentialID/rotate":                  "is not tied to an instance",
+	"DELETE /credentials/:credentialID":                       "is not tied to an instance",
+}
+
+func validInstanceState(state string) bool {
+	_, ok := instanceStateActions[state]
+	return ok
+}
+
+func instanceStateAllows(state string, action instanceAction) bool {
+	return instanceStateActions[state][action]
+}
+
+func canTransitionInstanceState(from, to string) bool {
+	for _, next := range instanceStateTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func instanceStateConflictMessage(state string, action instanceAction) string {
+	return fmt.Sprintf("instance is %s and does not allow %s changes", state, action)
+}
+
+// requireInstanceState rejects the request with 409 Conflict unless the
+// instance it targets is in a state that allows action. The instance is found
+// from the instanceID, activityID or occurrenceID path parameter.
+func (s *Server) requireInstanceState(action instanceAction) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state, ok := s.lookupRequestInstanceState(c)
+		if !ok {
+			c.Abort()
+			return
+		}
+		if !instanceStateAllows(state, action) {
+			c.AbortWithStatusJSON(http.StatusConflict, errorResponse{Error: instanceStateConflictMessage(state, action)})
+			return
+		}
+		c.Next()
+	}
+}
+
+func (s *Server) lookupRequestInstanceState(c *gin.Context) (string, bool) {
+	ctx := c.Request.Context()
+	var (
+		state    string
+		err      error
+		notFound string
+	)
+	switch {
+	case c.Param("instanceID") != "":
+		instanceID, ok := parseUUIDPath(c, "instanceID")
+		if !ok {
+			return "", false
+		}
+		state, err = s.queries.GetInstanceState(ctx, toPGUUID(instanceID))
+		notFound = "instance not found"
+	case c.Param("activityID") != "":
+		activityID, ok := parseUUIDPath(c, "activityID")
+		if !ok {
+			return "", false
+		}
+		var row db.GetInstanceStateByActivityRow
+		row, err = s.queries.GetInstanceStateByActivity(ctx, toPGUUID(activityID))
+		state = row.State
+		notFound = "activity not found"
+	case c.Param("occurrenceID") != "":
+		occurrenceID, ok := parseUUIDPath(c, "occurrenceID")
+		if !ok {
+			return "", false
+		}
+		var row db.GetInstanceStateByOccurrenceRow
+		row, err = s.queries.GetInstanceStateByOccurrence(ctx, toPGUUID(occurrenceID))
+		state = row.State
+		notFound = "occurrence not found"
+	default:
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "route has no instance to check"})
+		return "", false
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: notFound})
+			return "", false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return "", false
+	}
+	return state, true
+}
+
+// ensureImportTargetConfigurable refuses to apply an import over an existing
+// instance with the same name and season unless that instance can still be
+// configured.
+func ensureImportTargetConfigurable(ctx context.Context, qtx *db.Queries, name string, season int32) error {
+	instances, err := qtx.ListInstances(ctx)
+	if err != nil {
+		return err
+	}
+	for _, existing := range instances {
+		if existing.Name == name && existing.Season == season && !instanceStateAllows(existing.State, instanceActionConfigure) {
+			return errors.New(instanceStateConflictMessage(existing.State, instanceActionConfigure))
+		}
+	}
+	return nil
+}
+
+func instanceStateResponse(instanceID pgtype.UUID, state string, changedAt pgtype.Timestamptz) gin.H {
+	allowed := make([]string, 0, len(instanceStateTransitions[state]))
+	allowed = append(allowed, instanceStateTransitions[state]...)
+	return gin.H{
+		"instance_id":      pgUUIDString(instanceID),
+		"state":            state,
+		"state_changed_at": formatTimestamp(changedAt),
+		"next_states":      allowed,
+	}
+}
+
+func (s *Server) getInstanceState(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+
+	instance, err := s.queries.GetInstance(c.Request.Context(), toPGUUID(instanceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"state": instanceStateResponse(instance.ID, instance.State, instance.StateChangedAt)})
+}
+
+type setInstanceStateRequest struct {
+	State string `json:"state" binding:"required"`
+}
+
+// setInstanceState moves an instance to another lifecycle state. Only the
+// transitions in instanceStateTransitions are allowed.
+func (s *Server) setInstanceState(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+
+	var req setInstanceStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	target := strings.ToLower(strings.TrimSpace(req.State))
+	if !validInstanceState(target) {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "unknown state: " + req.State})
+		return
+	}
+
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	current, err := qtx.GetInstanceStateForUpdate(ctx, toPGUUID(instanceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if current != target && !canTransitionInstanceState(current, target) {
+		c.JSON(http.StatusConflict, errorResponse{Error: fmt.Sprintf("cannot move instance from %s to %s", current, target)})
+		return
+	}
+
+	updated, err := qtx.SetInstanceState(ctx, db.SetInstanceStateParams{
+		State: target,
+		ID:    toPGUUID(instanceID),
+	})
+	if err != nil {
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	response := gin.H{
+		"previous_state": current,
+		"state":          instanceStateResponse(toPGUUID(instanceID), updated.State, updated.StateChangedAt),
+	}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionInstanceState, gin.H{"state": current}, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
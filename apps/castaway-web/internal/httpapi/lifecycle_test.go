@@ -0,0 +1,140 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestInstanceStateAllowsActions(t *testing.T) {
+	cases := []struct {
+		state  string
+		action instanceAction
+		want   bool
+	}{
+		{state: instanceStateSetup, action: instanceActionDraft, want: true},
+		{state: instanceStateSetup, action: instanceActionScore, want: false},
+		{state: instanceStateDrafting, action: instanceActionPlay, want: false},
+		{state: instanceStateActive, action: instanceActionPlay, want: true},
+		{state: instanceStateCompleted, action: instanceActionPlay, want: false},
+		{state: instanceStateCompleted, action: instanceActionScore, want: true},
+		{state: instanceStateCompleted, action: instanceActionConfigure, want: false},
+		{state: instanceStateArchived, action: instanceActionLink, want: false},
+		{state: instanceStateCompleted, action: instanceActionOperate, want: true},
+		{state: instanceStateArchived, action: instanceActionOperate, want: false},
+		{state: "unknown", action: instanceActionConfigure, want: false},
+	}
+	for _, tc := range cases {
+		if got := instanceStateAllows(tc.state, tc.action); got != tc.want {
+			t.Fatalf("instanceStateAllows(%q, %q) = %v, want %v", tc.state, tc.action, got, tc.want)
+		}
+	}
+}
+
+func TestCanTransitionInstanceState(t *testing.T) {
+	allowed := [][2]string{
+		{instanceStateSetup, instanceStateDrafting},
+		{instanceStateDrafting, instanceStateActive},
+		{instanceStateActive, instanceStateCompleted},
+		{instanceStateCompleted, instanceStateArchived},
+		{instanceStateArchived, instanceStateCompleted},
+	}
+	for _, transition := range allowed {
+		if !canTransitionInstanceState(transition[0], transition[1]) {
+			t.Fatalf("expected %s -> %s to be allowed", transition[0], transition[1])
+		}
+	}
+	refused := [][2]string{
+		{instanceStateSetup, instanceStateActive},
+		{instanceStateActive, instanceStateSetup},
+		{instanceStateArchived, instanceStateActive},
+	}
+	for _, transition := range refused {
+		if canTransitionInstanceState(transition[0], transition[1]) {
+			t.Fatalf("expected %s -> %s to be refused", transition[0], transition[1])
+		}
+	}
+}
+
+// archivedInstanceDB answers every single-row query as if the request targeted
+// an archived instance, and fails everything else.
+type archivedInstanceDB struct{}
+
+func (archivedInstanceDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("unexpected exec")
+}
+
+func (archivedInstanceDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("unexpected query")
+}
+
+func (archivedInstanceDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return archivedInstanceRow{}
+}
+
+type archivedInstanceRow struct{}
+
+func (archivedInstanceRow) Scan(dest ...any) error {
+	for _, target := range dest {
+		switch value := target.(type) {
+		case *string:
+			*value = instanceStateArchived
+		case *pgtype.UUID:
+			*value = pgtype.UUID{Bytes: uuid.New(), Valid: true}
+		}
+	}
+	return nil
+}
+
+func TestEveryMutatingRouteChecksInstanceState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	server := New(nil)
+	server.queries = db.New(archivedInstanceDB{})
+	router := server.Router()
+
+	for _, route := range router.Routes() {
+		if route.Method == http.MethodGet || route.Method == http.MethodHead {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		if _, exempt := instanceStateExemptRoutes[key]; exempt {
+			continue
+		}
+		path := route.Path
+		for _, segment := range strings.Split(route.Path, "/") {
+			if strings.HasPrefix(segment, ":") {
+				path = strings.Replace(path, segment, uuid.NewString(), 1)
+			}
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(route.Method, path, nil))
+		if recorder.Code != http.StatusConflict || !strings.Contains(recorder.Body.String(), "instance is archived") {
+			t.Errorf("%s is not guarded by requireInstanceState: status = %d, body = %s", key, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func TestInstanceStateExemptRoutesExist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registered := map[string]bool{}
+	for _, route := range New(nil).Router().Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for key := range instanceStateExemptRoutes {
+		if !registered[key] {
+			t.Errorf("exempt route %s is not registered", key)
+		}
+	}
+}
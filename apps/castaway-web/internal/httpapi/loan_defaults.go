@@ -0,0 +1,258 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/scheduler"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/webhooks"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	loanStatusDefaulted = "defaulted"
+
+	occurrenceTypeLoanDefault = "loan_default"
+
+	jobTypeDefaultOverdueLoans = "default_overdue_loans"
+)
+
+// loanDefaultPenalty is read from the Loan Shark activity's default_penalty
+// metadata. The unpaid shortfall is multiplied, the flat charge is added, and
+// the total is capped at max_points when that is set. Without any config the
+// penalty is the shortfall itself.
+type loanDefaultPenalty struct {
+	ShortfallMultiplier *int32 `json:"shortfall_multiplier,omitempty"`
+	FlatPoints          int32  `json:"flat_points,omitempty"`
+	MaxPoints           int32  `json:"max_points,omitempty"`
+}
+
+type loanSharkActivityMetadata struct {
+	DefaultPenalty loanDefaultPenalty `json:"default_penalty"`
+}
+
+// loanDefaultSettlement is how an overdue loan is closed out: whatever balance
+// the participant has is collected, interest first, and the rest is the
+// shortfall the penalty is charged on.
+type loanDefaultSettlement struct {
+	InterestCollectedPoints  int32  `json:"interest_collected_points"`
+	PrincipalCollectedPoints int32  `json:"principal_collected_points"`
+	CollectedPoints          int32  `json:"collected_points"`
+	ShortfallPoints          int32  `json:"shortfall_points"`
+	PenaltyPoints            int32  `json:"penalty_points"`
+	OccurrenceID             string `json:"occurrence_id,omitempty"`
+	DefaultedAt              string `json:"defaulted_at,omitempty"`
+}
+
+func (p loanDefaultPenalty) points(shortfall int32) int32 {
+	if shortfall <= 0 {
+		return 0
+	}
+	multiplier := int32(1)
+	if p.ShortfallMultiplier != nil {
+		multiplier = *p.ShortfallMultiplier
+	}
+	points := shortfall*multiplier + p.FlatPoints
+	if p.MaxPoints > 0 && points > p.MaxPoints {
+		points = p.MaxPoints
+	}
+	if points < 0 {
+		return 0
+	}
+	return points
+}
+
+func planLoanDefault(principalOutstanding, interestOutstanding, balance int32, penalty loanDefaultPenalty) loanDefaultSettlement {
+	available := balance
+	if available < 0 {
+		available = 0
+	}
+	settlement := loanDefaultSettlement{}
+	settlement.InterestCollectedPoints = min(available, interestOutstanding)
+	settlement.PrincipalCollectedPoints = min(available-settlement.InterestCollectedPoints, principalOutstanding)
+	settlement.CollectedPoints = settlement.InterestCollectedPoints + settlement.PrincipalCollectedPoints
+	settlement.ShortfallPoints = principalOutstanding + interestOutstanding - settlement.CollectedPoints
+	settlement.PenaltyPoints = penalty.points(settlement.ShortfallPoints)
+	return settlement
+}
+
+func parseLoanDefaultPenalty(raw []byte) loanDefaultPenalty {
+	var metadata loanSharkActivityMetadata
+	if err := json.Unmarshal(nonEmptyMetadata(raw), &metadata); err != nil {
+		return loanDefaultPenalty{}
+	}
+	return metadata.DefaultPenalty
+}
+
+// defaultOverdueLoansJob defaults every active loan whose due date has passed
+// by the job's run.
+func (s *Server) defaultOverdueLoansJob(ctx context.Context, tx pgx.Tx, job scheduler.Job) (scheduler.Result, error) {
+	q := s.queries.WithTx(tx)
+	loans, err := q.ListActiveParticipantLoansByInstance(ctx, job.InstanceID)
+	if err != nil {
+		return scheduler.Result{}, err
+	}
+	overdue := make([]db.ListActiveParticipantLoansByInstanceRow, 0, len(loans))
+	for _, loan := range loans {
+		if loan.DueAt.Valid && !loan.DueAt.Time.After(job.Now) {
+			overdue = append(overdue, loan)
+		}
+	}
+	if len(overdue) == 0 {
+		return scheduler.Result{Skipped: true, Detail: gin.H{"reason": "no loans are overdue"}}, nil
+	}
+
+	loanActivity, err := s.ensureSystemActivity(ctx, q, job.InstanceID, activityTypeLoanShark, "Loan Shark", job.Now)
+	if err != nil {
+		return scheduler.Result{}, err
+	}
+	penalty := parseLoanDefaultPenalty(loanActivity.Metadata)
+	defaulted := make([]gin.H, 0, len(overdue))
+	for _, loan := range overdue {
+		settlement, err := s.defaultLoan(ctx, q, job.InstanceID, loanActivity.ID, loan, penalty, job.Now)
+		if err != nil {
+			return scheduler.Result{}, fmt.Errorf("default loan for %s: %w", loan.ParticipantName, err)
+		}
+		defaulted = append(defaulted, gin.H{
+			"loan_id":          pgUUIDString(loan.ID),
+			"participant_id":   pgUUIDString(loan.ParticipantID),
+			"participant_name": loan.ParticipantName,
+			"collected_points": settlement.CollectedPoints,
+			"shortfall_points": settlement.ShortfallPoints,
+			"penalty_points":   settlement.PenaltyPoints,
+		})
+	}
+	return scheduler.Result{Detail: gin.H{"defaulted_loans": defaulted}}, nil
+}
+
+// defaultLoan collects what it can toward loan, charges the configured
+// penalty on the shortfall as a correction, and marks the loan defaulted.
+func (s *Server) defaultLoan(ctx context.Context, q *db.Queries, instanceID, activityID pgtype.UUID, loan db.ListActiveParticipantLoansByInstanceRow, penalty loanDefaultPenalty, now time.Time) (loanDefaultSettlement, error) {
+	balance, err := s.currentBonusBalance(ctx, q, instanceID, loan.ParticipantID)
+	if err != nil {
+		return loanDefaultSettlement{}, err
+	}
+	settlement := planLoanDefault(loan.PrincipalPoints-loan.PrincipalRepaidPoints, loan.InterestPoints-loan.InterestRepaidPoints, balance, penalty)
+
+	occurrence, err := q.CreateActivityOccurrence(ctx, db.CreateActivityOccurrenceParams{
+		ActivityID:     activityID,
+		OccurrenceType: occurrenceTypeLoanDefault,
+		Name:           fmt.Sprintf("Loan Shark default — %s", loan.ParticipantName),
+		EffectiveAt:    optionalTime(now),
+		Status:         "resolved",
+		Metadata:       []byte("{}"),
+	})
+	if err != nil {
+		return loanDefaultSettlement{}, err
+	}
+	loanKey := pgUUIDString(loan.ID)
+	if settlement.CollectedPoints > 0 {
+		reason := fmt.Sprintf("Loan Shark default collection of %d points", settlement.CollectedPoints)
+		if _, err := s.revealSecretPointsOnSpend(ctx, q, instanceID, loan.ParticipantID, occurrence.ID, pgtype.UUID{}, settlement.CollectedPoints, now, reason, []byte("{}")); err != nil {
+			return loanDefaultSettlement{}, err
+		}
+		if _, err := q.CreateBonusPointLedgerEntry(ctx, db.CreateBonusPointLedgerEntryParams{
+			InstanceID:           instanceID,
+			ParticipantID:        loan.ParticipantID,
+			ActivityOccurrenceID: occurrence.ID,
+			EntryKind:            "spend",
+			Points:               -settlement.CollectedPoints,
+			Visibility:           "secret",
+			Reason:               reason,
+			EffectiveAt:          optionalTime(now),
+			AwardKey:             optionalText(ptrString("loan_shark:default:" + loanKey + ":collect")),
+			Metadata:             metadataWithConsumesSecretBalance([]byte("{}"), false),
+		}); err != nil {
+			return loanDefaultSettlement{}, err
+		}
+	}
+	if settlement.PenaltyPoints > 0 {
+		penaltyMetadata, err := json.Marshal(gin.H{"loan_id": loanKey, "shortfall_points": settlement.ShortfallPoints})
+		if err != nil {
+			return loanDefaultSettlement{}, err
+		}
+		if _, err := q.CreateBonusPointLedgerEntry(ctx, db.CreateBonusPointLedgerEntryParams{
+			InstanceID:           instanceID,
+			ParticipantID:        loan.ParticipantID,
+			ActivityOccurrenceID: occurrence.ID,
+			EntryKind:            "correction",
+			Points:               -settlement.PenaltyPoints,
+			Visibility:           "secret",
+			Reason:               fmt.Sprintf("Loan Shark default penalty on %d unpaid points", settlement.ShortfallPoints),
+			EffectiveAt:          optionalTime(now),
+			AwardKey:             optionalText(ptrString("loan_shark:default:" + loanKey + ":penalty")),
+			Metadata:             metadataWithConsumesSecretBalance(penaltyMetadata, false),
+		}); err != nil {
+			return loanDefaultSettlement{}, err
+		}
+	}
+
+	settlement.OccurrenceID = pgUUIDString(occurrence.ID)
+	settlement.DefaultedAt = now.UTC().Format(time.RFC3339)
+	metadata := map[string]any{}
+	if err := json.Unmarshal(nonEmptyMetadata(loan.Metadata), &metadata); err != nil {
+		metadata = map[string]any{}
+	}
+	metadata["default"] = settlement
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return loanDefaultSettlement{}, err
+	}
+	if _, err := q.UpdateParticipantLoan(ctx, db.UpdateParticipantLoanParams{
+		ID:                    loan.ID,
+		Status:                loanStatusDefaulted,
+		PrincipalPoints:       loan.PrincipalPoints,
+		InterestPoints:        loan.InterestPoints,
+		PrincipalRepaidPoints: loan.PrincipalRepaidPoints + settlement.PrincipalCollectedPoints,
+		InterestRepaidPoints:  loan.InterestRepaidPoints + settlement.InterestCollectedPoints,
+		DueAt:                 loan.DueAt,
+		SettledAt:             optionalTime(now),
+		Metadata:              encoded,
+	}); err != nil {
+		return loanDefaultSettlement{}, err
+	}
+	if err := recordDomainEvent(ctx, q, instanceID, webhooks.EventLoanDefaulted, gin.H{
+		"loan_id":          pgUUIDString(loan.ID),
+		"participant_id":   pgUUIDString(loan.ParticipantID),
+		"participant_name": loan.ParticipantName,
+		"occurrence_id":    settlement.OccurrenceID,
+		"defaulted_at":     settlement.DefaultedAt,
+	}); err != nil {
+		return loanDefaultSettlement{}, err
+	}
+	return settlement, nil
+}
+
+// latestLoanDefault reports the participant's most recent defaulted loan, or
+// nil when they have never defaulted.
+func (s *Server) latestLoanDefault(ctx context.Context, instanceID, participantID pgtype.UUID) (gin.H, error) {
+	loan, err := s.queries.GetLatestDefaultedParticipantLoanByParticipant(ctx, db.GetLatestDefaultedParticipantLoanByParticipantParams{InstanceID: instanceID, ParticipantID: participantID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var metadata struct {
+		Default loanDefaultSettlement `json:"default"`
+	}
+	if err := json.Unmarshal(nonEmptyMetadata(loan.Metadata), &metadata); err != nil {
+		return nil, err
+	}
+	return gin.H{
+		"loan_id":          pgUUIDString(loan.ID),
+		"principal_points": loan.PrincipalPoints,
+		"interest_points":  loan.InterestPoints,
+		"collected_points": metadata.Default.CollectedPoints,
+		"shortfall_points": metadata.Default.ShortfallPoints,
+		"penalty_points":   metadata.Default.PenaltyPoints,
+		"due_at":           formatTimestamp(loan.DueAt),
+		"defaulted_at":     formatNullableTimestamp(loan.SettledAt),
+	}, nil
+}
@@ -0,0 +1,42 @@
+package httpapi
+
+import "testing"
+
+func TestPlanLoanDefaultCollectsInterestFirstAndChargesPenalty(t *testing.T) {
+	doubled := int32(2)
+	cases := []struct {
+		name                                   string
+		principal, interest, balance           int32
+		penalty                                loanDefaultPenalty
+		wantInterest, wantPrincipal, wantShort int32
+		wantPenalty                            int32
+	}{
+		{name: "nothing to collect", principal: 3, interest: 1, balance: 0, wantShort: 4, wantPenalty: 4},
+		{name: "negative balance", principal: 3, interest: 1, balance: -2, wantShort: 4, wantPenalty: 4},
+		{name: "interest only", principal: 3, interest: 1, balance: 1, wantInterest: 1, wantShort: 3, wantPenalty: 3},
+		{name: "partial principal", principal: 3, interest: 1, balance: 2, penalty: loanDefaultPenalty{ShortfallMultiplier: &doubled, FlatPoints: 1}, wantInterest: 1, wantPrincipal: 1, wantShort: 2, wantPenalty: 5},
+		{name: "capped", principal: 4, interest: 0, balance: 0, penalty: loanDefaultPenalty{ShortfallMultiplier: &doubled, MaxPoints: 5}, wantShort: 4, wantPenalty: 5},
+		{name: "fully covered", principal: 3, interest: 1, balance: 10, penalty: loanDefaultPenalty{FlatPoints: 2}, wantInterest: 1, wantPrincipal: 3},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := planLoanDefault(tc.principal, tc.interest, tc.balance, tc.penalty)
+			if got.InterestCollectedPoints != tc.wantInterest || got.PrincipalCollectedPoints != tc.wantPrincipal || got.ShortfallPoints != tc.wantShort || got.PenaltyPoints != tc.wantPenalty {
+				t.Fatalf("unexpected settlement: %+v", got)
+			}
+			if got.CollectedPoints != got.InterestCollectedPoints+got.PrincipalCollectedPoints {
+				t.Fatalf("collected %d does not match parts %+v", got.CollectedPoints, got)
+			}
+		})
+	}
+}
+
+func TestParseLoanDefaultPenaltyReadsActivityMetadata(t *testing.T) {
+	penalty := parseLoanDefaultPenalty([]byte(`{"default_penalty":{"shortfall_multiplier":0,"flat_points":3}}`))
+	if penalty.points(4) != 3 {
+		t.Fatalf("expected flat penalty of 3, got %d", penalty.points(4))
+	}
+	if parseLoanDefaultPenalty(nil).points(4) != 4 {
+		t.Fatal("expected the shortfall as the default penalty")
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/gameplay"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/webhooks"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -260,13 +261,21 @@ func (s *Server) startStirThePotRound(c *gin.Context) {
 		}
 	}
 
+	tx, err := s.pool.Begin(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
 	now := time.Now().UTC()
-	activity, err := s.ensureSystemActivity(c.Request.Context(), s.queries, toPGUUID(instanceID), activityTypeStirThePot, "Stir the Pot", now)
+	activity, err := s.ensureSystemActivity(c.Request.Context(), qtx, toPGUUID(instanceID), activityTypeStirThePot, "Stir the Pot", now)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
-	if _, _, found, err := s.findOpenStirThePotRound(c.Request.Context(), s.queries, toPGUUID(instanceID), now); err != nil {
+	if _, _, found, err := s.findOpenStirThePotRound(c.Request.Context(), qtx, toPGUUID(instanceID), now); err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	} else if found {
@@ -274,7 +283,7 @@ func (s *Server) startStirThePotRound(c *gin.Context) {
 		return
 	}
 
-	targetEpisode, err := s.nextEpisodeTarget(c.Request.Context(), s.queries, toPGUUID(instanceID), now)
+	targetEpisode, err := s.nextEpisodeTarget(c.Request.Context(), qtx, toPGUUID(instanceID), now)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
 		return
@@ -290,7 +299,7 @@ func (s *Server) startStirThePotRound(c *gin.Context) {
 		return
 	}
 
-	created, err := s.queries.CreateActivityOccurrence(c.Request.Context(), db.CreateActivityOccurrenceParams{
+	created, err := qtx.CreateActivityOccurrence(c.Request.Context(), db.CreateActivityOccurrenceParams{
 		ActivityID:     activity.ID,
 		OccurrenceType: occurrenceTypeStirThePotRound,
 		Name:           name,
@@ -303,10 +312,20 @@ func (s *Server) startStirThePotRound(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"activity": activityToJSON(activity.ID, activity.InstanceID, activity.ActivityType, activity.Name, activity.Status, activity.StartsAt, activity.EndsAt, activity.Metadata, activity.CreatedAt, activity.UpdatedAt),
 		"round":    occurrenceToJSON(created.ID, created.ActivityID, created.OccurrenceType, created.Name, created.EffectiveAt, created.StartsAt, created.EndsAt, created.Status, created.SourceRef, created.Metadata, created.CreatedAt, created.UpdatedAt),
-	})
+	}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionStirThePotStart, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
 }
 
 func (s *Server) closeStirThePotRound(c *gin.Context) {
@@ -329,42 +348,73 @@ func (s *Server) closeStirThePotRound(c *gin.Context) {
 		return
 	}
 
+	tx, err := s.pool.Begin(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+
+	updatedRound, tribes, err := s.closeStirThePotRoundAt(c.Request.Context(), tx, toPGUUID(instanceID), round, strings.TrimSpace(discordUserIDFromRequest(c.Request)), now)
+	if err != nil {
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	response := gin.H{
+		"round":  occurrenceToJSON(updatedRound.ID, updatedRound.ActivityID, updatedRound.OccurrenceType, updatedRound.Name, updatedRound.EffectiveAt, updatedRound.StartsAt, updatedRound.EndsAt, updatedRound.Status, updatedRound.SourceRef, updatedRound.Metadata, updatedRound.CreatedAt, updatedRound.UpdatedAt),
+		"tribes": tribes,
+	}
+	before := gin.H{
+		"round": occurrenceToJSON(round.ID, round.ActivityID, round.OccurrenceType, round.Name, round.EffectiveAt, round.StartsAt, round.EndsAt, round.Status, round.SourceRef, round.Metadata, round.CreatedAt, round.UpdatedAt),
+	}
+	if err := recordAuditEvent(c, s.queries.WithTx(tx), toPGUUID(instanceID), auditActionStirThePotClose, before, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// closeStirThePotRoundAt closes round at now, totals each tribe's
+// contributions against the reward tiers and makes secret pot spends public.
+func (s *Server) closeStirThePotRoundAt(ctx context.Context, dbtx db.DBTX, instanceID pgtype.UUID, round db.ListActivityOccurrencesByActivityAndStatusRow, closedBy string, now time.Time) (db.UpdateActivityOccurrenceStatusAndMetadataRow, []gin.H, error) {
+	q := db.New(dbtx)
 	metadata := parseStirThePotRoundMetadata(round.Metadata)
 	if len(metadata.RewardTiers) == 0 {
 		metadata.RewardTiers = defaultStirThePotRewardTiers()
 	}
 	metadata.ClosedAt = now.Format(time.RFC3339)
-	metadata.ClosedBy = strings.TrimSpace(discordUserIDFromRequest(c.Request))
+	metadata.ClosedBy = closedBy
 	updatedMetadata, err := json.Marshal(metadata)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
-		return
+		return db.UpdateActivityOccurrenceStatusAndMetadataRow{}, nil, err
 	}
-	updatedRound, err := s.queries.UpdateActivityOccurrenceStatusAndMetadata(c.Request.Context(), db.UpdateActivityOccurrenceStatusAndMetadataParams{
+	updatedRound, err := q.UpdateActivityOccurrenceStatusAndMetadata(ctx, db.UpdateActivityOccurrenceStatusAndMetadataParams{
 		ID:       round.ID,
 		Status:   round.Status,
 		EndsAt:   optionalTime(now),
 		Metadata: updatedMetadata,
 	})
 	if err != nil {
-		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
-		return
+		return db.UpdateActivityOccurrenceStatusAndMetadataRow{}, nil, err
 	}
 
-	groups, err := s.queries.ListParticipantGroupsByInstance(c.Request.Context(), toPGUUID(instanceID))
+	groups, err := q.ListParticipantGroupsByInstance(ctx, instanceID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
-		return
+		return db.UpdateActivityOccurrenceStatusAndMetadataRow{}, nil, err
 	}
 	tribes := make([]gin.H, 0)
 	for _, group := range groups {
 		if !strings.EqualFold(strings.TrimSpace(group.Kind), "tribe") {
 			continue
 		}
-		contributionPoints, err := s.groupContributionPoints(c.Request.Context(), s.queries, round.ID, group.ID)
+		contributionPoints, err := s.groupContributionPoints(ctx, q, round.ID, group.ID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
-			return
+			return db.UpdateActivityOccurrenceStatusAndMetadataRow{}, nil, err
 		}
 		bonus := stirThePotBonusForContribution(contributionPoints, metadata.RewardTiers)
 		tribes = append(tribes, gin.H{
@@ -389,18 +439,13 @@ func (s *Server) closeStirThePotRound(c *gin.Context) {
 		return tribeName(tribes[i]) < tribeName(tribes[j])
 	})
 
-	if _, err := s.pool.Exec(c.Request.Context(),
+	if _, err := dbtx.Exec(ctx,
 		`UPDATE bonus_point_ledger_entries SET visibility = 'public' WHERE activity_occurrence_id = $1 AND visibility = 'secret'`,
 		round.ID,
 	); err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{Error: fmt.Sprintf("convert secret pot spends to public: %s", err.Error())})
-		return
+		return db.UpdateActivityOccurrenceStatusAndMetadataRow{}, nil, fmt.Errorf("convert secret pot spends to public: %w", err)
 	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"round":  occurrenceToJSON(updatedRound.ID, updatedRound.ActivityID, updatedRound.OccurrenceType, updatedRound.Name, updatedRound.EffectiveAt, updatedRound.StartsAt, updatedRound.EndsAt, updatedRound.Status, updatedRound.SourceRef, updatedRound.Metadata, updatedRound.CreatedAt, updatedRound.UpdatedAt),
-		"tribes": tribes,
-	})
+	return updatedRound, tribes, nil
 }
 
 func (s *Server) addStirThePotContribution(c *gin.Context) {
@@ -628,20 +673,28 @@ func (s *Server) startAuctionLot(c *gin.Context) {
 		return
 	}
 
+	tx, err := s.pool.Begin(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
 	now := time.Now().UTC()
-	activity, err := s.ensureSystemActivity(c.Request.Context(), s.queries, toPGUUID(instanceID), activityTypeIndividualPonyAuction, "Individual Pony Auction", now)
+	activity, err := s.ensureSystemActivity(c.Request.Context(), qtx, toPGUUID(instanceID), activityTypeIndividualPonyAuction, "Individual Pony Auction", now)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
-	if _, found, err := s.findOpenAuctionLotByContestant(c.Request.Context(), s.queries, activity.ID, contestantID); err != nil {
+	if _, found, err := s.findOpenAuctionLotByContestant(c.Request.Context(), qtx, activity.ID, contestantID); err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	} else if found {
 		c.JSON(http.StatusConflict, errorResponse{Error: "auction lot is already open for this contestant"})
 		return
 	}
-	targetEpisode, err := s.nextEpisodeTarget(c.Request.Context(), s.queries, toPGUUID(instanceID), now)
+	targetEpisode, err := s.nextEpisodeTarget(c.Request.Context(), qtx, toPGUUID(instanceID), now)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
 		return
@@ -651,7 +704,7 @@ func (s *Server) startAuctionLot(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
-	created, err := s.queries.CreateActivityOccurrence(c.Request.Context(), db.CreateActivityOccurrenceParams{
+	created, err := qtx.CreateActivityOccurrence(c.Request.Context(), db.CreateActivityOccurrenceParams{
 		ActivityID:     activity.ID,
 		OccurrenceType: occurrenceTypeAuctionLot,
 		Name:           fmt.Sprintf("%s Auction Lot — %s", contestant.Name, targetEpisode.EpisodeLabel),
@@ -663,12 +716,21 @@ func (s *Server) startAuctionLot(c *gin.Context) {
 		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"activity":     activityToJSON(activity.ID, activity.InstanceID, activity.ActivityType, activity.Name, activity.Status, activity.StartsAt, activity.EndsAt, activity.Metadata, activity.CreatedAt, activity.UpdatedAt),
 		"lot":          occurrenceToJSON(created.ID, created.ActivityID, created.OccurrenceType, created.Name, created.EffectiveAt, created.StartsAt, created.EndsAt, created.Status, created.SourceRef, created.Metadata, created.CreatedAt, created.UpdatedAt),
 		"contestant":   gin.H{"id": contestant.ID.String(), "name": contestant.Name},
 		"bidding_open": true,
-	})
+	}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionAuctionLotStart, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response)
 }
 
 func (s *Server) setAuctionBid(c *gin.Context) {
@@ -867,44 +929,81 @@ func (s *Server) stopAuctionLot(c *gin.Context) {
 		return
 	}
 
-	bids, err := s.rankAuctionBids(c.Request.Context(), s.queries, lot.ID)
+	now := time.Now().UTC()
+	tx, err := s.pool.Begin(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
-	now := time.Now().UTC()
+	defer rollbackTx(c, tx)
 
-	tx, err := s.pool.Begin(c.Request.Context())
+	qtx := s.queries.WithTx(tx)
+	settlement, err := s.settleAuctionLot(c.Request.Context(), qtx, toPGUUID(instanceID), contestantID, contestant.Name, lot, now)
 	if err != nil {
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"contestant":         gin.H{"id": contestant.ID.String(), "name": contestant.Name},
+		"lot_id":             pgUUIDString(lot.ID),
+		"winning_bid_points": settlement.winningBidPoints,
+		"price_points":       settlement.pricePoints,
+	}
+	if winner := settlement.winner; winner != nil {
+		response["winner"] = gin.H{"participant_id": pgUUIDString(winner.participantID), "participant_name": winner.participantName}
+	} else {
+		response["winner"] = nil
+	}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionAuctionLotStop, nil, response); err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
-	defer rollbackTx(c, tx)
-	qtx := s.queries.WithTx(tx)
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+type auctionSettlement struct {
+	winner           *auctionRankedBid
+	winningBidPoints int32
+	pricePoints      int32
+}
 
-	var winner *auctionRankedBid
-	winningBidPoints := int32(0)
-	pricePoints := int32(0)
+// settleAuctionLot refunds losing bids, charges the winner the second-highest
+// bid, grants the pony and marks the lot resolved at now.
+func (s *Server) settleAuctionLot(ctx context.Context, qtx *db.Queries, instanceID pgtype.UUID, contestantID uuid.UUID, contestantName string, lot db.ListActivityOccurrencesByActivityAndStatusRow, now time.Time) (auctionSettlement, error) {
+	bids, err := s.rankAuctionBids(ctx, qtx, lot.ID)
+	if err != nil {
+		return auctionSettlement{}, err
+	}
+
+	var settlement auctionSettlement
 	if len(bids) > 0 {
-		winner = &bids[0]
-		winningBidPoints = bids[0].bidPoints
+		settlement.winner = &bids[0]
+		settlement.winningBidPoints = bids[0].bidPoints
 		if len(bids) > 1 {
-			pricePoints = bids[1].bidPoints
+			settlement.pricePoints = bids[1].bidPoints
 		}
 	}
+	winner := settlement.winner
+	winningBidPoints := settlement.winningBidPoints
+	pricePoints := settlement.pricePoints
 
 	for index, bid := range bids {
 		refund := bid.bidPoints
-		reason := fmt.Sprintf("Refunded bid on %s", contestant.Name)
+		reason := fmt.Sprintf("Refunded bid on %s", contestantName)
 		if index == 0 {
 			refund = bid.bidPoints - pricePoints
-			reason = fmt.Sprintf("Auction settled for %s at %d points", contestant.Name, pricePoints)
+			reason = fmt.Sprintf("Auction settled for %s at %d points", contestantName, pricePoints)
 		}
 		if refund <= 0 {
 			continue
 		}
-		if _, err := qtx.CreateBonusPointLedgerEntry(c.Request.Context(), db.CreateBonusPointLedgerEntryParams{
-			InstanceID:           toPGUUID(instanceID),
+		if _, err := qtx.CreateBonusPointLedgerEntry(ctx, db.CreateBonusPointLedgerEntryParams{
+			InstanceID:           instanceID,
 			ParticipantID:        bid.participantID,
 			ActivityOccurrenceID: lot.ID,
 			EntryKind:            "correction",
@@ -915,14 +1014,13 @@ func (s *Server) stopAuctionLot(c *gin.Context) {
 			AwardKey:             optionalText(ptrString("auction:refund:" + uuid.NewString())),
 			Metadata:             metadataWithConsumesSecretBalance(lot.Metadata, false),
 		}); err != nil {
-			c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
-			return
+			return auctionSettlement{}, err
 		}
 	}
 
 	if winner != nil {
-		if _, err := qtx.CreateParticipantPonyOwnership(c.Request.Context(), db.CreateParticipantPonyOwnershipParams{
-			InstanceID:                 toPGUUID(instanceID),
+		if _, err := qtx.CreateParticipantPonyOwnership(ctx, db.CreateParticipantPonyOwnershipParams{
+			InstanceID:                 instanceID,
 			OwnerParticipantID:         winner.participantID,
 			ContestantID:               toPGUUID(contestantID),
 			SourceActivityOccurrenceID: lot.ID,
@@ -931,8 +1029,7 @@ func (s *Server) stopAuctionLot(c *gin.Context) {
 			Status:                     "active",
 			Metadata:                   lot.Metadata,
 		}); err != nil {
-			c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
-			return
+			return auctionSettlement{}, err
 		}
 	}
 
@@ -949,36 +1046,30 @@ func (s *Server) stopAuctionLot(c *gin.Context) {
 	}
 	metadata, err := json.Marshal(updatedMetadata)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
-		return
+		return auctionSettlement{}, err
 	}
-	if _, err := qtx.UpdateActivityOccurrenceStatusAndMetadata(c.Request.Context(), db.UpdateActivityOccurrenceStatusAndMetadataParams{
+	if _, err := qtx.UpdateActivityOccurrenceStatusAndMetadata(ctx, db.UpdateActivityOccurrenceStatusAndMetadataParams{
 		ID:       lot.ID,
 		Status:   "resolved",
 		EndsAt:   optionalTime(now),
 		Metadata: metadata,
 	}); err != nil {
-		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
-		return
-	}
-
-	if err := tx.Commit(c.Request.Context()); err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
-		return
-	}
-
-	response := gin.H{
-		"contestant":         gin.H{"id": contestant.ID.String(), "name": contestant.Name},
-		"lot_id":             pgUUIDString(lot.ID),
-		"winning_bid_points": winningBidPoints,
-		"price_points":       pricePoints,
+		return auctionSettlement{}, err
 	}
+	var winnerJSON any
 	if winner != nil {
-		response["winner"] = gin.H{"participant_id": pgUUIDString(winner.participantID), "participant_name": winner.participantName}
-	} else {
-		response["winner"] = nil
+		winnerJSON = gin.H{"participant_id": pgUUIDString(winner.participantID), "participant_name": winner.participantName}
+	}
+	if err := recordDomainEvent(ctx, qtx, instanceID, webhooks.EventAuctionLotClosed, gin.H{
+		"lot_id":          pgUUIDString(lot.ID),
+		"contestant_id":   contestantID.String(),
+		"contestant_name": contestantName,
+		"winner":          winnerJSON,
+		"price_points":    pricePoints,
+	}); err != nil {
+		return auctionSettlement{}, err
 	}
-	c.JSON(http.StatusOK, response)
+	return settlement, nil
 }
 
 func (s *Server) getMyPonies(c *gin.Context) {
@@ -1401,10 +1492,6 @@ func (s *Server) recordIndividualPonyImmunity(c *gin.Context) {
 		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
 		return
 	}
-	if err := tx.Commit(c.Request.Context()); err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
-		return
-	}
 	entriesJSON := make([]gin.H, 0, len(createdEntries))
 	for _, entry := range createdEntries {
 		entriesJSON = append(entriesJSON, gin.H{
@@ -1423,12 +1510,25 @@ func (s *Server) recordIndividualPonyImmunity(c *gin.Context) {
 			"created_at":             formatTimestamp(entry.CreatedAt),
 		})
 	}
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"contestant":      gin.H{"id": contestant.ID.String(), "name": contestant.Name},
 		"occurrence_id":   pgUUIDString(occurrence.ID),
 		"created_count":   len(entriesJSON),
 		"created_entries": entriesJSON,
-	})
+	}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionIndividualPonyImmunity, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := recordOccurrenceResolved(c.Request.Context(), qtx, occurrence.ID, len(createdEntries)); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 func (s *Server) recordMergeAuctionResults(c *gin.Context) {
@@ -1662,16 +1762,21 @@ func (s *Server) recordMergeAuctionResults(c *gin.Context) {
 		})
 	}
 
+	response := gin.H{
+		"activity":   activityToJSON(activity.ID, activity.InstanceID, activity.ActivityType, activity.Name, activity.Status, activity.StartsAt, activity.EndsAt, activity.Metadata, activity.CreatedAt, activity.UpdatedAt),
+		"occurrence": occurrenceToJSON(occurrence.ID, occurrence.ActivityID, occurrence.OccurrenceType, occurrence.Name, occurrence.EffectiveAt, occurrence.StartsAt, occurrence.EndsAt, occurrence.Status, occurrence.SourceRef, occurrence.Metadata, occurrence.CreatedAt, occurrence.UpdatedAt),
+		"results":    applied,
+	}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionMergeAuctionRecord, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
 	if err := tx.Commit(c.Request.Context()); err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"activity":   activityToJSON(activity.ID, activity.InstanceID, activity.ActivityType, activity.Name, activity.Status, activity.StartsAt, activity.EndsAt, activity.Metadata, activity.CreatedAt, activity.UpdatedAt),
-		"occurrence": occurrenceToJSON(occurrence.ID, occurrence.ActivityID, occurrence.OccurrenceType, occurrence.Name, occurrence.EffectiveAt, occurrence.StartsAt, occurrence.EndsAt, occurrence.Status, occurrence.SourceRef, occurrence.Metadata, occurrence.CreatedAt, occurrence.UpdatedAt),
-		"results":    applied,
-	})
+	c.JSON(http.StatusOK, response)
 }
 
 func (s *Server) requireLinkedParticipant(c *gin.Context, instanceID uuid.UUID) (db.GetParticipantByDiscordUserIDRow, bool) {
@@ -1731,13 +1836,26 @@ func (s *Server) resolveRequestedOrLinkedParticipant(c *gin.Context, instanceID
 	return participant, true
 }
 
+// requireInstanceAdminRequest writes the error response unless the acting
+// Discord user admins the instance. A super-admin service caller that sends
+// no Discord user acts as itself, so background readers such as the bot's
+// outbox poller need not borrow an admin's identity.
 func (s *Server) requireInstanceAdminRequest(c *gin.Context, instanceID uuid.UUID) bool {
+	return s.requireInstanceAdminTx(c, s.queries, instanceID)
+}
+
+// requireInstanceAdminTx is requireInstanceAdminRequest checking the admin
+// list through q, the transaction the request goes on to write in.
+func (s *Server) requireInstanceAdminTx(c *gin.Context, q *db.Queries, instanceID uuid.UUID) bool {
 	discordUserID := discordUserIDFromRequest(c.Request)
 	if strings.TrimSpace(discordUserID) == "" {
+		if s.isSuperAdminService(c.Request.Context(), toPGUUID(instanceID)) {
+			return true
+		}
 		c.JSON(http.StatusBadRequest, errorResponse{Error: "missing discord user id"})
 		return false
 	}
-	isAdmin, err := s.isInstanceAdmin(c.Request.Context(), toPGUUID(instanceID), discordUserID)
+	isAdmin, err := s.isInstanceAdminTx(c.Request.Context(), q, toPGUUID(instanceID), discordUserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return false
@@ -2081,6 +2199,14 @@ func (s *Server) revealSecretPointsOnSpend(ctx context.Context, q *db.Queries, i
 	}); err != nil {
 		return 0, err
 	}
+	if err := recordDomainEvent(ctx, q, instanceID, webhooks.EventSecretRevealed, gin.H{
+		"participant_id":         pgUUIDString(participantID),
+		"activity_occurrence_id": pgUUIDString(activityOccurrenceID),
+		"points":                 revealedPoints,
+		"reason":                 reason,
+	}); err != nil {
+		return 0, err
+	}
 	return revealedPoints, nil
 }
 
@@ -2225,6 +2351,13 @@ func (s *Server) loanStatusPayload(ctx context.Context, instanceID, participantI
 		response["interest_repaid_points"] = 0
 		response["total_due_points"] = 0
 		response["remaining_borrow_points"] = maxPrincipal
+		lastDefault, err := s.latestLoanDefault(ctx, instanceID, participantID)
+		if err != nil {
+			return nil, err
+		}
+		if lastDefault != nil {
+			response["last_default"] = lastDefault
+		}
 		return response, nil
 	}
 	principalOutstanding := loan.PrincipalPoints - loan.PrincipalRepaidPoints
@@ -0,0 +1,96 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/gameplay"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// previewOccurrenceResolution answers POST /occurrences/:id/resolve?preview=true
+// with the ledger entries the resolver would write, secret ones included, so
+// it is limited to instance admins. The resolver runs inside a transaction
+// that is always rolled back, so nothing is written and the occurrence keeps
+// its status even if a resolver writes through a query the preview does not
+// stub.
+func (s *Server) previewOccurrenceResolution(c *gin.Context, occurrenceID uuid.UUID) {
+	ctx := c.Request.Context()
+	occurrence, err := s.queries.GetActivityOccurrence(ctx, toPGUUID(occurrenceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "occurrence not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	activity, err := s.queries.GetInstanceActivity(ctx, occurrence.ActivityID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, uuid.UUID(activity.InstanceID.Bytes)) {
+		return
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+	entries, err := gameplay.NewService(qtx).PreviewActivityOccurrence(ctx, occurrence.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "occurrence not found"})
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, errorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+
+	names := make(map[string]string)
+	if len(entries) > 0 {
+		participants, err := qtx.ListParticipantsByInstance(ctx, entries[0].InstanceID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		for _, participant := range participants {
+			names[pgUUIDString(participant.ID)] = participant.Name
+		}
+	}
+	response := make([]gin.H, 0, len(entries))
+	for _, entry := range entries {
+		participantID := pgUUIDString(entry.ParticipantID)
+		response = append(response, previewLedgerEntryToJSON(entry, names[participantID]))
+	}
+	c.JSON(http.StatusOK, gin.H{"preview": true, "entries": response, "entry_count": len(response)})
+}
+
+func previewLedgerEntryToJSON(entry db.CreateBonusPointLedgerEntryRow, participantName string) gin.H {
+	return gin.H{
+		"participant_id":         pgUUIDString(entry.ParticipantID),
+		"participant_name":       participantName,
+		"activity_occurrence_id": pgUUIDString(entry.ActivityOccurrenceID),
+		"source_group_id":        pgUUIDPointer(entry.SourceGroupID),
+		"entry_kind":             entry.EntryKind,
+		"points":                 entry.Points,
+		"visibility":             entry.Visibility,
+		"reason":                 entry.Reason,
+		"effective_at":           formatTimestamp(entry.EffectiveAt),
+		"award_key":              pgTextPointer(entry.AwardKey),
+		"metadata":               json.RawMessage(entry.Metadata),
+	}
+}
@@ -0,0 +1,175 @@
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/gameplay"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// reresolveOccurrence voids an occurrence's ledger entries with linked
+// corrections, resolves it again on its current inputs, and reports the net
+// change per participant. Stir the Pot rounds are settled as contributions
+// arrive, so they cannot be re-resolved. A run that would take a bonus
+// balance negative is rejected with 409 unless allow_negative_balance=true,
+// in which case the affected balances are returned alongside the diff.
+func (s *Server) reresolveOccurrence(c *gin.Context) {
+	occurrenceID, ok := parseUUIDPath(c, "occurrenceID")
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	occurrence, err := s.queries.GetActivityOccurrence(ctx, toPGUUID(occurrenceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "occurrence not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if occurrence.OccurrenceType == occurrenceTypeStirThePotRound {
+		c.JSON(http.StatusConflict, errorResponse{Error: fmt.Sprintf("%s occurrences cannot be re-resolved", occurrence.OccurrenceType)})
+		return
+	}
+	activity, err := s.queries.GetInstanceActivity(ctx, occurrence.ActivityID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, uuid.UUID(activity.InstanceID.Bytes)) {
+		return
+	}
+	allowNegativeBalance := strings.EqualFold(strings.TrimSpace(c.Query("allow_negative_balance")), "true")
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+	result, err := gameplay.NewService(qtx).ReresolveActivityOccurrence(ctx, occurrence.ID, time.Now().UTC(), allowNegativeBalance)
+	if err != nil {
+		var negativeErr *gameplay.NegativeBalanceError
+		if errors.As(err, &negativeErr) {
+			participants, listErr := qtx.ListParticipantsByInstance(ctx, activity.InstanceID)
+			if listErr != nil {
+				c.JSON(http.StatusInternalServerError, errorResponse{Error: listErr.Error()})
+				return
+			}
+			c.JSON(http.StatusConflict, gin.H{
+				"error":             err.Error() + "; pass allow_negative_balance=true to apply it anyway",
+				"negative_balances": negativeBalancesToJSON(negativeErr.Balances, participants),
+			})
+			return
+		}
+		if errors.Is(err, gameplay.ErrOccurrenceNotResolved) {
+			c.JSON(http.StatusConflict, errorResponse{Error: err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, errorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	participants, err := qtx.ListParticipantsByInstance(ctx, activity.InstanceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	reversed := make([]gin.H, 0, len(result.Reversed))
+	for _, entry := range result.Reversed {
+		reversed = append(reversed, gin.H{
+			"id":             pgUUIDString(entry.ID),
+			"participant_id": pgUUIDString(entry.ParticipantID),
+			"entry_kind":     entry.EntryKind,
+			"points":         entry.Points,
+			"visibility":     entry.Visibility,
+			"reason":         entry.Reason,
+			"effective_at":   formatTimestamp(entry.EffectiveAt),
+			"created_at":     formatTimestamp(entry.CreatedAt),
+		})
+	}
+	created := make([]gin.H, 0, len(result.Created))
+	for _, entry := range result.Created {
+		created = append(created, createdLedgerEntryToJSON(entry))
+	}
+	diff := participantPointsDiffToJSON(result.Diff, participants)
+	negativeBalances := negativeBalancesToJSON(result.NegativeBalances, participants)
+	if err := recordAuditEvent(c, qtx, activity.InstanceID, auditActionOccurrenceReresolve,
+		gin.H{"occurrence_id": pgUUIDString(occurrence.ID), "reversed_entries": reversed},
+		gin.H{"created_entries": created, "diff": diff, "negative_balances": negativeBalances},
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := recordOccurrenceResolved(ctx, qtx, occurrence.ID, len(result.Created)); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"occurrence_id":     pgUUIDString(occurrence.ID),
+		"reversed_entries":  reversed,
+		"created_entries":   created,
+		"diff":              diff,
+		"negative_balances": negativeBalances,
+	})
+}
+
+func negativeBalancesToJSON(balances []gameplay.NegativeBalance, participants []db.ListParticipantsByInstanceRow) []gin.H {
+	names := make(map[string]string, len(participants))
+	for _, participant := range participants {
+		names[pgUUIDString(participant.ID)] = participant.Name
+	}
+	response := make([]gin.H, 0, len(balances))
+	for _, balance := range balances {
+		participantID := pgUUIDString(balance.ParticipantID)
+		response = append(response, gin.H{
+			"participant_id":   participantID,
+			"participant_name": names[participantID],
+			"balance_before":   balance.BalanceBefore,
+			"balance_after":    balance.BalanceAfter,
+		})
+	}
+	return response
+}
+
+func participantPointsDiffToJSON(diffs []gameplay.ParticipantPointsDiff, participants []db.ListParticipantsByInstanceRow) []gin.H {
+	names := make(map[string]string, len(participants))
+	for _, participant := range participants {
+		names[pgUUIDString(participant.ID)] = participant.Name
+	}
+	sorted := append([]gameplay.ParticipantPointsDiff(nil), diffs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return names[pgUUIDString(sorted[i].ParticipantID)] < names[pgUUIDString(sorted[j].ParticipantID)]
+	})
+	response := make([]gin.H, 0, len(sorted))
+	for _, diff := range sorted {
+		participantID := pgUUIDString(diff.ParticipantID)
+		response = append(response, gin.H{
+			"participant_id":   participantID,
+			"participant_name": names[participantID],
+			"before_points":    diff.BeforePoints,
+			"after_points":     diff.AfterPoints,
+			"net_points":       diff.NetPoints,
+		})
+	}
+	return response
+}
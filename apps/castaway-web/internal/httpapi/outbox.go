@@ -0,0 +1,135 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/webhooks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultOutboxEventLimit = 100
+	maxOutboxEventLimit     = 500
+)
+
+// recordDomainEvent writes an event to the outbox through q, which must be
+// the transaction making the change, and queues a delivery for every webhook
+// subscribed to eventType. Payloads can name participants, such as the one
+// behind a secret loan default, so only instance admins read the outbox and
+// only admins register webhooks.
+func recordDomainEvent(ctx context.Context, q *db.Queries, instanceID pgtype.UUID, eventType string, payload any) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return q.CreateOutboxEvent(ctx, db.CreateOutboxEventParams{
+		EventType:  eventType,
+		Payload:    encoded,
+		InstanceID: instanceID,
+	})
+}
+
+// recordOccurrenceResolved emits occurrence.resolved for an occurrence that
+// was just resolved into createdCount ledger entries.
+func recordOccurrenceResolved(ctx context.Context, q *db.Queries, occurrenceID pgtype.UUID, createdCount int) error {
+	occurrence, err := q.GetActivityOccurrence(ctx, occurrenceID)
+	if err != nil {
+		return err
+	}
+	activity, err := q.GetInstanceActivity(ctx, occurrence.ActivityID)
+	if err != nil {
+		return err
+	}
+	return recordDomainEvent(ctx, q, activity.InstanceID, webhooks.EventOccurrenceResolved, gin.H{
+		"occurrence_id":   pgUUIDString(occurrence.ID),
+		"occurrence_type": occurrence.OccurrenceType,
+		"name":            occurrence.Name,
+		"activity_id":     pgUUIDString(activity.ID),
+		"activity_type":   activity.ActivityType,
+		"created_count":   createdCount,
+	})
+}
+
+// listOutboxEvents answers GET /instances/:instanceID/outbox, oldest first,
+// for readers such as the Discord bot that poll instead of taking webhooks.
+// Events can carry secret participant data, so only instance admins may read
+// them. next_after is the cursor to pass back as after on the next poll.
+func (s *Server) listOutboxEvents(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+	params, err := outboxEventFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	params.InstanceID = toPGUUID(instanceID)
+
+	events, err := s.queries.ListOutboxEventsByInstance(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	nextAfter := ""
+	if params.AfterEventID.Valid {
+		nextAfter = pgUUIDString(params.AfterEventID)
+	}
+	response := make([]gin.H, 0, len(events))
+	for _, event := range events {
+		response = append(response, gin.H{
+			"id":          pgUUIDString(event.ID),
+			"type":        event.EventType,
+			"occurred_at": formatTimestamp(event.OccurredAt),
+			"data":        json.RawMessage(event.Payload),
+		})
+		nextAfter = pgUUIDString(event.ID)
+	}
+	c.JSON(http.StatusOK, gin.H{"events": response, "next_after": nextAfter})
+}
+
+// outboxEventFilter builds list params from the type (repeatable), after,
+// since and limit query values.
+func outboxEventFilter(query url.Values) (db.ListOutboxEventsByInstanceParams, error) {
+	params := db.ListOutboxEventsByInstanceParams{RowLimit: defaultOutboxEventLimit}
+	eventTypes, err := normalizeWebhookEventTypes(query["type"])
+	if err != nil {
+		return params, err
+	}
+	params.EventTypes = eventTypes
+	if raw := strings.TrimSpace(query.Get("after")); raw != "" {
+		after, err := uuid.Parse(raw)
+		if err != nil {
+			return params, errors.New("after must be an event id")
+		}
+		params.AfterEventID = toPGUUID(after)
+	}
+	if raw := strings.TrimSpace(query.Get("since")); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return params, errors.New("since must be an RFC3339 timestamp")
+		}
+		params.Since = optionalTime(since)
+	}
+	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxOutboxEventLimit {
+			return params, errors.New("limit must be between 1 and " + strconv.Itoa(maxOutboxEventLimit))
+		}
+		params.RowLimit = int32(limit)
+	}
+	return params, nil
+}
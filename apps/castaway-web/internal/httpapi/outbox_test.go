@@ -0,0 +1,55 @@
+package httpapi
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOutboxEventFilterParsesQuery(t *testing.T) {
+	query := url.Values{
+		"type":  {"loan.defaulted", " secret.revealed ", "loan.defaulted"},
+		"after": {"7f0c8a4e-5a65-4d1e-9a4f-0f7b9b7d2c11"},
+		"since": {"2026-03-01T00:00:00Z"},
+		"limit": {"25"},
+	}
+	params, err := outboxEventFilter(query)
+	if err != nil {
+		t.Fatalf("outboxEventFilter returned error: %v", err)
+	}
+	if !slices.Equal(params.EventTypes, []string{"loan.defaulted", "secret.revealed"}) {
+		t.Fatalf("unexpected event types: %v", params.EventTypes)
+	}
+	if pgUUIDString(params.AfterEventID) != "7f0c8a4e-5a65-4d1e-9a4f-0f7b9b7d2c11" {
+		t.Fatalf("unexpected after cursor: %+v", params.AfterEventID)
+	}
+	if !params.Since.Valid || !params.Since.Time.Equal(time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected since filter: %+v", params.Since)
+	}
+	if params.RowLimit != 25 {
+		t.Fatalf("RowLimit = %d, want 25", params.RowLimit)
+	}
+
+	defaults, err := outboxEventFilter(url.Values{})
+	if err != nil {
+		t.Fatalf("outboxEventFilter returned error: %v", err)
+	}
+	if len(defaults.EventTypes) != 0 || defaults.AfterEventID.Valid || defaults.Since.Valid || defaults.RowLimit != defaultOutboxEventLimit {
+		t.Fatalf("unexpected default filter: %+v", defaults)
+	}
+}
+
+func TestOutboxEventFilterRejectsBadValues(t *testing.T) {
+	for _, query := range []url.Values{
+		{"type": {"loan.forgiven"}},
+		{"after": {"latest"}},
+		{"since": {"yesterday"}},
+		{"limit": {"0"}},
+		{"limit": {"501"}},
+	} {
+		if _, err := outboxEventFilter(query); err == nil {
+			t.Fatalf("expected error for %v", query)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/scheduler"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/scoring"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	jobTypeCloseStirThePot  = "close_stir_the_pot"
+	jobTypeCloseAuctionLots = "close_auction_lots"
+	jobTypeEpisodeRecap     = "episode_recap"
+
+	scheduledJobActor = "scheduler"
+)
+
+// JobHandlers returns the scheduler jobs that run when an episode airs.
+func (s *Server) JobHandlers() map[string]scheduler.Handler {
+	return map[string]scheduler.Handler{
+		jobTypeCloseStirThePot:     s.closeStirThePotJob,
+		jobTypeCloseAuctionLots:    s.closeAuctionLotsJob,
+		jobTypeEpisodeRecap:        s.episodeRecapJob,
+		jobTypeExpireAdvantages:    s.expireAdvantagesJob,
+		jobTypeDefaultOverdueLoans: s.defaultOverdueLoansJob,
+	}
+}
+
+// closeStirThePotJob closes open Stir the Pot rounds whose target episode has
+// aired by the job's boundary.
+func (s *Server) closeStirThePotJob(ctx context.Context, tx pgx.Tx, job scheduler.Job) (scheduler.Result, error) {
+	q := s.queries.WithTx(tx)
+	activity, err := s.primarySystemActivity(ctx, q, job.InstanceID, activityTypeStirThePot)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return scheduler.Result{Skipped: true, Detail: gin.H{"reason": "no stir the pot activity"}}, nil
+	}
+	if err != nil {
+		return scheduler.Result{}, err
+	}
+	occurrences, err := q.ListActivityOccurrencesByActivityAndStatus(ctx, db.ListActivityOccurrencesByActivityAndStatusParams{ActivityID: activity.ID, Status: "recorded"})
+	if err != nil {
+		return scheduler.Result{}, err
+	}
+
+	closed := make([]gin.H, 0)
+	for _, occurrence := range occurrences {
+		if occurrence.OccurrenceType != occurrenceTypeStirThePotRound || stirThePotRoundIsClosed(occurrence) {
+			continue
+		}
+		if !targetEpisodeAired(parseStirThePotRoundMetadata(occurrence.Metadata).TargetEpisode, job) {
+			continue
+		}
+		round, tribes, err := s.closeStirThePotRoundAt(ctx, tx, job.InstanceID, occurrence, scheduledJobActor, job.Now)
+		if err != nil {
+			return scheduler.Result{}, fmt.Errorf("close round %s: %w", pgUUIDString(occurrence.ID), err)
+		}
+		closed = append(closed, gin.H{"round_id": pgUUIDString(round.ID), "tribes": tribes})
+	}
+	if len(closed) == 0 {
+		return scheduler.Result{Skipped: true, Detail: gin.H{"reason": "no open round targets this episode"}}, nil
+	}
+	return scheduler.Result{Detail: gin.H{"closed_rounds": closed}}, nil
+}
+
+// closeAuctionLotsJob settles open auction lots whose target episode has
+// aired by the job's boundary.
+func (s *Server) closeAuctionLotsJob(ctx context.Context, tx pgx.Tx, job scheduler.Job) (scheduler.Result, error) {
+	q := s.queries.WithTx(tx)
+	_, lots, err := s.listOpenAuctionLots(ctx, q, job.InstanceID)
+	if err != nil {
+		return scheduler.Result{}, err
+	}
+
+	settled := make([]gin.H, 0)
+	for _, lot := range lots {
+		var metadata auctionLotMetadata
+		if err := json.Unmarshal(nonEmptyMetadata(lot.Metadata), &metadata); err != nil {
+			continue
+		}
+		if !targetEpisodeAired(metadata.TargetEpisode, job) {
+			continue
+		}
+		settlement, err := s.settleAuctionLot(ctx, q, job.InstanceID, lot.ContestantID, lot.ContestantName, lot.Occurrence, job.Now)
+		if err != nil {
+			return scheduler.Result{}, fmt.Errorf("settle lot for %s: %w", lot.ContestantName, err)
+		}
+		var winnerID any
+		if settlement.winner != nil {
+			winnerID = pgUUIDString(settlement.winner.participantID)
+		}
+		settled = append(settled, gin.H{
+			"lot_id":                pgUUIDString(lot.Occurrence.ID),
+			"contestant_name":       lot.ContestantName,
+			"winner_participant_id": winnerID,
+			"price_points":          settlement.pricePoints,
+		})
+	}
+	if len(settled) == 0 {
+		return scheduler.Result{Skipped: true, Detail: gin.H{"reason": "no open lot targets this episode"}}, nil
+	}
+	return scheduler.Result{Detail: gin.H{"settled_lots": settled}}, nil
+}
+
+// episodeRecapJob records the leaderboard as the previous episode ended, with
+// rank changes against the episode before it.
+func (s *Server) episodeRecapJob(ctx context.Context, tx pgx.Tx, job scheduler.Job) (scheduler.Result, error) {
+	episodes, err := s.queries.WithTx(tx).ListInstanceEpisodes(ctx, job.InstanceID)
+	if err != nil {
+		return scheduler.Result{}, err
+	}
+	current := -1
+	for index, episode := range episodes {
+		if episode.ID == job.EpisodeID {
+			current = index
+			break
+		}
+	}
+	if current < 1 {
+		return scheduler.Result{Skipped: true, Detail: gin.H{"reason": "no previous episode to recap"}}, nil
+	}
+	ended := episodes[current-1]
+
+	inputs, err := s.loadLeaderboardInputs(ctx, job.InstanceID)
+	if err != nil {
+		return scheduler.Result{}, err
+	}
+	cutoff := job.EpisodeAirsAt.Add(-time.Microsecond)
+	board, err := s.scoreLeaderboardAt(ctx, inputs, &cutoff)
+	if err != nil {
+		return scheduler.Result{}, err
+	}
+	ranks := scoring.Ranks(board)
+	var previousRanks map[string]int
+	if current > 1 {
+		previousCutoff := ended.AirsAt.Time.Add(-time.Microsecond)
+		previous, err := s.scoreLeaderboardAt(ctx, inputs, &previousCutoff)
+		if err != nil {
+			return scheduler.Result{}, err
+		}
+		previousRanks = scoring.Ranks(previous)
+	}
+
+	rows := make([]gin.H, 0, len(board))
+	for _, row := range board {
+		var previousRank, rankChange any
+		if rank, ok := previousRanks[row.ParticipantID]; ok {
+			previousRank = rank
+			rankChange = rank - ranks[row.ParticipantID]
+		}
+		rows = append(rows, gin.H{
+			"participant_id":   row.ParticipantID,
+			"participant_name": row.ParticipantName,
+			"rank":             ranks[row.ParticipantID],
+			"previous_rank":    previousRank,
+			"rank_change":      rankChange,
+			"total_points":     row.TotalPoints,
+		})
+	}
+	return scheduler.Result{Detail: gin.H{
+		"episode":     episodeToJSON(ended.ID, ended.EpisodeNumber, ended.Label, ended.AirsAt),
+		"as_of":       cutoff.UTC().Format(time.RFC3339Nano),
+		"leaderboard": rows,
+	}}, nil
+}
+
+// targetEpisodeAired reports whether a round or lot aimed at target is due at
+// the job's episode boundary.
+func targetEpisodeAired(target mergeTargetEpisodeMetadata, job scheduler.Job) bool {
+	if target.EpisodeID != "" && target.EpisodeID == pgUUIDString(job.EpisodeID) {
+		return true
+	}
+	airsAt, err := time.Parse(time.RFC3339, target.EpisodeAirsAt)
+	if err != nil {
+		return false
+	}
+	return !airsAt.After(job.EpisodeAirsAt)
+}
+
+func scheduledJobToJSON(row db.ListScheduledJobsByInstanceRow) gin.H {
+	return gin.H{
+		"id":             pgUUIDString(row.ID),
+		"episode_id":     pgUUIDString(row.EpisodeID),
+		"episode_number": row.EpisodeNumber,
+		"job_type":       row.JobType,
+		"run_at":         formatTimestamp(row.RunAt),
+		"status":         row.Status,
+		"attempts":       row.Attempts,
+		"last_error":     pgTextPointer(row.LastError),
+		"completed_at":   formatNullableTimestamp(row.CompletedAt),
+		"created_at":     formatTimestamp(row.CreatedAt),
+		"updated_at":     formatTimestamp(row.UpdatedAt),
+	}
+}
+
+// findScheduledJob loads the instance's job with jobID, writing a 404 when
+// either is missing.
+func (s *Server) findScheduledJob(c *gin.Context, q *db.Queries, instanceID, jobID pgtype.UUID) (db.ListScheduledJobsByInstanceRow, bool) {
+	ctx := c.Request.Context()
+	if _, err := q.GetInstance(ctx, instanceID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return db.ListScheduledJobsByInstanceRow{}, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return db.ListScheduledJobsByInstanceRow{}, false
+	}
+	rows, err := q.ListScheduledJobsByInstance(ctx, instanceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return db.ListScheduledJobsByInstanceRow{}, false
+	}
+	for _, row := range rows {
+		if row.ID == jobID {
+			return row, true
+		}
+	}
+	c.JSON(http.StatusNotFound, errorResponse{Error: "scheduled job not found"})
+	return db.ListScheduledJobsByInstanceRow{}, false
+}
+
+func (s *Server) listScheduledJobs(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+
+	if _, err := s.queries.GetInstance(c.Request.Context(), toPGUUID(instanceID)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	rows, err := s.queries.ListScheduledJobsByInstance(c.Request.Context(), toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	response := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, scheduledJobToJSON(row))
+	}
+	c.JSON(http.StatusOK, gin.H{"jobs": response})
+}
+
+func (s *Server) listScheduledJobRuns(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	jobID, ok := parseUUIDPath(c, "jobID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+	job, ok := s.findScheduledJob(c, s.queries, toPGUUID(instanceID), toPGUUID(jobID))
+	if !ok {
+		return
+	}
+
+	rows, err := s.queries.ListScheduledJobRuns(c.Request.Context(), db.ListScheduledJobRunsParams{
+		InstanceID:     toPGUUID(instanceID),
+		ScheduledJobID: toPGUUID(jobID),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	runs := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		runs = append(runs, gin.H{
+			"id":          pgUUIDString(row.ID),
+			"status":      row.Status,
+			"started_at":  formatTimestamp(row.StartedAt),
+			"finished_at": formatTimestamp(row.FinishedAt),
+			"result":      json.RawMessage(nonEmptyMetadata(row.Result)),
+			"error":       pgTextPointer(row.Error),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"job": scheduledJobToJSON(job), "runs": runs})
+}
+
+// retryScheduledJob puts a failed or skipped job back in the queue to run on
+// the scheduler's next pass.
+func (s *Server) retryScheduledJob(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	jobID, ok := parseUUIDPath(c, "jobID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+	job, ok := s.findScheduledJob(c, s.queries, toPGUUID(instanceID), toPGUUID(jobID))
+	if !ok {
+		return
+	}
+	if job.Status != scheduler.StatusFailed && job.Status != scheduler.StatusSkipped {
+		c.JSON(http.StatusConflict, errorResponse{Error: fmt.Sprintf("only failed or skipped jobs can be retried; job is %s", job.Status)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	if _, err := qtx.RetryScheduledJob(ctx, db.RetryScheduledJobParams{
+		RunAt:      optionalTime(time.Now().UTC()),
+		InstanceID: toPGUUID(instanceID),
+		ID:         toPGUUID(jobID),
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusConflict, errorResponse{Error: "scheduled job changed; reload and try again"})
+			return
+		}
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	retried, ok := s.findScheduledJob(c, qtx, toPGUUID(instanceID), toPGUUID(jobID))
+	if !ok {
+		return
+	}
+	response := scheduledJobToJSON(retried)
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionScheduledJobRetry, scheduledJobToJSON(job), response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"job": response})
+}
@@ -0,0 +1,97 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/scoring"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+func scoringStrategyResponse(strategy scoring.Strategy) gin.H {
+	return gin.H{
+		"name":        strategy.Name(),
+		"description": strategy.Description(),
+		"default":     strategy.Name() == scoring.DefaultStrategy().Name(),
+	}
+}
+
+func (s *Server) listScoringStrategies(c *gin.Context) {
+	strategies := scoring.Strategies()
+	response := make([]gin.H, 0, len(strategies))
+	for _, strategy := range strategies {
+		response = append(response, scoringStrategyResponse(strategy))
+	}
+	c.JSON(http.StatusOK, gin.H{"scoring_strategies": response})
+}
+
+type setScoringStrategyRequest struct {
+	ScoringStrategy string `json:"scoring_strategy" binding:"required"`
+}
+
+func (s *Server) setScoringStrategy(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+
+	var req setScoringStrategyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	strategy, ok := scoring.StrategyByName(strings.TrimSpace(req.ScoringStrategy))
+	if !ok {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "unknown scoring_strategy: " + req.ScoringStrategy})
+		return
+	}
+
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	previous, err := qtx.GetInstanceScoringStrategy(ctx, toPGUUID(instanceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if _, err := qtx.SetInstanceScoringStrategy(ctx, db.SetInstanceScoringStrategyParams{
+		ScoringStrategy: strategy.Name(),
+		ID:              toPGUUID(instanceID),
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	response := scoringStrategyResponse(strategy)
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionScoringStrategySet, gin.H{"name": previous}, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"scoring_strategy": response})
+}
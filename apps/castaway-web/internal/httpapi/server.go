@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +13,7 @@ import (
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/gameplay"
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/scoring"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/webhooks"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -27,6 +27,11 @@ type Server struct {
 	queries                 *db.Queries
 	serviceAuth             ServiceAuthConfig
 	serviceAuthBearerTokens map[string]struct{}
+	credentialTouches       *credentialTouches
+	eventStreamInterval     time.Duration
+	streams                 *instanceFeeds
+	done                    <-chan struct{}
+	webhookPrivateTargets   bool
 }
 
 type Option func(*Server)
@@ -49,6 +54,9 @@ func New(pool *pgxpool.Pool, options ...Option) *Server {
 		queries:                 db.New(pool),
 		serviceAuth:             normalizeServiceAuthConfig(ServiceAuthConfig{}),
 		serviceAuthBearerTokens: make(map[string]struct{}),
+		credentialTouches:       newCredentialTouches(),
+		eventStreamInterval:     defaultEventStreamInterval,
+		streams:                 newInstanceFeeds(),
 	}
 	server.registerMetrics()
 	for _, option := range options {
@@ -69,51 +77,99 @@ func (s *Server) Router() *gin.Engine {
 	protected.GET("/instances", s.listInstances)
 	protected.POST("/instances", s.createInstance)
 	protected.POST("/instances/import", s.importInstance)
+	protected.POST("/instances/bootstrap", s.bootstrapInstance)
+	protected.GET("/imports/:importID", s.getImport)
+	protected.POST("/imports/:importID/apply", s.applyImport)
 	protected.GET("/instances/:instanceID", s.getInstance)
-	protected.POST("/instances/:instanceID/contestants", s.createContestant)
+	protected.GET("/instances/:instanceID/state", s.getInstanceState)
+	protected.PUT("/instances/:instanceID/state", s.setInstanceState)
+	protected.GET("/instances/:instanceID/episodes", s.listEpisodes)
+	protected.POST("/instances/:instanceID/episodes", s.requireInstanceState(instanceActionConfigure), s.createEpisode)
+	protected.PATCH("/instances/:instanceID/episodes/:episodeID", s.requireInstanceState(instanceActionConfigure), s.updateEpisode)
+	protected.DELETE("/instances/:instanceID/episodes/:episodeID", s.requireInstanceState(instanceActionConfigure), s.deleteEpisode)
+	protected.GET("/instances/:instanceID/events", s.streamInstanceEvents)
+	protected.GET("/instances/:instanceID/audit", s.listAuditEvents)
+	protected.GET("/instances/:instanceID/outbox", s.listOutboxEvents)
+	protected.GET("/instances/:instanceID/scheduled-jobs", s.listScheduledJobs)
+	protected.GET("/instances/:instanceID/scheduled-jobs/:jobID/runs", s.listScheduledJobRuns)
+	protected.POST("/instances/:instanceID/scheduled-jobs/:jobID/retry", s.requireInstanceState(instanceActionOperate), s.retryScheduledJob)
+	protected.GET("/instances/:instanceID/webhooks", s.listWebhooks)
+	protected.POST("/instances/:instanceID/webhooks", s.requireInstanceState(instanceActionOperate), s.createWebhook)
+	protected.DELETE("/instances/:instanceID/webhooks/:webhookID", s.requireInstanceState(instanceActionOperate), s.deleteWebhook)
+	protected.GET("/instances/:instanceID/webhooks/deliveries", s.listWebhookDeliveries)
+	protected.POST("/instances/:instanceID/webhooks/deliveries/:deliveryID/retry", s.requireInstanceState(instanceActionOperate), s.retryWebhookDelivery)
+	protected.POST("/instances/:instanceID/contestants", s.requireInstanceState(instanceActionConfigure), s.createContestant)
 	protected.GET("/instances/:instanceID/contestants", s.listContestants)
 
-	protected.POST("/instances/:instanceID/participants", s.createParticipant)
+	protected.POST("/instances/:instanceID/participants", s.requireInstanceState(instanceActionConfigure), s.createParticipant)
 	protected.GET("/instances/:instanceID/participants", s.listParticipants)
 	protected.GET("/instances/:instanceID/participants/me", s.getLinkedParticipant)
-	protected.PUT("/instances/:instanceID/participants/:participantID/discord-link", s.linkParticipantDiscordUser)
-	protected.DELETE("/instances/:instanceID/participants/:participantID/discord-link", s.unlinkParticipantDiscordUser)
+	protected.PUT("/instances/:instanceID/participants/:participantID/discord-link", s.requireInstanceState(instanceActionLink), s.linkParticipantDiscordUser)
+	protected.DELETE("/instances/:instanceID/participants/:participantID/discord-link", s.requireInstanceState(instanceActionLink), s.unlinkParticipantDiscordUser)
 	protected.GET("/instances/:instanceID/participants/:participantID/bonus-ledger", s.bonusLedger)
+	protected.GET("/instances/:instanceID/participants/:participantID/advantages", s.listParticipantAdvantages)
+	protected.GET("/instances/:instanceID/advantages", s.listAdvantages)
+	protected.POST("/instances/:instanceID/advantages", s.requireInstanceState(instanceActionPlay), s.grantAdvantage)
+	protected.POST("/instances/:instanceID/advantages/:advantageID/play", s.requireInstanceState(instanceActionPlay), s.playAdvantage)
+	protected.GET("/instances/:instanceID/groups", s.listParticipantGroups)
+	protected.POST("/instances/:instanceID/groups", s.requireInstanceState(instanceActionConfigure), s.createParticipantGroup)
+	protected.POST("/instances/:instanceID/groups/realignments/preview", s.previewGroupRealignment)
+	protected.POST("/instances/:instanceID/groups/realignments", s.requireInstanceState(instanceActionConfigure), s.applyGroupRealignment)
+	protected.GET("/instances/:instanceID/groups/:groupID", s.getParticipantGroup)
+	protected.POST("/instances/:instanceID/groups/:groupID/memberships", s.requireInstanceState(instanceActionConfigure), s.createGroupMembership)
+	protected.POST("/instances/:instanceID/groups/:groupID/memberships/:participantID/end", s.requireInstanceState(instanceActionConfigure), s.endGroupMembership)
 	protected.GET("/instances/:instanceID/stir-the-pot/me", s.getStirThePotStatus)
 	protected.GET("/instances/:instanceID/stir-the-pot/tribes/show", s.getStirThePotTribeStatus)
-	protected.POST("/instances/:instanceID/stir-the-pot/start", s.startStirThePotRound)
-	protected.POST("/instances/:instanceID/stir-the-pot/close", s.closeStirThePotRound)
-	protected.POST("/instances/:instanceID/stir-the-pot/me/contributions", s.addStirThePotContribution)
+	protected.POST("/instances/:instanceID/stir-the-pot/start", s.requireInstanceState(instanceActionPlay), s.startStirThePotRound)
+	protected.POST("/instances/:instanceID/stir-the-pot/close", s.requireInstanceState(instanceActionPlay), s.closeStirThePotRound)
+	protected.POST("/instances/:instanceID/stir-the-pot/me/contributions", s.requireIdempotency(), s.requireInstanceState(instanceActionPlay), s.addStirThePotContribution)
 	protected.GET("/instances/:instanceID/auction/me", s.getAuctionStatus)
-	protected.POST("/instances/:instanceID/auction/lots/start", s.startAuctionLot)
-	protected.POST("/instances/:instanceID/auction/lots/:contestantID/stop", s.stopAuctionLot)
-	protected.PUT("/instances/:instanceID/auction/contestants/:contestantID/bid/me", s.setAuctionBid)
+	protected.POST("/instances/:instanceID/auction/lots/start", s.requireInstanceState(instanceActionPlay), s.startAuctionLot)
+	protected.POST("/instances/:instanceID/auction/lots/:contestantID/stop", s.requireInstanceState(instanceActionPlay), s.stopAuctionLot)
+	protected.PUT("/instances/:instanceID/auction/contestants/:contestantID/bid/me", s.requireIdempotency(), s.requireInstanceState(instanceActionPlay), s.setAuctionBid)
 	protected.GET("/instances/:instanceID/ponies/me", s.getMyPonies)
 	protected.GET("/instances/:instanceID/loan-shark/me", s.getLoanSharkStatus)
-	protected.POST("/instances/:instanceID/loan-shark/me/borrow", s.borrowFromLoanShark)
-	protected.POST("/instances/:instanceID/loan-shark/me/repay", s.repayLoanShark)
-	protected.POST("/instances/:instanceID/individual-pony/immunity", s.recordIndividualPonyImmunity)
-	protected.POST("/instances/:instanceID/merge-auction/record", s.recordMergeAuctionResults)
-	protected.POST("/instances/:instanceID/finale-bingo/loan-sharks", s.recordFinaleBingoLoanSharks)
+	protected.POST("/instances/:instanceID/loan-shark/me/borrow", s.requireIdempotency(), s.requireInstanceState(instanceActionPlay), s.borrowFromLoanShark)
+	protected.POST("/instances/:instanceID/loan-shark/me/repay", s.requireIdempotency(), s.requireInstanceState(instanceActionPlay), s.repayLoanShark)
+	protected.POST("/instances/:instanceID/individual-pony/immunity", s.requireInstanceState(instanceActionPlay), s.recordIndividualPonyImmunity)
+	protected.POST("/instances/:instanceID/merge-auction/record", s.requireInstanceState(instanceActionPlay), s.recordMergeAuctionResults)
+	protected.POST("/instances/:instanceID/finale-bingo/loan-sharks", s.requireInstanceState(instanceActionPlay), s.recordFinaleBingoLoanSharks)
 	protected.POST("/instances/:instanceID/finale-bingo/scores/preview", s.previewFinaleBingoScores)
-	protected.POST("/instances/:instanceID/finale-bingo/scores", s.recordFinaleBingoScores)
+	protected.POST("/instances/:instanceID/finale-bingo/scores", s.requireInstanceState(instanceActionScore), s.recordFinaleBingoScores)
 
-	protected.PUT("/instances/:instanceID/drafts/:participantID", s.replaceDraft)
+	protected.PUT("/instances/:instanceID/drafts/:participantID", s.requireInstanceState(instanceActionDraft), s.replaceDraft)
 	protected.GET("/instances/:instanceID/drafts/:participantID", s.getDraft)
+	protected.GET("/instances/:instanceID/draft-deadline", s.getDraftDeadline)
+	protected.PUT("/instances/:instanceID/draft-deadline", s.requireInstanceState(instanceActionConfigure), s.setDraftDeadline)
+	protected.GET("/instances/:instanceID/draft-overrides", s.listDraftOverrides)
 
-	protected.PUT("/instances/:instanceID/outcomes/:position", s.upsertOutcome)
+	protected.PUT("/instances/:instanceID/outcomes/:position", s.requireInstanceState(instanceActionScore), s.upsertOutcome)
 	protected.GET("/instances/:instanceID/outcomes", s.listOutcomes)
+	protected.GET("/instances/:instanceID/outcomes/history", s.listOutcomeHistory)
 
 	protected.GET("/instances/:instanceID/leaderboard", s.leaderboard)
+	protected.POST("/instances/:instanceID/leaderboard/what-if", s.whatIfLeaderboard)
+	protected.GET("/instances/:instanceID/win-probabilities", s.winProbabilities)
+	protected.GET("/instances/:instanceID/contestant-odds", s.listContestantOdds)
+	protected.PUT("/instances/:instanceID/contestant-odds", s.requireInstanceState(instanceActionConfigure), s.setContestantOdds)
+	protected.GET("/scoring-strategies", s.listScoringStrategies)
+	protected.PUT("/instances/:instanceID/scoring-strategy", s.requireInstanceState(instanceActionConfigure), s.setScoringStrategy)
+	protected.GET("/activity-types", s.listActivityTypes)
+	protected.GET("/credentials", s.listAPICredentials)
+	protected.POST("/credentials", s.createAPICredential)
+	protected.POST("/credentials/:credentialID/rotate", s.rotateAPICredential)
+	protected.DELETE("/credentials/:credentialID", s.revokeAPICredential)
+	protected.GET("/audit", s.listGlobalAuditEvents)
 	protected.GET("/instances/:instanceID/activities", s.listActivities)
-	protected.POST("/instances/:instanceID/activities", s.createActivity)
+	protected.POST("/instances/:instanceID/activities", s.requireInstanceState(instanceActionConfigure), s.createActivity)
 	protected.GET("/activities/:activityID", s.getActivity)
 	protected.GET("/activities/:activityID/occurrences", s.listOccurrences)
-	protected.POST("/activities/:activityID/occurrences", s.createOccurrence)
+	protected.POST("/activities/:activityID/occurrences", s.requireInstanceState(instanceActionScore), s.createOccurrence)
 	protected.GET("/occurrences/:occurrenceID", s.getOccurrence)
-	protected.POST("/occurrences/:occurrenceID/participants", s.createOccurrenceParticipant)
-	protected.POST("/occurrences/:occurrenceID/groups", s.createOccurrenceGroup)
-	protected.POST("/occurrences/:occurrenceID/resolve", s.resolveOccurrence)
+	protected.POST("/occurrences/:occurrenceID/participants", s.requireInstanceState(instanceActionScore), s.createOccurrenceParticipant)
+	protected.POST("/occurrences/:occurrenceID/groups", s.requireInstanceState(instanceActionScore), s.createOccurrenceGroup)
+	protected.POST("/occurrences/:occurrenceID/resolve", s.requireInstanceState(instanceActionScore), s.resolveOccurrence)
+	protected.POST("/occurrences/:occurrenceID/reresolve", s.requireInstanceState(instanceActionScore), s.reresolveOccurrence)
 	protected.GET("/instances/:instanceID/participants/:participantID/activity-history", s.participantActivityHistory)
 
 	return r
@@ -128,11 +184,13 @@ func (s *Server) health(c *gin.Context) {
 }
 
 type instanceResponse struct {
-	ID             string                `json:"id"`
-	Name           string                `json:"name"`
-	Season         int32                 `json:"season"`
-	CreatedAt      string                `json:"created_at"`
-	CurrentEpisode *instanceEpisodeBrief `json:"current_episode,omitempty"`
+	ID              string                `json:"id"`
+	Name            string                `json:"name"`
+	Season          int32                 `json:"season"`
+	CreatedAt       string                `json:"created_at"`
+	State           string                `json:"state"`
+	ScoringStrategy string                `json:"scoring_strategy,omitempty"`
+	CurrentEpisode  *instanceEpisodeBrief `json:"current_episode,omitempty"`
 }
 
 type instanceEpisodeBrief struct {
@@ -142,12 +200,13 @@ type instanceEpisodeBrief struct {
 	AirsAt        string `json:"airs_at"`
 }
 
-func toInstanceResponse(id pgtype.UUID, name string, season int32, createdAt pgtype.Timestamptz) instanceResponse {
+func toInstanceResponse(id pgtype.UUID, name string, season int32, createdAt pgtype.Timestamptz, state string) instanceResponse {
 	return instanceResponse{
 		ID:        uuid.UUID(id.Bytes).String(),
 		Name:      name,
 		Season:    season,
 		CreatedAt: createdAt.Time.UTC().Format("2006-01-02T15:04:05Z07:00"),
+		State:     state,
 	}
 }
 
@@ -181,16 +240,21 @@ func (s *Server) listInstances(c *gin.Context) {
 		if !matchesContainsFold(instance.Name, nameFilter) {
 			continue
 		}
-		response = append(response, toInstanceResponse(instance.ID, instance.Name, instance.Season, instance.CreatedAt))
+		if credential, ok := apiCredentialFromContext(c.Request.Context()); ok && !credential.allowsInstance(instance.ID) {
+			continue
+		}
+		response = append(response, toInstanceResponse(instance.ID, instance.Name, instance.Season, instance.CreatedAt, instance.State))
 	}
 
 	c.JSON(http.StatusOK, gin.H{"instances": response})
 }
 
 type createInstanceRequest struct {
-	Name        string   `json:"name" binding:"required"`
-	Season      int32    `json:"season" binding:"required"`
-	Contestants []string `json:"contestants"`
+	Name            string   `json:"name" binding:"required"`
+	Season          int32    `json:"season" binding:"required"`
+	Contestants     []string `json:"contestants"`
+	ScoringStrategy string   `json:"scoring_strategy"`
+	State           string   `json:"state"`
 }
 
 func (s *Server) createInstance(c *gin.Context) {
@@ -199,6 +263,19 @@ func (s *Server) createInstance(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
 		return
 	}
+	strategy, ok := scoring.StrategyByName(strings.TrimSpace(req.ScoringStrategy))
+	if !ok {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "unknown scoring_strategy: " + req.ScoringStrategy})
+		return
+	}
+	initialState := instanceStateSetup
+	if raw := strings.TrimSpace(req.State); raw != "" {
+		initialState = strings.ToLower(raw)
+		if !validInstanceState(initialState) {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: "unknown state: " + req.State})
+			return
+		}
+	}
 
 	tx, err := s.pool.Begin(c.Request.Context())
 	if err != nil {
@@ -218,6 +295,7 @@ func (s *Server) createInstance(c *gin.Context) {
 	createdInstance, err := qtx.CreateInstance(c.Request.Context(), db.CreateInstanceParams{
 		Name:   req.Name,
 		Season: req.Season,
+		State:  pgtype.Text{String: initialState, Valid: true},
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
@@ -243,12 +321,23 @@ func (s *Server) createInstance(c *gin.Context) {
 		return
 	}
 
+	scoringStrategy, err := qtx.SetInstanceScoringStrategy(c.Request.Context(), db.SetInstanceScoringStrategyParams{
+		ScoringStrategy: strategy.Name(),
+		ID:              createdInstance.ID,
+	})
+	if err != nil {
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+
 	if err := tx.Commit(c.Request.Context()); err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"instance": toInstanceResponse(createdInstance.ID, createdInstance.Name, createdInstance.Season, createdInstance.CreatedAt)})
+	instanceJSON := toInstanceResponse(createdInstance.ID, createdInstance.Name, createdInstance.Season, createdInstance.CreatedAt, createdInstance.State)
+	instanceJSON.ScoringStrategy = scoringStrategy
+	c.JSON(http.StatusCreated, gin.H{"instance": instanceJSON})
 }
 
 func (s *Server) getInstance(c *gin.Context) {
@@ -295,7 +384,12 @@ func (s *Server) getInstance(c *gin.Context) {
 		})
 	}
 
-	instanceJSON := toInstanceResponse(instance.ID, instance.Name, instance.Season, instance.CreatedAt)
+	instanceJSON := toInstanceResponse(instance.ID, instance.Name, instance.Season, instance.CreatedAt, instance.State)
+	instanceJSON.ScoringStrategy, err = s.queries.GetInstanceScoringStrategy(c.Request.Context(), instance.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
 	currentEpisode, err := s.queries.GetCurrentEpisodeAt(c.Request.Context(), db.GetCurrentEpisodeAtParams{
 		InstanceID: instance.ID,
 		At:         pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
@@ -314,12 +408,7 @@ func (s *Server) getInstance(c *gin.Context) {
 	}
 	episodes := make([]gin.H, 0, len(episodeRows))
 	for _, episode := range episodeRows {
-		episodes = append(episodes, gin.H{
-			"id":             pgUUIDString(episode.ID),
-			"episode_number": episode.EpisodeNumber,
-			"label":          episode.Label,
-			"airs_at":        formatTimestamp(episode.AirsAt),
-		})
+		episodes = append(episodes, episodeToJSON(episode.ID, episode.EpisodeNumber, episode.Label, episode.AirsAt))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -524,7 +613,15 @@ func (s *Server) linkParticipantDiscordUser(c *gin.Context) {
 		}
 	}
 
-	updated, err := s.queries.SetParticipantDiscordUserID(c.Request.Context(), db.SetParticipantDiscordUserIDParams{
+	tx, err := s.pool.Begin(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	updated, err := qtx.SetParticipantDiscordUserID(c.Request.Context(), db.SetParticipantDiscordUserIDParams{
 		ID:            toPGUUID(participantID),
 		DiscordUserID: pgtype.Text{String: targetDiscordUserID, Valid: true},
 	})
@@ -532,6 +629,17 @@ func (s *Server) linkParticipantDiscordUser(c *gin.Context) {
 		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
 		return
 	}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionParticipantDiscordLink,
+		participantSummaryToJSON(participant.ID, participant.Name, pgTextString(participant.DiscordUserID)),
+		participantSummaryToJSON(updated.ID, updated.Name, targetDiscordUserID),
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"participant": participantSummaryToJSON(updated.ID, updated.Name)})
 }
@@ -575,11 +683,30 @@ func (s *Server) unlinkParticipantDiscordUser(c *gin.Context) {
 		return
 	}
 
-	updated, err := s.queries.ClearParticipantDiscordUserID(c.Request.Context(), toPGUUID(participantID))
+	tx, err := s.pool.Begin(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	updated, err := qtx.ClearParticipantDiscordUserID(c.Request.Context(), toPGUUID(participantID))
 	if err != nil {
 		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
 		return
 	}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionParticipantDiscordUnlink,
+		participantSummaryToJSON(participant.ID, participant.Name, pgTextString(participant.DiscordUserID)),
+		participantSummaryToJSON(updated.ID, updated.Name),
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"participant": participantSummaryToJSON(updated.ID, updated.Name)})
 }
@@ -612,7 +739,8 @@ func (s *Server) currentTribeName(ctx context.Context, participantID pgtype.UUID
 }
 
 type replaceDraftRequest struct {
-	ContestantIDs []string `json:"contestant_ids" binding:"required"`
+	ContestantIDs  []string `json:"contestant_ids" binding:"required"`
+	OverrideReason string   `json:"override_reason"`
 }
 
 func (s *Server) replaceDraft(c *gin.Context) {
@@ -684,6 +812,32 @@ func (s *Server) replaceDraft(c *gin.Context) {
 		contestantUUIDs = append(contestantUUIDs, contestantID)
 	}
 
+	deadline, err := s.resolveDraftDeadline(c.Request.Context(), s.queries, toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	// Once the deadline passes only instance admins may change drafts, and each
+	// change is recorded as an override with who made it and why.
+	override := deadline.lockedAt(time.Now().UTC())
+	actorDiscordUserID := strings.TrimSpace(discordUserIDFromRequest(c.Request))
+	overrideReason := strings.TrimSpace(req.OverrideReason)
+	if override {
+		isAdmin, err := s.isInstanceAdmin(c.Request.Context(), toPGUUID(instanceID), actorDiscordUserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		if !isAdmin {
+			c.JSON(http.StatusForbidden, errorResponse{Error: "draft deadline has passed"})
+			return
+		}
+		if overrideReason == "" {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: "override_reason is required after the draft deadline"})
+			return
+		}
+	}
+
 	tx, err := s.pool.Begin(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
@@ -699,6 +853,19 @@ func (s *Server) replaceDraft(c *gin.Context) {
 	}()
 
 	qtx := s.queries.WithTx(tx)
+	var previousContestantIDs []string
+	if override {
+		previousPicks, err := qtx.ListDraftPicksForParticipant(c.Request.Context(), toPGUUID(participantID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		previousContestantIDs = make([]string, 0, len(previousPicks))
+		for _, pick := range previousPicks {
+			previousContestantIDs = append(previousContestantIDs, pgUUIDString(pick.ContestantID))
+		}
+	}
+
 	if err := qtx.DeleteDraftPicksForParticipant(c.Request.Context(), toPGUUID(participantID)); err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
@@ -722,12 +889,51 @@ func (s *Server) replaceDraft(c *gin.Context) {
 		}
 	}
 
+	response := gin.H{"status": "draft saved"}
+	if override {
+		contestantIDs := make([]string, 0, len(contestantUUIDs))
+		for _, contestantID := range contestantUUIDs {
+			contestantIDs = append(contestantIDs, contestantID.String())
+		}
+		previousJSON, err := json.Marshal(previousContestantIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		contestantJSON, err := json.Marshal(contestantIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		actor := draftOverrideActor(c.Request.Context(), actorDiscordUserID)
+		created, err := qtx.CreateDraftOverride(c.Request.Context(), db.CreateDraftOverrideParams{
+			ActorDiscordUserID:    actor,
+			Reason:                overrideReason,
+			DraftDeadline:         optionalTime(deadline.at),
+			PreviousContestantIds: previousJSON,
+			ContestantIds:         contestantJSON,
+			InstanceID:            toPGUUID(instanceID),
+			ParticipantID:         toPGUUID(participantID),
+		})
+		if err != nil {
+			c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+			return
+		}
+		response["override"] = gin.H{
+			"id":                    pgUUIDString(created.ID),
+			"actor_discord_user_id": actor,
+			"reason":                overrideReason,
+			"draft_deadline":        formatTimestamp(optionalTime(deadline.at)),
+			"created_at":            formatTimestamp(created.CreatedAt),
+		}
+	}
+
 	if err := tx.Commit(c.Request.Context()); err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "draft saved"})
+	c.JSON(http.StatusOK, response)
 }
 
 func (s *Server) getDraft(c *gin.Context) {
@@ -790,7 +996,19 @@ func (s *Server) getDraft(c *gin.Context) {
 }
 
 type upsertOutcomeRequest struct {
-	ContestantID string `json:"contestant_id"`
+	ContestantID  string `json:"contestant_id"`
+	EpisodeID     string `json:"episode_id"`
+	EpisodeNumber *int32 `json:"episode_number"`
+	Reason        string `json:"reason"`
+	Note          string `json:"note"`
+}
+
+var outcomeReasons = map[string]struct{}{
+	"voted_out": {},
+	"medevac":   {},
+	"quit":      {},
+	"removed":   {},
+	"finalist":  {},
 }
 
 func (s *Server) upsertOutcome(c *gin.Context) {
@@ -834,40 +1052,172 @@ func (s *Server) upsertOutcome(c *gin.Context) {
 		contestantParam = toPGUUID(contestantID)
 	}
 
+	reason := strings.ToLower(strings.TrimSpace(req.Reason))
+	if reason != "" {
+		if _, ok := outcomeReasons[reason]; !ok {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: "reason must be one of voted_out, medevac, quit, removed, finalist"})
+			return
+		}
+	}
+
+	var episodeID *uuid.UUID
+	if strings.TrimSpace(req.EpisodeID) != "" {
+		parsed, err := uuid.Parse(strings.TrimSpace(req.EpisodeID))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid episode_id"})
+			return
+		}
+		episodeID = &parsed
+	}
+	episode, hasEpisode, err := s.findInstanceEpisode(c.Request.Context(), toPGUUID(instanceID), episodeID, req.EpisodeNumber)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if (episodeID != nil || req.EpisodeNumber != nil) && !hasEpisode {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "episode does not belong to this instance"})
+		return
+	}
+
 	positionInt32, err := conv.ToInt32(position)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
 		return
 	}
 
-	outcome, err := s.queries.UpsertOutcomePosition(c.Request.Context(), db.UpsertOutcomePositionParams{
-		InstanceID:   toPGUUID(instanceID),
-		Position:     positionInt32,
-		ContestantID: contestantParam,
+	tx, err := s.pool.Begin(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	existing, err := qtx.GetOutcomePosition(c.Request.Context(), db.GetOutcomePositionParams{
+		InstanceID: toPGUUID(instanceID),
+		Position:   positionInt32,
+	})
+	hasExisting := err == nil
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	// Corrections keep the recorded episode and reason unless the caller
+	// overrides them; brand new outcomes default to the episode that most
+	// recently aired.
+	episodeParam := pgtype.UUID{}
+	switch {
+	case hasEpisode:
+		episodeParam = episode.ID
+	case hasExisting && existing.EpisodeID.Valid:
+		episodeParam = existing.EpisodeID
+	case !hasExisting:
+		current, err := qtx.GetCurrentEpisodeAt(c.Request.Context(), db.GetCurrentEpisodeAtParams{
+			InstanceID: toPGUUID(instanceID),
+			At:         optionalTime(time.Now().UTC()),
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		if err == nil {
+			episodeParam = current.ID
+		}
+	}
+	if reason == "" && hasExisting {
+		reason = existing.Reason
+	}
+
+	var note *string
+	if trimmed := strings.TrimSpace(req.Note); trimmed != "" {
+		note = &trimmed
+	}
+	var recordedBy *string
+	if discordUserID := strings.TrimSpace(discordUserIDFromRequest(c.Request)); discordUserID != "" {
+		recordedBy = &discordUserID
+	}
+
+	outcome, err := qtx.UpsertOutcomePosition(c.Request.Context(), db.UpsertOutcomePositionParams{
+		InstanceID:              toPGUUID(instanceID),
+		Position:                positionInt32,
+		ContestantID:            contestantParam,
+		EpisodeID:               episodeParam,
+		Reason:                  reason,
+		Note:                    optionalText(note),
+		RecordedByDiscordUserID: optionalText(recordedBy),
 	})
 	if err != nil {
 		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
 		return
 	}
 
-	response := gin.H{
-		"position": outcome.Position,
+	response := outcomePositionToJSON(outcome.Position, outcome.ContestantID, outcome.EpisodeID, outcome.Reason)
+	var before any
+	if hasExisting {
+		before = outcomePositionToJSON(existing.Position, existing.ContestantID, existing.EpisodeID, existing.Reason)
 	}
-	if outcome.ContestantID.Valid {
-		response["contestant_id"] = uuid.UUID(outcome.ContestantID.Bytes).String()
-	} else {
-		response["contestant_id"] = nil
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionOutcomeUpsert, before, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
 	}
+	if err := recordDomainEvent(c.Request.Context(), qtx, toPGUUID(instanceID), webhooks.EventOutcomeRecorded, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"outcome": response})
 }
 
+func outcomePositionToJSON(position int32, contestantID pgtype.UUID, episodeID pgtype.UUID, reason string) gin.H {
+	return gin.H{
+		"position":      position,
+		"contestant_id": pgUUIDPointer(contestantID),
+		"episode_id":    pgUUIDPointer(episodeID),
+		"reason":        reason,
+	}
+}
+
 func (s *Server) listOutcomes(c *gin.Context) {
 	instanceID, ok := parseUUIDPath(c, "instanceID")
 	if !ok {
 		return
 	}
 
-	outcomes, err := s.queries.ListOutcomePositionsByInstance(c.Request.Context(), toPGUUID(instanceID))
+	episodeNumber, ok := parseOptionalEpisodeQuery(c)
+	if !ok {
+		return
+	}
+
+	var outcomes []db.ListOutcomePositionsByInstanceRow
+	var err error
+	if episodeNumber != nil {
+		episodes, listErr := s.queries.ListInstanceEpisodes(c.Request.Context(), toPGUUID(instanceID))
+		if listErr != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: listErr.Error()})
+			return
+		}
+		windowEnd, found := episodeWindowEnd(episodes, *episodeNumber, time.Now().UTC())
+		if !found {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "episode not found"})
+			return
+		}
+		rows, listErr := s.queries.ListOutcomePositionsAsOf(c.Request.Context(), db.ListOutcomePositionsAsOfParams{
+			InstanceID: toPGUUID(instanceID),
+			AsOf:       optionalTime(windowEnd),
+		})
+		err = listErr
+		for _, row := range rows {
+			outcomes = append(outcomes, db.ListOutcomePositionsByInstanceRow(row))
+		}
+	} else {
+		outcomes, err = s.queries.ListOutcomePositionsByInstance(c.Request.Context(), toPGUUID(instanceID))
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
@@ -885,7 +1235,13 @@ func (s *Server) listOutcomes(c *gin.Context) {
 
 	response := make([]gin.H, 0, len(outcomes))
 	for _, outcome := range outcomes {
-		row := gin.H{"position": outcome.Position}
+		row := gin.H{
+			"position":       outcome.Position,
+			"episode_id":     pgUUIDPointer(outcome.EpisodeID),
+			"episode_number": pgInt4Pointer(outcome.EpisodeNumber),
+			"episode_label":  pgTextPointer(outcome.EpisodeLabel),
+			"reason":         outcome.Reason,
+		}
 		if outcome.ContestantID.Valid {
 			contestantID := uuid.UUID(outcome.ContestantID.Bytes)
 			row["contestant_id"] = contestantID.String()
@@ -900,111 +1256,96 @@ func (s *Server) listOutcomes(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"outcomes": response})
 }
 
-func (s *Server) leaderboard(c *gin.Context) {
+func (s *Server) listOutcomeHistory(c *gin.Context) {
 	instanceID, ok := parseUUIDPath(c, "instanceID")
 	if !ok {
 		return
 	}
 
-	participantFilter, ok := parseOptionalParticipantIDQuery(c)
-	if !ok {
-		return
+	var positionFilter *int32
+	if raw := strings.TrimSpace(c.Query("position")); raw != "" {
+		position, err := strconv.Atoi(raw)
+		if err != nil || position <= 0 {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: "position must be a positive integer"})
+			return
+		}
+		positionInt32, err := conv.ToInt32(position)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+			return
+		}
+		positionFilter = &positionInt32
 	}
 
-	contestants, err := s.queries.ListContestantsByInstance(c.Request.Context(), toPGUUID(instanceID))
+	history, err := s.queries.ListOutcomePositionHistoryByInstance(c.Request.Context(), toPGUUID(instanceID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
-	participants, err := s.queries.ListParticipantsByInstance(c.Request.Context(), toPGUUID(instanceID))
+
+	contestants, err := s.queries.ListContestantsByInstance(c.Request.Context(), toPGUUID(instanceID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
-	draftPicks, err := s.queries.ListDraftPicksForInstance(c.Request.Context(), toPGUUID(instanceID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
-		return
+	contestantNames := make(map[string]string, len(contestants))
+	for _, contestant := range contestants {
+		contestantNames[pgUUIDString(contestant.ID)] = contestant.Name
 	}
-	outcomes, err := s.queries.ListOutcomePositionsByInstance(c.Request.Context(), toPGUUID(instanceID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
-		return
+	contestantName := func(id pgtype.UUID) *string {
+		if !id.Valid {
+			return nil
+		}
+		name := contestantNames[pgUUIDString(id)]
+		return &name
 	}
 
-	participantNames := make(map[string]string, len(participants))
-	participantDiscordUserIDs := make(map[string]string, len(participants))
-	currentTribeNames := make(map[string]string, len(participants))
-	leaderboardAt := time.Now().UTC()
-	for _, participant := range participants {
-		participantID := uuid.UUID(participant.ID.Bytes).String()
-		participantNames[participantID] = participant.Name
-		if participant.DiscordUserID.Valid {
-			participantDiscordUserIDs[participantID] = strings.TrimSpace(participant.DiscordUserID.String)
-		}
-		tribeName, err := s.currentTribeName(c.Request.Context(), participant.ID, leaderboardAt)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
-			return
+	response := make([]gin.H, 0, len(history))
+	for _, entry := range history {
+		if positionFilter != nil && entry.Position != *positionFilter {
+			continue
 		}
-		currentTribeNames[participantID] = tribeName
+		response = append(response, gin.H{
+			"id":                          pgUUIDString(entry.ID),
+			"position":                    entry.Position,
+			"contestant_id":               pgUUIDPointer(entry.ContestantID),
+			"contestant_name":             contestantName(entry.ContestantID),
+			"previous_contestant_id":      pgUUIDPointer(entry.PreviousContestantID),
+			"previous_contestant_name":    contestantName(entry.PreviousContestantID),
+			"episode_id":                  pgUUIDPointer(entry.EpisodeID),
+			"episode_number":              pgInt4Pointer(entry.EpisodeNumber),
+			"episode_label":               pgTextPointer(entry.EpisodeLabel),
+			"reason":                      entry.Reason,
+			"note":                        pgTextPointer(entry.Note),
+			"recorded_by_discord_user_id": pgTextPointer(entry.RecordedByDiscordUserID),
+			"recorded_at":                 formatTimestamp(entry.RecordedAt),
+		})
 	}
 
-	draftsByParticipant := make(map[string][]scoring.DraftPick, len(participants))
-	for _, pick := range draftPicks {
-		participantID := uuid.UUID(pick.ParticipantID.Bytes).String()
-		draftsByParticipant[participantID] = append(draftsByParticipant[participantID], scoring.DraftPick{
-			Position:     int(pick.Position),
-			ContestantID: uuid.UUID(pick.ContestantID.Bytes).String(),
-		})
+	c.JSON(http.StatusOK, gin.H{"history": response})
+}
+
+// findInstanceEpisode resolves an episode reference given either as a public
+// ID or as an episode number. It reports false when nothing matches or when
+// neither reference is supplied.
+func (s *Server) findInstanceEpisode(ctx context.Context, instanceID pgtype.UUID, episodeID *uuid.UUID, episodeNumber *int32) (db.ListInstanceEpisodesRow, bool, error) {
+	if episodeID == nil && episodeNumber == nil {
+		return db.ListInstanceEpisodesRow{}, false, nil
 	}
-	for participantID := range draftsByParticipant {
-		sort.Slice(draftsByParticipant[participantID], func(i, j int) bool {
-			return draftsByParticipant[participantID][i].Position < draftsByParticipant[participantID][j].Position
-		})
+	episodes, err := s.queries.ListInstanceEpisodes(ctx, instanceID)
+	if err != nil {
+		return db.ListInstanceEpisodesRow{}, false, err
 	}
-
-	finalPositions := map[string]int{}
-	for _, outcome := range outcomes {
-		if !outcome.ContestantID.Valid {
+	for _, episode := range episodes {
+		if episodeID != nil && pgUUIDString(episode.ID) != episodeID.String() {
 			continue
 		}
-		finalPositions[uuid.UUID(outcome.ContestantID.Bytes).String()] = int(outcome.Position)
-	}
-
-	visibleBonusByParticipant := make(map[string]int, len(participants))
-	gameplayService := gameplay.NewService(s.queries)
-	for _, participant := range participants {
-		participantID := uuid.UUID(participant.ID.Bytes).String()
-		bonusPoints, err := gameplayService.VisibleBonusTotalByParticipant(c.Request.Context(), toPGUUID(instanceID), participant.ID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
-			return
-		}
-		visibleBonusByParticipant[participantID] = int(bonusPoints)
-	}
-
-	leaderboard := scoring.CalculateLeaderboard(len(contestants), participantNames, draftsByParticipant, finalPositions, visibleBonusByParticipant)
-
-	response := make([]gin.H, 0, len(leaderboard))
-	for _, row := range leaderboard {
-		if participantFilter != nil && row.ParticipantID != participantFilter.String() {
+		if episodeNumber != nil && episode.EpisodeNumber != *episodeNumber {
 			continue
 		}
-		response = append(response, gin.H{
-			"participant_id":              row.ParticipantID,
-			"participant_name":            row.ParticipantName,
-			"participant_discord_user_id": participantDiscordUserIDs[row.ParticipantID],
-			"current_tribe_name":          currentTribeNames[row.ParticipantID],
-			"score":                       row.Score,
-			"draft_points":                row.DraftPoints,
-			"bonus_points":                row.BonusPoints,
-			"total_points":                row.TotalPoints,
-			"points_available":            row.PointsAvailable,
-		})
+		return episode, true, nil
 	}
-
-	c.JSON(http.StatusOK, gin.H{"leaderboard": response})
+	return db.ListInstanceEpisodesRow{}, false, nil
 }
 
 func (s *Server) bonusLedger(c *gin.Context) {
@@ -1333,7 +1674,7 @@ func (s *Server) participantActivityHistory(c *gin.Context) {
 			"id":   pgUUIDString(participant.ID),
 			"name": participant.Name,
 		},
-		"instance":   toInstanceResponse(instance.ID, instance.Name, instance.Season, instance.CreatedAt),
+		"instance":   toInstanceResponse(instance.ID, instance.Name, instance.Season, instance.CreatedAt, instance.State),
 		"activities": activities,
 	})
 }
@@ -1423,6 +1764,16 @@ func (s *Server) createActivity(c *gin.Context) {
 		return
 	}
 
+	activityType, ok := gameplay.LookupActivityType(req.ActivityType)
+	if !ok {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "unsupported activity_type " + strconv.Quote(req.ActivityType)})
+		return
+	}
+	metadata := defaultJSONB(req.Metadata)
+	if !validateMetadataRequest(c, activityType.Schemas.Activity, metadata) {
+		return
+	}
+
 	activity, err := s.queries.CreateInstanceActivity(c.Request.Context(), db.CreateInstanceActivityParams{
 		InstanceID:   toPGUUID(instanceID),
 		ActivityType: req.ActivityType,
@@ -1430,7 +1781,7 @@ func (s *Server) createActivity(c *gin.Context) {
 		Status:       req.Status,
 		StartsAt:     optionalTime(req.StartsAt),
 		EndsAt:       optionalTimePtr(req.EndsAt),
-		Metadata:     defaultJSONB(req.Metadata),
+		Metadata:     metadata,
 	})
 	if err != nil {
 		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
@@ -1546,6 +1897,16 @@ func (s *Server) createOccurrence(c *gin.Context) {
 		return
 	}
 
+	metadata := defaultJSONB(req.Metadata)
+	activityType, registered, err := s.registeredActivityType(c.Request.Context(), toPGUUID(activityID))
+	if err != nil {
+		respondActivityLookupError(c, err, "activity not found")
+		return
+	}
+	if registered && !validateMetadataRequest(c, activityType.Schemas.Occurrence, metadata) {
+		return
+	}
+
 	occurrence, err := s.queries.CreateActivityOccurrence(c.Request.Context(), db.CreateActivityOccurrenceParams{
 		ActivityID:     toPGUUID(activityID),
 		OccurrenceType: req.OccurrenceType,
@@ -1555,7 +1916,7 @@ func (s *Server) createOccurrence(c *gin.Context) {
 		EndsAt:         optionalTimePtr(req.EndsAt),
 		Status:         req.Status,
 		SourceRef:      optionalText(req.SourceRef),
-		Metadata:       defaultJSONB(req.Metadata),
+		Metadata:       metadata,
 	})
 	if err != nil {
 		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
@@ -1606,13 +1967,28 @@ func (s *Server) createOccurrenceParticipant(c *gin.Context) {
 		result = *req.Result
 	}
 
+	metadata := defaultJSONB(req.Metadata)
+	occurrence, err := s.queries.GetActivityOccurrence(c.Request.Context(), toPGUUID(occurrenceID))
+	if err != nil {
+		respondActivityLookupError(c, err, "occurrence not found")
+		return
+	}
+	activityType, registered, err := s.registeredActivityType(c.Request.Context(), occurrence.ActivityID)
+	if err != nil {
+		respondActivityLookupError(c, err, "activity not found")
+		return
+	}
+	if registered && !validateMetadataRequest(c, activityType.Schemas.Participant, metadata) {
+		return
+	}
+
 	created, err := s.queries.CreateActivityOccurrenceParticipant(c.Request.Context(), db.CreateActivityOccurrenceParticipantParams{
 		ActivityOccurrenceID: toPGUUID(occurrenceID),
 		ParticipantID:        toPGUUID(participantID),
 		ParticipantGroupID:   participantGroupID,
 		Role:                 req.Role,
 		Result:               result,
-		Metadata:             defaultJSONB(req.Metadata),
+		Metadata:             metadata,
 	})
 	if err != nil {
 		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
@@ -1689,6 +2065,17 @@ func (s *Server) resolveOccurrence(c *gin.Context) {
 	if !ok {
 		return
 	}
+	if raw := strings.TrimSpace(c.Query("preview")); raw != "" {
+		preview, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: "preview must be true or false"})
+			return
+		}
+		if preview {
+			s.previewOccurrenceResolution(c, occurrenceID)
+			return
+		}
+	}
 
 	tx, err := s.pool.Begin(c.Request.Context())
 	if err != nil {
@@ -1704,7 +2091,8 @@ func (s *Server) resolveOccurrence(c *gin.Context) {
 		}
 	}()
 
-	createdEntries, err := gameplay.NewService(s.queries.WithTx(tx)).ResolveActivityOccurrence(c.Request.Context(), toPGUUID(occurrenceID))
+	qtx := s.queries.WithTx(tx)
+	createdEntries, err := gameplay.NewService(qtx).ResolveActivityOccurrence(c.Request.Context(), toPGUUID(occurrenceID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			c.JSON(http.StatusNotFound, errorResponse{Error: "occurrence not found"})
@@ -1717,34 +2105,57 @@ func (s *Server) resolveOccurrence(c *gin.Context) {
 		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
 		return
 	}
-
-	if err := tx.Commit(c.Request.Context()); err != nil {
+	if err := recordOccurrenceResolved(c.Request.Context(), qtx, toPGUUID(occurrenceID), len(createdEntries)); err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
 		return
 	}
-
 	response := make([]gin.H, 0, len(createdEntries))
 	for _, entry := range createdEntries {
-		response = append(response, gin.H{
-			"id":                     pgUUIDString(entry.ID),
-			"instance_id":            pgUUIDString(entry.InstanceID),
-			"participant_id":         pgUUIDString(entry.ParticipantID),
-			"activity_occurrence_id": pgUUIDString(entry.ActivityOccurrenceID),
-			"source_group_id":        pgUUIDPointer(entry.SourceGroupID),
-			"entry_kind":             entry.EntryKind,
-			"points":                 entry.Points,
-			"visibility":             entry.Visibility,
-			"reason":                 entry.Reason,
-			"effective_at":           formatTimestamp(entry.EffectiveAt),
-			"award_key":              pgTextPointer(entry.AwardKey),
-			"metadata":               json.RawMessage(entry.Metadata),
-			"created_at":             formatTimestamp(entry.CreatedAt),
-		})
+		response = append(response, createdLedgerEntryToJSON(entry))
+	}
+	occurrence, err := qtx.GetActivityOccurrence(c.Request.Context(), toPGUUID(occurrenceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	activity, err := qtx.GetInstanceActivity(c.Request.Context(), occurrence.ActivityID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := recordAuditEvent(c, qtx, activity.InstanceID, auditActionOccurrenceResolve,
+		gin.H{"occurrence_id": occurrenceID.String()},
+		gin.H{"occurrence_id": occurrenceID.String(), "created_entries": response}); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := tx.Commit(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"created_entries": response, "created_count": len(response)})
 }
 
+func createdLedgerEntryToJSON(entry db.CreateBonusPointLedgerEntryRow) gin.H {
+	return gin.H{
+		"id":                     pgUUIDString(entry.ID),
+		"instance_id":            pgUUIDString(entry.InstanceID),
+		"participant_id":         pgUUIDString(entry.ParticipantID),
+		"activity_occurrence_id": pgUUIDString(entry.ActivityOccurrenceID),
+		"source_group_id":        pgUUIDPointer(entry.SourceGroupID),
+		"entry_kind":             entry.EntryKind,
+		"points":                 entry.Points,
+		"visibility":             entry.Visibility,
+		"reason":                 entry.Reason,
+		"effective_at":           formatTimestamp(entry.EffectiveAt),
+		"award_key":              pgTextPointer(entry.AwardKey),
+		"metadata":               json.RawMessage(entry.Metadata),
+		"created_at":             formatTimestamp(entry.CreatedAt),
+	}
+}
+
 func activityToJSON(id, instanceID pgtype.UUID, activityType, name, status string, startsAt, endsAt pgtype.Timestamptz, metadata []byte, createdAt, updatedAt pgtype.Timestamptz) gin.H {
 	return gin.H{
 		"id":            pgUUIDString(id),
@@ -1894,6 +2305,41 @@ func parseOptionalSeasonQuery(c *gin.Context) (*int32, bool) {
 	return &seasonInt32, true
 }
 
+func parseOptionalEpisodeQuery(c *gin.Context) (*int32, bool) {
+	raw := strings.TrimSpace(c.Query("episode"))
+	if raw == "" {
+		return nil, true
+	}
+
+	episode, err := strconv.Atoi(raw)
+	if err != nil || episode < 0 {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "episode must be a non-negative integer"})
+		return nil, false
+	}
+
+	episodeInt32, err := conv.ToInt32(episode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return nil, false
+	}
+	return &episodeInt32, true
+}
+
+func parseOptionalAsOfQuery(c *gin.Context) (*time.Time, bool) {
+	raw := strings.TrimSpace(c.Query("as_of"))
+	if raw == "" {
+		return nil, true
+	}
+
+	asOf, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "as_of must be an RFC3339 timestamp"})
+		return nil, false
+	}
+	asOf = asOf.UTC()
+	return &asOf, true
+}
+
 func parseOptionalParticipantIDQuery(c *gin.Context) (*uuid.UUID, bool) {
 	raw := strings.TrimSpace(c.Query("participant_id"))
 	if raw == "" {
@@ -1972,6 +2418,14 @@ func pgTextString(value pgtype.Text) string {
 	return strings.TrimSpace(value.String)
 }
 
+func pgInt4Pointer(value pgtype.Int4) *int32 {
+	if !value.Valid {
+		return nil
+	}
+	formatted := value.Int32
+	return &formatted
+}
+
 func optionalText(value *string) pgtype.Text {
 	if value == nil {
 		return pgtype.Text{}
@@ -1,9 +1,11 @@
 package httpapi_test
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -14,7 +16,9 @@ import (
 	appinternal "github.com/bry-guy/srvivor/apps/castaway-web/internal/app"
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/httpapi"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/scheduler"
 	"github.com/bry-guy/srvivor/apps/castaway-web/internal/seeddata"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/webhooks"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -1604,6 +1608,2571 @@ func TestRecordMergeAuctionResults_RejectsDuplicateContestantResult(t *testing.T
 	}
 }
 
+func TestOutcomesTrackEpisodesReasonsAndCorrectionHistory(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	instance := createInstanceForTest(t, ctx, queries, "Outcome History", 50)
+	contestantA := createContestantForTest(t, ctx, queries, instance.ID, "Contestant A")
+	contestantB := createContestantForTest(t, ctx, queries, instance.ID, "Contestant B")
+	contestantC := createContestantForTest(t, ctx, queries, instance.ID, "Contestant C")
+	createEpisodeForTest(t, ctx, queries, instance.ID, 1, "Episode 1", time.Date(2026, time.March, 4, 20, 0, 0, 0, time.UTC))
+	createEpisodeForTest(t, ctx, queries, instance.ID, 2, "Episode 2", time.Date(2026, time.March, 11, 20, 0, 0, 0, time.UTC))
+
+	router := httpapi.New(pool).Router()
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+
+	for _, write := range []struct {
+		position int
+		body     string
+	}{
+		{position: 3, body: fmt.Sprintf(`{"contestant_id":"%s","episode_number":1}`, uuid.UUID(contestantA.ID.Bytes).String())},
+		{position: 2, body: fmt.Sprintf(`{"contestant_id":"%s","episode_number":2,"reason":"medevac"}`, uuid.UUID(contestantB.ID.Bytes).String())},
+		{position: 3, body: fmt.Sprintf(`{"contestant_id":"%s","note":"wrong castaway recorded"}`, uuid.UUID(contestantC.ID.Bytes).String())},
+	} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, fmt.Sprintf("%s/outcomes/%d", instancePath, write.position), write.body, "", "admin-1"))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("upsert outcome %d status = %d, body = %s", write.position, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	badReasonRecorder := httptest.NewRecorder()
+	router.ServeHTTP(badReasonRecorder, authorizedJSONRequest(http.MethodPut, instancePath+"/outcomes/1", `{"reason":"abducted"}`, "", ""))
+	if badReasonRecorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected invalid reason to fail with 400, got %d body=%s", badReasonRecorder.Code, badReasonRecorder.Body.String())
+	}
+
+	var afterEpisodeOne struct {
+		Outcomes []struct {
+			Position      int     `json:"position"`
+			ContestantID  *string `json:"contestant_id"`
+			EpisodeNumber *int    `json:"episode_number"`
+			Reason        string  `json:"reason"`
+		} `json:"outcomes"`
+	}
+	episodeRecorder := httptest.NewRecorder()
+	router.ServeHTTP(episodeRecorder, httptest.NewRequest(http.MethodGet, instancePath+"/outcomes?episode=1", nil))
+	if episodeRecorder.Code != http.StatusOK {
+		t.Fatalf("list outcomes after episode 1 status = %d, body = %s", episodeRecorder.Code, episodeRecorder.Body.String())
+	}
+	if err := json.Unmarshal(episodeRecorder.Body.Bytes(), &afterEpisodeOne); err != nil {
+		t.Fatalf("unmarshal outcomes response: %v", err)
+	}
+	if len(afterEpisodeOne.Outcomes) != 1 {
+		t.Fatalf("expected only the episode 1 elimination, got %+v", afterEpisodeOne.Outcomes)
+	}
+	first := afterEpisodeOne.Outcomes[0]
+	if first.Position != 3 || first.ContestantID == nil || *first.ContestantID != uuid.UUID(contestantC.ID.Bytes).String() {
+		t.Fatalf("expected corrected contestant C at position 3, got %+v", first)
+	}
+	if first.EpisodeNumber == nil || *first.EpisodeNumber != 1 || first.Reason != "voted_out" {
+		t.Fatalf("expected correction to keep episode 1 and voted_out reason, got %+v", first)
+	}
+
+	var history struct {
+		History []struct {
+			ContestantID            *string `json:"contestant_id"`
+			PreviousContestantID    *string `json:"previous_contestant_id"`
+			PreviousContestantName  *string `json:"previous_contestant_name"`
+			EpisodeNumber           *int    `json:"episode_number"`
+			Note                    *string `json:"note"`
+			RecordedByDiscordUserID *string `json:"recorded_by_discord_user_id"`
+		} `json:"history"`
+	}
+	historyRecorder := httptest.NewRecorder()
+	router.ServeHTTP(historyRecorder, httptest.NewRequest(http.MethodGet, instancePath+"/outcomes/history?position=3", nil))
+	if historyRecorder.Code != http.StatusOK {
+		t.Fatalf("outcome history status = %d, body = %s", historyRecorder.Code, historyRecorder.Body.String())
+	}
+	if err := json.Unmarshal(historyRecorder.Body.Bytes(), &history); err != nil {
+		t.Fatalf("unmarshal outcome history response: %v", err)
+	}
+	if len(history.History) != 2 {
+		t.Fatalf("expected two history rows for position 3, got %+v", history.History)
+	}
+	correction := history.History[1]
+	if correction.PreviousContestantID == nil || *correction.PreviousContestantID != uuid.UUID(contestantA.ID.Bytes).String() {
+		t.Fatalf("expected correction to record contestant A as previous, got %+v", correction)
+	}
+	if correction.PreviousContestantName == nil || *correction.PreviousContestantName != "Contestant A" {
+		t.Fatalf("expected previous contestant name, got %+v", correction)
+	}
+	if correction.Note == nil || *correction.Note != "wrong castaway recorded" {
+		t.Fatalf("expected correction note, got %+v", correction)
+	}
+	if correction.RecordedByDiscordUserID == nil || *correction.RecordedByDiscordUserID != "admin-1" {
+		t.Fatalf("expected recorder discord user id, got %+v", correction)
+	}
+}
+
+func TestOutcomesAsOfEpisodeReadCorrectionHistory(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	instance := createInstanceForTest(t, ctx, queries, "Outcome As Of", 50)
+	contestantA := createContestantForTest(t, ctx, queries, instance.ID, "Contestant A")
+	contestantB := createContestantForTest(t, ctx, queries, instance.ID, "Contestant B")
+	createEpisodeForTest(t, ctx, queries, instance.ID, 1, "Episode 1", time.Date(2026, time.March, 4, 20, 0, 0, 0, time.UTC))
+	createEpisodeForTest(t, ctx, queries, instance.ID, 2, "Episode 2", time.Date(2026, time.March, 11, 20, 0, 0, 0, time.UTC))
+
+	router := httpapi.New(pool).Router()
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+	upsert := func(body string) {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, instancePath+"/outcomes/4", body, "", "admin-1"))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("upsert outcome status = %d, body = %s", recorder.Code, recorder.Body.String())
+		}
+	}
+
+	// Record position 4 against episode 1, then correct it as of episode 2.
+	// The board after episode 1 must still show the original entry.
+	upsert(fmt.Sprintf(`{"contestant_id":"%s","episode_number":1}`, uuid.UUID(contestantA.ID.Bytes).String()))
+	upsert(fmt.Sprintf(`{"contestant_id":"%s","episode_number":2,"note":"wrong castaway recorded"}`, uuid.UUID(contestantB.ID.Bytes).String()))
+
+	listOutcomes := func(query string) []struct {
+		Position     int     `json:"position"`
+		ContestantID *string `json:"contestant_id"`
+	} {
+		t.Helper()
+		var response struct {
+			Outcomes []struct {
+				Position     int     `json:"position"`
+				ContestantID *string `json:"contestant_id"`
+			} `json:"outcomes"`
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, instancePath+"/outcomes"+query, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("list outcomes%s status = %d, body = %s", query, recorder.Code, recorder.Body.String())
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("unmarshal outcomes response: %v", err)
+		}
+		return response.Outcomes
+	}
+
+	earlier := listOutcomes("?episode=1")
+	if len(earlier) != 1 || earlier[0].Position != 4 || earlier[0].ContestantID == nil || *earlier[0].ContestantID != uuid.UUID(contestantA.ID.Bytes).String() {
+		t.Fatalf("expected the board after episode 1 to show contestant A at position 4, got %+v", earlier)
+	}
+	current := listOutcomes("")
+	if len(current) != 1 || current[0].ContestantID == nil || *current[0].ContestantID != uuid.UUID(contestantB.ID.Bytes).String() {
+		t.Fatalf("expected the live board to show the correction, got %+v", current)
+	}
+}
+
+func TestLeaderboardPointInTimeAndRankChange(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	instance := createInstanceForTest(t, ctx, queries, "Leaderboard History", 50)
+	contestantA := createContestantForTest(t, ctx, queries, instance.ID, "Contestant A")
+	contestantB := createContestantForTest(t, ctx, queries, instance.ID, "Contestant B")
+	contestantC := createContestantForTest(t, ctx, queries, instance.ID, "Contestant C")
+	alpha := createParticipantForTest(t, ctx, queries, instance.ID, "Alpha")
+	beta := createParticipantForTest(t, ctx, queries, instance.ID, "Beta")
+	createDraftPickForTest(t, ctx, queries, instance.ID, alpha.ID, contestantA.ID, 1)
+	createDraftPickForTest(t, ctx, queries, instance.ID, alpha.ID, contestantB.ID, 2)
+	createDraftPickForTest(t, ctx, queries, instance.ID, alpha.ID, contestantC.ID, 3)
+	createDraftPickForTest(t, ctx, queries, instance.ID, beta.ID, contestantA.ID, 1)
+	createDraftPickForTest(t, ctx, queries, instance.ID, beta.ID, contestantC.ID, 2)
+	createDraftPickForTest(t, ctx, queries, instance.ID, beta.ID, contestantB.ID, 3)
+	createEpisodeForTest(t, ctx, queries, instance.ID, 1, "Episode 1", time.Date(2026, time.March, 4, 20, 0, 0, 0, time.UTC))
+	createEpisodeForTest(t, ctx, queries, instance.ID, 2, "Episode 2", time.Date(2026, time.March, 11, 20, 0, 0, 0, time.UTC))
+	createEpisodeForTest(t, ctx, queries, instance.ID, 3, "Episode 3", time.Date(2026, time.March, 18, 20, 0, 0, 0, time.UTC))
+	activity := createActivityForTest(t, ctx, queries, instance.ID, time.Date(2026, time.March, 21, 12, 0, 0, 0, time.UTC), nil, "journey", "Journey 3")
+	occurrence := createOccurrenceForTest(t, ctx, queries, activity.ID, "journey_resolution", "Journey 3 Resolution", time.Date(2026, time.March, 21, 13, 0, 0, 0, time.UTC))
+	createLedgerEntryForTest(t, ctx, queries, instance.ID, beta.ID, occurrence.ID, pgtype.UUID{}, "award", 5, "public", "journey reward", "beta-journey")
+
+	router := httpapi.New(pool).Router()
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+	for _, write := range []struct {
+		position     int
+		contestantID pgtype.UUID
+		episode      int
+	}{
+		{position: 3, contestantID: contestantC.ID, episode: 1},
+		{position: 2, contestantID: contestantB.ID, episode: 2},
+		{position: 1, contestantID: contestantA.ID, episode: 3},
+	} {
+		recorder := httptest.NewRecorder()
+		body := fmt.Sprintf(`{"contestant_id":"%s","episode_number":%d}`, uuid.UUID(write.contestantID.Bytes).String(), write.episode)
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, fmt.Sprintf("%s/outcomes/%d", instancePath, write.position), body, "", ""))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("upsert outcome %d status = %d, body = %s", write.position, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	type leaderboardRow struct {
+		ParticipantName string `json:"participant_name"`
+		Rank            int    `json:"rank"`
+		PreviousRank    *int   `json:"previous_rank"`
+		RankChange      *int   `json:"rank_change"`
+		TotalPoints     int    `json:"total_points"`
+	}
+	type leaderboardResponse struct {
+		Episode *struct {
+			EpisodeNumber int `json:"episode_number"`
+		} `json:"episode"`
+		Leaderboard []leaderboardRow `json:"leaderboard"`
+	}
+	fetch := func(query string) (leaderboardResponse, int) {
+		var response leaderboardResponse
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, instancePath+"/leaderboard"+query, nil))
+		if recorder.Code == http.StatusOK {
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("unmarshal leaderboard response: %v", err)
+			}
+		}
+		return response, recorder.Code
+	}
+
+	afterEpisodeOne, status := fetch("?episode=1")
+	if status != http.StatusOK {
+		t.Fatalf("leaderboard after episode 1 status = %d", status)
+	}
+	if afterEpisodeOne.Episode == nil || afterEpisodeOne.Episode.EpisodeNumber != 1 {
+		t.Fatalf("expected episode 1 in response, got %+v", afterEpisodeOne.Episode)
+	}
+	if len(afterEpisodeOne.Leaderboard) != 2 || afterEpisodeOne.Leaderboard[0].ParticipantName != "Alpha" || afterEpisodeOne.Leaderboard[0].TotalPoints != 1 || afterEpisodeOne.Leaderboard[1].TotalPoints != 0 {
+		t.Fatalf("unexpected leaderboard after episode 1: %+v", afterEpisodeOne.Leaderboard)
+	}
+	if afterEpisodeOne.Leaderboard[0].PreviousRank != nil || afterEpisodeOne.Leaderboard[0].RankChange != nil {
+		t.Fatalf("expected no rank change for the first episode, got %+v", afterEpisodeOne.Leaderboard[0])
+	}
+
+	afterEpisodeTwo, status := fetch("?as_of=2026-03-12T00:00:00Z")
+	if status != http.StatusOK {
+		t.Fatalf("leaderboard as of episode 2 status = %d", status)
+	}
+	if afterEpisodeTwo.Leaderboard[0].ParticipantName != "Alpha" || afterEpisodeTwo.Leaderboard[0].TotalPoints != 3 || afterEpisodeTwo.Leaderboard[1].TotalPoints != 1 {
+		t.Fatalf("unexpected leaderboard as of episode 2: %+v", afterEpisodeTwo.Leaderboard)
+	}
+	if afterEpisodeTwo.Leaderboard[0].RankChange == nil || *afterEpisodeTwo.Leaderboard[0].RankChange != 0 {
+		t.Fatalf("expected unchanged rank after episode 2, got %+v", afterEpisodeTwo.Leaderboard[0])
+	}
+
+	current, status := fetch("")
+	if status != http.StatusOK {
+		t.Fatalf("current leaderboard status = %d", status)
+	}
+	if len(current.Leaderboard) != 2 || current.Leaderboard[0].ParticipantName != "Beta" || current.Leaderboard[0].TotalPoints != 9 {
+		t.Fatalf("expected Beta to lead after the journey reward, got %+v", current.Leaderboard)
+	}
+	leader, trailer := current.Leaderboard[0], current.Leaderboard[1]
+	if leader.Rank != 1 || leader.PreviousRank == nil || *leader.PreviousRank != 2 || leader.RankChange == nil || *leader.RankChange != 1 {
+		t.Fatalf("expected Beta to climb one rank, got %+v", leader)
+	}
+	if trailer.Rank != 2 || trailer.RankChange == nil || *trailer.RankChange != -1 {
+		t.Fatalf("expected Alpha to drop one rank, got %+v", trailer)
+	}
+
+	if _, status := fetch("?episode=1&as_of=2026-03-12T00:00:00Z"); status != http.StatusBadRequest {
+		t.Fatalf("expected combined as_of and episode to fail with 400, got %d", status)
+	}
+	if _, status := fetch("?episode=9"); status != http.StatusNotFound {
+		t.Fatalf("expected unknown episode to fail with 404, got %d", status)
+	}
+}
+
+func TestStagedImportValidatesBeforeApply(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	router := httpapi.New(pool).Router()
+
+	type importResponse struct {
+		Import struct {
+			ID         string  `json:"id"`
+			InstanceID *string `json:"instance_id"`
+			Status     string  `json:"status"`
+			Errors     []struct {
+				Submission int    `json:"submission"`
+				Message    string `json:"message"`
+			} `json:"errors"`
+			Preview *struct {
+				Contestants  []string `json:"contestants"`
+				Participants []struct {
+					Name  string   `json:"name"`
+					Picks []string `json:"picks"`
+				} `json:"participants"`
+			} `json:"preview"`
+		} `json:"import"`
+	}
+	decode := func(recorder *httptest.ResponseRecorder) importResponse {
+		var response importResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("unmarshal import response: %v", err)
+		}
+		return response
+	}
+
+	stageRecorder := httptest.NewRecorder()
+	router.ServeHTTP(stageRecorder, authorizedJSONRequest(http.MethodPost, "/instances/import", `{"season":51,"name":"Staged Pool","submissions":[{"participant_name":"Brain","rankings":["Alpha One","Bravo Two"]},{"participant_name":"Keith","rankings":["Bravo","Alpha"]}]}`, "", ""))
+	if stageRecorder.Code != http.StatusCreated {
+		t.Fatalf("stage import status = %d, body = %s", stageRecorder.Code, stageRecorder.Body.String())
+	}
+	staged := decode(stageRecorder)
+	if staged.Import.Status != "validated" || staged.Import.InstanceID != nil {
+		t.Fatalf("expected validated import without an instance, got %+v", staged.Import)
+	}
+	if staged.Import.Preview == nil || len(staged.Import.Preview.Participants) != 2 || staged.Import.Preview.Participants[0].Name != "Bryan" {
+		t.Fatalf("expected preview with normalized participants, got %+v", staged.Import.Preview)
+	}
+	instances, err := db.New(pool).ListInstances(ctx)
+	if err != nil {
+		t.Fatalf("list instances: %v", err)
+	}
+	if len(instances) != 0 {
+		t.Fatalf("expected staging to leave instances untouched, got %+v", instances)
+	}
+
+	getRecorder := httptest.NewRecorder()
+	router.ServeHTTP(getRecorder, httptest.NewRequest(http.MethodGet, "/imports/"+staged.Import.ID, nil))
+	if getRecorder.Code != http.StatusOK || decode(getRecorder).Import.Status != "validated" {
+		t.Fatalf("get import status = %d, body = %s", getRecorder.Code, getRecorder.Body.String())
+	}
+
+	applyRecorder := httptest.NewRecorder()
+	router.ServeHTTP(applyRecorder, authorizedJSONRequest(http.MethodPost, "/imports/"+staged.Import.ID+"/apply", `{}`, "", ""))
+	if applyRecorder.Code != http.StatusOK {
+		t.Fatalf("apply import status = %d, body = %s", applyRecorder.Code, applyRecorder.Body.String())
+	}
+	applied := decode(applyRecorder)
+	if applied.Import.Status != "applied" || applied.Import.InstanceID == nil {
+		t.Fatalf("expected applied import linked to an instance, got %+v", applied.Import)
+	}
+	participants, err := db.New(pool).ListParticipantsByInstance(ctx, pgtype.UUID{Bytes: uuid.MustParse(*applied.Import.InstanceID), Valid: true})
+	if err != nil {
+		t.Fatalf("list participants: %v", err)
+	}
+	if len(participants) != 2 {
+		t.Fatalf("expected two imported participants, got %+v", participants)
+	}
+
+	reapplyRecorder := httptest.NewRecorder()
+	router.ServeHTTP(reapplyRecorder, authorizedJSONRequest(http.MethodPost, "/imports/"+staged.Import.ID+"/apply", `{}`, "", ""))
+	if reapplyRecorder.Code != http.StatusConflict {
+		t.Fatalf("expected re-apply to conflict, got %d body=%s", reapplyRecorder.Code, reapplyRecorder.Body.String())
+	}
+
+	invalidRecorder := httptest.NewRecorder()
+	router.ServeHTTP(invalidRecorder, authorizedJSONRequest(http.MethodPost, "/instances/import", `{"season":52,"submissions":[{"participant_name":"Bryan","rankings":["Alpha","Alpha"]},{"participant_name":"brain","rankings":["Alpha"]}]}`, "", ""))
+	if invalidRecorder.Code != http.StatusCreated {
+		t.Fatalf("stage invalid import status = %d, body = %s", invalidRecorder.Code, invalidRecorder.Body.String())
+	}
+	invalid := decode(invalidRecorder)
+	if invalid.Import.Status != "failed" || len(invalid.Import.Errors) != 2 {
+		t.Fatalf("expected failed import with two errors, got %+v", invalid.Import)
+	}
+	blockedRecorder := httptest.NewRecorder()
+	router.ServeHTTP(blockedRecorder, authorizedJSONRequest(http.MethodPost, "/imports/"+invalid.Import.ID+"/apply", `{}`, "", ""))
+	if blockedRecorder.Code != http.StatusConflict {
+		t.Fatalf("expected failed import apply to conflict, got %d body=%s", blockedRecorder.Code, blockedRecorder.Body.String())
+	}
+}
+
+func TestMergeReimportKeepsGameplayAndDiffsDrafts(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	router := httpapi.New(pool).Router()
+
+	type appliedImport struct {
+		Instance struct {
+			ID string `json:"id"`
+		} `json:"instance"`
+		Diff json.RawMessage `json:"diff"`
+	}
+	stageAndApply := func(body, applyBody string) appliedImport {
+		t.Helper()
+		stageRecorder := httptest.NewRecorder()
+		router.ServeHTTP(stageRecorder, authorizedJSONRequest(http.MethodPost, "/instances/import", body, "", ""))
+		if stageRecorder.Code != http.StatusCreated {
+			t.Fatalf("stage import status = %d, body = %s", stageRecorder.Code, stageRecorder.Body.String())
+		}
+		var staged struct {
+			Import struct {
+				ID string `json:"id"`
+			} `json:"import"`
+		}
+		if err := json.Unmarshal(stageRecorder.Body.Bytes(), &staged); err != nil {
+			t.Fatalf("unmarshal staged import: %v", err)
+		}
+		applyRecorder := httptest.NewRecorder()
+		router.ServeHTTP(applyRecorder, authorizedJSONRequest(http.MethodPost, "/imports/"+staged.Import.ID+"/apply", applyBody, "", ""))
+		if applyRecorder.Code != http.StatusOK {
+			t.Fatalf("apply import status = %d, body = %s", applyRecorder.Code, applyRecorder.Body.String())
+		}
+		var applied appliedImport
+		if err := json.Unmarshal(applyRecorder.Body.Bytes(), &applied); err != nil {
+			t.Fatalf("unmarshal applied import: %v", err)
+		}
+		return applied
+	}
+
+	first := stageAndApply(`{"season":51,"name":"Merge Pool","submissions":[{"participant_name":"Bryan","rankings":["Alpha","Bravo","Charlie"]},{"participant_name":"Keith","rankings":["Bravo","Alpha","Charlie"]}]}`, `{}`)
+	instanceID := first.Instance.ID
+	instancePGID := pgtype.UUID{Bytes: uuid.MustParse(instanceID), Valid: true}
+	createActivityForTest(t, ctx, queries, instancePGID, time.Date(2026, time.March, 21, 12, 0, 0, 0, time.UTC), nil, "journey", "Journey 1")
+
+	merged := stageAndApply(`{"season":51,"name":"Merge Pool","submissions":[{"participant_name":"Bryan","rankings":["Alpha","Bravo","Charlie"]},{"participant_name":"Keith","rankings":["Alpha","Bravo","Charlie"]},{"participant_name":"Amanda","rankings":["Charlie","Alpha"]}]}`, `{"mode":"merge"}`)
+	if merged.Instance.ID != instanceID {
+		t.Fatalf("expected merge to keep instance %s, got %s", instanceID, merged.Instance.ID)
+	}
+
+	var diff struct {
+		InstanceCreated bool `json:"instance_created"`
+		Participants    []struct {
+			ParticipantName string `json:"participant_name"`
+			Created         bool   `json:"created"`
+			Added           []any  `json:"added"`
+			Changed         []struct {
+				Position int    `json:"position"`
+				From     string `json:"from"`
+				To       string `json:"to"`
+			} `json:"changed"`
+		} `json:"participants"`
+		UnchangedParticipants []string `json:"unchanged_participants"`
+	}
+	if err := json.Unmarshal(merged.Diff, &diff); err != nil {
+		t.Fatalf("unmarshal diff: %v", err)
+	}
+	if diff.InstanceCreated || len(diff.UnchangedParticipants) != 1 || diff.UnchangedParticipants[0] != "Bryan" {
+		t.Fatalf("expected Bryan unchanged on existing instance, got %+v", diff)
+	}
+	if len(diff.Participants) != 2 || diff.Participants[0].ParticipantName != "Keith" || len(diff.Participants[0].Changed) != 2 {
+		t.Fatalf("expected Keith's swapped picks in diff, got %+v", diff.Participants)
+	}
+	if diff.Participants[0].Changed[0].From != "Bravo" || diff.Participants[0].Changed[0].To != "Alpha" {
+		t.Fatalf("unexpected Keith change: %+v", diff.Participants[0].Changed[0])
+	}
+	if !diff.Participants[1].Created || len(diff.Participants[1].Added) != 2 {
+		t.Fatalf("expected Amanda to be created with two picks, got %+v", diff.Participants[1])
+	}
+
+	activities, err := queries.ListInstanceActivitiesByInstance(ctx, instancePGID)
+	if err != nil {
+		t.Fatalf("list activities: %v", err)
+	}
+	if len(activities) != 1 {
+		t.Fatalf("expected merge to keep gameplay activities, got %+v", activities)
+	}
+	participants, err := queries.ListParticipantsByInstance(ctx, instancePGID)
+	if err != nil {
+		t.Fatalf("list participants: %v", err)
+	}
+	if len(participants) != 3 {
+		t.Fatalf("expected three participants after merge, got %+v", participants)
+	}
+
+	// Once the draft deadline passes, a merge that rewrites drafts needs an
+	// instance admin and a reason, and records an override.
+	if _, err := queries.SetInstanceDraftDeadline(ctx, db.SetInstanceDraftDeadlineParams{DraftDeadline: timestamptz(time.Now().UTC().Add(-time.Hour)), ID: instancePGID}); err != nil {
+		t.Fatalf("set draft deadline: %v", err)
+	}
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instancePGID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	stageRecorder := httptest.NewRecorder()
+	router.ServeHTTP(stageRecorder, authorizedJSONRequest(http.MethodPost, "/instances/import", `{"season":51,"name":"Merge Pool","submissions":[{"participant_name":"Bryan","rankings":["Charlie","Bravo","Alpha"]}]}`, "", ""))
+	if stageRecorder.Code != http.StatusCreated {
+		t.Fatalf("stage late import status = %d, body = %s", stageRecorder.Code, stageRecorder.Body.String())
+	}
+	var late struct {
+		Import struct {
+			ID string `json:"id"`
+		} `json:"import"`
+	}
+	if err := json.Unmarshal(stageRecorder.Body.Bytes(), &late); err != nil {
+		t.Fatalf("unmarshal late import: %v", err)
+	}
+	for _, attempt := range []struct {
+		body          string
+		discordUserID string
+		wantStatus    int
+	}{
+		{body: `{"mode":"merge","override_reason":"late ballot"}`, discordUserID: "player-discord", wantStatus: http.StatusForbidden},
+		{body: `{"mode":"merge"}`, discordUserID: "admin-discord", wantStatus: http.StatusBadRequest},
+		{body: `{"mode":"merge","override_reason":"late ballot"}`, discordUserID: "admin-discord", wantStatus: http.StatusOK},
+	} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPost, "/imports/"+late.Import.ID+"/apply", attempt.body, "", attempt.discordUserID))
+		if recorder.Code != attempt.wantStatus {
+			t.Fatalf("late apply %s as %s status = %d, want %d, body = %s", attempt.body, attempt.discordUserID, recorder.Code, attempt.wantStatus, recorder.Body.String())
+		}
+	}
+	overrides, err := queries.ListDraftOverridesByInstance(ctx, instancePGID)
+	if err != nil {
+		t.Fatalf("list draft overrides: %v", err)
+	}
+	if len(overrides) != 1 || overrides[0].ParticipantName != "Bryan" || overrides[0].Reason != "late ballot" || overrides[0].ActorDiscordUserID != "admin-discord" {
+		t.Fatalf("expected one override for Bryan's late draft, got %+v", overrides)
+	}
+
+	events, err := queries.ListAuditEventsByInstance(ctx, db.ListAuditEventsByInstanceParams{
+		InstanceID: instancePGID,
+		Action:     pgtype.Text{String: "instance.import", Valid: true},
+		RowLimit:   10,
+	})
+	if err != nil {
+		t.Fatalf("list import audit events: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected an audit event for each apply, got %+v", events)
+	}
+	if events[0].ActorDiscordUserID.String != "admin-discord" || !strings.Contains(string(events[0].After), `"participant_name":"Bryan"`) || !strings.Contains(string(events[0].After), `"mode":"merge"`) {
+		t.Fatalf("unexpected late import audit event: actor=%v after=%s", events[0].ActorDiscordUserID, events[0].After)
+	}
+	if !strings.Contains(string(events[1].After), `"participant_name":"Keith"`) {
+		t.Fatalf("expected the merge audit event to carry its diff, got %s", events[1].After)
+	}
+}
+
+func TestLeaderboardUsesInstanceScoringStrategy(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	instance := createInstanceForTest(t, ctx, queries, "Strategy Pool", 53)
+	contestantA := createContestantForTest(t, ctx, queries, instance.ID, "Contestant A")
+	contestantB := createContestantForTest(t, ctx, queries, instance.ID, "Contestant B")
+	contestantC := createContestantForTest(t, ctx, queries, instance.ID, "Contestant C")
+	alpha := createParticipantForTest(t, ctx, queries, instance.ID, "Alpha")
+	createDraftPickForTest(t, ctx, queries, instance.ID, alpha.ID, contestantA.ID, 1)
+	createDraftPickForTest(t, ctx, queries, instance.ID, alpha.ID, contestantB.ID, 2)
+	createDraftPickForTest(t, ctx, queries, instance.ID, alpha.ID, contestantC.ID, 3)
+	upsertOutcomeForTest(t, ctx, queries, instance.ID, 1, contestantA.ID)
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+
+	router := httpapi.New(pool).Router()
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+	readLeaderboard := func() (string, int) {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodGet, instancePath+"/leaderboard", "", "", ""))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("leaderboard status = %d, body = %s", recorder.Code, recorder.Body.String())
+		}
+		var response struct {
+			ScoringStrategy string `json:"scoring_strategy"`
+			Leaderboard     []struct {
+				DraftPoints int `json:"draft_points"`
+			} `json:"leaderboard"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("unmarshal leaderboard: %v", err)
+		}
+		if len(response.Leaderboard) != 1 {
+			t.Fatalf("expected one leaderboard row, got %+v", response.Leaderboard)
+		}
+		return response.ScoringStrategy, response.Leaderboard[0].DraftPoints
+	}
+
+	if strategy, draftPoints := readLeaderboard(); strategy != "distance" || draftPoints != 3 {
+		t.Fatalf("expected distance scoring worth 3, got %s worth %d", strategy, draftPoints)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, instancePath+"/scoring-strategy", `{"scoring_strategy":"winner-double"}`, "", "player-discord"))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected non-admin strategy change to be forbidden, got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, instancePath+"/scoring-strategy", `{"scoring_strategy":"coin-flip"}`, "", "admin-discord"))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected unknown strategy to be rejected, got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, instancePath+"/scoring-strategy", `{"scoring_strategy":"winner-double"}`, "", "admin-discord"))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("set scoring strategy status = %d, body = %s", recorder.Code, recorder.Body.String())
+	}
+
+	if strategy, draftPoints := readLeaderboard(); strategy != "winner-double" || draftPoints != 6 {
+		t.Fatalf("expected winner-double scoring worth 6, got %s worth %d", strategy, draftPoints)
+	}
+}
+
+func TestReplaceDraftEnforcesDeadlineWithAdminOverrides(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	instance := createInstanceForTest(t, ctx, queries, "Deadline Pool", 52)
+	alpha := createContestantForTest(t, ctx, queries, instance.ID, "Alpha")
+	bravo := createContestantForTest(t, ctx, queries, instance.ID, "Bravo")
+	bryan := createParticipantForTest(t, ctx, queries, instance.ID, "Bryan")
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	now := time.Now().UTC()
+	createEpisodeForTest(t, ctx, queries, instance.ID, 0, "Preseason", now.Add(-48*time.Hour))
+	firstEpisode := createEpisodeForTest(t, ctx, queries, instance.ID, 1, "Episode 1", now.Add(48*time.Hour))
+
+	router := httpapi.New(pool).Router()
+	instanceID := uuid.UUID(instance.ID.Bytes).String()
+	draftPath := "/instances/" + instanceID + "/drafts/" + uuid.UUID(bryan.ID.Bytes).String()
+	alphaID := uuid.UUID(alpha.ID.Bytes).String()
+	bravoID := uuid.UUID(bravo.ID.Bytes).String()
+
+	type deadlineResponse struct {
+		DraftDeadline *string `json:"draft_deadline"`
+		Source        string  `json:"source"`
+		Locked        bool    `json:"locked"`
+	}
+	readDeadline := func(recorder *httptest.ResponseRecorder) deadlineResponse {
+		t.Helper()
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("draft deadline status = %d, body = %s", recorder.Code, recorder.Body.String())
+		}
+		var response deadlineResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("unmarshal draft deadline: %v", err)
+		}
+		return response
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodGet, "/instances/"+instanceID+"/draft-deadline", "", "", ""))
+	defaultDeadline := readDeadline(recorder)
+	if defaultDeadline.Source != "first_episode" || defaultDeadline.Locked || defaultDeadline.DraftDeadline == nil {
+		t.Fatalf("unexpected default deadline: %+v", defaultDeadline)
+	}
+	if *defaultDeadline.DraftDeadline != firstEpisode.AirsAt.Time.UTC().Format(time.RFC3339) {
+		t.Fatalf("expected deadline at first episode %s, got %s", firstEpisode.AirsAt.Time.UTC().Format(time.RFC3339), *defaultDeadline.DraftDeadline)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, draftPath, `{"contestant_ids":["`+alphaID+`","`+bravoID+`"]}`, "", "player-discord"))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("draft before deadline status = %d, body = %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, "/instances/"+instanceID+"/draft-deadline", `{"draft_deadline":"`+now.Add(-time.Hour).Format(time.RFC3339)+`"}`, "", "player-discord"))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected non-admin deadline update to be forbidden, got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, "/instances/"+instanceID+"/draft-deadline", `{"draft_deadline":"`+now.Add(-time.Hour).Format(time.RFC3339)+`"}`, "", "admin-discord"))
+	explicitDeadline := readDeadline(recorder)
+	if explicitDeadline.Source != "instance" || !explicitDeadline.Locked {
+		t.Fatalf("unexpected explicit deadline: %+v", explicitDeadline)
+	}
+
+	swapped := `{"contestant_ids":["` + bravoID + `","` + alphaID + `"]}`
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, draftPath, swapped, "", "player-discord"))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected player draft after deadline to be forbidden, got %d body = %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, draftPath, swapped, "", "admin-discord"))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected admin override without reason to be rejected, got %d body = %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, draftPath, `{"contestant_ids":["`+bravoID+`","`+alphaID+`"],"override_reason":"Late submission approved"}`, "", "admin-discord"))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("admin override status = %d, body = %s", recorder.Code, recorder.Body.String())
+	}
+
+	picks, err := queries.ListDraftPicksForParticipant(ctx, bryan.ID)
+	if err != nil {
+		t.Fatalf("list draft picks: %v", err)
+	}
+	if len(picks) != 2 || picks[0].ContestantID != bravo.ID {
+		t.Fatalf("expected override to rewrite draft, got %+v", picks)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodGet, "/instances/"+instanceID+"/draft-overrides", "", "", "admin-discord"))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("draft overrides status = %d, body = %s", recorder.Code, recorder.Body.String())
+	}
+	var overrides struct {
+		Overrides []struct {
+			ParticipantName       string   `json:"participant_name"`
+			ActorDiscordUserID    string   `json:"actor_discord_user_id"`
+			Reason                string   `json:"reason"`
+			PreviousContestantIDs []string `json:"previous_contestant_ids"`
+			ContestantIDs         []string `json:"contestant_ids"`
+		} `json:"overrides"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &overrides); err != nil {
+		t.Fatalf("unmarshal draft overrides: %v", err)
+	}
+	if len(overrides.Overrides) != 1 {
+		t.Fatalf("expected one override, got %+v", overrides.Overrides)
+	}
+	override := overrides.Overrides[0]
+	if override.ParticipantName != "Bryan" || override.ActorDiscordUserID != "admin-discord" || override.Reason != "Late submission approved" {
+		t.Fatalf("unexpected override: %+v", override)
+	}
+	if len(override.PreviousContestantIDs) != 2 || override.PreviousContestantIDs[0] != alphaID || override.ContestantIDs[0] != bravoID {
+		t.Fatalf("expected override to record previous and new picks, got %+v", override)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, "/instances/"+instanceID+"/draft-deadline", `{"draft_deadline":null}`, "", "admin-discord"))
+	resetDeadline := readDeadline(recorder)
+	if resetDeadline.Source != "first_episode" || resetDeadline.Locked {
+		t.Fatalf("expected reset deadline to fall back to first episode, got %+v", resetDeadline)
+	}
+}
+
+func TestWhatIfLeaderboardProjectsWithoutPersisting(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	instance := createInstanceForTest(t, ctx, queries, "What If Pool", 54)
+	contestantA := createContestantForTest(t, ctx, queries, instance.ID, "Contestant A")
+	contestantB := createContestantForTest(t, ctx, queries, instance.ID, "Contestant B")
+	contestantC := createContestantForTest(t, ctx, queries, instance.ID, "Contestant C")
+	alpha := createParticipantForTest(t, ctx, queries, instance.ID, "Alpha")
+	bravo := createParticipantForTest(t, ctx, queries, instance.ID, "Bravo")
+	createDraftPickForTest(t, ctx, queries, instance.ID, alpha.ID, contestantA.ID, 1)
+	createDraftPickForTest(t, ctx, queries, instance.ID, alpha.ID, contestantB.ID, 2)
+	createDraftPickForTest(t, ctx, queries, instance.ID, alpha.ID, contestantC.ID, 3)
+	createDraftPickForTest(t, ctx, queries, instance.ID, bravo.ID, contestantC.ID, 1)
+	createDraftPickForTest(t, ctx, queries, instance.ID, bravo.ID, contestantB.ID, 2)
+	createDraftPickForTest(t, ctx, queries, instance.ID, bravo.ID, contestantA.ID, 3)
+
+	router := httpapi.New(pool).Router()
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPost, instancePath+"/leaderboard/what-if", `{"eliminations":[{"contestant_name":"contestant a"}]}`, "", ""))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("what-if status = %d, body = %s", recorder.Code, recorder.Body.String())
+	}
+	var response struct {
+		ScoringStrategy string `json:"scoring_strategy"`
+		Eliminations    []struct {
+			ContestantName string `json:"contestant_name"`
+			Position       int    `json:"position"`
+		} `json:"eliminations"`
+		Leaderboard []struct {
+			ParticipantName string `json:"participant_name"`
+			Rank            int    `json:"rank"`
+			CurrentRank     int    `json:"current_rank"`
+			RankChange      int    `json:"rank_change"`
+			TotalPoints     int    `json:"total_points"`
+			PointsChange    int    `json:"points_change"`
+		} `json:"leaderboard"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unmarshal what-if: %v", err)
+	}
+	if response.ScoringStrategy != "distance" {
+		t.Fatalf("expected distance strategy, got %q", response.ScoringStrategy)
+	}
+	if len(response.Eliminations) != 1 || response.Eliminations[0].ContestantName != "Contestant A" || response.Eliminations[0].Position != 3 {
+		t.Fatalf("unexpected eliminations: %+v", response.Eliminations)
+	}
+	if len(response.Leaderboard) != 2 {
+		t.Fatalf("expected two leaderboard rows, got %+v", response.Leaderboard)
+	}
+	leader := response.Leaderboard[0]
+	if leader.ParticipantName != "Bravo" || leader.Rank != 1 || leader.TotalPoints != 1 || leader.PointsChange != 1 || leader.CurrentRank != 1 || leader.RankChange != 0 {
+		t.Fatalf("unexpected projected leader: %+v", leader)
+	}
+	trailer := response.Leaderboard[1]
+	if trailer.ParticipantName != "Alpha" || trailer.Rank != 2 || trailer.RankChange != -1 || trailer.PointsChange != 0 {
+		t.Fatalf("unexpected projected trailer: %+v", trailer)
+	}
+
+	outcomes, err := queries.ListOutcomePositionsByInstance(ctx, instance.ID)
+	if err != nil {
+		t.Fatalf("list outcomes: %v", err)
+	}
+	for _, outcome := range outcomes {
+		if outcome.ContestantID.Valid {
+			t.Fatalf("expected what-if to leave outcomes untouched, got %+v", outcomes)
+		}
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPost, instancePath+"/leaderboard/what-if", `{"eliminations":[{"contestant_name":"Nobody"}]}`, "", ""))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected unknown contestant to be rejected, got %d", recorder.Code)
+	}
+}
+
+func TestWinProbabilitiesUseAdminOddsAndSeed(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	instance := createInstanceForTest(t, ctx, queries, "Odds Pool", 55)
+	contestantA := createContestantForTest(t, ctx, queries, instance.ID, "Contestant A")
+	contestantB := createContestantForTest(t, ctx, queries, instance.ID, "Contestant B")
+	contestantC := createContestantForTest(t, ctx, queries, instance.ID, "Contestant C")
+	alpha := createParticipantForTest(t, ctx, queries, instance.ID, "Alpha")
+	bravo := createParticipantForTest(t, ctx, queries, instance.ID, "Bravo")
+	createDraftPickForTest(t, ctx, queries, instance.ID, alpha.ID, contestantA.ID, 1)
+	createDraftPickForTest(t, ctx, queries, instance.ID, alpha.ID, contestantB.ID, 2)
+	createDraftPickForTest(t, ctx, queries, instance.ID, alpha.ID, contestantC.ID, 3)
+	createDraftPickForTest(t, ctx, queries, instance.ID, bravo.ID, contestantC.ID, 1)
+	createDraftPickForTest(t, ctx, queries, instance.ID, bravo.ID, contestantB.ID, 2)
+	createDraftPickForTest(t, ctx, queries, instance.ID, bravo.ID, contestantA.ID, 3)
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+
+	router := httpapi.New(pool).Router()
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+	oddsBody := fmt.Sprintf(`{"odds":[{"contestant_id":%q,"odds":50},{"contestant_id":%q,"odds":1},{"contestant_id":%q,"odds":1}]}`,
+		uuid.UUID(contestantA.ID.Bytes).String(), uuid.UUID(contestantB.ID.Bytes).String(), uuid.UUID(contestantC.ID.Bytes).String())
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, instancePath+"/contestant-odds", oddsBody, "", "player-discord"))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected non-admin odds change to be forbidden, got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPut, instancePath+"/contestant-odds", oddsBody, "", "admin-discord"))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("set odds status = %d, body = %s", recorder.Code, recorder.Body.String())
+	}
+
+	type probabilitiesResponse struct {
+		Iterations    int    `json:"iterations"`
+		Seed          uint64 `json:"seed"`
+		Weighted      bool   `json:"weighted"`
+		Probabilities []struct {
+			ParticipantName     string  `json:"participant_name"`
+			WinProbability      float64 `json:"win_probability"`
+			ExpectedTotalPoints float64 `json:"expected_total_points"`
+		} `json:"probabilities"`
+	}
+	readProbabilities := func(query string) probabilitiesResponse {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodGet, instancePath+"/win-probabilities"+query, "", "", ""))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("win probabilities status = %d, body = %s", recorder.Code, recorder.Body.String())
+		}
+		var response probabilitiesResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("unmarshal win probabilities: %v", err)
+		}
+		return response
+	}
+
+	weighted := readProbabilities("?iterations=2000&seed=42")
+	if !weighted.Weighted || weighted.Iterations != 2000 || weighted.Seed != 42 {
+		t.Fatalf("unexpected simulation settings: %+v", weighted)
+	}
+	if len(weighted.Probabilities) != 2 || weighted.Probabilities[0].ParticipantName != "Alpha" || weighted.Probabilities[0].WinProbability < 0.9 {
+		t.Fatalf("expected Alpha to be a heavy favourite, got %+v", weighted.Probabilities)
+	}
+	if again := readProbabilities("?iterations=2000&seed=42"); fmt.Sprint(again) != fmt.Sprint(weighted) {
+		t.Fatalf("expected the same seed to reproduce results, got %+v and %+v", weighted, again)
+	}
+	if unweighted := readProbabilities("?iterations=2000&seed=42&weighted=false"); unweighted.Weighted || unweighted.Probabilities[0].WinProbability > 0.65 {
+		t.Fatalf("expected an unweighted simulation to be close to even, got %+v", unweighted)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodGet, instancePath+"/win-probabilities?iterations=0", "", "", ""))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected invalid iterations to be rejected, got %d", recorder.Code)
+	}
+}
+
+func TestInstanceLifecycleGatesWrites(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	router := httpapi.New(pool).Router()
+
+	createRecorder := httptest.NewRecorder()
+	router.ServeHTTP(createRecorder, authorizedJSONRequest(http.MethodPost, "/instances", `{"name":"Lifecycle Pool","season":50,"contestants":["Alpha","Bravo"]}`, "", ""))
+	if createRecorder.Code != http.StatusCreated {
+		t.Fatalf("create instance status = %d, body = %s", createRecorder.Code, createRecorder.Body.String())
+	}
+	var created struct {
+		Instance struct {
+			ID    string `json:"id"`
+			State string `json:"state"`
+		} `json:"instance"`
+	}
+	if err := json.Unmarshal(createRecorder.Body.Bytes(), &created); err != nil {
+		t.Fatalf("unmarshal created instance: %v", err)
+	}
+	if created.Instance.State != "setup" {
+		t.Fatalf("expected new instance to start in setup, got %q", created.Instance.State)
+	}
+	instanceID := uuid.MustParse(created.Instance.ID)
+	instancePath := "/instances/" + created.Instance.ID
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: pgtype.UUID{Bytes: instanceID, Valid: true}, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	contestants, err := queries.ListContestantsByInstance(ctx, pgtype.UUID{Bytes: instanceID, Valid: true})
+	if err != nil {
+		t.Fatalf("list contestants: %v", err)
+	}
+	outcomeBody := fmt.Sprintf(`{"contestant_id":"%s"}`, uuid.UUID(contestants[0].ID.Bytes).String())
+
+	expectStatus := func(name, method, path, body, discordUserID string, want int) {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(method, path, body, "", discordUserID))
+		if recorder.Code != want {
+			t.Fatalf("%s status = %d, want %d, body = %s", name, recorder.Code, want, recorder.Body.String())
+		}
+	}
+	setState := func(state string, want int) {
+		t.Helper()
+		expectStatus("move to "+state, http.MethodPut, instancePath+"/state", fmt.Sprintf(`{"state":%q}`, state), "admin-discord", want)
+	}
+
+	expectStatus("outcome during setup", http.MethodPut, instancePath+"/outcomes/2", outcomeBody, "admin-discord", http.StatusConflict)
+	expectStatus("non-admin transition", http.MethodPut, instancePath+"/state", `{"state":"drafting"}`, "player-discord", http.StatusForbidden)
+	setState("active", http.StatusConflict)
+	setState("drafting", http.StatusOK)
+	setState("active", http.StatusOK)
+
+	expectStatus("contestant while active", http.MethodPost, instancePath+"/contestants", `{"name":"Charlie"}`, "", http.StatusCreated)
+	expectStatus("outcome while active", http.MethodPut, instancePath+"/outcomes/3", outcomeBody, "admin-discord", http.StatusOK)
+
+	setState("completed", http.StatusOK)
+	expectStatus("contestant after completion", http.MethodPost, instancePath+"/contestants", `{"name":"Delta"}`, "", http.StatusConflict)
+	expectStatus("loan after completion", http.MethodPost, instancePath+"/loan-shark/me/borrow", `{"points":1}`, "player-discord", http.StatusConflict)
+
+	setState("archived", http.StatusOK)
+	expectStatus("outcome after archive", http.MethodPut, instancePath+"/outcomes/3", outcomeBody, "admin-discord", http.StatusConflict)
+
+	stateRecorder := httptest.NewRecorder()
+	router.ServeHTTP(stateRecorder, httptest.NewRequest(http.MethodGet, instancePath+"/state", nil))
+	if stateRecorder.Code != http.StatusOK {
+		t.Fatalf("get state status = %d, body = %s", stateRecorder.Code, stateRecorder.Body.String())
+	}
+	var state struct {
+		State struct {
+			State      string   `json:"state"`
+			NextStates []string `json:"next_states"`
+		} `json:"state"`
+	}
+	if err := json.Unmarshal(stateRecorder.Body.Bytes(), &state); err != nil {
+		t.Fatalf("unmarshal state: %v", err)
+	}
+	if state.State.State != "archived" || len(state.State.NextStates) != 1 || state.State.NextStates[0] != "completed" {
+		t.Fatalf("unexpected archived state response: %+v", state.State)
+	}
+}
+
+func TestEpisodeManagementGuardsBoundaries(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	router := httpapi.New(pool).Router()
+	instance := createInstanceForTest(t, ctx, queries, "Episode Pool", 50)
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	episodesPath := "/instances/" + uuid.UUID(instance.ID.Bytes).String() + "/episodes"
+
+	sendEpisodeRequest := func(name, method, path, body, discordUserID string, want int) map[string]any {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(method, path, body, "", discordUserID))
+		if recorder.Code != want {
+			t.Fatalf("%s status = %d, want %d, body = %s", name, recorder.Code, want, recorder.Body.String())
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal %s: %v", name, err)
+		}
+		return payload
+	}
+	episodeID := func(payload map[string]any) string {
+		t.Helper()
+		episode, ok := payload["episode"].(map[string]any)
+		if !ok {
+			t.Fatalf("expected episode in payload, got %#v", payload)
+		}
+		return episode["id"].(string)
+	}
+
+	sendEpisodeRequest("non-admin create", http.MethodPost, episodesPath, `{"episode_number":1,"airs_at":"2026-03-05T01:00:00Z"}`, "player-discord", http.StatusForbidden)
+	first := episodeID(sendEpisodeRequest("create episode 1", http.MethodPost, episodesPath, `{"episode_number":1,"airs_at":"2026-03-05T01:00:00Z"}`, "admin-discord", http.StatusCreated))
+	second := episodeID(sendEpisodeRequest("create episode 2", http.MethodPost, episodesPath, `{"episode_number":2,"airs_at":"2026-03-12T01:00:00Z"}`, "admin-discord", http.StatusCreated))
+	sendEpisodeRequest("create out of order", http.MethodPost, episodesPath, `{"episode_number":3,"airs_at":"2026-03-10T01:00:00Z"}`, "admin-discord", http.StatusConflict)
+	sendEpisodeRequest("create duplicate", http.MethodPost, episodesPath, `{"episode_number":2,"airs_at":"2026-03-19T01:00:00Z"}`, "admin-discord", http.StatusConflict)
+
+	createActivityForTest(t, ctx, queries, instance.ID, time.Date(2026, time.March, 5, 1, 0, 0, 0, time.UTC), nil, "tribal_pony", "Episode 1 Ponies")
+
+	relabeled := sendEpisodeRequest("relabel episode 1", http.MethodPatch, episodesPath+"/"+first, `{"label":"Premiere"}`, "admin-discord", http.StatusOK)
+	if label := relabeled["episode"].(map[string]any)["label"]; label != "Premiere" {
+		t.Fatalf("expected relabeled episode, got %#v", relabeled)
+	}
+	sendEpisodeRequest("reschedule used episode", http.MethodPatch, episodesPath+"/"+first, `{"airs_at":"2026-03-06T01:00:00Z"}`, "admin-discord", http.StatusConflict)
+	sendEpisodeRequest("delete used episode", http.MethodDelete, episodesPath+"/"+first, "", "admin-discord", http.StatusConflict)
+	sendEpisodeRequest("reschedule before episode 1", http.MethodPatch, episodesPath+"/"+second, `{"airs_at":"2026-03-04T01:00:00Z"}`, "admin-discord", http.StatusConflict)
+	sendEpisodeRequest("reschedule episode 2", http.MethodPatch, episodesPath+"/"+second, `{"airs_at":"2026-03-13T01:00:00Z"}`, "admin-discord", http.StatusOK)
+	sendEpisodeRequest("delete episode 2", http.MethodDelete, episodesPath+"/"+second, "", "admin-discord", http.StatusOK)
+
+	listed := sendEpisodeRequest("list episodes", http.MethodGet, episodesPath, "", "", http.StatusOK)
+	episodes, ok := listed["episodes"].([]any)
+	if !ok || len(episodes) != 1 {
+		t.Fatalf("expected one remaining episode, got %#v", listed)
+	}
+}
+
+func TestBootstrapInstanceFromSeasonConfig(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	router := httpapi.New(pool).Router()
+	config, err := os.ReadFile("../../seeds/season-config.example.yaml")
+	if err != nil {
+		t.Fatalf("read season config: %v", err)
+	}
+
+	bootstrap := func(name, body, discordUserID string, want int) map[string]any {
+		t.Helper()
+		req := authorizedJSONRequest(http.MethodPost, "/instances/bootstrap", body, "", discordUserID)
+		req.Header.Set("Content-Type", "application/yaml")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != want {
+			t.Fatalf("%s status = %d, want %d, body = %s", name, recorder.Code, want, recorder.Body.String())
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal %s: %v", name, err)
+		}
+		return payload
+	}
+	instanceIDFrom := func(payload map[string]any) string {
+		t.Helper()
+		return payload["instance"].(map[string]any)["id"].(string)
+	}
+
+	created := bootstrap("first bootstrap", string(config), "", http.StatusCreated)
+	if status := created["bootstrap"].(map[string]any)["status"]; status != "created" {
+		t.Fatalf("expected created bootstrap, got %#v", created)
+	}
+	instanceID := uuid.MustParse(instanceIDFrom(created))
+	instancePGID := pgtype.UUID{Bytes: instanceID, Valid: true}
+
+	episodes, err := queries.ListInstanceEpisodes(ctx, instancePGID)
+	if err != nil {
+		t.Fatalf("list episodes: %v", err)
+	}
+	if len(episodes) != 3 || episodes[1].Label != "Episode 1" {
+		t.Fatalf("expected configured episodes, got %+v", episodes)
+	}
+	linked, err := queries.GetParticipantByDiscordUserID(ctx, db.GetParticipantByDiscordUserIDParams{InstanceID: instancePGID, DiscordUserID: pgtype.Text{String: "100000000000000002", Valid: true}})
+	if err != nil || linked.Name != "Morgan" {
+		t.Fatalf("expected Morgan linked to discord user, got %+v, err %v", linked, err)
+	}
+	isAdmin, err := queries.IsInstanceAdmin(ctx, db.IsInstanceAdminParams{InstanceID: instancePGID, DiscordUserID: "100000000000000001"})
+	if err != nil || !isAdmin {
+		t.Fatalf("expected configured admin, got %v, err %v", isAdmin, err)
+	}
+	activities, err := queries.ListInstanceActivitiesByType(ctx, db.ListInstanceActivitiesByTypeParams{InstanceID: instancePGID, ActivityType: "stir_the_pot"})
+	if err != nil || len(activities) != 1 || !strings.Contains(string(activities[0].Metadata), `"reward_tiers"`) {
+		t.Fatalf("expected stir the pot activity with reward tiers, got %+v, err %v", activities, err)
+	}
+
+	unchanged := bootstrap("repeat bootstrap", string(config), "100000000000000001", http.StatusOK)
+	if status := unchanged["bootstrap"].(map[string]any)["status"]; status != "unchanged" || instanceIDFrom(unchanged) != instanceID.String() {
+		t.Fatalf("expected unchanged bootstrap of the same instance, got %#v", unchanged)
+	}
+
+	webhook, err := queries.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+		InstanceID: instancePGID,
+		Url:        "https://example.com/hook",
+		Secret:     "secret",
+		EventTypes: []string{},
+	})
+	if err != nil {
+		t.Fatalf("create webhook subscription: %v", err)
+	}
+
+	changed := strings.Replace(string(config), "  - Delta\n", "  - Delta\n  - Echo\n", 1)
+	bootstrap("non-admin replace", changed, "player-discord", http.StatusForbidden)
+	replaced := bootstrap("replace in setup", changed, "100000000000000001", http.StatusCreated)
+	if status := replaced["bootstrap"].(map[string]any)["status"]; status != "replaced" || instanceIDFrom(replaced) != instanceID.String() {
+		t.Fatalf("expected the instance replaced in place, got %#v", replaced)
+	}
+	contestants, err := queries.ListContestantsByInstance(ctx, instancePGID)
+	if err != nil || len(contestants) != 5 {
+		t.Fatalf("expected the replaced roster, got %+v, err %v", contestants, err)
+	}
+	webhooks, err := queries.ListWebhookSubscriptionsByInstance(ctx, instancePGID)
+	if err != nil || len(webhooks) != 1 || webhooks[0].ID != webhook.ID {
+		t.Fatalf("expected the webhook subscription to survive the replace, got %+v, err %v", webhooks, err)
+	}
+	events, err := queries.ListAuditEventsByInstance(ctx, db.ListAuditEventsByInstanceParams{
+		InstanceID: instancePGID,
+		Action:     pgtype.Text{String: "instance.bootstrap", Valid: true},
+		RowLimit:   10,
+	})
+	if err != nil || len(events) != 2 || !strings.Contains(string(events[0].After), `"status":"replaced"`) || events[0].ActorDiscordUserID.String != "100000000000000001" {
+		t.Fatalf("expected created and replaced bootstrap audit events, got %+v, err %v", events, err)
+	}
+
+	if _, err := queries.SetInstanceState(ctx, db.SetInstanceStateParams{State: "active", ID: instancePGID}); err != nil {
+		t.Fatalf("activate instance: %v", err)
+	}
+	bootstrap("replace while active", string(config), "100000000000000001", http.StatusConflict)
+}
+
+func TestSchedulerClosesStirThePotWhenEpisodeAirs(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	server := httpapi.New(pool)
+	router := server.Router()
+	instance := createInstanceForTest(t, ctx, queries, "Scheduler Pool", 50)
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	now := time.Now().UTC()
+	createEpisodeForTest(t, ctx, queries, instance.ID, 1, "Episode 1", now.Add(-time.Hour))
+	createEpisodeForTest(t, ctx, queries, instance.ID, 2, "Episode 2", now.Add(24*time.Hour))
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+
+	startRecorder := httptest.NewRecorder()
+	router.ServeHTTP(startRecorder, authorizedJSONRequest(http.MethodPost, instancePath+"/stir-the-pot/start", `{}`, "", "admin-discord"))
+	if startRecorder.Code != http.StatusCreated {
+		t.Fatalf("start stir the pot status = %d, body = %s", startRecorder.Code, startRecorder.Body.String())
+	}
+
+	clockAt := now
+	runner := scheduler.New(pool, server.JobHandlers(), scheduler.WithClock(scheduler.ClockFunc(func() time.Time { return clockAt })))
+	if _, err := runner.RunDue(ctx); err != nil {
+		t.Fatalf("run due jobs before episode 2: %v", err)
+	}
+	if stirThePotRoundClosedForTest(t, ctx, queries, instance.ID) {
+		t.Fatal("expected round to stay open before episode 2 airs")
+	}
+
+	clockAt = now.Add(25 * time.Hour)
+	if _, err := runner.RunDue(ctx); err != nil {
+		t.Fatalf("run due jobs after episode 2: %v", err)
+	}
+	if !stirThePotRoundClosedForTest(t, ctx, queries, instance.ID) {
+		t.Fatal("expected round closed after episode 2 airs")
+	}
+
+	type scheduledJob struct {
+		ID            string `json:"id"`
+		EpisodeNumber int32  `json:"episode_number"`
+		JobType       string `json:"job_type"`
+		Status        string `json:"status"`
+	}
+	listJobs := func() map[string]scheduledJob {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodGet, instancePath+"/scheduled-jobs", "", "", "admin-discord"))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("list scheduled jobs status = %d, body = %s", recorder.Code, recorder.Body.String())
+		}
+		var payload struct {
+			Jobs []scheduledJob `json:"jobs"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal scheduled jobs: %v", err)
+		}
+		jobs := make(map[string]scheduledJob, len(payload.Jobs))
+		for _, job := range payload.Jobs {
+			jobs[fmt.Sprintf("%s:%d", job.JobType, job.EpisodeNumber)] = job
+		}
+		return jobs
+	}
+	jobs := listJobs()
+	wantStatuses := map[string]string{
+		"close_stir_the_pot:1": "skipped",
+		"close_stir_the_pot:2": "succeeded",
+		"close_auction_lots:2": "skipped",
+		"episode_recap:1":      "skipped",
+		"episode_recap:2":      "succeeded",
+	}
+	for key, want := range wantStatuses {
+		if jobs[key].Status != want {
+			t.Fatalf("job %s status = %q, want %q (jobs %+v)", key, jobs[key].Status, want, jobs)
+		}
+	}
+
+	closeJob := jobs["close_stir_the_pot:2"]
+	runsRecorder := httptest.NewRecorder()
+	router.ServeHTTP(runsRecorder, authorizedJSONRequest(http.MethodGet, instancePath+"/scheduled-jobs/"+closeJob.ID+"/runs", "", "", "admin-discord"))
+	if runsRecorder.Code != http.StatusOK {
+		t.Fatalf("list job runs status = %d, body = %s", runsRecorder.Code, runsRecorder.Body.String())
+	}
+	var runs struct {
+		Runs []struct {
+			Status string         `json:"status"`
+			Result map[string]any `json:"result"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(runsRecorder.Body.Bytes(), &runs); err != nil {
+		t.Fatalf("unmarshal job runs: %v", err)
+	}
+	if len(runs.Runs) != 1 || runs.Runs[0].Status != "succeeded" || runs.Runs[0].Result["closed_rounds"] == nil {
+		t.Fatalf("expected one successful close run, got %+v", runs.Runs)
+	}
+
+	if _, err := runner.RunDue(ctx); err != nil {
+		t.Fatalf("rerun due jobs: %v", err)
+	}
+	for key, job := range listJobs() {
+		if job.Status != jobs[key].Status {
+			t.Fatalf("expected rerun to leave %s unchanged, got %q", key, job.Status)
+		}
+	}
+
+	retryPath := instancePath + "/scheduled-jobs/" + jobs["episode_recap:1"].ID + "/retry"
+	for _, tc := range []struct {
+		name          string
+		path          string
+		discordUserID string
+		want          int
+	}{
+		{name: "non-admin retry", path: retryPath, discordUserID: "player-discord", want: http.StatusForbidden},
+		{name: "retry succeeded job", path: instancePath + "/scheduled-jobs/" + closeJob.ID + "/retry", discordUserID: "admin-discord", want: http.StatusConflict},
+		{name: "retry skipped job", path: retryPath, discordUserID: "admin-discord", want: http.StatusOK},
+	} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPost, tc.path, "", "", tc.discordUserID))
+		if recorder.Code != tc.want {
+			t.Fatalf("%s status = %d, want %d, body = %s", tc.name, recorder.Code, tc.want, recorder.Body.String())
+		}
+	}
+	if status := listJobs()["episode_recap:1"].Status; status != "pending" {
+		t.Fatalf("expected retried job pending, got %q", status)
+	}
+}
+
+func stirThePotRoundClosedForTest(t *testing.T, ctx context.Context, queries *db.Queries, instanceID pgtype.UUID) bool {
+	t.Helper()
+	activities, err := queries.ListInstanceActivitiesByType(ctx, db.ListInstanceActivitiesByTypeParams{InstanceID: instanceID, ActivityType: "stir_the_pot"})
+	if err != nil || len(activities) != 1 {
+		t.Fatalf("list stir the pot activities: %v (%d)", err, len(activities))
+	}
+	rounds, err := queries.ListActivityOccurrencesByActivityAndStatus(ctx, db.ListActivityOccurrencesByActivityAndStatusParams{ActivityID: activities[0].ID, Status: "recorded"})
+	if err != nil || len(rounds) != 1 {
+		t.Fatalf("list stir the pot rounds: %v (%d)", err, len(rounds))
+	}
+	var metadata struct {
+		ClosedAt string `json:"closed_at"`
+		ClosedBy string `json:"closed_by"`
+	}
+	if err := json.Unmarshal(rounds[0].Metadata, &metadata); err != nil {
+		t.Fatalf("unmarshal round metadata: %v", err)
+	}
+	return metadata.ClosedAt != "" && metadata.ClosedBy == "scheduler"
+}
+
+func TestParticipantGroupsApiEnforcesEpisodeBoundaries(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	router := httpapi.New(pool).Router()
+	instance := createInstanceForTest(t, ctx, queries, "Tribe Pool", 50)
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	createEpisodeForTest(t, ctx, queries, instance.ID, 1, "Premiere", time.Date(2026, time.March, 5, 1, 0, 0, 0, time.UTC))
+	createEpisodeForTest(t, ctx, queries, instance.ID, 2, "Swap", time.Date(2026, time.March, 12, 1, 0, 0, 0, time.UTC))
+	alice := createParticipantForTest(t, ctx, queries, instance.ID, "Alice")
+	groupsPath := "/instances/" + uuid.UUID(instance.ID.Bytes).String() + "/groups"
+	aliceID := uuid.UUID(alice.ID.Bytes).String()
+
+	sendGroupRequest := func(name, method, path, body, discordUserID string, want int) map[string]any {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(method, path, body, "", discordUserID))
+		if recorder.Code != want {
+			t.Fatalf("%s status = %d, want %d, body = %s", name, recorder.Code, want, recorder.Body.String())
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal %s: %v", name, err)
+		}
+		return payload
+	}
+
+	sendGroupRequest("non-admin create", http.MethodPost, groupsPath, `{"name":"Lavo"}`, "player-discord", http.StatusForbidden)
+	created := sendGroupRequest("create tribe", http.MethodPost, groupsPath, `{"name":"Lavo","kind":"tribe"}`, "admin-discord", http.StatusCreated)
+	sendGroupRequest("create duplicate", http.MethodPost, groupsPath, `{"name":"Lavo","kind":"tribe"}`, "admin-discord", http.StatusConflict)
+	groupPath := groupsPath + "/" + created["group"].(map[string]any)["id"].(string)
+	membershipsPath := groupPath + "/memberships"
+
+	sendGroupRequest("off-boundary start", http.MethodPost, membershipsPath, `{"participant_id":"`+aliceID+`","starts_at":"2026-03-06T01:00:00Z"}`, "admin-discord", http.StatusBadRequest)
+	sendGroupRequest("add member", http.MethodPost, membershipsPath, `{"participant_id":"`+aliceID+`","starts_at":"2026-03-05T01:00:00Z"}`, "admin-discord", http.StatusCreated)
+	sendGroupRequest("overlapping member", http.MethodPost, membershipsPath, `{"participant_id":"`+aliceID+`","role":"captain","starts_at":"2026-03-12T01:00:00Z"}`, "admin-discord", http.StatusConflict)
+	sendGroupRequest("off-boundary end", http.MethodPost, membershipsPath+"/"+aliceID+"/end", `{"ends_at":"2026-03-10T01:00:00Z"}`, "admin-discord", http.StatusBadRequest)
+	sendGroupRequest("end membership", http.MethodPost, membershipsPath+"/"+aliceID+"/end", `{"ends_at":"2026-03-12T01:00:00Z"}`, "admin-discord", http.StatusOK)
+	sendGroupRequest("end closed membership", http.MethodPost, membershipsPath+"/"+aliceID+"/end", `{"ends_at":"2026-03-12T01:00:00Z"}`, "admin-discord", http.StatusNotFound)
+
+	before := sendGroupRequest("list before swap", http.MethodGet, groupsPath+"?as_of=2026-03-06T00:00:00Z", "", "", http.StatusOK)
+	groups, ok := before["groups"].([]any)
+	if !ok || len(groups) != 1 {
+		t.Fatalf("expected one group, got %#v", before)
+	}
+	if members := groups[0].(map[string]any)["members"].([]any); len(members) != 1 || members[0].(map[string]any)["participant_name"] != "Alice" {
+		t.Fatalf("expected Alice in Lavo before the swap, got %#v", members)
+	}
+
+	detail := sendGroupRequest("get after swap", http.MethodGet, groupPath+"?as_of=2026-03-13T00:00:00Z", "", "", http.StatusOK)
+	group := detail["group"].(map[string]any)
+	if members := group["members"].([]any); len(members) != 0 {
+		t.Fatalf("expected no members after the swap, got %#v", members)
+	}
+	if memberships := group["memberships"].([]any); len(memberships) != 1 || memberships[0].(map[string]any)["ends_at"] != "2026-03-12T01:00:00Z" {
+		t.Fatalf("expected one ended membership period, got %#v", memberships)
+	}
+}
+
+func TestGroupRealignmentPreviewsThenAppliesSwap(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	router := httpapi.New(pool).Router()
+	instance := createInstanceForTest(t, ctx, queries, "Swap Pool", 50)
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	premiere := time.Date(2026, time.March, 5, 1, 0, 0, 0, time.UTC)
+	swapAt := time.Date(2026, time.March, 12, 1, 0, 0, 0, time.UTC)
+	createEpisodeForTest(t, ctx, queries, instance.ID, 1, "Premiere", premiere)
+	createEpisodeForTest(t, ctx, queries, instance.ID, 2, "Swap", swapAt)
+	lavo := createParticipantGroupForTest(t, ctx, queries, instance.ID, "Lavo", "tribe")
+	gata := createParticipantGroupForTest(t, ctx, queries, instance.ID, "Gata", "tribe")
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+
+	sendRealignRequest := func(name, method, path, body string, want int) map[string]any {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(method, path, body, "", "admin-discord"))
+		if recorder.Code != want {
+			t.Fatalf("%s status = %d, want %d, body = %s", name, recorder.Code, want, recorder.Body.String())
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal %s: %v", name, err)
+		}
+		return payload
+	}
+	for _, member := range []struct {
+		name  string
+		group pgtype.UUID
+	}{{"Alice", lavo.ID}, {"Bob", lavo.ID}, {"Cara", gata.ID}} {
+		participant := createParticipantForTest(t, ctx, queries, instance.ID, member.name)
+		sendRealignRequest("add "+member.name, http.MethodPost, instancePath+"/groups/"+uuid.UUID(member.group.Bytes).String()+"/memberships",
+			`{"participant_id":"`+uuid.UUID(participant.ID.Bytes).String()+`","starts_at":"2026-03-05T01:00:00Z"}`, http.StatusCreated)
+	}
+	activity := createActivityForTest(t, ctx, queries, instance.ID, premiere, nil, "tribal_pony", "Tribal Pony")
+	if _, err := queries.CreateActivityGroupAssignment(ctx, db.CreateActivityGroupAssignmentParams{
+		ActivityID:         activity.ID,
+		ParticipantGroupID: gata.ID,
+		Role:               "tribe",
+		StartsAt:           timestamptz(premiere),
+		Configuration:      testEmptyJSONB,
+	}); err != nil {
+		t.Fatalf("create activity group assignment: %v", err)
+	}
+
+	swap := `{"effective_at":"2026-03-12T01:00:00Z","layout":[{"group":"Lavo","participants":["Alice"]},{"group":"Tuku","participants":["Bob","Cara"]}]}`
+	sendRealignRequest("off-boundary preview", http.MethodPost, instancePath+"/groups/realignments/preview", `{"effective_at":"2026-03-10T01:00:00Z","merge_into":"Merge"}`, http.StatusBadRequest)
+	preview := sendRealignRequest("preview swap", http.MethodPost, instancePath+"/groups/realignments/preview", swap, http.StatusOK)
+	plan := preview["realignment"].(map[string]any)
+	if preview["applied"] != false || len(plan["moves"].([]any)) != 2 || len(plan["assignments"].([]any)) != 1 {
+		t.Fatalf("unexpected preview: %#v", preview)
+	}
+	if assignment := plan["assignments"].([]any)[0].(map[string]any); assignment["participant_group_name"] != "Gata" || assignment["action"] != "end" {
+		t.Fatalf("expected Gata's assignment to end, got %#v", assignment)
+	}
+	groups, err := queries.ListParticipantGroupsByInstance(ctx, instance.ID)
+	if err != nil || len(groups) != 2 {
+		t.Fatalf("expected preview to leave groups untouched, got %d groups, err %v", len(groups), err)
+	}
+
+	applied := sendRealignRequest("apply swap", http.MethodPost, instancePath+"/groups/realignments", swap, http.StatusOK)
+	if applied["applied"] != true {
+		t.Fatalf("unexpected apply response: %#v", applied)
+	}
+	sendRealignRequest("reapply swap", http.MethodPost, instancePath+"/groups/realignments", swap, http.StatusConflict)
+
+	listed := sendRealignRequest("list after swap", http.MethodGet, instancePath+"/groups?as_of=2026-03-13T00:00:00Z", "", http.StatusOK)
+	members := map[string]int{}
+	for _, group := range listed["groups"].([]any) {
+		group := group.(map[string]any)
+		members[group["name"].(string)] = len(group["members"].([]any))
+	}
+	if members["Lavo"] != 1 || members["Tuku"] != 2 || members["Gata"] != 0 {
+		t.Fatalf("unexpected members after swap: %#v", members)
+	}
+	active, err := queries.ListActiveActivityGroupAssignmentsAt(ctx, db.ListActiveActivityGroupAssignmentsAtParams{ActivityID: activity.ID, At: timestamptz(swapAt)})
+	if err != nil || len(active) != 0 {
+		t.Fatalf("expected Gata's assignment to end at the swap, got %+v, err %v", active, err)
+	}
+}
+
+func TestAdvantagesApiHidesSecretsAndExpires(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	router := httpapi.New(pool).Router()
+	instance := createInstanceForTest(t, ctx, queries, "Advantage Pool", 50)
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	alice := createParticipantForTest(t, ctx, queries, instance.ID, "Alice")
+	bob := createParticipantForTest(t, ctx, queries, instance.ID, "Bob")
+	for discordUserID, participantID := range map[string]pgtype.UUID{"alice-discord": alice.ID, "bob-discord": bob.ID} {
+		if _, err := queries.SetParticipantDiscordUserID(ctx, db.SetParticipantDiscordUserIDParams{ID: participantID, DiscordUserID: pgtype.Text{String: discordUserID, Valid: true}}); err != nil {
+			t.Fatalf("link %s: %v", discordUserID, err)
+		}
+	}
+	advantagesPath := "/instances/" + uuid.UUID(instance.ID.Bytes).String() + "/advantages"
+	now := time.Now().UTC()
+
+	sendAdvantageRequest := func(name, method, path, body, discordUserID string, want int) map[string]any {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(method, path, body, "", discordUserID))
+		if recorder.Code != want {
+			t.Fatalf("%s status = %d, want %d, body = %s", name, recorder.Code, want, recorder.Body.String())
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal %s: %v", name, err)
+		}
+		return payload
+	}
+	listNames := func(discordUserID string) map[string]string {
+		t.Helper()
+		payload := sendAdvantageRequest("list as "+discordUserID, http.MethodGet, advantagesPath, "", discordUserID, http.StatusOK)
+		statuses := map[string]string{}
+		for _, item := range payload["advantages"].([]any) {
+			advantage := item.(map[string]any)
+			statuses[advantage["name"].(string)] = advantage["status"].(string) + "/" + advantage["visibility"].(string)
+		}
+		return statuses
+	}
+
+	aliceID := uuid.UUID(alice.ID.Bytes).String()
+	bobID := uuid.UUID(bob.ID.Bytes).String()
+	sendAdvantageRequest("player grant", http.MethodPost, advantagesPath, `{"participant_id":"`+bobID+`","advantage_type":"idol","name":"Idol"}`, "bob-discord", http.StatusForbidden)
+	granted := sendAdvantageRequest("grant secret idol", http.MethodPost, advantagesPath,
+		`{"participant_id":"`+aliceID+`","advantage_type":"idol","name":"Hidden Idol","effective_at":"`+now.Add(-time.Hour).Format(time.RFC3339)+`"}`, "admin-discord", http.StatusCreated)
+	idolID := granted["advantage"].(map[string]any)["id"].(string)
+	lapsed := sendAdvantageRequest("grant lapsing scroll", http.MethodPost, advantagesPath,
+		`{"participant_id":"`+bobID+`","advantage_type":"loan_shark","name":"Scroll","visibility":"public","effective_at":"`+now.Add(-48*time.Hour).Format(time.RFC3339)+`","effective_until":"`+now.Add(-24*time.Hour).Format(time.RFC3339)+`"}`, "admin-discord", http.StatusCreated)
+	scrollID := lapsed["advantage"].(map[string]any)["id"].(string)
+
+	if got := listNames("bob-discord"); len(got) != 1 || got["Scroll"] != "expired/public" {
+		t.Fatalf("expected Bob to see only his expired scroll, got %#v", got)
+	}
+	if got := listNames("alice-discord"); len(got) != 2 || got["Hidden Idol"] != "active/secret" {
+		t.Fatalf("expected Alice to see her secret idol, got %#v", got)
+	}
+
+	sendAdvantageRequest("play someone else's idol", http.MethodPost, advantagesPath+"/"+idolID+"/play", `{}`, "bob-discord", http.StatusForbidden)
+	sendAdvantageRequest("play expired scroll", http.MethodPost, advantagesPath+"/"+scrollID+"/play", `{}`, "bob-discord", http.StatusConflict)
+	played := sendAdvantageRequest("play idol", http.MethodPost, advantagesPath+"/"+idolID+"/play", `{"reveal":true,"note":"at tribal"}`, "alice-discord", http.StatusOK)
+	if advantage := played["advantage"].(map[string]any); advantage["status"] != "used" || advantage["visibility"] != "revealed" || advantage["used_at"] == nil {
+		t.Fatalf("unexpected played advantage: %#v", advantage)
+	}
+	sendAdvantageRequest("replay idol", http.MethodPost, advantagesPath+"/"+idolID+"/play", `{}`, "alice-discord", http.StatusConflict)
+
+	if got := listNames("bob-discord"); got["Hidden Idol"] != "used/revealed" {
+		t.Fatalf("expected the revealed idol to be visible to Bob, got %#v", got)
+	}
+	participantList := sendAdvantageRequest("list Alice's advantages", http.MethodGet, "/instances/"+uuid.UUID(instance.ID.Bytes).String()+"/participants/"+aliceID+"/advantages?status=used", "", "", http.StatusOK)
+	if advantages := participantList["advantages"].([]any); len(advantages) != 1 {
+		t.Fatalf("expected Alice's used idol, got %#v", advantages)
+	}
+}
+
+func TestSchedulerDefaultsOverdueLoanWithConfiguredPenalty(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	server := httpapi.New(pool)
+	router := server.Router()
+	instance := createInstanceForTest(t, ctx, queries, "Loan Default Pool", 50)
+	participant := createParticipantForTest(t, ctx, queries, instance.ID, "Alice")
+	if _, err := queries.SetParticipantDiscordUserID(ctx, db.SetParticipantDiscordUserIDParams{ID: participant.ID, DiscordUserID: pgtype.Text{String: "alice-discord", Valid: true}}); err != nil {
+		t.Fatalf("link participant: %v", err)
+	}
+	now := time.Now().UTC()
+	createEpisodeForTest(t, ctx, queries, instance.ID, 1, "Episode 1", now.Add(-time.Hour))
+	createEpisodeForTest(t, ctx, queries, instance.ID, 2, "Episode 2", now.Add(24*time.Hour))
+	if _, err := queries.CreateInstanceActivity(ctx, db.CreateInstanceActivityParams{
+		InstanceID:   instance.ID,
+		ActivityType: "loan_shark",
+		Name:         "Loan Shark",
+		Status:       "active",
+		StartsAt:     timestamptz(now.Add(-2 * time.Hour)),
+		Metadata:     []byte(`{"default_penalty":{"shortfall_multiplier":2}}`),
+	}); err != nil {
+		t.Fatalf("create loan shark activity: %v", err)
+	}
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+
+	type loanStatus struct {
+		Loan struct {
+			HasActiveLoan        bool `json:"has_active_loan"`
+			BonusPointsAvailable int  `json:"bonus_points_available"`
+			LastDefault          *struct {
+				CollectedPoints int `json:"collected_points"`
+				ShortfallPoints int `json:"shortfall_points"`
+				PenaltyPoints   int `json:"penalty_points"`
+			} `json:"last_default"`
+		} `json:"loan"`
+	}
+	sendLoanRequest := func(name, method, path, body string) loanStatus {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(method, path, body, "", "alice-discord"))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s status = %d, body = %s", name, recorder.Code, recorder.Body.String())
+		}
+		var payload loanStatus
+		if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal %s: %v", name, err)
+		}
+		return payload
+	}
+	borrowed := sendLoanRequest("borrow", http.MethodPost, instancePath+"/loan-shark/me/borrow", `{"points":3}`)
+	if !borrowed.Loan.HasActiveLoan || borrowed.Loan.BonusPointsAvailable != 3 {
+		t.Fatalf("unexpected loan after borrow: %+v", borrowed.Loan)
+	}
+
+	clockAt := now
+	runner := scheduler.New(pool, server.JobHandlers(), scheduler.WithClock(scheduler.ClockFunc(func() time.Time { return clockAt })))
+	if _, err := runner.RunDue(ctx); err != nil {
+		t.Fatalf("run due jobs before the loan is due: %v", err)
+	}
+	if status := sendLoanRequest("status before due", http.MethodGet, instancePath+"/loan-shark/me", ""); !status.Loan.HasActiveLoan || status.Loan.LastDefault != nil {
+		t.Fatalf("expected loan to stay active before it is due: %+v", status.Loan)
+	}
+
+	clockAt = now.Add(25 * time.Hour)
+	if _, err := runner.RunDue(ctx); err != nil {
+		t.Fatalf("run due jobs after the loan is due: %v", err)
+	}
+	status := sendLoanRequest("status after default", http.MethodGet, instancePath+"/loan-shark/me", "")
+	if status.Loan.HasActiveLoan || status.Loan.LastDefault == nil {
+		t.Fatalf("expected a defaulted loan, got %+v", status.Loan)
+	}
+	if got := *status.Loan.LastDefault; got.CollectedPoints != 3 || got.ShortfallPoints != 1 || got.PenaltyPoints != 2 {
+		t.Fatalf("unexpected default summary: %+v", got)
+	}
+	if status.Loan.BonusPointsAvailable != -2 {
+		t.Fatalf("expected the penalty to leave -2 points, got %d", status.Loan.BonusPointsAvailable)
+	}
+
+	entries, err := queries.ListAllBonusPointLedgerEntriesForParticipant(ctx, db.ListAllBonusPointLedgerEntriesForParticipantParams{InstanceID: instance.ID, ParticipantID: participant.ID})
+	if err != nil {
+		t.Fatalf("list ledger entries: %v", err)
+	}
+	corrections := 0
+	for _, entry := range entries {
+		if entry.EntryKind == "correction" {
+			corrections++
+			if entry.Points != -2 || entry.Visibility != "secret" {
+				t.Fatalf("unexpected penalty entry: %+v", entry)
+			}
+		}
+	}
+	if corrections != 1 {
+		t.Fatalf("expected one penalty correction, got %d in %+v", corrections, entries)
+	}
+
+	type outboxPage struct {
+		Events []struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+			Data struct {
+				ParticipantID string `json:"participant_id"`
+			} `json:"data"`
+		} `json:"events"`
+		NextAfter string `json:"next_after"`
+	}
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	for discordUserID, wantStatus := range map[string]int{"": http.StatusBadRequest, "alice-discord": http.StatusForbidden} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodGet, instancePath+"/outbox", "", "", discordUserID))
+		if recorder.Code != wantStatus {
+			t.Fatalf("outbox as %q status = %d, want %d", discordUserID, recorder.Code, wantStatus)
+		}
+	}
+	readOutbox := func(query string) outboxPage {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodGet, instancePath+"/outbox?"+query, "", "", "admin-discord"))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("outbox status = %d, body = %s", recorder.Code, recorder.Body.String())
+		}
+		var page outboxPage
+		if err := json.Unmarshal(recorder.Body.Bytes(), &page); err != nil {
+			t.Fatalf("unmarshal outbox: %v", err)
+		}
+		return page
+	}
+	page := readOutbox("type=loan.defaulted")
+	if len(page.Events) != 1 || page.Events[0].Type != "loan.defaulted" || page.Events[0].Data.ParticipantID != uuid.UUID(participant.ID.Bytes).String() {
+		t.Fatalf("expected one loan.defaulted event for Alice, got %+v", page)
+	}
+	if page.NextAfter != page.Events[0].ID {
+		t.Fatalf("next_after = %q, want %q", page.NextAfter, page.Events[0].ID)
+	}
+	if next := readOutbox("type=loan.defaulted&after=" + page.NextAfter); len(next.Events) != 0 || next.NextAfter != page.NextAfter {
+		t.Fatalf("expected no events after the cursor, got %+v", next)
+	}
+}
+
+func TestReresolveOccurrenceReversesAndReportsDiff(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	server := httpapi.New(pool)
+	router := server.Router()
+	instance := createInstanceForTest(t, ctx, queries, "Reresolve Pool", 50)
+	alice := createParticipantForTest(t, ctx, queries, instance.ID, "Alice")
+	bob := createParticipantForTest(t, ctx, queries, instance.ID, "Bob")
+	effectiveAt := time.Date(2026, time.March, 22, 9, 0, 0, 0, time.UTC)
+	activity := createActivityForTest(t, ctx, queries, instance.ID, effectiveAt.Add(-time.Hour), nil, "manual_adjustment", "Manual Adjustments")
+	occurrence := createOccurrenceForTest(t, ctx, queries, activity.ID, "manual_correction", "Episode 1 Correction", effectiveAt)
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	setPoints := func(participantID pgtype.UUID, points int) {
+		t.Helper()
+		if _, err := queries.UpsertActivityOccurrenceParticipant(ctx, db.UpsertActivityOccurrenceParticipantParams{
+			ActivityOccurrenceID: occurrence.ID,
+			ParticipantID:        participantID,
+			Role:                 "target",
+			Metadata:             []byte(fmt.Sprintf(`{"points":%d,"visibility":"public","reason":"manual correction","award_key":"manual-adjustment"}`, points)),
+		}); err != nil {
+			t.Fatalf("upsert occurrence participant: %v", err)
+		}
+	}
+	setPoints(alice.ID, 3)
+	occurrencePath := "/occurrences/" + uuid.UUID(occurrence.ID.Bytes).String()
+
+	forbiddenRecorder := httptest.NewRecorder()
+	router.ServeHTTP(forbiddenRecorder, authorizedJSONRequest(http.MethodPost, occurrencePath+"/reresolve", "", "", "player-discord"))
+	if forbiddenRecorder.Code != http.StatusForbidden {
+		t.Fatalf("non-admin reresolve status = %d, body = %s", forbiddenRecorder.Code, forbiddenRecorder.Body.String())
+	}
+	earlyRecorder := httptest.NewRecorder()
+	router.ServeHTTP(earlyRecorder, authorizedJSONRequest(http.MethodPost, occurrencePath+"/reresolve", "", "", "admin-discord"))
+	if earlyRecorder.Code != http.StatusConflict {
+		t.Fatalf("reresolve before resolve status = %d, body = %s", earlyRecorder.Code, earlyRecorder.Body.String())
+	}
+	resolveRecorder := httptest.NewRecorder()
+	router.ServeHTTP(resolveRecorder, httptest.NewRequest(http.MethodPost, occurrencePath+"/resolve", nil))
+	if resolveRecorder.Code != http.StatusOK {
+		t.Fatalf("resolve status = %d, body = %s", resolveRecorder.Code, resolveRecorder.Body.String())
+	}
+
+	type reresolveResponse struct {
+		ReversedEntries []struct {
+			ParticipantID string `json:"participant_id"`
+			EntryKind     string `json:"entry_kind"`
+			Points        int    `json:"points"`
+		} `json:"reversed_entries"`
+		CreatedEntries []struct {
+			Points int `json:"points"`
+		} `json:"created_entries"`
+		Diff []struct {
+			ParticipantName string `json:"participant_name"`
+			BeforePoints    int    `json:"before_points"`
+			AfterPoints     int    `json:"after_points"`
+			NetPoints       int    `json:"net_points"`
+		} `json:"diff"`
+	}
+	reresolve := func() reresolveResponse {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPost, occurrencePath+"/reresolve", "", "", "admin-discord"))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("reresolve status = %d, body = %s", recorder.Code, recorder.Body.String())
+		}
+		var response reresolveResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("unmarshal reresolve response: %v", err)
+		}
+		return response
+	}
+
+	setPoints(alice.ID, 5)
+	setPoints(bob.ID, 2)
+	first := reresolve()
+	if len(first.ReversedEntries) != 1 || first.ReversedEntries[0].EntryKind != "correction" || first.ReversedEntries[0].Points != -3 {
+		t.Fatalf("unexpected reversed entries: %+v", first.ReversedEntries)
+	}
+	if len(first.CreatedEntries) != 2 || len(first.Diff) != 2 {
+		t.Fatalf("unexpected reresolve response: %+v", first)
+	}
+	if first.Diff[0].ParticipantName != "Alice" || first.Diff[0].BeforePoints != 3 || first.Diff[0].AfterPoints != 5 || first.Diff[0].NetPoints != 2 {
+		t.Fatalf("unexpected diff for Alice: %+v", first.Diff[0])
+	}
+	if first.Diff[1].ParticipantName != "Bob" || first.Diff[1].BeforePoints != 0 || first.Diff[1].NetPoints != 2 {
+		t.Fatalf("unexpected diff for Bob: %+v", first.Diff[1])
+	}
+
+	second := reresolve()
+	if len(second.ReversedEntries) != 2 || len(second.CreatedEntries) != 2 {
+		t.Fatalf("expected the second run to reverse only the live entries: %+v", second)
+	}
+	for _, diff := range second.Diff {
+		if diff.NetPoints != 0 {
+			t.Fatalf("expected no net change on unchanged inputs: %+v", second.Diff)
+		}
+	}
+
+	entries, err := queries.ListAllBonusPointLedgerEntriesForParticipant(ctx, db.ListAllBonusPointLedgerEntriesForParticipantParams{InstanceID: instance.ID, ParticipantID: alice.ID})
+	if err != nil {
+		t.Fatalf("list Alice ledger: %v", err)
+	}
+	var total int32
+	for _, entry := range entries {
+		total += entry.Points
+	}
+	if len(entries) != 5 || total != 5 {
+		t.Fatalf("expected Alice's ledger to keep history and net to 5, got %d entries totalling %d", len(entries), total)
+	}
+
+	var audited, resolvedEvents int
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM audit_events WHERE action = 'occurrence.reresolve' AND actor_discord_user_id = 'admin-discord'`).Scan(&audited); err != nil {
+		t.Fatalf("count reresolve audit events: %v", err)
+	}
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM outbox_events WHERE event_type = 'occurrence.resolved' AND payload->>'occurrence_id' = $1`, uuid.UUID(occurrence.ID.Bytes).String()).Scan(&resolvedEvents); err != nil {
+		t.Fatalf("count occurrence.resolved events: %v", err)
+	}
+	if audited != 2 || resolvedEvents != 3 {
+		t.Fatalf("expected 2 audited re-resolves and 3 occurrence.resolved events, got %d and %d", audited, resolvedEvents)
+	}
+
+	// Bob spends his 2 points, so correcting them to -1 would leave him at -3.
+	spend := createOccurrenceForTest(t, ctx, queries, activity.ID, "manual_correction", "Bob Spend", effectiveAt)
+	createLedgerEntryForTest(t, ctx, queries, instance.ID, bob.ID, spend.ID, pgtype.UUID{}, "spend", -2, "public", "spent", "bob-spend")
+	setPoints(bob.ID, -1)
+	rejectedRecorder := httptest.NewRecorder()
+	router.ServeHTTP(rejectedRecorder, authorizedJSONRequest(http.MethodPost, occurrencePath+"/reresolve", "", "", "admin-discord"))
+	if rejectedRecorder.Code != http.StatusConflict || !strings.Contains(rejectedRecorder.Body.String(), `"balance_after":-3`) {
+		t.Fatalf("expected a negative balance to be rejected, status = %d, body = %s", rejectedRecorder.Code, rejectedRecorder.Body.String())
+	}
+	allowedRecorder := httptest.NewRecorder()
+	router.ServeHTTP(allowedRecorder, authorizedJSONRequest(http.MethodPost, occurrencePath+"/reresolve?allow_negative_balance=true", "", "", "admin-discord"))
+	if allowedRecorder.Code != http.StatusOK || !strings.Contains(allowedRecorder.Body.String(), `"balance_after":-3`) {
+		t.Fatalf("expected the allowed run to surface the negative balance, status = %d, body = %s", allowedRecorder.Code, allowedRecorder.Body.String())
+	}
+}
+
+func TestResolvePreviewReturnsEntriesWithoutWriting(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	server := httpapi.New(pool)
+	router := server.Router()
+	instance := createInstanceForTest(t, ctx, queries, "Resolve Preview Pool", 50)
+	alice := createParticipantForTest(t, ctx, queries, instance.ID, "Alice")
+	effectiveAt := time.Date(2026, time.March, 22, 9, 0, 0, 0, time.UTC)
+	activity := createActivityForTest(t, ctx, queries, instance.ID, effectiveAt.Add(-time.Hour), nil, "manual_adjustment", "Manual Adjustments")
+	occurrence := createOccurrenceForTest(t, ctx, queries, activity.ID, "manual_correction", "Episode 1 Correction", effectiveAt)
+	if _, err := queries.UpsertActivityOccurrenceParticipant(ctx, db.UpsertActivityOccurrenceParticipantParams{
+		ActivityOccurrenceID: occurrence.ID,
+		ParticipantID:        alice.ID,
+		Role:                 "target",
+		Metadata:             []byte(`{"points":4,"visibility":"secret","reason":"hidden idol bonus","award_key":"hidden-idol"}`),
+	}); err != nil {
+		t.Fatalf("upsert occurrence participant: %v", err)
+	}
+	resolvePath := "/occurrences/" + uuid.UUID(occurrence.ID.Bytes).String() + "/resolve"
+
+	badRecorder := httptest.NewRecorder()
+	router.ServeHTTP(badRecorder, httptest.NewRequest(http.MethodPost, resolvePath+"?preview=maybe", nil))
+	if badRecorder.Code != http.StatusBadRequest {
+		t.Fatalf("bad preview status = %d, body = %s", badRecorder.Code, badRecorder.Body.String())
+	}
+
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	forbiddenRecorder := httptest.NewRecorder()
+	router.ServeHTTP(forbiddenRecorder, authorizedJSONRequest(http.MethodPost, resolvePath+"?preview=true", "", "", "player-discord"))
+	if forbiddenRecorder.Code != http.StatusForbidden {
+		t.Fatalf("non-admin preview status = %d, body = %s", forbiddenRecorder.Code, forbiddenRecorder.Body.String())
+	}
+
+	previewRecorder := httptest.NewRecorder()
+	router.ServeHTTP(previewRecorder, authorizedJSONRequest(http.MethodPost, resolvePath+"?preview=true", "", "", "admin-discord"))
+	if previewRecorder.Code != http.StatusOK {
+		t.Fatalf("preview status = %d, body = %s", previewRecorder.Code, previewRecorder.Body.String())
+	}
+	var preview struct {
+		Preview bool `json:"preview"`
+		Entries []struct {
+			ParticipantName string `json:"participant_name"`
+			Points          int    `json:"points"`
+			Visibility      string `json:"visibility"`
+		} `json:"entries"`
+		EntryCount int `json:"entry_count"`
+	}
+	if err := json.Unmarshal(previewRecorder.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("unmarshal preview response: %v", err)
+	}
+	if !preview.Preview || preview.EntryCount != 1 || preview.Entries[0].ParticipantName != "Alice" || preview.Entries[0].Points != 4 || preview.Entries[0].Visibility != "secret" {
+		t.Fatalf("unexpected preview response: %+v", preview)
+	}
+	entries, err := queries.ListAllBonusPointLedgerEntriesForParticipant(ctx, db.ListAllBonusPointLedgerEntriesForParticipantParams{InstanceID: instance.ID, ParticipantID: alice.ID})
+	if err != nil {
+		t.Fatalf("list Alice ledger: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected preview to write nothing, got %+v", entries)
+	}
+	stored, err := queries.GetActivityOccurrence(ctx, occurrence.ID)
+	if err != nil {
+		t.Fatalf("get occurrence: %v", err)
+	}
+	if stored.Status == "resolved" {
+		t.Fatal("expected preview to leave the occurrence unresolved")
+	}
+
+	resolveRecorder := httptest.NewRecorder()
+	router.ServeHTTP(resolveRecorder, httptest.NewRequest(http.MethodPost, resolvePath, nil))
+	if resolveRecorder.Code != http.StatusOK || !strings.Contains(resolveRecorder.Body.String(), `"created_count":1`) {
+		t.Fatalf("resolve status = %d, body = %s", resolveRecorder.Code, resolveRecorder.Body.String())
+	}
+}
+
+func TestActivityTypeSchemasRejectBadMetadataOnWrite(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	server := httpapi.New(pool)
+	router := server.Router()
+	instance := createInstanceForTest(t, ctx, queries, "Activity Types Pool", 50)
+	alice := createParticipantForTest(t, ctx, queries, instance.ID, "Alice")
+	group := createParticipantGroupForTest(t, ctx, queries, instance.ID, "Lotus", "tribe")
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+
+	send := func(name, method, path, body string, wantStatus int) string {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != wantStatus {
+			t.Fatalf("%s status = %d, want %d, body = %s", name, recorder.Code, wantStatus, recorder.Body.String())
+		}
+		return recorder.Body.String()
+	}
+
+	var types struct {
+		ActivityTypes []struct {
+			ActivityType string `json:"activity_type"`
+			Resolvable   bool   `json:"resolvable"`
+			Schemas      struct {
+				Participant struct {
+					Required []string `json:"required"`
+				} `json:"participant"`
+			} `json:"schemas"`
+		} `json:"activity_types"`
+	}
+	if err := json.Unmarshal([]byte(send("list activity types", http.MethodGet, "/activity-types", "", http.StatusOK)), &types); err != nil {
+		t.Fatalf("unmarshal activity types: %v", err)
+	}
+	foundWordle := false
+	for _, activityType := range types.ActivityTypes {
+		if activityType.ActivityType == "tribe_wordle" {
+			foundWordle = activityType.Resolvable && len(activityType.Schemas.Participant.Required) == 1 && activityType.Schemas.Participant.Required[0] == "guess_count"
+		}
+	}
+	if !foundWordle {
+		t.Fatalf("expected tribe_wordle with a guess_count participant schema: %+v", types)
+	}
+
+	send("unknown activity type", http.MethodPost, instancePath+"/activities", `{"activity_type":"bingo_night","name":"Bingo","status":"active","starts_at":"2026-03-21T12:00:00Z"}`, http.StatusBadRequest)
+	body := send("bad loan shark penalty", http.MethodPost, instancePath+"/activities", `{"activity_type":"loan_shark","name":"Loan Shark","status":"active","starts_at":"2026-03-21T12:00:00Z","metadata":{"default_penalty":{"flat_points":-1}}}`, http.StatusBadRequest)
+	if !strings.Contains(body, "metadata.default_penalty.flat_points must be at least 0") {
+		t.Fatalf("unexpected loan shark error: %s", body)
+	}
+
+	var ponyActivity struct {
+		Activity struct {
+			ID string `json:"id"`
+		} `json:"activity"`
+	}
+	if err := json.Unmarshal([]byte(send("create tribal pony", http.MethodPost, instancePath+"/activities", `{"activity_type":"tribal_pony","name":"Pony Tribes","status":"active","starts_at":"2026-03-21T12:00:00Z"}`, http.StatusCreated)), &ponyActivity); err != nil {
+		t.Fatalf("unmarshal tribal pony activity: %v", err)
+	}
+	body = send("immunity without winners", http.MethodPost, "/activities/"+ponyActivity.Activity.ID+"/occurrences", `{"occurrence_type":"immunity_result","name":"Episode 2 Immunity","effective_at":"2026-03-22T09:00:00Z","status":"recorded","metadata":{"winners":["vatu"]}}`, http.StatusBadRequest)
+	if !strings.Contains(body, "metadata.winning_survivor_tribes is required") {
+		t.Fatalf("unexpected immunity error: %s", body)
+	}
+	send("immunity for missing activity", http.MethodPost, "/activities/"+uuid.NewString()+"/occurrences", `{"occurrence_type":"immunity_result","name":"Episode 2 Immunity","effective_at":"2026-03-22T09:00:00Z","status":"recorded"}`, http.StatusNotFound)
+
+	wordleActivity := createActivityForTest(t, ctx, queries, instance.ID, time.Date(2026, time.March, 21, 12, 0, 0, 0, time.UTC), nil, "tribe_wordle", "Tribe Wordle")
+	wordle := createOccurrenceForTest(t, ctx, queries, wordleActivity.ID, "challenge_result", "Week 2 Wordle", time.Date(2026, time.March, 22, 9, 0, 0, 0, time.UTC))
+	participantsPath := "/occurrences/" + uuid.UUID(wordle.ID.Bytes).String() + "/participants"
+	participantBody := func(guessCount string) string {
+		return fmt.Sprintf(`{"participant_id":%q,"participant_group_id":%q,"role":"player","metadata":{"guess_count":%s}}`, uuid.UUID(alice.ID.Bytes).String(), uuid.UUID(group.ID.Bytes).String(), guessCount)
+	}
+	body = send("wordle with text guess count", http.MethodPost, participantsPath, participantBody(`"three"`), http.StatusBadRequest)
+	if !strings.Contains(body, "metadata.guess_count must be an integer") {
+		t.Fatalf("unexpected wordle error: %s", body)
+	}
+	send("wordle with guess count", http.MethodPost, participantsPath, participantBody("3"), http.StatusCreated)
+}
+
+func TestAdminMutationsRecordAuditEvents(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	instance := createInstanceForTest(t, ctx, queries, "Audit Pool", 50)
+	alice := createParticipantForTest(t, ctx, queries, instance.ID, "Alice")
+	contestant := createContestantForTest(t, ctx, queries, instance.ID, "Contestant A")
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	router := httpapi.New(pool, httpapi.WithServiceAuth(httpapi.ServiceAuthConfig{
+		Enabled:      true,
+		BearerTokens: []string{"audit-token"},
+	})).Router()
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+
+	serve := func(req *http.Request, wantStatus int) []byte {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != wantStatus {
+			t.Fatalf("%s %s status = %d, body = %s", req.Method, req.URL.Path, recorder.Code, recorder.Body.String())
+		}
+		return recorder.Body.Bytes()
+	}
+	serve(authorizedJSONRequest(http.MethodPut, instancePath+"/outcomes/1", fmt.Sprintf(`{"contestant_id":%q,"reason":"voted_out"}`, uuid.UUID(contestant.ID.Bytes).String()), "audit-token", "admin-discord"), http.StatusOK)
+	serve(authorizedJSONRequest(http.MethodPut, instancePath+"/participants/"+uuid.UUID(alice.ID.Bytes).String()+"/discord-link", `{"discord_user_id":"alice-discord"}`, "audit-token", "admin-discord"), http.StatusOK)
+	serve(authorizedJSONRequest(http.MethodGet, instancePath+"/audit", "", "audit-token", "alice-discord"), http.StatusForbidden)
+	serve(authorizedJSONRequest(http.MethodGet, instancePath+"/audit?since=yesterday", "", "audit-token", "admin-discord"), http.StatusBadRequest)
+
+	type auditResponse struct {
+		Events []struct {
+			Action             string          `json:"action"`
+			ServicePrincipal   *string         `json:"service_principal"`
+			ActorDiscordUserID *string         `json:"actor_discord_user_id"`
+			Method             string          `json:"method"`
+			Route              string          `json:"route"`
+			Before             json.RawMessage `json:"before"`
+			After              json.RawMessage `json:"after"`
+		} `json:"events"`
+	}
+	var all auditResponse
+	if err := json.Unmarshal(serve(authorizedJSONRequest(http.MethodGet, instancePath+"/audit", "", "audit-token", "admin-discord"), http.StatusOK), &all); err != nil {
+		t.Fatalf("unmarshal audit response: %v", err)
+	}
+	if len(all.Events) != 2 {
+		t.Fatalf("expected 2 audit events, got %+v", all.Events)
+	}
+	link := all.Events[0]
+	if link.Action != "participant.discord_link" || link.Method != http.MethodPut || link.Route != "/instances/:instanceID/participants/:participantID/discord-link" {
+		t.Fatalf("unexpected link event: %+v", link)
+	}
+	if link.ServicePrincipal == nil || *link.ServicePrincipal != httpapi.DefaultServicePrincipal || link.ActorDiscordUserID == nil || *link.ActorDiscordUserID != "admin-discord" {
+		t.Fatalf("unexpected link actor: %+v", link)
+	}
+	if !strings.Contains(string(link.After), `"discord_user_id":"alice-discord"`) || strings.Contains(string(link.Before), "alice-discord") {
+		t.Fatalf("unexpected link payloads: before=%s after=%s", link.Before, link.After)
+	}
+	outcome := all.Events[1]
+	if outcome.Action != "outcome.upsert" || string(outcome.Before) != "null" || !strings.Contains(string(outcome.After), `"reason":"voted_out"`) {
+		t.Fatalf("unexpected outcome event: %+v", outcome)
+	}
+
+	var filtered auditResponse
+	if err := json.Unmarshal(serve(authorizedJSONRequest(http.MethodGet, instancePath+"/audit?action=outcome.upsert&actor=admin-discord", "", "audit-token", "admin-discord"), http.StatusOK), &filtered); err != nil {
+		t.Fatalf("unmarshal filtered audit response: %v", err)
+	}
+	if len(filtered.Events) != 1 || filtered.Events[0].Action != "outcome.upsert" {
+		t.Fatalf("unexpected filtered events: %+v", filtered.Events)
+	}
+
+	serve(authorizedJSONRequest(http.MethodPut, instancePath+"/scoring-strategy", `{"scoring_strategy":"winner-double"}`, "audit-token", "admin-discord"), http.StatusOK)
+	serve(authorizedJSONRequest(http.MethodPost, instancePath+"/episodes", `{"episode_number":1,"airs_at":"2026-03-04T01:00:00Z"}`, "audit-token", "admin-discord"), http.StatusCreated)
+	serve(authorizedJSONRequest(http.MethodPut, instancePath+"/state", `{"state":"completed"}`, "audit-token", "admin-discord"), http.StatusOK)
+	var recent auditResponse
+	if err := json.Unmarshal(serve(authorizedJSONRequest(http.MethodGet, instancePath+"/audit?limit=3", "", "audit-token", "admin-discord"), http.StatusOK), &recent); err != nil {
+		t.Fatalf("unmarshal recent audit response: %v", err)
+	}
+	if len(recent.Events) != 3 || recent.Events[0].Action != "instance.state" || recent.Events[1].Action != "episode.create" || recent.Events[2].Action != "scoring_strategy.set" {
+		t.Fatalf("unexpected recent events: %+v", recent.Events)
+	}
+	if !strings.Contains(string(recent.Events[0].Before), `"state":"active"`) || !strings.Contains(string(recent.Events[2].After), `"name":"winner-double"`) {
+		t.Fatalf("unexpected recent payloads: %+v", recent.Events)
+	}
+
+	if _, err := pool.Exec(ctx, `UPDATE audit_events SET action = 'edited'`); err == nil {
+		t.Fatal("expected audit events to reject updates")
+	}
+	if _, err := pool.Exec(ctx, `DELETE FROM audit_events`); err == nil {
+		t.Fatal("expected audit events to reject deletes")
+	}
+	if _, err := pool.Exec(ctx, `TRUNCATE audit_events`); err == nil {
+		t.Fatal("expected audit events to reject truncation")
+	}
+	if err := queries.DeleteInstanceByNameSeason(ctx, db.DeleteInstanceByNameSeasonParams{Name: instance.Name, Season: instance.Season}); err != nil {
+		t.Fatalf("expected deleting the instance to cascade to its audit events: %v", err)
+	}
+}
+
+func TestWebhookOutboxDeliversSignedEvents(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	instance := createInstanceForTest(t, ctx, queries, "Webhook Pool", 50)
+	contestant := createContestantForTest(t, ctx, queries, instance.ID, "Contestant A")
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	serviceAuth := httpapi.WithServiceAuth(httpapi.ServiceAuthConfig{
+		Enabled:      true,
+		BearerTokens: []string{"webhook-token"},
+	})
+	router := httpapi.New(pool, serviceAuth, httpapi.WithWebhookPrivateTargets(true)).Router()
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+
+	serve := func(req *http.Request, wantStatus int) []byte {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != wantStatus {
+			t.Fatalf("%s %s status = %d, body = %s", req.Method, req.URL.Path, recorder.Code, recorder.Body.String())
+		}
+		return recorder.Body.Bytes()
+	}
+
+	type received struct {
+		envelope webhooks.Envelope
+		err      error
+	}
+	deliveries := make(chan received, 4)
+	var secret string
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err == nil {
+			err = webhooks.Verify(secret, r.Header.Get(webhooks.SignatureHeader), body, time.Now(), time.Minute)
+		}
+		var envelope webhooks.Envelope
+		if err == nil {
+			err = json.Unmarshal(body, &envelope)
+		}
+		deliveries <- received{envelope: envelope, err: err}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer subscriber.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	serve(authorizedJSONRequest(http.MethodPost, instancePath+"/webhooks", `{"url":"ftp://example.com"}`, "webhook-token", "admin-discord"), http.StatusBadRequest)
+	strictRouter := httpapi.New(pool, serviceAuth).Router()
+	for _, target := range []string{subscriber.URL, "http://169.254.169.254/latest/meta-data", "http://[::1]:8080/hook", "http://10.0.0.5/hook"} {
+		recorder := httptest.NewRecorder()
+		strictRouter.ServeHTTP(recorder, authorizedJSONRequest(http.MethodPost, instancePath+"/webhooks", fmt.Sprintf(`{"url":%q}`, target), "webhook-token", "admin-discord"))
+		if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), "not allowed") {
+			t.Fatalf("expected internal target %s to be rejected, status = %d, body = %s", target, recorder.Code, recorder.Body.String())
+		}
+	}
+	serve(authorizedJSONRequest(http.MethodPost, instancePath+"/webhooks", fmt.Sprintf(`{"url":%q,"event_types":["bogus"]}`, subscriber.URL), "webhook-token", "admin-discord"), http.StatusBadRequest)
+
+	var created struct {
+		Webhook struct {
+			ID         string   `json:"id"`
+			EventTypes []string `json:"event_types"`
+			Secret     string   `json:"secret"`
+		} `json:"webhook"`
+	}
+	if err := json.Unmarshal(serve(authorizedJSONRequest(http.MethodPost, instancePath+"/webhooks", fmt.Sprintf(`{"url":%q,"event_types":["outcome.recorded"]}`, subscriber.URL), "webhook-token", "admin-discord"), http.StatusCreated), &created); err != nil {
+		t.Fatalf("unmarshal webhook response: %v", err)
+	}
+	if !strings.HasPrefix(created.Webhook.Secret, "whsec_") || len(created.Webhook.EventTypes) != 1 {
+		t.Fatalf("unexpected created webhook: %+v", created.Webhook)
+	}
+	secret = created.Webhook.Secret
+	serve(authorizedJSONRequest(http.MethodPost, instancePath+"/webhooks", fmt.Sprintf(`{"url":%q}`, failing.URL), "webhook-token", "admin-discord"), http.StatusCreated)
+
+	listBody := serve(authorizedJSONRequest(http.MethodGet, instancePath+"/webhooks", "", "webhook-token", "admin-discord"), http.StatusOK)
+	if strings.Contains(string(listBody), secret) {
+		t.Fatalf("expected listed webhooks to omit secrets, got %s", listBody)
+	}
+
+	serve(authorizedJSONRequest(http.MethodPut, instancePath+"/outcomes/1", fmt.Sprintf(`{"contestant_id":%q,"reason":"voted_out"}`, uuid.UUID(contestant.ID.Bytes).String()), "webhook-token", "admin-discord"), http.StatusOK)
+
+	dispatcher := webhooks.New(pool, webhooks.WithMaxAttempts(1), webhooks.WithPrivateTargets(true))
+	attempted, err := dispatcher.RunDue(ctx)
+	if err != nil {
+		t.Fatalf("run due deliveries: %v", err)
+	}
+	if attempted != 2 {
+		t.Fatalf("expected 2 delivery attempts, got %d", attempted)
+	}
+	select {
+	case got := <-deliveries:
+		if got.err != nil {
+			t.Fatalf("subscriber rejected delivery: %v", got.err)
+		}
+		if got.envelope.Type != webhooks.EventOutcomeRecorded || got.envelope.InstanceID != uuid.UUID(instance.ID.Bytes).String() || !strings.Contains(string(got.envelope.Data), `"reason":"voted_out"`) {
+			t.Fatalf("unexpected envelope: %+v", got.envelope)
+		}
+	default:
+		t.Fatal("expected subscriber to receive a delivery")
+	}
+
+	type deliveriesResponse struct {
+		Deliveries []struct {
+			ID             string `json:"id"`
+			Status         string `json:"status"`
+			Attempts       int32  `json:"attempts"`
+			LastStatusCode *int32 `json:"last_status_code"`
+		} `json:"deliveries"`
+	}
+	var dead deliveriesResponse
+	if err := json.Unmarshal(serve(authorizedJSONRequest(http.MethodGet, instancePath+"/webhooks/deliveries?status=dead", "", "webhook-token", "admin-discord"), http.StatusOK), &dead); err != nil {
+		t.Fatalf("unmarshal deliveries response: %v", err)
+	}
+	if len(dead.Deliveries) != 1 || dead.Deliveries[0].LastStatusCode == nil || *dead.Deliveries[0].LastStatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected dead deliveries: %+v", dead.Deliveries)
+	}
+	retryPath := instancePath + "/webhooks/deliveries/" + dead.Deliveries[0].ID + "/retry"
+	retried := serve(authorizedJSONRequest(http.MethodPost, retryPath, "", "webhook-token", "admin-discord"), http.StatusOK)
+	if !strings.Contains(string(retried), `"status":"pending"`) || !strings.Contains(string(retried), `"attempts":0`) {
+		t.Fatalf("unexpected retried delivery: %s", retried)
+	}
+	serve(authorizedJSONRequest(http.MethodPost, retryPath, "", "webhook-token", "admin-discord"), http.StatusConflict)
+
+	serve(authorizedJSONRequest(http.MethodDelete, instancePath+"/webhooks/"+created.Webhook.ID, "", "webhook-token", "admin-discord"), http.StatusOK)
+	serve(authorizedJSONRequest(http.MethodDelete, instancePath+"/webhooks/"+created.Webhook.ID, "", "webhook-token", "admin-discord"), http.StatusNotFound)
+}
+
+func TestInstanceEventStreamRespectsSecretVisibility(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	instance := createInstanceForTest(t, ctx, queries, "Stream Pool", 50)
+	alice := createParticipantForTest(t, ctx, queries, instance.ID, "Alice")
+	bob := createParticipantForTest(t, ctx, queries, instance.ID, "Bob")
+	contestant := createContestantForTest(t, ctx, queries, instance.ID, "Contestant A")
+	createEpisodeForTest(t, ctx, queries, instance.ID, 1, "Episode 1", time.Now().UTC().Add(7*24*time.Hour))
+	if _, err := queries.CreateInstanceAdmin(ctx, db.CreateInstanceAdminParams{InstanceID: instance.ID, DiscordUserID: "admin-discord"}); err != nil {
+		t.Fatalf("create instance admin: %v", err)
+	}
+	shutdown := make(chan struct{})
+	router := httpapi.New(pool, httpapi.WithServiceAuth(httpapi.ServiceAuthConfig{
+		Enabled:      true,
+		BearerTokens: []string{"stream-token"},
+	}), httpapi.WithEventStreamInterval(50*time.Millisecond), httpapi.WithShutdown(shutdown)).Router()
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+
+	serve := func(req *http.Request, wantStatus int) {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != wantStatus {
+			t.Fatalf("%s %s status = %d, body = %s", req.Method, req.URL.Path, recorder.Code, recorder.Body.String())
+		}
+	}
+	for discordUserID, participant := range map[string]db.CreateParticipantRow{"alice-discord": alice, "bob-discord": bob} {
+		serve(authorizedJSONRequest(http.MethodPut, instancePath+"/participants/"+uuid.UUID(participant.ID.Bytes).String()+"/discord-link", fmt.Sprintf(`{"discord_user_id":%q}`, discordUserID), "stream-token", "admin-discord"), http.StatusOK)
+	}
+	activity := createActivityForTest(t, ctx, queries, instance.ID, time.Date(2026, time.March, 21, 12, 0, 0, 0, time.UTC), nil, "journey", "Journey 1")
+	occurrence := createOccurrenceForTest(t, ctx, queries, activity.ID, "journey_resolution", "Journey 1 Resolution", time.Date(2026, time.March, 21, 12, 0, 0, 0, time.UTC))
+	createLedgerEntryForTest(t, ctx, queries, instance.ID, alice.ID, occurrence.ID, pgtype.UUID{}, "award", 2, "public", "public reward", "alice-public")
+	createLedgerEntryForTest(t, ctx, queries, instance.ID, bob.ID, occurrence.ID, pgtype.UUID{}, "award", 5, "secret", "hidden reward", "bob-secret")
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, server.URL+instancePath+"/events", nil)
+	if err != nil {
+		t.Fatalf("build stream request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer stream-token")
+	req.Header.Set("X-Discord-User-ID", "alice-discord")
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("open event stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("unexpected stream response: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	type streamedEvent struct {
+		name string
+		data string
+	}
+	events := make(chan streamedEvent, 16)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(resp.Body)
+		var current streamedEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event:"):
+				current.name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			case strings.HasPrefix(line, "data:"):
+				current.data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			case line == "" && current.name != "":
+				events <- current
+				current = streamedEvent{}
+			}
+		}
+	}()
+	next := func(name string) string {
+		t.Helper()
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					t.Fatalf("stream closed while waiting for %s", name)
+				}
+				if strings.Contains(event.data, uuid.UUID(bob.ID.Bytes).String()) && strings.Contains(event.data, "bonus_points_available") {
+					t.Fatalf("alice's stream exposed bob's balance: %s", event.data)
+				}
+				if event.name == name {
+					return event.data
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for %s", name)
+			}
+		}
+	}
+
+	var snapshot struct {
+		Leaderboard []struct {
+			ParticipantID string `json:"participant_id"`
+			BonusPoints   int    `json:"bonus_points"`
+		} `json:"leaderboard"`
+		Balance struct {
+			ParticipantID        string `json:"participant_id"`
+			BonusPointsAvailable int32  `json:"bonus_points_available"`
+		} `json:"balance"`
+	}
+	if err := json.Unmarshal([]byte(next("snapshot")), &snapshot); err != nil {
+		t.Fatalf("unmarshal snapshot: %v", err)
+	}
+	if snapshot.Balance.ParticipantID != uuid.UUID(alice.ID.Bytes).String() || snapshot.Balance.BonusPointsAvailable != 2 {
+		t.Fatalf("unexpected snapshot balance: %+v", snapshot.Balance)
+	}
+	for _, row := range snapshot.Leaderboard {
+		if row.ParticipantID == uuid.UUID(bob.ID.Bytes).String() && row.BonusPoints != 0 {
+			t.Fatalf("expected leaderboard to hide bob's secret points, got %+v", row)
+		}
+	}
+
+	serve(authorizedJSONRequest(http.MethodPost, instancePath+"/auction/lots/start", fmt.Sprintf(`{"contestant_id":%q}`, uuid.UUID(contestant.ID.Bytes).String()), "stream-token", "admin-discord"), http.StatusCreated)
+	if opened := next("auction_lot.opened"); !strings.Contains(opened, `"contestant_name":"Contestant A"`) {
+		t.Fatalf("unexpected lot opened event: %s", opened)
+	}
+
+	createLedgerEntryForTest(t, ctx, queries, instance.ID, alice.ID, occurrence.ID, pgtype.UUID{}, "award", 3, "secret", "alice hidden reward", "alice-secret")
+	if balance := next("balance.updated"); !strings.Contains(balance, `"bonus_points_available":5`) {
+		t.Fatalf("unexpected balance event: %s", balance)
+	}
+
+	close(shutdown)
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("expected the stream to end on shutdown")
+		}
+	}
+}
+
+func TestIdempotencyKeyReplaysPlayerWrites(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	router := httpapi.New(pool).Router()
+	instance := createInstanceForTest(t, ctx, queries, "Idempotency Pool", 50)
+	participant := createParticipantForTest(t, ctx, queries, instance.ID, "Alice")
+	if _, err := queries.SetParticipantDiscordUserID(ctx, db.SetParticipantDiscordUserIDParams{ID: participant.ID, DiscordUserID: pgtype.Text{String: "alice-discord", Valid: true}}); err != nil {
+		t.Fatalf("link participant: %v", err)
+	}
+	now := time.Now().UTC()
+	createEpisodeForTest(t, ctx, queries, instance.ID, 1, "Episode 1", now.Add(-time.Hour))
+	createEpisodeForTest(t, ctx, queries, instance.ID, 2, "Episode 2", now.Add(24*time.Hour))
+	if _, err := queries.CreateInstanceActivity(ctx, db.CreateInstanceActivityParams{
+		InstanceID:   instance.ID,
+		ActivityType: "loan_shark",
+		Name:         "Loan Shark",
+		Status:       "active",
+		StartsAt:     timestamptz(now.Add(-2 * time.Hour)),
+		Metadata:     testEmptyJSONB,
+	}); err != nil {
+		t.Fatalf("create loan shark activity: %v", err)
+	}
+	instancePath := "/instances/" + uuid.UUID(instance.ID.Bytes).String()
+
+	send := func(path, body, key string, wantStatus int) *httptest.ResponseRecorder {
+		t.Helper()
+		req := authorizedJSONRequest(http.MethodPost, path, body, "", "alice-discord")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != wantStatus {
+			t.Fatalf("POST %s (key %q) status = %d, body = %s", path, key, recorder.Code, recorder.Body.String())
+		}
+		return recorder
+	}
+	balance := func() int {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodGet, instancePath+"/loan-shark/me", "", "", "alice-discord"))
+		var status struct {
+			Loan struct {
+				BonusPointsAvailable int `json:"bonus_points_available"`
+			} `json:"loan"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil {
+			t.Fatalf("unmarshal loan status: %v", err)
+		}
+		return status.Loan.BonusPointsAvailable
+	}
+
+	borrowed := send(instancePath+"/loan-shark/me/borrow", `{"points":3}`, "interaction-borrow", http.StatusOK)
+	replayedBorrow := send(instancePath+"/loan-shark/me/borrow", `{"points":3}`, "interaction-borrow", http.StatusOK)
+	if replayedBorrow.Header().Get("Idempotent-Replayed") != "true" || replayedBorrow.Body.String() != borrowed.Body.String() {
+		t.Fatalf("expected the original borrow response to be replayed, got %q", replayedBorrow.Body.String())
+	}
+	if got := balance(); got != 3 {
+		t.Fatalf("expected 3 points after one borrow, got %d", got)
+	}
+
+	send(instancePath+"/loan-shark/me/repay", `{"points":1}`, "interaction-repay", http.StatusOK)
+	send(instancePath+"/loan-shark/me/repay", `{"points":1}`, "interaction-repay", http.StatusOK)
+	if got := balance(); got != 2 {
+		t.Fatalf("expected a replayed repayment to apply once, got balance %d", got)
+	}
+	send(instancePath+"/loan-shark/me/repay", `{"points":2}`, "interaction-repay", http.StatusUnprocessableEntity)
+
+	send(instancePath+"/loan-shark/me/repay", `{"points":1}`, "", http.StatusOK)
+	if got := balance(); got != 1 {
+		t.Fatalf("expected a request without a key to apply, got balance %d", got)
+	}
+
+	oversized := `{"points":1,"note":"` + strings.Repeat("x", 1<<20) + `"}`
+	send(instancePath+"/loan-shark/me/repay", oversized, "interaction-oversized", http.StatusRequestEntityTooLarge)
+
+	if _, err := queries.SetInstanceState(ctx, db.SetInstanceStateParams{State: "completed", ID: instance.ID}); err != nil {
+		t.Fatalf("complete instance: %v", err)
+	}
+	replayedAfterClose := send(instancePath+"/loan-shark/me/borrow", `{"points":3}`, "interaction-borrow", http.StatusOK)
+	if replayedAfterClose.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("expected a retry to replay after the instance closed, got %q", replayedAfterClose.Body.String())
+	}
+	send(instancePath+"/loan-shark/me/borrow", `{"points":1}`, "", http.StatusConflict)
+}
+
+func TestAPICredentialsAreScopedRotatedAndRevoked(t *testing.T) {
+	ctx, pool := integrationPool(t)
+	defer pool.Close()
+	resetDatabase(t, ctx, pool)
+
+	queries := db.New(pool)
+	router := httpapi.New(pool, httpapi.WithServiceAuth(httpapi.ServiceAuthConfig{Enabled: true, BearerTokens: []string{"bootstrap-token"}})).Router()
+	allowed := createInstanceForTest(t, ctx, queries, "Credential Pool", 50)
+	other := createInstanceForTest(t, ctx, queries, "Other Credential Pool", 51)
+	allowedPath := "/instances/" + uuid.UUID(allowed.ID.Bytes).String()
+	otherPath := "/instances/" + uuid.UUID(other.ID.Bytes).String()
+
+	type credentialToken struct {
+		Credential struct {
+			ID     string   `json:"id"`
+			Scopes []string `json:"scopes"`
+		} `json:"credential"`
+		Token string `json:"token"`
+	}
+	send := func(method, path, body, token string, wantStatus int) *httptest.ResponseRecorder {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(method, path, body, token, ""))
+		if recorder.Code != wantStatus {
+			t.Fatalf("%s %s status = %d, want %d, body = %s", method, path, recorder.Code, wantStatus, recorder.Body.String())
+		}
+		return recorder
+	}
+	issue := func(body string) credentialToken {
+		t.Helper()
+		var issued credentialToken
+		if err := json.Unmarshal(send(http.MethodPost, "/credentials", body, "bootstrap-token", http.StatusCreated).Body.Bytes(), &issued); err != nil {
+			t.Fatalf("decode credential: %v", err)
+		}
+		if !strings.HasPrefix(issued.Token, "cak_") {
+			t.Fatalf("unexpected token %q", issued.Token)
+		}
+		return issued
+	}
+
+	suffix := uuid.NewString()
+	reader := issue(fmt.Sprintf(`{"name":"reader-%s","scopes":["read-only"],"instance_ids":[%q]}`, suffix, uuid.UUID(allowed.ID.Bytes).String()))
+	send(http.MethodGet, allowedPath+"/leaderboard", "", reader.Token, http.StatusOK)
+	send(http.MethodGet, otherPath+"/leaderboard", "", reader.Token, http.StatusForbidden)
+	send(http.MethodPost, allowedPath+"/participants", `{"name":"Alice"}`, reader.Token, http.StatusForbidden)
+	send(http.MethodGet, "/credentials", "", reader.Token, http.StatusForbidden)
+
+	player := issue(fmt.Sprintf(`{"name":"player-%s","scopes":["player-actions"],"discord_user_id":"alice-discord"}`, suffix))
+	actAs := func(token, discordUserID string, wantStatus int) {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, authorizedJSONRequest(http.MethodGet, allowedPath+"/participants/me", "", token, discordUserID))
+		if recorder.Code != wantStatus {
+			t.Fatalf("participants/me as %q status = %d, want %d, body = %s", discordUserID, recorder.Code, wantStatus, recorder.Body.String())
+		}
+	}
+	actAs(player.Token, "alice-discord", http.StatusNotFound)
+	actAs(player.Token, "bob-discord", http.StatusForbidden)
+	send(http.MethodPost, allowedPath+"/contestants", `{"name":"Contestant A"}`, player.Token, http.StatusForbidden)
+	send(http.MethodPost, allowedPath+"/participants", `{"name":"Alice"}`, player.Token, http.StatusForbidden)
+	send(http.MethodPut, allowedPath+"/outcomes/1", `{}`, player.Token, http.StatusForbidden)
+	send(http.MethodPost, allowedPath+"/activities", `{}`, player.Token, http.StatusForbidden)
+
+	var listed struct {
+		Instances []struct {
+			ID string `json:"id"`
+		} `json:"instances"`
+	}
+	if err := json.Unmarshal(send(http.MethodGet, "/instances", "", reader.Token, http.StatusOK).Body.Bytes(), &listed); err != nil {
+		t.Fatalf("decode instances: %v", err)
+	}
+	if len(listed.Instances) != 1 || listed.Instances[0].ID != uuid.UUID(allowed.ID.Bytes).String() {
+		t.Fatalf("expected only the allowed instance, got %+v", listed.Instances)
+	}
+
+	admin := issue(fmt.Sprintf(`{"name":"admin-%s","scopes":["super-admin","read-only"]}`, suffix))
+	if len(admin.Credential.Scopes) != 2 || admin.Credential.Scopes[0] != "read-only" {
+		t.Fatalf("unexpected normalized scopes %v", admin.Credential.Scopes)
+	}
+	send(http.MethodPost, otherPath+"/participants", `{"name":"Bob"}`, admin.Token, http.StatusCreated)
+	actAs(admin.Token, "bob-discord", http.StatusNotFound)
+
+	var rotated credentialToken
+	if err := json.Unmarshal(send(http.MethodPost, "/credentials/"+admin.Credential.ID+"/rotate", `{"grace_period_seconds":300}`, admin.Token, http.StatusOK).Body.Bytes(), &rotated); err != nil {
+		t.Fatalf("decode rotated credential: %v", err)
+	}
+	if rotated.Token == admin.Token {
+		t.Fatal("expected rotation to issue a new token")
+	}
+	send(http.MethodGet, "/credentials", "", admin.Token, http.StatusOK)
+	send(http.MethodGet, "/credentials", "", rotated.Token, http.StatusOK)
+
+	send(http.MethodDelete, "/credentials/"+admin.Credential.ID, "", "bootstrap-token", http.StatusOK)
+	send(http.MethodGet, "/credentials", "", rotated.Token, http.StatusUnauthorized)
+	send(http.MethodGet, "/credentials", "", admin.Token, http.StatusUnauthorized)
+	send(http.MethodPost, "/credentials/"+admin.Credential.ID+"/rotate", "", "bootstrap-token", http.StatusNotFound)
+
+	send(http.MethodGet, "/audit", "", reader.Token, http.StatusForbidden)
+	var audit struct {
+		Events []struct {
+			Action string          `json:"action"`
+			After  json.RawMessage `json:"after"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal(send(http.MethodGet, "/audit", "", "bootstrap-token", http.StatusOK).Body.Bytes(), &audit); err != nil {
+		t.Fatalf("decode audit events: %v", err)
+	}
+	wantActions := []string{"credential.revoke", "credential.rotate", "credential.create", "credential.create", "credential.create"}
+	if len(audit.Events) != len(wantActions) {
+		t.Fatalf("expected %d credential audit events, got %+v", len(wantActions), audit.Events)
+	}
+	for i, event := range audit.Events {
+		if event.Action != wantActions[i] {
+			t.Fatalf("audit event %d action = %q, want %q", i, event.Action, wantActions[i])
+		}
+		if i < 3 && !strings.Contains(string(event.After), admin.Credential.ID) {
+			t.Fatalf("audit event %d does not name the admin credential: %s", i, event.After)
+		}
+	}
+	if rotation := string(audit.Events[1].After); !strings.Contains(rotation, `"previous_token_expires_at"`) {
+		t.Fatalf("rotation audit event lacks the grace period: %s", rotation)
+	}
+	if strings.Contains(string(audit.Events[1].After), rotated.Token) || strings.Contains(string(audit.Events[2].After), admin.Token) {
+		t.Fatal("expected credential audit events to leave out tokens")
+	}
+}
+
 func integrationPool(t *testing.T) (context.Context, *pgxpool.Pool) {
 	t.Helper()
 	databaseURL := os.Getenv("CASTAWAY_TEST_DATABASE_URL")
@@ -1682,7 +4251,7 @@ func authorizedJSONRequest(method, path, body, bearerToken, discordUserID string
 
 func createInstanceForTest(t *testing.T, ctx context.Context, queries *db.Queries, name string, season int32) db.CreateInstanceRow {
 	t.Helper()
-	instance, err := queries.CreateInstance(ctx, db.CreateInstanceParams{Name: name, Season: season})
+	instance, err := queries.CreateInstance(ctx, db.CreateInstanceParams{Name: name, Season: season, State: pgtype.Text{String: "active", Valid: true}})
 	if err != nil {
 		t.Fatalf("create instance: %v", err)
 	}
@@ -1744,6 +4313,21 @@ func upsertOutcomeForTest(t *testing.T, ctx context.Context, queries *db.Queries
 	}
 }
 
+func createEpisodeForTest(t *testing.T, ctx context.Context, queries *db.Queries, instanceID pgtype.UUID, number int32, label string, airsAt time.Time) db.CreateInstanceEpisodeRow {
+	t.Helper()
+	episode, err := queries.CreateInstanceEpisode(ctx, db.CreateInstanceEpisodeParams{
+		InstanceID:    instanceID,
+		EpisodeNumber: number,
+		Label:         label,
+		AirsAt:        timestamptz(airsAt),
+		Metadata:      testEmptyJSONB,
+	})
+	if err != nil {
+		t.Fatalf("create episode %d: %v", number, err)
+	}
+	return episode
+}
+
 func createActivityForTest(t *testing.T, ctx context.Context, queries *db.Queries, instanceID pgtype.UUID, startsAt time.Time, endsAt *time.Time, activityType string, name string) db.CreateInstanceActivityRow {
 	t.Helper()
 	activity, err := queries.CreateInstanceActivity(ctx, db.CreateInstanceActivityParams{
@@ -0,0 +1,326 @@
+package httpapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/webhooks"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// WithWebhookPrivateTargets lets webhooks be created for loopback, private
+// and link-local addresses, for local development and tests. Otherwise such
+// targets are rejected.
+func WithWebhookPrivateTargets(allowed bool) Option {
+	return func(s *Server) {
+		s.webhookPrivateTargets = allowed
+	}
+}
+
+type createWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+func (s *Server) listWebhooks(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+	subscriptions, err := s.queries.ListWebhookSubscriptionsByInstance(c.Request.Context(), toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	response := make([]gin.H, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, webhookSubscriptionToJSON(db.CreateWebhookSubscriptionRow(subscription)))
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": response, "event_types": webhooks.EventTypes()})
+}
+
+// createWebhook subscribes url to the instance's domain events. URLs that
+// resolve to internal addresses are refused. The signing secret is generated
+// here and only returned in this response.
+func (s *Server) createWebhook(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+	var req createWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	target, err := webhooks.ValidateTarget(c.Request.Context(), req.URL, s.webhookPrivateTargets)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	eventTypes, err := normalizeWebhookEventTypes(req.EventTypes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	subscription, err := qtx.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+		Url:        target.String(),
+		Secret:     secret,
+		EventTypes: eventTypes,
+		InstanceID: toPGUUID(instanceID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	response := webhookSubscriptionToJSON(subscription)
+	// The audit copy leaves the secret out.
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionWebhookCreate, nil, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	response["secret"] = secret
+	c.JSON(http.StatusCreated, gin.H{"webhook": response})
+}
+
+func (s *Server) deleteWebhook(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	webhookID, ok := parseUUIDPath(c, "webhookID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	deleted, err := qtx.DeleteWebhookSubscription(ctx, db.DeleteWebhookSubscriptionParams{
+		InstanceID: toPGUUID(instanceID),
+		ID:         toPGUUID(webhookID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "webhook not found"})
+			return
+		}
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	response := webhookSubscriptionToJSON(db.CreateWebhookSubscriptionRow(deleted))
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionWebhookDelete, response, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"webhook": response})
+}
+
+// listWebhookDeliveries shows recent deliveries, newest first. Filtering on
+// status=dead gives the dead-letter view.
+func (s *Server) listWebhookDeliveries(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+	status := strings.TrimSpace(c.Query("status"))
+	switch status {
+	case "", webhooks.StatusPending, webhooks.StatusDelivered, webhooks.StatusDead:
+	default:
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "status must be one of pending, delivered, dead"})
+		return
+	}
+	deliveries, err := s.queries.ListWebhookDeliveriesByInstance(c.Request.Context(), db.ListWebhookDeliveriesByInstanceParams{
+		InstanceID: toPGUUID(instanceID),
+		Status:     pgtype.Text{String: status, Valid: status != ""},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	response := make([]gin.H, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, webhookDeliveryToJSON(db.GetWebhookDeliveryRow(delivery)))
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": response})
+}
+
+// retryWebhookDelivery requeues a dead delivery with a fresh attempt count.
+func (s *Server) retryWebhookDelivery(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseUUIDPath(c, "deliveryID")
+	if !ok {
+		return
+	}
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+	params := db.GetWebhookDeliveryParams{InstanceID: toPGUUID(instanceID), ID: toPGUUID(deliveryID)}
+	delivery, err := s.queries.GetWebhookDelivery(c.Request.Context(), params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "delivery not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if delivery.Status != webhooks.StatusDead {
+		c.JSON(http.StatusConflict, errorResponse{Error: fmt.Sprintf("only dead deliveries can be retried; delivery is %s", delivery.Status)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	retried, err := qtx.RetryWebhookDelivery(ctx, db.RetryWebhookDeliveryParams{
+		NextAttemptAt: optionalTime(time.Now().UTC()),
+		InstanceID:    toPGUUID(instanceID),
+		ID:            toPGUUID(deliveryID),
+	})
+	if err != nil {
+		c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+		return
+	}
+	if retried == 0 {
+		c.JSON(http.StatusConflict, errorResponse{Error: "delivery changed; reload and try again"})
+		return
+	}
+	before := webhookDeliveryToJSON(delivery)
+	delivery, err = qtx.GetWebhookDelivery(ctx, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	response := webhookDeliveryToJSON(delivery)
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionWebhookDeliveryRetry, before, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"delivery": response})
+}
+
+// normalizeWebhookEventTypes trims, dedupes and sorts event types. An empty
+// list subscribes to every event.
+func normalizeWebhookEventTypes(raw []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(raw))
+	eventTypes := make([]string, 0, len(raw))
+	for _, eventType := range raw {
+		eventType = strings.TrimSpace(eventType)
+		if eventType == "" {
+			continue
+		}
+		if !webhooks.IsEventType(eventType) {
+			return nil, fmt.Errorf("unsupported event type %q", eventType)
+		}
+		if _, ok := seen[eventType]; ok {
+			continue
+		}
+		seen[eventType] = struct{}{}
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	return eventTypes, nil
+}
+
+func newWebhookSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(raw), nil
+}
+
+func webhookSubscriptionToJSON(subscription db.CreateWebhookSubscriptionRow) gin.H {
+	return gin.H{
+		"id":          pgUUIDString(subscription.ID),
+		"url":         subscription.Url,
+		"event_types": subscription.EventTypes,
+		"created_at":  formatTimestamp(subscription.CreatedAt),
+	}
+}
+
+func webhookDeliveryToJSON(delivery db.GetWebhookDeliveryRow) gin.H {
+	return gin.H{
+		"id":               pgUUIDString(delivery.ID),
+		"webhook_id":       pgUUIDString(delivery.SubscriptionID),
+		"url":              delivery.Url,
+		"event_id":         pgUUIDString(delivery.EventID),
+		"event_type":       delivery.EventType,
+		"payload":          json.RawMessage(delivery.Payload),
+		"status":           delivery.Status,
+		"attempts":         delivery.Attempts,
+		"next_attempt_at":  formatTimestamp(delivery.NextAttemptAt),
+		"last_status_code": pgInt4Pointer(delivery.LastStatusCode),
+		"last_error":       pgTextPointer(delivery.LastError),
+		"delivered_at":     formatNullableTimestamp(delivery.DeliveredAt),
+		"created_at":       formatTimestamp(delivery.CreatedAt),
+		"updated_at":       formatTimestamp(delivery.UpdatedAt),
+	}
+}
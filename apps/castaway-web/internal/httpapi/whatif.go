@@ -0,0 +1,196 @@
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/scoring"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type whatIfElimination struct {
+	ContestantID   string `json:"contestant_id"`
+	ContestantName string `json:"contestant_name"`
+	Position       *int   `json:"position"`
+}
+
+type whatIfRequest struct {
+	Eliminations []whatIfElimination `json:"eliminations" binding:"required"`
+}
+
+type resolvedWhatIfElimination struct {
+	ContestantID   string `json:"contestant_id"`
+	ContestantName string `json:"contestant_name"`
+	Position       int    `json:"position"`
+}
+
+// whatIfLeaderboard projects the leaderboard with hypothetical eliminations
+// layered over the real outcomes. Nothing is persisted.
+func (s *Server) whatIfLeaderboard(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+
+	var req whatIfRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	if len(req.Eliminations) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "eliminations cannot be empty"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	inputs, err := s.loadLeaderboardInputs(ctx, toPGUUID(instanceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	finalPositions, err := s.finalPositionsAt(ctx, inputs, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	visibleBonusByParticipant, err := s.visibleBonusAt(ctx, inputs, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	projectedPositions, eliminations, err := applyWhatIfEliminations(inputs.contestants, finalPositions, req.Eliminations)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	current := scoring.CalculateLeaderboard(inputs.strategy, inputs.totalPositions, inputs.participantNames, inputs.draftsByParticipant, finalPositions, visibleBonusByParticipant)
+	projected := scoring.CalculateLeaderboard(inputs.strategy, inputs.totalPositions, inputs.participantNames, inputs.draftsByParticipant, projectedPositions, visibleBonusByParticipant)
+	currentRanks := scoring.Ranks(current)
+	projectedRanks := scoring.Ranks(projected)
+	currentTotals := make(map[string]int, len(current))
+	for _, row := range current {
+		currentTotals[row.ParticipantID] = row.TotalPoints
+	}
+
+	response := make([]gin.H, 0, len(projected))
+	for _, row := range projected {
+		response = append(response, gin.H{
+			"participant_id":              row.ParticipantID,
+			"participant_name":            row.ParticipantName,
+			"rank":                        projectedRanks[row.ParticipantID],
+			"current_rank":                currentRanks[row.ParticipantID],
+			"rank_change":                 currentRanks[row.ParticipantID] - projectedRanks[row.ParticipantID],
+			"draft_points":                row.DraftPoints,
+			"bonus_points":                row.BonusPoints,
+			"total_points":                row.TotalPoints,
+			"current_total_points":        currentTotals[row.ParticipantID],
+			"points_change":               row.TotalPoints - currentTotals[row.ParticipantID],
+			"points_available":            row.PointsAvailable,
+			"max_possible_points":         row.MaxPossiblePoints,
+			"eliminated_on_current_bonus": row.EliminatedOnCurrentBonus,
+			"clinched_on_current_bonus":   row.ClinchedOnCurrentBonus,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scoring_strategy": inputs.strategy.Name(),
+		"eliminations":     eliminations,
+		"leaderboard":      response,
+	})
+}
+
+// applyWhatIfEliminations returns a copy of finalPositions with the
+// hypothetical eliminations added. Eliminations without a position take the
+// highest open position in request order, so the first one listed goes next.
+func applyWhatIfEliminations(contestants []db.ListContestantsByInstanceRow, finalPositions map[string]int, eliminations []whatIfElimination) (map[string]int, []resolvedWhatIfElimination, error) {
+	totalPositions := len(contestants)
+	projected := make(map[string]int, len(finalPositions)+len(eliminations))
+	taken := make(map[int]struct{}, len(finalPositions)+len(eliminations))
+	for contestantID, position := range finalPositions {
+		projected[contestantID] = position
+		taken[position] = struct{}{}
+	}
+
+	names := make(map[string]string, len(contestants))
+	for _, contestant := range contestants {
+		names[uuid.UUID(contestant.ID.Bytes).String()] = contestant.Name
+	}
+
+	resolved := make([]resolvedWhatIfElimination, 0, len(eliminations))
+	for _, elimination := range eliminations {
+		contestantID, err := resolveWhatIfContestant(contestants, elimination)
+		if err != nil {
+			return nil, nil, err
+		}
+		if position, ok := projected[contestantID]; ok {
+			return nil, nil, fmt.Errorf("%s is already out at position %d", names[contestantID], position)
+		}
+
+		position := 0
+		if elimination.Position != nil {
+			position = *elimination.Position
+			if position < 1 || position > totalPositions {
+				return nil, nil, fmt.Errorf("position must be between 1 and %d", totalPositions)
+			}
+			if _, ok := taken[position]; ok {
+				return nil, nil, fmt.Errorf("position %d is already taken", position)
+			}
+		} else {
+			for candidate := totalPositions; candidate >= 1; candidate-- {
+				if _, ok := taken[candidate]; !ok {
+					position = candidate
+					break
+				}
+			}
+			if position == 0 {
+				return nil, nil, errors.New("no open positions remain")
+			}
+		}
+
+		projected[contestantID] = position
+		taken[position] = struct{}{}
+		resolved = append(resolved, resolvedWhatIfElimination{
+			ContestantID:   contestantID,
+			ContestantName: names[contestantID],
+			Position:       position,
+		})
+	}
+	return projected, resolved, nil
+}
+
+func resolveWhatIfContestant(contestants []db.ListContestantsByInstanceRow, elimination whatIfElimination) (string, error) {
+	if rawID := strings.TrimSpace(elimination.ContestantID); rawID != "" {
+		contestantID, err := uuid.Parse(rawID)
+		if err != nil {
+			return "", fmt.Errorf("invalid contestant id: %s", rawID)
+		}
+		for _, contestant := range contestants {
+			if uuid.UUID(contestant.ID.Bytes) == contestantID {
+				return contestantID.String(), nil
+			}
+		}
+		return "", fmt.Errorf("contestant does not belong to this instance: %s", rawID)
+	}
+
+	name := strings.TrimSpace(elimination.ContestantName)
+	if name == "" {
+		return "", errors.New("each elimination needs a contestant_id or contestant_name")
+	}
+	for _, contestant := range contestants {
+		if strings.EqualFold(strings.TrimSpace(contestant.Name), name) {
+			return uuid.UUID(contestant.ID.Bytes).String(), nil
+		}
+	}
+	return "", fmt.Errorf("contestant not found: %s", name)
+}
@@ -0,0 +1,78 @@
+package httpapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func whatIfContestants(names ...string) []db.ListContestantsByInstanceRow {
+	contestants := make([]db.ListContestantsByInstanceRow, 0, len(names))
+	for _, name := range names {
+		contestants = append(contestants, db.ListContestantsByInstanceRow{
+			ID:   pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			Name: name,
+		})
+	}
+	return contestants
+}
+
+func TestApplyWhatIfEliminationsFillsNextOpenPositions(t *testing.T) {
+	contestants := whatIfContestants("Alpha", "Bravo", "Charlie", "Delta")
+	alphaID := uuid.UUID(contestants[0].ID.Bytes).String()
+	bravoID := uuid.UUID(contestants[1].ID.Bytes).String()
+	charlieID := uuid.UUID(contestants[2].ID.Bytes).String()
+	finals := map[string]int{alphaID: 4}
+
+	projected, resolved, err := applyWhatIfEliminations(contestants, finals, []whatIfElimination{
+		{ContestantName: " bravo "},
+		{ContestantID: charlieID},
+	})
+	if err != nil {
+		t.Fatalf("apply eliminations: %v", err)
+	}
+	if projected[alphaID] != 4 || projected[bravoID] != 3 || projected[charlieID] != 2 {
+		t.Fatalf("unexpected projected positions: %+v", projected)
+	}
+	if len(finals) != 1 {
+		t.Fatalf("expected real outcomes to be left untouched, got %+v", finals)
+	}
+	if len(resolved) != 2 || resolved[0].ContestantName != "Bravo" || resolved[0].Position != 3 || resolved[1].ContestantName != "Charlie" {
+		t.Fatalf("unexpected resolved eliminations: %+v", resolved)
+	}
+}
+
+func TestApplyWhatIfEliminationsRejectsInvalidEliminations(t *testing.T) {
+	contestants := whatIfContestants("Alpha", "Bravo", "Charlie")
+	alphaID := uuid.UUID(contestants[0].ID.Bytes).String()
+	finals := map[string]int{alphaID: 3}
+	two := 2
+	three := 3
+	seven := 7
+
+	cases := []struct {
+		name         string
+		eliminations []whatIfElimination
+		want         string
+	}{
+		{name: "already out", eliminations: []whatIfElimination{{ContestantName: "Alpha"}}, want: "already out"},
+		{name: "unknown", eliminations: []whatIfElimination{{ContestantName: "Zulu"}}, want: "contestant not found"},
+		{name: "foreign id", eliminations: []whatIfElimination{{ContestantID: uuid.NewString()}}, want: "does not belong"},
+		{name: "empty", eliminations: []whatIfElimination{{}}, want: "contestant_id or contestant_name"},
+		{name: "duplicate", eliminations: []whatIfElimination{{ContestantName: "Bravo"}, {ContestantName: "bravo"}}, want: "already out"},
+		{name: "taken position", eliminations: []whatIfElimination{{ContestantName: "Bravo", Position: &three}}, want: "position 3 is already taken"},
+		{name: "out of range", eliminations: []whatIfElimination{{ContestantName: "Bravo", Position: &seven}}, want: "between 1 and 3"},
+		{name: "explicit position", eliminations: []whatIfElimination{{ContestantName: "Bravo", Position: &two}, {ContestantName: "Charlie", Position: &two}}, want: "position 2 is already taken"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := applyWhatIfEliminations(contestants, finals, tc.eliminations)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package httpapi
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/scoring"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxGeneratedSeed keeps server-chosen seeds exactly representable as JSON
+// numbers so clients can pass them back to reproduce a run.
+const maxGeneratedSeed = 1<<53 - 1
+
+func contestantOddsResponse(contestantID pgtype.UUID, contestantName string, winOdds pgtype.Float8) gin.H {
+	var odds any
+	if winOdds.Valid {
+		odds = winOdds.Float64
+	}
+	return gin.H{
+		"contestant_id":   pgUUIDString(contestantID),
+		"contestant_name": contestantName,
+		"odds":            odds,
+	}
+}
+
+func (s *Server) listContestantOdds(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+
+	rows, err := s.queries.ListContestantOddsByInstance(c.Request.Context(), toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, contestantOddsResponse(row.ContestantID, row.ContestantName, row.WinOdds))
+	}
+	c.JSON(http.StatusOK, gin.H{"odds": response})
+}
+
+type contestantOddsInput struct {
+	ContestantID string   `json:"contestant_id" binding:"required"`
+	Odds         *float64 `json:"odds"`
+}
+
+type setContestantOddsRequest struct {
+	Odds []contestantOddsInput `json:"odds" binding:"required"`
+}
+
+// setContestantOdds records admin-supplied odds used to weight win
+// probability simulations. A null odds value clears it.
+func (s *Server) setContestantOdds(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+
+	var req setContestantOddsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	params := make([]db.SetContestantOddsParams, 0, len(req.Odds))
+	for _, input := range req.Odds {
+		contestantID, err := uuid.Parse(strings.TrimSpace(input.ContestantID))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: "invalid contestant_id: " + input.ContestantID})
+			return
+		}
+		var winOdds pgtype.Float8
+		if input.Odds != nil {
+			if *input.Odds <= 0 {
+				c.JSON(http.StatusBadRequest, errorResponse{Error: "odds must be greater than zero"})
+				return
+			}
+			winOdds = pgtype.Float8{Float64: *input.Odds, Valid: true}
+		}
+		params = append(params, db.SetContestantOddsParams{
+			WinOdds:      winOdds,
+			InstanceID:   toPGUUID(instanceID),
+			ContestantID: toPGUUID(contestantID),
+		})
+	}
+
+	if !s.requireInstanceAdminRequest(c, instanceID) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	defer rollbackTx(c, tx)
+	qtx := s.queries.WithTx(tx)
+
+	current, err := qtx.ListContestantOddsByInstance(ctx, toPGUUID(instanceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	currentByContestant := make(map[string]gin.H, len(current))
+	for _, row := range current {
+		currentByContestant[pgUUIDString(row.ContestantID)] = contestantOddsResponse(row.ContestantID, row.ContestantName, row.WinOdds)
+	}
+	before := make([]gin.H, 0, len(params))
+	response := make([]gin.H, 0, len(params))
+	for _, param := range params {
+		if previous, ok := currentByContestant[pgUUIDString(param.ContestantID)]; ok {
+			before = append(before, previous)
+		}
+		row, err := qtx.SetContestantOdds(ctx, param)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				c.JSON(http.StatusNotFound, errorResponse{Error: "contestant not found in instance: " + pgUUIDString(param.ContestantID)})
+				return
+			}
+			c.JSON(statusFromPg(err), errorResponse{Error: err.Error()})
+			return
+		}
+		response = append(response, contestantOddsResponse(row.ContestantID, row.ContestantName, row.WinOdds))
+	}
+	if err := recordAuditEvent(c, qtx, toPGUUID(instanceID), auditActionContestantOddsSet, before, response); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"odds": response})
+}
+
+// winProbabilities runs a Monte Carlo simulation of the remaining final
+// positions and reports each participant's chance of finishing first.
+func (s *Server) winProbabilities(c *gin.Context) {
+	instanceID, ok := parseUUIDPath(c, "instanceID")
+	if !ok {
+		return
+	}
+	iterations, ok := parseSimulationIterationsQuery(c)
+	if !ok {
+		return
+	}
+	seed, ok := parseSimulationSeedQuery(c)
+	if !ok {
+		return
+	}
+	weighted := true
+	if raw := strings.TrimSpace(c.Query("weighted")); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: "weighted must be a boolean"})
+			return
+		}
+		weighted = parsed
+	}
+
+	ctx := c.Request.Context()
+	inputs, err := s.loadLeaderboardInputs(ctx, toPGUUID(instanceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse{Error: "instance not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	finalPositions, err := s.finalPositionsAt(ctx, inputs, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	visibleBonusByParticipant, err := s.visibleBonusAt(ctx, inputs, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	odds := map[string]float64{}
+	if weighted {
+		rows, err := s.queries.ListContestantOddsByInstance(ctx, inputs.instanceID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		for _, row := range rows {
+			if row.WinOdds.Valid {
+				odds[pgUUIDString(row.ContestantID)] = row.WinOdds.Float64
+			}
+		}
+	}
+
+	contestantIDs := make([]string, 0, len(inputs.contestants))
+	for _, contestant := range inputs.contestants {
+		contestantIDs = append(contestantIDs, uuid.UUID(contestant.ID.Bytes).String())
+	}
+
+	current := scoring.CalculateLeaderboard(inputs.strategy, inputs.totalPositions, inputs.participantNames, inputs.draftsByParticipant, finalPositions, visibleBonusByParticipant)
+	currentTotals := make(map[string]int, len(current))
+	for _, row := range current {
+		currentTotals[row.ParticipantID] = row.TotalPoints
+	}
+
+	results := scoring.Simulate(inputs.strategy, inputs.totalPositions, contestantIDs, inputs.participantNames, inputs.draftsByParticipant, finalPositions, visibleBonusByParticipant, scoring.SimulationOptions{
+		Iterations: iterations,
+		Seed:       seed,
+		Odds:       odds,
+	})
+
+	response := make([]gin.H, 0, len(results))
+	for _, result := range results {
+		response = append(response, gin.H{
+			"participant_id":        result.ParticipantID,
+			"participant_name":      result.ParticipantName,
+			"win_probability":       result.WinProbability,
+			"expected_total_points": result.ExpectedTotalPoints,
+			"current_total_points":  currentTotals[result.ParticipantID],
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scoring_strategy": inputs.strategy.Name(),
+		"iterations":       iterations,
+		"seed":             seed,
+		"weighted":         len(odds) > 0,
+		"probabilities":    response,
+	})
+}
+
+func parseSimulationIterationsQuery(c *gin.Context) (int, bool) {
+	raw := strings.TrimSpace(c.Query("iterations"))
+	if raw == "" {
+		return scoring.DefaultSimulationIterations, true
+	}
+	iterations, err := strconv.Atoi(raw)
+	if err != nil || iterations <= 0 || iterations > scoring.MaxSimulationIterations {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("iterations must be between 1 and %d", scoring.MaxSimulationIterations)})
+		return 0, false
+	}
+	return iterations, true
+}
+
+// parseSimulationSeedQuery reads the seed query parameter, choosing a random
+// seed when it is omitted so every response can be reproduced.
+func parseSimulationSeedQuery(c *gin.Context) (uint64, bool) {
+	raw := strings.TrimSpace(c.Query("seed"))
+	if raw == "" {
+		var buf [8]byte
+		if _, err := rand.Read(buf[:]); err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return 0, false
+		}
+		return binary.BigEndian.Uint64(buf[:]) & maxGeneratedSeed, true
+	}
+	seed, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "seed must be a non-negative integer"})
+		return 0, false
+	}
+	return seed, true
+}
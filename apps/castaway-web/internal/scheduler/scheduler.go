@@ -0,0 +1,349 @@
+// Package scheduler runs jobs at instance episode boundaries. Jobs live in
+// Postgres so they survive restarts, each job runs at most once per episode,
+// and every attempt is recorded as a run. The runner also sweeps expired
+// request state, such as idempotency keys, on every tick.
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Job statuses.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusSkipped   = "skipped"
+	StatusFailed    = "failed"
+)
+
+const (
+	// DefaultLookback is how far back an episode may have aired and still get
+	// jobs planned for it, so a server that was down over a boundary catches up.
+	DefaultLookback = 7 * 24 * time.Hour
+	// DefaultMaxAttempts is how many times a failing job runs before it is
+	// left failed for an admin to retry.
+	DefaultMaxAttempts = 5
+
+	maxBackoff = time.Hour
+)
+
+// Clock reports the current time. Tests inject a fixed clock so due jobs run
+// without waiting for a real episode to air.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to Clock.
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock reads the wall clock.
+var SystemClock Clock = ClockFunc(func() time.Time { return time.Now().UTC() })
+
+// Job is a claimed job handed to its Handler.
+type Job struct {
+	ID            pgtype.UUID
+	InstanceID    pgtype.UUID
+	EpisodeID     pgtype.UUID
+	EpisodeNumber int32
+	EpisodeAirsAt time.Time
+	JobType       string
+	RunAt         time.Time
+	Attempts      int32
+	Now           time.Time
+}
+
+// Result is what a handler reports. Detail is stored as the run's JSON result.
+type Result struct {
+	Skipped bool
+	Detail  any
+}
+
+// Handler performs one job inside tx. Returning an error rolls back the
+// handler's writes and schedules a retry.
+type Handler func(ctx context.Context, tx pgx.Tx, job Job) (Result, error)
+
+type Runner struct {
+	pool        *pgxpool.Pool
+	queries     *db.Queries
+	handlers    map[string]Handler
+	clock       Clock
+	lookback    time.Duration
+	maxAttempts int32
+	logger      *slog.Logger
+}
+
+type Option func(*Runner)
+
+func WithClock(clock Clock) Option {
+	return func(r *Runner) {
+		r.clock = clock
+	}
+}
+
+func WithLookback(lookback time.Duration) Option {
+	return func(r *Runner) {
+		r.lookback = lookback
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(r *Runner) {
+		r.logger = logger
+	}
+}
+
+func New(pool *pgxpool.Pool, handlers map[string]Handler, options ...Option) *Runner {
+	runner := &Runner{
+		pool:        pool,
+		queries:     db.New(pool),
+		handlers:    handlers,
+		clock:       SystemClock,
+		lookback:    DefaultLookback,
+		maxAttempts: DefaultMaxAttempts,
+		logger:      slog.Default(),
+	}
+	for _, option := range options {
+		option(runner)
+	}
+	return runner
+}
+
+// JobTypes lists the registered job types in a stable order.
+func (r *Runner) JobTypes() []string {
+	types := make([]string, 0, len(r.handlers))
+	for jobType := range r.handlers {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Plan creates a pending job of every registered type for each episode of an
+// active instance that airs after now minus the lookback. Re-planning only
+// moves the run time of jobs that are still pending.
+func (r *Runner) Plan(ctx context.Context) (int64, error) {
+	plannedAfter := pgtype.Timestamptz{Time: r.clock.Now().Add(-r.lookback), Valid: true}
+	var planned int64
+	for _, jobType := range r.JobTypes() {
+		rows, err := r.queries.PlanEpisodeJobs(ctx, db.PlanEpisodeJobsParams{
+			JobType:      jobType,
+			PlannedAfter: plannedAfter,
+		})
+		if err != nil {
+			return planned, fmt.Errorf("plan %s jobs: %w", jobType, err)
+		}
+		planned += rows
+	}
+	return planned, nil
+}
+
+// RunDue plans jobs and then runs every job whose time has come, returning
+// how many were run.
+func (r *Runner) RunDue(ctx context.Context) (int, error) {
+	if _, err := r.Plan(ctx); err != nil {
+		return 0, err
+	}
+	ran := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return ran, err
+		}
+		claimed, err := r.runNext(ctx)
+		if err != nil {
+			return ran, err
+		}
+		if !claimed {
+			return ran, nil
+		}
+		ran++
+	}
+}
+
+// Sweep deletes idempotency keys whose replay window has passed.
+func (r *Runner) Sweep(ctx context.Context) error {
+	if err := r.queries.DeleteExpiredIdempotencyKeys(ctx, pgtype.Timestamptz{Time: r.clock.Now(), Valid: true}); err != nil {
+		return fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+	return nil
+}
+
+// Run calls RunDue and Sweep every interval until ctx is cancelled.
+func (r *Runner) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if ran, err := r.RunDue(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("scheduler run failed", slog.String("service", "castaway-web"), slog.String("error", err.Error()))
+		} else if ran > 0 {
+			r.logger.Info("scheduler ran jobs", slog.String("service", "castaway-web"), slog.Int("jobs", ran))
+		}
+		if err := r.Sweep(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("scheduler sweep failed", slog.String("service", "castaway-web"), slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runNext claims one due job and runs it. The claim row stays locked for the
+// whole run so concurrent runners skip it, and the handler runs in a
+// savepoint so a failure can be recorded without keeping its writes.
+func (r *Runner) runNext(ctx context.Context) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin scheduler tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	q := r.queries.WithTx(tx)
+
+	now := r.clock.Now()
+	claimed, err := q.ClaimDueScheduledJob(ctx, pgtype.Timestamptz{Time: now, Valid: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("claim scheduled job: %w", err)
+	}
+	job := Job{
+		ID:            claimed.ID,
+		InstanceID:    claimed.InstanceID,
+		EpisodeID:     claimed.EpisodeID,
+		EpisodeNumber: claimed.EpisodeNumber,
+		EpisodeAirsAt: claimed.EpisodeAirsAt.Time,
+		JobType:       claimed.JobType,
+		RunAt:         claimed.RunAt.Time,
+		Attempts:      claimed.Attempts,
+		Now:           now,
+	}
+
+	result, runErr := r.runHandler(ctx, tx, claimed.InstanceState, job)
+	finishedAt := pgtype.Timestamptz{Time: r.clock.Now(), Valid: true}
+	if runErr != nil {
+		if err := q.FailScheduledJob(ctx, db.FailScheduledJobParams{
+			LastError:   pgtype.Text{String: runErr.Error(), Valid: true},
+			MaxAttempts: r.maxAttempts,
+			RetryAt:     pgtype.Timestamptz{Time: now.Add(Backoff(job.Attempts)), Valid: true},
+			ID:          job.ID,
+		}); err != nil {
+			return false, fmt.Errorf("record failed job: %w", err)
+		}
+		if err := q.CreateScheduledJobRun(ctx, db.CreateScheduledJobRunParams{
+			Status:         StatusFailed,
+			StartedAt:      pgtype.Timestamptz{Time: now, Valid: true},
+			FinishedAt:     finishedAt,
+			Result:         []byte("{}"),
+			Error:          pgtype.Text{String: runErr.Error(), Valid: true},
+			ScheduledJobID: job.ID,
+		}); err != nil {
+			return false, fmt.Errorf("record failed run: %w", err)
+		}
+		r.logger.Warn("scheduled job failed",
+			slog.String("service", "castaway-web"),
+			slog.String("job_type", job.JobType),
+			slog.Int("attempt", int(job.Attempts)+1),
+			slog.String("error", runErr.Error()),
+		)
+		return true, commit(ctx, tx)
+	}
+
+	status := StatusSucceeded
+	if result.Skipped {
+		status = StatusSkipped
+	}
+	detail, err := marshalDetail(result.Detail)
+	if err != nil {
+		return false, err
+	}
+	if err := q.CompleteScheduledJob(ctx, db.CompleteScheduledJobParams{
+		Status:      status,
+		CompletedAt: finishedAt,
+		ID:          job.ID,
+	}); err != nil {
+		return false, fmt.Errorf("complete scheduled job: %w", err)
+	}
+	if err := q.CreateScheduledJobRun(ctx, db.CreateScheduledJobRunParams{
+		Status:         status,
+		StartedAt:      pgtype.Timestamptz{Time: now, Valid: true},
+		FinishedAt:     finishedAt,
+		Result:         detail,
+		ScheduledJobID: job.ID,
+	}); err != nil {
+		return false, fmt.Errorf("record job run: %w", err)
+	}
+	return true, commit(ctx, tx)
+}
+
+func (r *Runner) runHandler(ctx context.Context, tx pgx.Tx, instanceState string, job Job) (Result, error) {
+	if instanceState != "active" {
+		return Result{Skipped: true, Detail: map[string]string{"reason": "instance is " + instanceState}}, nil
+	}
+	handler, ok := r.handlers[job.JobType]
+	if !ok {
+		return Result{Skipped: true, Detail: map[string]string{"reason": "no handler for " + job.JobType}}, nil
+	}
+
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return Result{}, fmt.Errorf("begin job savepoint: %w", err)
+	}
+	result, err := handler(ctx, savepoint, job)
+	if err != nil {
+		_ = savepoint.Rollback(ctx)
+		return Result{}, err
+	}
+	if err := savepoint.Commit(ctx); err != nil {
+		return Result{}, fmt.Errorf("release job savepoint: %w", err)
+	}
+	return result, nil
+}
+
+// Backoff is the delay before retrying a job that has failed attempts times:
+// one minute, doubling up to an hour.
+func Backoff(attempts int32) time.Duration {
+	delay := time.Minute
+	for i := int32(0); i < attempts; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
+
+func marshalDetail(detail any) ([]byte, error) {
+	if detail == nil {
+		return []byte("{}"), nil
+	}
+	encoded, err := json.Marshal(detail)
+	if err != nil {
+		return nil, fmt.Errorf("encode job result: %w", err)
+	}
+	return encoded, nil
+}
+
+func commit(ctx context.Context, tx pgx.Tx) error {
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit scheduler tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBackoffDoublesUpToAnHour(t *testing.T) {
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{attempts: 0, want: time.Minute},
+		{attempts: 1, want: 2 * time.Minute},
+		{attempts: 3, want: 8 * time.Minute},
+		{attempts: 6, want: time.Hour},
+		{attempts: 40, want: time.Hour},
+	}
+	for _, tc := range tests {
+		if got := Backoff(tc.attempts); got != tc.want {
+			t.Fatalf("Backoff(%d) = %s, want %s", tc.attempts, got, tc.want)
+		}
+	}
+}
+
+func TestNewAppliesOptions(t *testing.T) {
+	fixed := time.Date(2026, time.September, 24, 0, 0, 0, 0, time.UTC)
+	runner := New(nil, map[string]Handler{"b": nil, "a": nil},
+		WithClock(ClockFunc(func() time.Time { return fixed })),
+		WithLookback(time.Hour),
+	)
+
+	if got := runner.clock.Now(); !got.Equal(fixed) {
+		t.Fatalf("clock = %s, want %s", got, fixed)
+	}
+	if runner.lookback != time.Hour {
+		t.Fatalf("lookback = %s, want 1h", runner.lookback)
+	}
+	if runner.maxAttempts != DefaultMaxAttempts {
+		t.Fatalf("max attempts = %d, want %d", runner.maxAttempts, DefaultMaxAttempts)
+	}
+	if got := runner.JobTypes(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("job types = %v, want [a b]", got)
+	}
+}
+
+func TestMarshalDetailDefaultsToEmptyObject(t *testing.T) {
+	got, err := marshalDetail(nil)
+	if err != nil || string(got) != "{}" {
+		t.Fatalf("marshalDetail(nil) = %q, %v", got, err)
+	}
+	got, err = marshalDetail(map[string]int{"closed": 1})
+	if err != nil || string(got) != `{"closed":1}` {
+		t.Fatalf("marshalDetail(map) = %q, %v", got, err)
+	}
+}
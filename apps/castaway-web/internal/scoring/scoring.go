@@ -15,19 +15,35 @@ type LeaderboardEntry struct {
 	BonusPoints     int
 	TotalPoints     int
 	PointsAvailable int
+	// MaxPossiblePoints is the most TotalPoints can reach if bonus totals
+	// stay where they are.
+	MaxPossiblePoints int
+	// EliminatedOnCurrentBonus is set when the participant can no longer
+	// finish first, even in a tie, unless bonus totals change. Future awards,
+	// spends and corrections are not bounded, so it is not a guarantee.
+	EliminatedOnCurrentBonus bool
+	// ClinchedOnCurrentBonus is set when no other participant can catch the
+	// participant unless bonus totals change.
+	ClinchedOnCurrentBonus bool
 }
 
+// CalculateLeaderboard scores every participant's draft with strategy and adds
+// their visible bonus points. A nil strategy uses DefaultStrategy.
 func CalculateLeaderboard(
+	strategy Strategy,
 	totalPositions int,
 	participantNames map[string]string,
 	draftsByParticipant map[string][]DraftPick,
 	finalPositions map[string]int,
 	visibleBonusByParticipant map[string]int,
 ) []LeaderboardEntry {
+	if strategy == nil {
+		strategy = DefaultStrategy()
+	}
 	entries := make([]LeaderboardEntry, 0, len(participantNames))
 	for participantID, participantName := range participantNames {
 		draft := draftsByParticipant[participantID]
-		draftPoints := calculateCurrentScore(draft, finalPositions, totalPositions)
+		draftPoints := calculateCurrentScore(strategy, draft, finalPositions, totalPositions)
 		bonusPoints := visibleBonusByParticipant[participantID]
 		totalPoints := draftPoints + bonusPoints
 		entry := LeaderboardEntry{
@@ -37,10 +53,14 @@ func CalculateLeaderboard(
 			DraftPoints:     draftPoints,
 			BonusPoints:     bonusPoints,
 			TotalPoints:     totalPoints,
-			PointsAvailable: calculatePointsAvailable(draft, finalPositions, totalPositions),
+			PointsAvailable: strategy.PointsAvailable(draft, finalPositions, totalPositions),
+			// PointsAvailable is an estimate for the distance strategy, so
+			// the bound uses the best open position for every remaining pick.
+			MaxPossiblePoints: totalPoints + bestCasePointsAvailable(strategy, draft, finalPositions, totalPositions),
 		}
 		entries = append(entries, entry)
 	}
+	markContention(entries)
 
 	sort.Slice(entries, func(i, j int) bool {
 		if entries[i].TotalPoints != entries[j].TotalPoints {
@@ -52,14 +72,56 @@ func CalculateLeaderboard(
 	return entries
 }
 
-func calculateCurrentScore(draft []DraftPick, finalPositions map[string]int, totalPositions int) int {
+// Ranks assigns standard competition ranks ("1224") by total points, so tied
+// participants share a rank and the next rank is skipped.
+func Ranks(entries []LeaderboardEntry) map[string]int {
+	ranks := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		rank := 1
+		for _, other := range entries {
+			if other.TotalPoints > entry.TotalPoints {
+				rank++
+			}
+		}
+		ranks[entry.ParticipantID] = rank
+	}
+	return ranks
+}
+
+// markContention compares every participant's current total with the best
+// the others can still reach from their drafts. Bonus totals are held where
+// they are: draft points never go down, so with bonus fixed a current total is
+// a floor and MaxPossiblePoints a ceiling. Awards, spends and corrections
+// still to come can move either bound, which is why the flags say so.
+func markContention(entries []LeaderboardEntry) {
+	if len(entries) < 2 {
+		return
+	}
+	for i := range entries {
+		bestOtherTotal, bestOtherMax := 0, 0
+		first := true
+		for j, other := range entries {
+			if i == j {
+				continue
+			}
+			if first || other.TotalPoints > bestOtherTotal {
+				bestOtherTotal = other.TotalPoints
+			}
+			if first || other.MaxPossiblePoints > bestOtherMax {
+				bestOtherMax = other.MaxPossiblePoints
+			}
+			first = false
+		}
+		entries[i].EliminatedOnCurrentBonus = entries[i].MaxPossiblePoints < bestOtherTotal
+		entries[i].ClinchedOnCurrentBonus = entries[i].TotalPoints > bestOtherMax
+	}
+}
+
+func calculateCurrentScore(strategy Strategy, draft []DraftPick, finalPositions map[string]int, totalPositions int) int {
 	currentScore := 0
 	for _, draftEntry := range draft {
 		if finalPosition, ok := finalPositions[draftEntry.ContestantID]; ok {
-			distance := abs(draftEntry.Position - finalPosition)
-			positionValue := totalPositions - finalPosition + 1
-			entryScore := max(0, positionValue-distance)
-			currentScore += entryScore
+			currentScore += strategy.PickPoints(draftEntry.Position, finalPosition, totalPositions)
 		}
 	}
 	return currentScore
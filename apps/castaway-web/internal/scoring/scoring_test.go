@@ -1,6 +1,10 @@
 package scoring
 
-import "testing"
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
 
 func TestCalculateLeaderboardSort(t *testing.T) {
 	participantNames := map[string]string{"p1": "Bryan", "p2": "Amanda"}
@@ -18,7 +22,7 @@ func TestCalculateLeaderboardSort(t *testing.T) {
 	}
 	finals := map[string]int{"A": 1, "B": 2}
 
-	leaderboard := CalculateLeaderboard(3, participantNames, drafts, finals, map[string]int{"p2": 2})
+	leaderboard := CalculateLeaderboard(DefaultStrategy(), 3, participantNames, drafts, finals, map[string]int{"p2": 2})
 	if len(leaderboard) != 2 {
 		t.Fatalf("expected 2 rows, got %d", len(leaderboard))
 	}
@@ -41,7 +45,7 @@ func TestCalculateLeaderboardPointsAvailable(t *testing.T) {
 	}
 	finals := map[string]int{"A": 1}
 
-	leaderboard := CalculateLeaderboard(3, participantNames, drafts, finals, nil)
+	leaderboard := CalculateLeaderboard(DefaultStrategy(), 3, participantNames, drafts, finals, nil)
 	if leaderboard[0].Score != 3 {
 		t.Fatalf("expected score 3, got %d", leaderboard[0].Score)
 	}
@@ -52,3 +56,228 @@ func TestCalculateLeaderboardPointsAvailable(t *testing.T) {
 		t.Fatalf("expected points available > 0, got %d", leaderboard[0].PointsAvailable)
 	}
 }
+
+func TestRanksShareTiedPositions(t *testing.T) {
+	entries := []LeaderboardEntry{
+		{ParticipantID: "p1", TotalPoints: 12},
+		{ParticipantID: "p2", TotalPoints: 9},
+		{ParticipantID: "p3", TotalPoints: 9},
+		{ParticipantID: "p4", TotalPoints: 4},
+	}
+
+	ranks := Ranks(entries)
+	expected := map[string]int{"p1": 1, "p2": 2, "p3": 2, "p4": 4}
+	for participantID, rank := range expected {
+		if ranks[participantID] != rank {
+			t.Fatalf("expected %s to rank %d, got %d", participantID, rank, ranks[participantID])
+		}
+	}
+}
+
+func TestStrategiesScoreDraftPicks(t *testing.T) {
+	participantNames := map[string]string{"p1": "Bryan"}
+	drafts := map[string][]DraftPick{
+		"p1": {
+			{Position: 1, ContestantID: "A"},
+			{Position: 2, ContestantID: "B"},
+			{Position: 3, ContestantID: "C"},
+		},
+	}
+	finals := map[string]int{"A": 1, "B": 3, "C": 2}
+
+	expected := map[string]int{
+		StrategyDistance:        4,
+		StrategyExactMatchBonus: 4 + ExactMatchBonusPoints,
+		StrategyTopHeavy:        12,
+		StrategyWinnerDouble:    7,
+	}
+	for name, draftPoints := range expected {
+		strategy, ok := StrategyByName(name)
+		if !ok {
+			t.Fatalf("expected strategy %q to be registered", name)
+		}
+		leaderboard := CalculateLeaderboard(strategy, 3, participantNames, drafts, finals, nil)
+		if leaderboard[0].DraftPoints != draftPoints {
+			t.Fatalf("%s: expected %d draft points, got %d", name, draftPoints, leaderboard[0].DraftPoints)
+		}
+	}
+}
+
+func TestStrategyByNameDefaultsAndRejectsUnknown(t *testing.T) {
+	strategy, ok := StrategyByName("")
+	if !ok || strategy.Name() != StrategyDistance {
+		t.Fatalf("expected empty name to select distance, got %v", strategy)
+	}
+	if _, ok := StrategyByName("coin-flip"); ok {
+		t.Fatal("expected unknown strategy to be rejected")
+	}
+}
+
+func TestBestCasePointsAvailableUsesOpenPositions(t *testing.T) {
+	draft := []DraftPick{
+		{Position: 1, ContestantID: "A"},
+		{Position: 2, ContestantID: "B"},
+		{Position: 3, ContestantID: "C"},
+	}
+	finals := map[string]int{"C": 3}
+
+	// A can still win (3 + bonus) and B can still finish second (2 + bonus).
+	available := ExactMatchBonusStrategy{}.PointsAvailable(draft, finals, 3)
+	if available != 3+2+2*ExactMatchBonusPoints {
+		t.Fatalf("expected %d points available, got %d", 3+2+2*ExactMatchBonusPoints, available)
+	}
+}
+
+func TestSimulateSettledSeasonIsDeterministicAndSplitsTies(t *testing.T) {
+	names := map[string]string{"p1": "Alpha", "p2": "Bravo", "p3": "Charlie"}
+	drafts := map[string][]DraftPick{
+		"p1": {{Position: 1, ContestantID: "A"}, {Position: 2, ContestantID: "B"}},
+		"p2": {{Position: 1, ContestantID: "A"}, {Position: 2, ContestantID: "B"}},
+		"p3": {{Position: 1, ContestantID: "B"}, {Position: 2, ContestantID: "A"}},
+	}
+	finals := map[string]int{"A": 1, "B": 2}
+
+	results := Simulate(nil, 2, []string{"A", "B"}, names, drafts, finals, map[string]int{"p3": 1}, SimulationOptions{Iterations: 50, Seed: 7})
+	if len(results) != 3 {
+		t.Fatalf("expected three results, got %+v", results)
+	}
+	if results[0].ParticipantName != "Alpha" || results[0].WinProbability != 0.5 || results[0].ExpectedTotalPoints != 3 {
+		t.Fatalf("unexpected first result: %+v", results[0])
+	}
+	if results[1].ParticipantName != "Bravo" || results[1].WinProbability != 0.5 {
+		t.Fatalf("unexpected second result: %+v", results[1])
+	}
+	if results[2].ParticipantName != "Charlie" || results[2].WinProbability != 0 || results[2].ExpectedTotalPoints != 2 {
+		t.Fatalf("unexpected third result: %+v", results[2])
+	}
+}
+
+func TestSimulateUsesOddsAndSeed(t *testing.T) {
+	names := map[string]string{"p1": "Alpha", "p2": "Bravo"}
+	drafts := map[string][]DraftPick{
+		"p1": {{Position: 1, ContestantID: "A"}, {Position: 2, ContestantID: "B"}, {Position: 3, ContestantID: "C"}},
+		"p2": {{Position: 1, ContestantID: "C"}, {Position: 2, ContestantID: "B"}, {Position: 3, ContestantID: "A"}},
+	}
+	contestants := []string{"A", "B", "C"}
+	opts := SimulationOptions{Iterations: 2000, Seed: 42, Odds: map[string]float64{"A": 50, "B": 1, "C": 1}}
+
+	results := Simulate(nil, 3, contestants, names, drafts, map[string]int{}, map[string]int{}, opts)
+	if results[0].ParticipantName != "Alpha" || results[0].WinProbability < 0.9 {
+		t.Fatalf("expected the favourite's drafter to be a heavy favourite, got %+v", results)
+	}
+	if total := results[0].WinProbability + results[1].WinProbability; total < 0.999 || total > 1.001 {
+		t.Fatalf("expected win probabilities to sum to 1, got %f", total)
+	}
+
+	again := Simulate(nil, 3, contestants, names, drafts, map[string]int{}, map[string]int{}, opts)
+	if again[0] != results[0] || again[1] != results[1] {
+		t.Fatalf("expected the same seed to reproduce results, got %+v and %+v", results, again)
+	}
+
+	uniform := Simulate(nil, 3, contestants, names, drafts, map[string]int{}, map[string]int{}, SimulationOptions{Iterations: 2000, Seed: 42})
+	for _, result := range uniform {
+		if result.WinProbability < 0.35 || result.WinProbability > 0.65 {
+			t.Fatalf("expected symmetric drafts to split a uniform simulation, got %+v", uniform)
+		}
+	}
+}
+
+func TestCalculateLeaderboardFlagsClinchAndElimination(t *testing.T) {
+	participantNames := map[string]string{"p1": "Bryan", "p2": "Amanda", "p3": "Keith"}
+	drafts := map[string][]DraftPick{
+		"p1": {{Position: 1, ContestantID: "A"}, {Position: 2, ContestantID: "B"}, {Position: 3, ContestantID: "C"}},
+		"p2": {{Position: 1, ContestantID: "B"}, {Position: 2, ContestantID: "A"}, {Position: 3, ContestantID: "C"}},
+		"p3": {{Position: 1, ContestantID: "C"}, {Position: 2, ContestantID: "B"}, {Position: 3, ContestantID: "A"}},
+	}
+
+	open := CalculateLeaderboard(DefaultStrategy(), 3, participantNames, drafts, map[string]int{}, nil)
+	for _, entry := range open {
+		if entry.ClinchedOnCurrentBonus || entry.EliminatedOnCurrentBonus {
+			t.Fatalf("expected nobody decided before any outcomes, got %+v", entry)
+		}
+	}
+
+	contention := func(bonus map[string]int) map[string]LeaderboardEntry {
+		byID := map[string]LeaderboardEntry{}
+		for _, entry := range CalculateLeaderboard(DefaultStrategy(), 3, participantNames, drafts, map[string]int{"A": 1, "B": 2}, bonus) {
+			byID[entry.ParticipantID] = entry
+		}
+		return byID
+	}
+
+	byID := contention(nil)
+	if leader := byID["p1"]; !leader.ClinchedOnCurrentBonus || leader.EliminatedOnCurrentBonus || leader.TotalPoints != 5 || leader.MaxPossiblePoints != 6 {
+		t.Fatalf("expected p1 to have clinched, got %+v", leader)
+	}
+	if second := byID["p2"]; !second.EliminatedOnCurrentBonus || second.MaxPossiblePoints != 4 {
+		t.Fatalf("expected p2 to be eliminated, got %+v", second)
+	}
+	if third := byID["p3"]; !third.EliminatedOnCurrentBonus || third.ClinchedOnCurrentBonus {
+		t.Fatalf("expected p3 to be eliminated, got %+v", third)
+	}
+
+	// A bonus point lets p2 tie p1, so neither is decided.
+	byID = contention(map[string]int{"p2": 1})
+	if leader := byID["p1"]; leader.ClinchedOnCurrentBonus || leader.EliminatedOnCurrentBonus {
+		t.Fatalf("expected p1 to no longer have clinched, got %+v", leader)
+	}
+	if second := byID["p2"]; second.ClinchedOnCurrentBonus || second.EliminatedOnCurrentBonus || second.MaxPossiblePoints != 5 {
+		t.Fatalf("expected p2 to still be able to tie, got %+v", second)
+	}
+}
+
+// sharedStrategyCases is the fixture the CLI scorer is also tested against,
+// so the two implementations cannot drift apart.
+type sharedStrategyCases struct {
+	Strategies []struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	} `json:"strategies"`
+	Cases []struct {
+		Name           string         `json:"name"`
+		TotalPositions int            `json:"total_positions"`
+		Draft          []string       `json:"draft"`
+		FinalPositions map[string]int `json:"final_positions"`
+		Expected       map[string]struct {
+			Score           int `json:"score"`
+			PointsAvailable int `json:"points_available"`
+		} `json:"expected"`
+	} `json:"cases"`
+}
+
+func TestStrategiesMatchSharedCases(t *testing.T) {
+	raw, err := os.ReadFile("../../../../testdata/scoring-strategies.json")
+	if err != nil {
+		t.Fatalf("read shared strategy cases: %v", err)
+	}
+	var fixture sharedStrategyCases
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		t.Fatalf("decode shared strategy cases: %v", err)
+	}
+
+	strategies := Strategies()
+	if len(strategies) != len(fixture.Strategies) {
+		t.Fatalf("expected %d strategies in the shared fixture, got %d", len(strategies), len(fixture.Strategies))
+	}
+	for i, want := range fixture.Strategies {
+		if strategies[i].Name() != want.Name || strategies[i].Description() != want.Description {
+			t.Fatalf("strategy %d = %q (%q), want %q (%q)", i, strategies[i].Name(), strategies[i].Description(), want.Name, want.Description)
+		}
+	}
+	for _, tc := range fixture.Cases {
+		draft := make([]DraftPick, 0, len(tc.Draft))
+		for i, contestantID := range tc.Draft {
+			draft = append(draft, DraftPick{Position: i + 1, ContestantID: contestantID})
+		}
+		for name, want := range tc.Expected {
+			strategy, ok := StrategyByName(name)
+			if !ok {
+				t.Fatalf("expected strategy %q to be registered", name)
+			}
+			leaderboard := CalculateLeaderboard(strategy, tc.TotalPositions, map[string]string{"p1": "Bryan"}, map[string][]DraftPick{"p1": draft}, tc.FinalPositions, nil)
+			if leaderboard[0].DraftPoints != want.Score || leaderboard[0].PointsAvailable != want.PointsAvailable {
+				t.Fatalf("%s: %s scored %d with %d available, want %d with %d", tc.Name, name, leaderboard[0].DraftPoints, leaderboard[0].PointsAvailable, want.Score, want.PointsAvailable)
+			}
+		}
+	}
+}
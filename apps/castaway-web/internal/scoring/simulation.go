@@ -0,0 +1,183 @@
+package scoring
+
+import (
+	"math/rand/v2"
+	"sort"
+)
+
+const (
+	// DefaultSimulationIterations is used when SimulationOptions.Iterations is
+	// not positive.
+	DefaultSimulationIterations = 10000
+	// MaxSimulationIterations bounds the work a single simulation may do.
+	MaxSimulationIterations = 100000
+)
+
+// SimulationOptions controls a Monte Carlo simulation of the rest of a season.
+type SimulationOptions struct {
+	Iterations int
+	Seed       uint64
+	// Odds weights the contestants still in the game by contestant id. Each
+	// open position, best first, is filled by a contestant drawn with
+	// probability proportional to its odds. Contestants without odds take
+	// the average of those that have them, so an empty map is uniform.
+	Odds map[string]float64
+}
+
+type SimulationResult struct {
+	ParticipantID       string
+	ParticipantName     string
+	WinProbability      float64
+	ExpectedTotalPoints float64
+}
+
+// Simulate plays out the remaining final positions opts.Iterations times and
+// reports how often each participant finishes first, with ties splitting the
+// win, and their average total points. A nil strategy uses DefaultStrategy.
+func Simulate(
+	strategy Strategy,
+	totalPositions int,
+	contestantIDs []string,
+	participantNames map[string]string,
+	draftsByParticipant map[string][]DraftPick,
+	finalPositions map[string]int,
+	visibleBonusByParticipant map[string]int,
+	opts SimulationOptions,
+) []SimulationResult {
+	if strategy == nil {
+		strategy = DefaultStrategy()
+	}
+	iterations := opts.Iterations
+	if iterations <= 0 {
+		iterations = DefaultSimulationIterations
+	}
+	iterations = min(iterations, MaxSimulationIterations)
+
+	taken := make(map[int]struct{}, len(finalPositions))
+	positions := make(map[string]int, totalPositions)
+	for contestantID, position := range finalPositions {
+		taken[position] = struct{}{}
+		positions[contestantID] = position
+	}
+	openPositions := make([]int, 0, totalPositions)
+	for position := 1; position <= totalPositions; position++ {
+		if _, ok := taken[position]; !ok {
+			openPositions = append(openPositions, position)
+		}
+	}
+	remaining := make([]string, 0, len(contestantIDs))
+	for _, contestantID := range contestantIDs {
+		if _, ok := finalPositions[contestantID]; !ok {
+			remaining = append(remaining, contestantID)
+		}
+	}
+	sort.Strings(remaining)
+	weights := simulationWeights(remaining, opts.Odds)
+
+	participantIDs := make([]string, 0, len(participantNames))
+	for participantID := range participantNames {
+		participantIDs = append(participantIDs, participantID)
+	}
+	sort.Strings(participantIDs)
+
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed)) //nolint:gosec // seeded for reproducible simulations, not security
+	wins := make(map[string]float64, len(participantIDs))
+	totals := make(map[string]float64, len(participantIDs))
+	order := make([]int, len(remaining))
+	leaders := make([]string, 0, len(participantIDs))
+	for range iterations {
+		weightedOrder(rng, weights, order)
+		for slot, index := range order {
+			if slot < len(openPositions) {
+				positions[remaining[index]] = openPositions[slot]
+			} else {
+				delete(positions, remaining[index])
+			}
+		}
+
+		best := 0
+		leaders = leaders[:0]
+		for _, participantID := range participantIDs {
+			total := calculateCurrentScore(strategy, draftsByParticipant[participantID], positions, totalPositions) + visibleBonusByParticipant[participantID]
+			totals[participantID] += float64(total)
+			switch {
+			case len(leaders) == 0 || total > best:
+				best = total
+				leaders = append(leaders[:0], participantID)
+			case total == best:
+				leaders = append(leaders, participantID)
+			}
+		}
+		for _, participantID := range leaders {
+			wins[participantID] += 1 / float64(len(leaders))
+		}
+	}
+
+	results := make([]SimulationResult, 0, len(participantIDs))
+	for _, participantID := range participantIDs {
+		results = append(results, SimulationResult{
+			ParticipantID:       participantID,
+			ParticipantName:     participantNames[participantID],
+			WinProbability:      wins[participantID] / float64(iterations),
+			ExpectedTotalPoints: totals[participantID] / float64(iterations),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].WinProbability != results[j].WinProbability {
+			return results[i].WinProbability > results[j].WinProbability
+		}
+		if results[i].ExpectedTotalPoints != results[j].ExpectedTotalPoints {
+			return results[i].ExpectedTotalPoints > results[j].ExpectedTotalPoints
+		}
+		return results[i].ParticipantName < results[j].ParticipantName
+	})
+	return results
+}
+
+func simulationWeights(contestantIDs []string, odds map[string]float64) []float64 {
+	sum, count := 0.0, 0
+	for _, contestantID := range contestantIDs {
+		if weight, ok := odds[contestantID]; ok && weight > 0 {
+			sum += weight
+			count++
+		}
+	}
+	fallback := 1.0
+	if count > 0 {
+		fallback = sum / float64(count)
+	}
+
+	weights := make([]float64, len(contestantIDs))
+	for index, contestantID := range contestantIDs {
+		weights[index] = fallback
+		if weight, ok := odds[contestantID]; ok && weight > 0 {
+			weights[index] = weight
+		}
+	}
+	return weights
+}
+
+// weightedOrder fills order with a random permutation of the indexes of
+// weights, drawing each next index with probability proportional to its
+// weight among those not yet drawn.
+func weightedOrder(rng *rand.Rand, weights []float64, order []int) {
+	for index := range order {
+		order[index] = index
+	}
+	for slot := range order {
+		total := 0.0
+		for _, index := range order[slot:] {
+			total += weights[index]
+		}
+		target := rng.Float64() * total
+		chosen := len(order) - 1
+		for candidate := slot; candidate < len(order); candidate++ {
+			target -= weights[order[candidate]]
+			if target < 0 {
+				chosen = candidate
+				break
+			}
+		}
+		order[slot], order[chosen] = order[chosen], order[slot]
+	}
+}
@@ -0,0 +1,172 @@
+package scoring
+
+// The CLI scorer implements the same strategies for local scoring. Both are
+// pinned to the cases in testdata/scoring-strategies.json at the repository
+// root; change that file and both packages together.
+const (
+	StrategyDistance        = "distance"
+	StrategyExactMatchBonus = "exact-match-bonus"
+	StrategyTopHeavy        = "top-heavy"
+	StrategyWinnerDouble    = "winner-double"
+
+	// ExactMatchBonusPoints is added to a pick that lands on its exact final
+	// position under the exact-match-bonus strategy.
+	ExactMatchBonusPoints = 5
+)
+
+// Strategy scores a single draft pick against the contestant's final position
+// and estimates how many draft points a participant can still earn.
+type Strategy interface {
+	Name() string
+	Description() string
+	PickPoints(draftPosition, finalPosition, totalPositions int) int
+	PointsAvailable(draft []DraftPick, finalPositions map[string]int, totalPositions int) int
+}
+
+// DefaultStrategy returns the distance strategy every instance used before
+// strategies were configurable.
+func DefaultStrategy() Strategy {
+	return DistanceStrategy{}
+}
+
+// Strategies lists every built-in strategy, default first.
+func Strategies() []Strategy {
+	return []Strategy{
+		DistanceStrategy{},
+		ExactMatchBonusStrategy{},
+		TopHeavyStrategy{},
+		WinnerDoubleStrategy{},
+	}
+}
+
+// StrategyByName looks up a built-in strategy. An empty name selects the
+// default strategy.
+func StrategyByName(name string) (Strategy, bool) {
+	if name == "" {
+		return DefaultStrategy(), true
+	}
+	for _, strategy := range Strategies() {
+		if strategy.Name() == name {
+			return strategy, true
+		}
+	}
+	return nil, false
+}
+
+// DistanceStrategy awards max(0, (N - final + 1) - |pick - final|) per pick.
+type DistanceStrategy struct{}
+
+func (DistanceStrategy) Name() string { return StrategyDistance }
+
+func (DistanceStrategy) Description() string {
+	return "Each pick earns its final position value minus the distance between the drafted and final positions."
+}
+
+func (DistanceStrategy) PickPoints(draftPosition, finalPosition, totalPositions int) int {
+	return distancePoints(draftPosition, finalPosition, totalPositions)
+}
+
+func (DistanceStrategy) PointsAvailable(draft []DraftPick, finalPositions map[string]int, totalPositions int) int {
+	return calculatePointsAvailable(draft, finalPositions, totalPositions)
+}
+
+// ExactMatchBonusStrategy scores like DistanceStrategy and adds a flat bonus
+// for every pick that lands on its exact final position.
+type ExactMatchBonusStrategy struct{}
+
+func (ExactMatchBonusStrategy) Name() string { return StrategyExactMatchBonus }
+
+func (ExactMatchBonusStrategy) Description() string {
+	return "Distance scoring plus 5 bonus points for every pick placed in its exact final position."
+}
+
+func (ExactMatchBonusStrategy) PickPoints(draftPosition, finalPosition, totalPositions int) int {
+	points := distancePoints(draftPosition, finalPosition, totalPositions)
+	if draftPosition == finalPosition {
+		points += ExactMatchBonusPoints
+	}
+	return points
+}
+
+func (s ExactMatchBonusStrategy) PointsAvailable(draft []DraftPick, finalPositions map[string]int, totalPositions int) int {
+	return bestCasePointsAvailable(s, draft, finalPositions, totalPositions)
+}
+
+// TopHeavyStrategy scores like DistanceStrategy but triples points for
+// contestants who finish in the top three and doubles them for fourth to
+// sixth.
+type TopHeavyStrategy struct{}
+
+func (TopHeavyStrategy) Name() string { return StrategyTopHeavy }
+
+func (TopHeavyStrategy) Description() string {
+	return "Distance scoring weighted toward the end of the game: top three finishers count triple and fourth to sixth count double."
+}
+
+func (TopHeavyStrategy) PickPoints(draftPosition, finalPosition, totalPositions int) int {
+	points := distancePoints(draftPosition, finalPosition, totalPositions)
+	switch {
+	case finalPosition <= 3:
+		return points * 3
+	case finalPosition <= 6:
+		return points * 2
+	default:
+		return points
+	}
+}
+
+func (s TopHeavyStrategy) PointsAvailable(draft []DraftPick, finalPositions map[string]int, totalPositions int) int {
+	return bestCasePointsAvailable(s, draft, finalPositions, totalPositions)
+}
+
+// WinnerDoubleStrategy scores like DistanceStrategy but doubles the points
+// earned by each participant's first pick, their predicted winner.
+type WinnerDoubleStrategy struct{}
+
+func (WinnerDoubleStrategy) Name() string { return StrategyWinnerDouble }
+
+func (WinnerDoubleStrategy) Description() string {
+	return "Distance scoring where the pick drafted to win counts double."
+}
+
+func (WinnerDoubleStrategy) PickPoints(draftPosition, finalPosition, totalPositions int) int {
+	points := distancePoints(draftPosition, finalPosition, totalPositions)
+	if draftPosition == 1 {
+		return points * 2
+	}
+	return points
+}
+
+func (s WinnerDoubleStrategy) PointsAvailable(draft []DraftPick, finalPositions map[string]int, totalPositions int) int {
+	return bestCasePointsAvailable(s, draft, finalPositions, totalPositions)
+}
+
+func distancePoints(draftPosition, finalPosition, totalPositions int) int {
+	positionValue := totalPositions - finalPosition + 1
+	return max(0, positionValue-abs(draftPosition-finalPosition))
+}
+
+// bestCasePointsAvailable sums, for every pick still in the game, the most the
+// strategy could award if that contestant finished in any open position.
+func bestCasePointsAvailable(strategy Strategy, draft []DraftPick, finalPositions map[string]int, totalPositions int) int {
+	taken := make(map[int]struct{}, len(finalPositions))
+	for _, position := range finalPositions {
+		taken[position] = struct{}{}
+	}
+
+	pointsAvailable := 0
+	for _, pick := range draft {
+		if _, finished := finalPositions[pick.ContestantID]; finished {
+			continue
+		}
+		best := 0
+		for position := 1; position <= totalPositions; position++ {
+			if _, ok := taken[position]; ok {
+				continue
+			}
+			best = max(best, strategy.PickPoints(pick.Position, position, totalPositions))
+		}
+		pointsAvailable += best
+	}
+	return pointsAvailable
+}
@@ -0,0 +1,132 @@
+package seeddata
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadSeasonConfig reads a season config file. See ParseSeasonConfig for the
+// format.
+func LoadSeasonConfig(path string) (SeasonSeed, error) {
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return SeasonSeed{}, fmt.Errorf("read season config: %w", err)
+	}
+	return ParseSeasonConfig(payload)
+}
+
+// ParseSeasonConfig decodes a YAML (or JSON) season config. The config uses the
+// same keys as the JSON seed file, so a config is a single SeasonSeed written
+// as YAML. Unknown keys are rejected so typos do not silently drop data.
+func ParseSeasonConfig(payload []byte) (SeasonSeed, error) {
+	var document any
+	if err := yaml.Unmarshal(payload, &document); err != nil {
+		return SeasonSeed{}, fmt.Errorf("parse season config: %w", err)
+	}
+	if document == nil {
+		return SeasonSeed{}, errors.New("season config is empty")
+	}
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return SeasonSeed{}, fmt.Errorf("convert season config: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	var season SeasonSeed
+	if err := decoder.Decode(&season); err != nil {
+		return SeasonSeed{}, fmt.Errorf("decode season config: %w", err)
+	}
+	if err := validateSeasonConfig(season); err != nil {
+		return SeasonSeed{}, err
+	}
+	return season, nil
+}
+
+// Checksum fingerprints a season seed so re-applying an unchanged config can
+// be detected.
+func Checksum(season SeasonSeed) (string, error) {
+	payload, err := json.Marshal(season)
+	if err != nil {
+		return "", fmt.Errorf("marshal season seed: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func validateSeasonConfig(season SeasonSeed) error {
+	if season.Season <= 0 {
+		return errors.New("season must be > 0")
+	}
+	if strings.TrimSpace(season.InstanceName) == "" {
+		return errors.New("instance_name is required")
+	}
+
+	contestants := make(map[string]struct{}, len(season.Contestants))
+	for _, name := range season.Contestants {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if key == "" {
+			return errors.New("contestant names cannot be empty")
+		}
+		if _, ok := contestants[key]; ok {
+			return fmt.Errorf("duplicate contestant: %s", name)
+		}
+		contestants[key] = struct{}{}
+	}
+
+	participants := make(map[string]struct{}, len(season.Participants))
+	discordUsers := make(map[string]string, len(season.Participants))
+	for _, participant := range season.Participants {
+		key := strings.ToLower(strings.TrimSpace(participant.Name))
+		if key == "" {
+			return errors.New("participant names cannot be empty")
+		}
+		if _, ok := participants[key]; ok {
+			return fmt.Errorf("duplicate participant: %s", participant.Name)
+		}
+		participants[key] = struct{}{}
+		if discordUserID := strings.TrimSpace(participant.DiscordUserID); discordUserID != "" {
+			if other, ok := discordUsers[discordUserID]; ok {
+				return fmt.Errorf("participants %s and %s share discord_user_id %s", other, participant.Name, discordUserID)
+			}
+			discordUsers[discordUserID] = participant.Name
+		}
+	}
+
+	episodes := make(map[int]struct{}, len(season.Episodes))
+	for _, episode := range season.Episodes {
+		if episode.EpisodeNumber < 0 {
+			return fmt.Errorf("episode_number must not be negative: %d", episode.EpisodeNumber)
+		}
+		if episode.AirsAt.IsZero() {
+			return fmt.Errorf("episode %d must include airs_at", episode.EpisodeNumber)
+		}
+		if _, ok := episodes[episode.EpisodeNumber]; ok {
+			return fmt.Errorf("duplicate episode: %d", episode.EpisodeNumber)
+		}
+		episodes[episode.EpisodeNumber] = struct{}{}
+	}
+
+	for _, group := range season.ParticipantGroups {
+		for _, membership := range group.Memberships {
+			if _, ok := participants[strings.ToLower(strings.TrimSpace(membership.ParticipantName))]; !ok {
+				return fmt.Errorf("group %s lists unknown participant %s", group.Name, membership.ParticipantName)
+			}
+		}
+	}
+
+	for _, admin := range season.Admins {
+		if strings.TrimSpace(admin) == "" {
+			return errors.New("admin discord user ids cannot be empty")
+		}
+	}
+	return nil
+}
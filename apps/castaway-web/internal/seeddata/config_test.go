@@ -0,0 +1,67 @@
+package seeddata
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadSeasonConfigExample(t *testing.T) {
+	season, err := LoadSeasonConfig("../../seeds/season-config.example.yaml")
+	if err != nil {
+		t.Fatalf("load season config: %v", err)
+	}
+	if season.Season != 51 || season.InstanceName != "Season 51 Pool" || season.State != "setup" {
+		t.Fatalf("unexpected season header: %+v", season)
+	}
+	if len(season.Episodes) != 3 || !season.Episodes[1].AirsAt.Equal(time.Date(2026, time.September, 24, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected episodes: %+v", season.Episodes)
+	}
+	if season.Participants[0].DiscordUserID != "100000000000000001" || len(season.Admins) != 1 {
+		t.Fatalf("expected discord ids and admins, got %+v / %+v", season.Participants, season.Admins)
+	}
+	if got := season.ParticipantGroups[0].Memberships; len(got) != 2 || got[1].ParticipantName != "Casey" {
+		t.Fatalf("unexpected memberships: %+v", got)
+	}
+	if tiers := season.Activities[0].RewardTiers; len(tiers) != 2 || tiers[1].Contributions != 5 || tiers[1].Bonus != 2 {
+		t.Fatalf("unexpected reward tiers: %+v", tiers)
+	}
+
+	first, err := Checksum(season)
+	if err != nil {
+		t.Fatalf("checksum: %v", err)
+	}
+	again, err := LoadSeasonConfig("../../seeds/season-config.example.yaml")
+	if err != nil {
+		t.Fatalf("reload season config: %v", err)
+	}
+	second, err := Checksum(again)
+	if err != nil {
+		t.Fatalf("checksum: %v", err)
+	}
+	if first != second {
+		t.Fatalf("expected stable checksum, got %s and %s", first, second)
+	}
+}
+
+func TestParseSeasonConfigRejectsInvalidConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{name: "unknown key", config: "season: 51\ninstance_name: Pool\ncontestant: [Alpha]\n", wantErr: "unknown field"},
+		{name: "missing season", config: "instance_name: Pool\n", wantErr: "season must be > 0"},
+		{name: "duplicate participant", config: "season: 51\ninstance_name: Pool\nparticipants:\n  - name: Riley\n  - name: riley\n", wantErr: "duplicate participant"},
+		{name: "unknown member", config: "season: 51\ninstance_name: Pool\nparticipant_groups:\n  - name: Lumo\n    memberships:\n      - participant_name: Riley\n        starts_at: 2026-09-24T00:00:00Z\n", wantErr: "unknown participant"},
+		{name: "duplicate episode", config: "season: 51\ninstance_name: Pool\nepisodes:\n  - episode_number: 1\n    airs_at: 2026-09-24T00:00:00Z\n  - episode_number: 1\n    airs_at: 2026-10-01T00:00:00Z\n", wantErr: "duplicate episode"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSeasonConfig([]byte(tc.config))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -13,14 +13,27 @@ import (
 )
 
 type SeasonSeed struct {
-	Season            int                    `json:"season"`
-	InstanceName      string                 `json:"instance_name"`
+	Season       int    `json:"season"`
+	InstanceName string `json:"instance_name"`
+	// State is the lifecycle state the seeded instance starts in. Seeded
+	// seasons are playable unless they say otherwise.
+	State             string                 `json:"state,omitempty"`
 	Contestants       []string               `json:"contestants"`
 	Participants      []ParticipantSeed      `json:"participants"`
 	Outcomes          []OutcomeSeed          `json:"outcomes"`
 	ParticipantGroups []ParticipantGroupSeed `json:"participant_groups,omitempty"`
 	Activities        []ActivitySeed         `json:"activities,omitempty"`
 	Advantages        []AdvantageSeed        `json:"advantages,omitempty"`
+	// Episodes replaces the default schedule for the season when set.
+	Episodes []EpisodeSeed `json:"episodes,omitempty"`
+	// Admins lists the Discord user ids allowed to administer the instance.
+	Admins []string `json:"admins,omitempty"`
+}
+
+type EpisodeSeed struct {
+	EpisodeNumber int       `json:"episode_number"`
+	Label         string    `json:"label,omitempty"`
+	AirsAt        time.Time `json:"airs_at"`
 }
 
 type ParticipantGroupSeed struct {
@@ -38,8 +51,9 @@ type GroupMembershipSeed struct {
 }
 
 type ParticipantSeed struct {
-	Name  string   `json:"name"`
-	Picks []string `json:"picks"`
+	Name          string   `json:"name"`
+	DiscordUserID string   `json:"discord_user_id,omitempty"`
+	Picks         []string `json:"picks"`
 }
 
 type OutcomeSeed struct {
@@ -54,11 +68,19 @@ type ActivitySeed struct {
 	StartsAt               time.Time                           `json:"starts_at"`
 	EndsAt                 *time.Time                          `json:"ends_at,omitempty"`
 	Metadata               json.RawMessage                     `json:"metadata,omitempty"`
+	RewardTiers            []RewardTierSeed                    `json:"reward_tiers,omitempty"`
 	GroupAssignments       []ActivityGroupAssignmentSeed       `json:"activity_group_assignments,omitempty"`
 	ParticipantAssignments []ActivityParticipantAssignmentSeed `json:"activity_participant_assignments,omitempty"`
 	Occurrences            []OccurrenceSeed                    `json:"occurrences,omitempty"`
 }
 
+// RewardTierSeed is a contribution threshold and the bonus it earns. Tiers are
+// stored in the activity metadata as reward_tiers.
+type RewardTierSeed struct {
+	Contributions int `json:"contributions"`
+	Bonus         int `json:"bonus"`
+}
+
 type ActivityGroupAssignmentSeed struct {
 	ParticipantGroupName string          `json:"participant_group_name"`
 	Role                 string          `json:"role,omitempty"`
@@ -103,6 +125,7 @@ type AdvantageSeed struct {
 	AdvantageType   string          `json:"advantage_type"`
 	Name            string          `json:"name"`
 	Status          string          `json:"status,omitempty"`
+	Visibility      string          `json:"visibility,omitempty"`
 	GrantedAt       time.Time       `json:"granted_at"`
 	EffectiveAt     time.Time       `json:"effective_at"`
 	EffectiveUntil  *time.Time      `json:"effective_until,omitempty"`
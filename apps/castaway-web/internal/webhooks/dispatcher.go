@@ -0,0 +1,384 @@
+// Package webhooks delivers outbox events to subscriber URLs. Events are
+// written to Postgres in the same transaction as the change that caused them,
+// so a delivery is only attempted for changes that committed, and each
+// delivery is retried with backoff until it succeeds or is marked dead.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bry-guy/srvivor/apps/castaway-web/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Domain event types written to the outbox.
+const (
+	EventOccurrenceResolved = "occurrence.resolved"
+	EventOutcomeRecorded    = "outcome.recorded"
+	EventAuctionLotClosed   = "auction_lot.closed"
+	EventLoanDefaulted      = "loan.defaulted"
+	EventSecretRevealed     = "secret.revealed"
+)
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+// Headers sent with every delivery.
+const (
+	SignatureHeader = "X-Castaway-Signature"
+	EventHeader     = "X-Castaway-Event"
+	DeliveryHeader  = "X-Castaway-Delivery"
+)
+
+const (
+	// DefaultMaxAttempts is how many times a delivery is tried before it is
+	// marked dead.
+	DefaultMaxAttempts = 8
+	// DefaultTimeout bounds each HTTP attempt.
+	DefaultTimeout = 10 * time.Second
+	// leaseMargin is added to the client timeout to get how long a claimed
+	// delivery is hidden from other dispatchers. A dispatcher that dies
+	// mid-request leaves the delivery to be retried once the lease runs out.
+	leaseMargin = time.Minute
+
+	maxBackoff = 6 * time.Hour
+)
+
+var eventTypes = []string{
+	EventAuctionLotClosed,
+	EventLoanDefaulted,
+	EventOccurrenceResolved,
+	EventOutcomeRecorded,
+	EventSecretRevealed,
+}
+
+// EventTypes lists every event type subscribers can filter on.
+func EventTypes() []string {
+	return append([]string(nil), eventTypes...)
+}
+
+// IsEventType reports whether eventType is one the outbox emits.
+func IsEventType(eventType string) bool {
+	for _, known := range eventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Envelope is the JSON body posted to subscribers.
+type Envelope struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	InstanceID string          `json:"instance_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type Dispatcher struct {
+	pool         *pgxpool.Pool
+	queries      *db.Queries
+	client       *http.Client
+	allowPrivate bool
+	now          func() time.Time
+	maxAttempts  int32
+	logger       *slog.Logger
+}
+
+type Option func(*Dispatcher)
+
+// WithClock replaces the wall clock, so tests can make retries due at once.
+func WithClock(now func() time.Time) Option {
+	return func(d *Dispatcher) {
+		d.now = now
+	}
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+// WithPrivateTargets lets the default client connect to loopback, private
+// and link-local addresses. It is meant for local development and tests;
+// WithHTTPClient replaces the client and its address checks entirely.
+func WithPrivateTargets(allowed bool) Option {
+	return func(d *Dispatcher) {
+		d.allowPrivate = allowed
+	}
+}
+
+func WithMaxAttempts(maxAttempts int32) Option {
+	return func(d *Dispatcher) {
+		d.maxAttempts = maxAttempts
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(d *Dispatcher) {
+		d.logger = logger
+	}
+}
+
+func New(pool *pgxpool.Pool, options ...Option) *Dispatcher {
+	dispatcher := &Dispatcher{
+		pool:        pool,
+		queries:     db.New(pool),
+		now:         func() time.Time { return time.Now().UTC() },
+		maxAttempts: DefaultMaxAttempts,
+		logger:      slog.Default(),
+	}
+	for _, option := range options {
+		option(dispatcher)
+	}
+	if dispatcher.client == nil {
+		dispatcher.client = newClient(DefaultTimeout, dispatcher.allowPrivate)
+	}
+	return dispatcher
+}
+
+// RunDue sends every delivery whose next attempt is due, returning how many
+// were attempted.
+func (d *Dispatcher) RunDue(ctx context.Context) (int, error) {
+	attempted := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return attempted, err
+		}
+		claimed, err := d.deliverNext(ctx)
+		if err != nil {
+			return attempted, err
+		}
+		if !claimed {
+			return attempted, nil
+		}
+		attempted++
+	}
+}
+
+// Run calls RunDue every interval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if attempted, err := d.RunDue(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Error("webhook dispatch failed", slog.String("service", "castaway-web"), slog.String("error", err.Error()))
+		} else if attempted > 0 {
+			d.logger.Info("webhook deliveries attempted", slog.String("service", "castaway-web"), slog.Int("deliveries", attempted))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverNext claims one due delivery, posts it and records the result. The
+// claim commits before the request goes out, leasing the delivery by moving
+// its next attempt past the request timeout, so no row lock or transaction
+// is held while waiting on the subscriber.
+func (d *Dispatcher) deliverNext(ctx context.Context) (bool, error) {
+	now := d.now()
+	delivery, claimed, err := d.claim(ctx, now)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	statusCode, sendErr := d.send(ctx, delivery, now)
+	if sendErr != nil {
+		lastStatusCode := pgtype.Int4{}
+		if statusCode != 0 {
+			lastStatusCode = pgtype.Int4{Int32: int32(statusCode), Valid: true}
+		}
+		if err := d.queries.FailWebhookDelivery(ctx, db.FailWebhookDeliveryParams{
+			LastStatusCode: lastStatusCode,
+			LastError:      pgtype.Text{String: sendErr.Error(), Valid: true},
+			MaxAttempts:    d.maxAttempts,
+			RetryAt:        pgtype.Timestamptz{Time: now.Add(Backoff(delivery.Attempts)), Valid: true},
+			ID:             delivery.ID,
+		}); err != nil {
+			return false, fmt.Errorf("record failed delivery: %w", err)
+		}
+		d.logger.Warn("webhook delivery failed",
+			slog.String("service", "castaway-web"),
+			slog.String("event_type", delivery.EventType),
+			slog.Int("attempt", int(delivery.Attempts)+1),
+			slog.String("error", sendErr.Error()),
+		)
+		return true, nil
+	}
+
+	if err := d.queries.CompleteWebhookDelivery(ctx, db.CompleteWebhookDeliveryParams{
+		LastStatusCode: pgtype.Int4{Int32: int32(statusCode), Valid: true},
+		DeliveredAt:    pgtype.Timestamptz{Time: d.now(), Valid: true},
+		ID:             delivery.ID,
+	}); err != nil {
+		return false, fmt.Errorf("complete webhook delivery: %w", err)
+	}
+	return true, nil
+}
+
+// claim locks the next due delivery, leases it and commits.
+func (d *Dispatcher) claim(ctx context.Context, now time.Time) (db.ClaimDueWebhookDeliveryRow, bool, error) {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return db.ClaimDueWebhookDeliveryRow{}, false, fmt.Errorf("begin webhook tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	q := d.queries.WithTx(tx)
+
+	delivery, err := q.ClaimDueWebhookDelivery(ctx, pgtype.Timestamptz{Time: now, Valid: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.ClaimDueWebhookDeliveryRow{}, false, nil
+	}
+	if err != nil {
+		return db.ClaimDueWebhookDeliveryRow{}, false, fmt.Errorf("claim webhook delivery: %w", err)
+	}
+	if err := q.LeaseWebhookDelivery(ctx, db.LeaseWebhookDeliveryParams{
+		LeaseUntil: pgtype.Timestamptz{Time: now.Add(d.leaseDuration()), Valid: true},
+		ID:         delivery.ID,
+	}); err != nil {
+		return db.ClaimDueWebhookDeliveryRow{}, false, fmt.Errorf("lease webhook delivery: %w", err)
+	}
+	if err := commit(ctx, tx); err != nil {
+		return db.ClaimDueWebhookDeliveryRow{}, false, err
+	}
+	return delivery, true, nil
+}
+
+// leaseDuration outlasts the longest request the client can make.
+func (d *Dispatcher) leaseDuration() time.Duration {
+	timeout := d.client.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return timeout + leaseMargin
+}
+
+// send posts one delivery and returns the response status. Any status
+// outside 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, delivery db.ClaimDueWebhookDeliveryRow, now time.Time) (int, error) {
+	body, err := json.Marshal(Envelope{
+		ID:         uuid.UUID(delivery.EventID.Bytes).String(),
+		Type:       delivery.EventType,
+		InstanceID: uuid.UUID(delivery.InstanceID.Bytes).String(),
+		OccurredAt: delivery.OccurredAt.Time.UTC(),
+		Data:       json.RawMessage(delivery.Payload),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("encode webhook body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, uuid.UUID(delivery.ID.Bytes).String())
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, now, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("subscriber responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value for body sent at: the Unix
+// timestamp and a hex HMAC-SHA256 of "<timestamp>.<body>" keyed by secret,
+// formatted as "t=<timestamp>,v1=<hmac>". Subscribers recompute the HMAC
+// and should reject stale timestamps to stop replays.
+func Sign(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + signature(secret, timestamp, body)
+}
+
+// Verify checks header against body and secret, rejecting signatures older
+// than tolerance.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var timestamp, sent string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			sent = value
+		}
+	}
+	if timestamp == "" || sent == "" {
+		return errors.New("malformed signature header")
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("malformed signature timestamp")
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return errors.New("signature timestamp outside tolerance")
+	}
+	if !hmac.Equal([]byte(sent), []byte(signature(secret, timestamp, body))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func signature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff is the delay before retrying a delivery that has failed attempts
+// times: thirty seconds, doubling up to six hours.
+func Backoff(attempts int32) time.Duration {
+	delay := 30 * time.Second
+	for i := int32(0); i < attempts; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
+
+func commit(ctx context.Context, tx pgx.Tx) error {
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit webhook tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDoublesUpToSixHours(t *testing.T) {
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{attempts: 0, want: 30 * time.Second},
+		{attempts: 1, want: time.Minute},
+		{attempts: 4, want: 8 * time.Minute},
+		{attempts: 10, want: 6 * time.Hour},
+		{attempts: 40, want: 6 * time.Hour},
+	}
+	for _, tc := range tests {
+		if got := Backoff(tc.attempts); got != tc.want {
+			t.Fatalf("Backoff(%d) = %s, want %s", tc.attempts, got, tc.want)
+		}
+	}
+}
+
+func TestSignRoundTripsThroughVerify(t *testing.T) {
+	sentAt := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"type":"outcome.recorded"}`)
+	header := Sign("whsec_test", sentAt, body)
+
+	if err := Verify("whsec_test", header, body, sentAt.Add(time.Minute), 5*time.Minute); err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if err := Verify("whsec_other", header, body, sentAt, 5*time.Minute); err == nil {
+		t.Fatal("expected a different secret to fail verification")
+	}
+	if err := Verify("whsec_test", header, []byte(`{"type":"loan.defaulted"}`), sentAt, 5*time.Minute); err == nil {
+		t.Fatal("expected a changed body to fail verification")
+	}
+	if err := Verify("whsec_test", header, body, sentAt.Add(10*time.Minute), 5*time.Minute); err == nil {
+		t.Fatal("expected a stale signature to fail verification")
+	}
+	if err := Verify("whsec_test", "v1=abc", body, sentAt, 5*time.Minute); err == nil {
+		t.Fatal("expected a header without a timestamp to fail verification")
+	}
+}
+
+func TestIsEventType(t *testing.T) {
+	for _, eventType := range EventTypes() {
+		if !IsEventType(eventType) {
+			t.Fatalf("IsEventType(%q) = false", eventType)
+		}
+	}
+	if IsEventType("instance.deleted") {
+		t.Fatal("IsEventType accepted an unknown event type")
+	}
+}
@@ -0,0 +1,96 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrDisallowedTarget is returned for webhook URLs that point at loopback,
+// private, link-local or other internal addresses, such as a cloud metadata
+// service.
+var ErrDisallowedTarget = errors.New("webhook target address is not allowed")
+
+// sharedAddressSpace is the carrier-grade NAT range, which netip does not
+// count as private but is just as internal.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// PublicAddr reports whether addr is a public unicast address a webhook may be
+// sent to.
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	switch {
+	case !addr.IsValid(),
+		addr.IsUnspecified(),
+		addr.IsLoopback(),
+		addr.IsPrivate(),
+		addr.IsLinkLocalUnicast(),
+		addr.IsLinkLocalMulticast(),
+		addr.IsInterfaceLocalMulticast(),
+		addr.IsMulticast(),
+		sharedAddressSpace.Contains(addr):
+		return false
+	}
+	return true
+}
+
+// ValidateTarget parses raw as an absolute http or https URL and, unless
+// allowPrivate is set, resolves its host and rejects it when any address it
+// resolves to is not public. The dispatcher checks again at dial time, so a
+// name that later resolves somewhere internal is still refused.
+func ValidateTarget(ctx context.Context, raw string, allowPrivate bool) (*url.URL, error) {
+	target, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return nil, errors.New("url must be an absolute http or https URL")
+	}
+	if allowPrivate {
+		return target, nil
+	}
+	host := target.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !PublicAddr(addr) {
+			return nil, fmt.Errorf("%w: %s", ErrDisallowedTarget, host)
+		}
+		return target, nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, fmt.Errorf("resolve webhook host %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !PublicAddr(addr) {
+			return nil, fmt.Errorf("%w: %s resolves to %s", ErrDisallowedTarget, host, addr.Unmap())
+		}
+	}
+	return target, nil
+}
+
+// newClient returns the dispatcher's HTTP client. Unless allowPrivate is set
+// it refuses to connect to any address that is not public, which also covers
+// redirects and DNS answers that changed since the subscription was created.
+func newClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !PublicAddr(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrDisallowedTarget, addrPort.Addr().Unmap())
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
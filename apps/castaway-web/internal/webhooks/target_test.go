@@ -0,0 +1,74 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestPublicAddrRejectsInternalRanges(t *testing.T) {
+	for _, raw := range []string{
+		"127.0.0.1",
+		"::1",
+		"0.0.0.0",
+		"10.1.2.3",
+		"172.16.0.1",
+		"192.168.1.1",
+		"169.254.169.254",
+		"100.64.0.1",
+		"fd00:ec2::254",
+		"fe80::1",
+		"::ffff:127.0.0.1",
+		"224.0.0.1",
+	} {
+		if PublicAddr(netip.MustParseAddr(raw)) {
+			t.Fatalf("PublicAddr(%s) = true", raw)
+		}
+	}
+	for _, raw := range []string{"93.184.216.34", "2606:4700::1111"} {
+		if !PublicAddr(netip.MustParseAddr(raw)) {
+			t.Fatalf("PublicAddr(%s) = false", raw)
+		}
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	ctx := context.Background()
+	for _, raw := range []string{"ftp://example.com", "/hooks", "https://"} {
+		if _, err := ValidateTarget(ctx, raw, false); err == nil || errors.Is(err, ErrDisallowedTarget) {
+			t.Fatalf("ValidateTarget(%q) = %v, want a URL error", raw, err)
+		}
+	}
+	for _, raw := range []string{"http://127.0.0.1:8080/hook", "http://169.254.169.254/latest/meta-data", "https://[fd00::1]/hook"} {
+		if _, err := ValidateTarget(ctx, raw, false); !errors.Is(err, ErrDisallowedTarget) {
+			t.Fatalf("ValidateTarget(%q) = %v, want ErrDisallowedTarget", raw, err)
+		}
+	}
+	target, err := ValidateTarget(ctx, " http://127.0.0.1:8080/hook ", true)
+	if err != nil || target.String() != "http://127.0.0.1:8080/hook" {
+		t.Fatalf("ValidateTarget with private targets allowed = %v, %v", target, err)
+	}
+	if _, err := ValidateTarget(ctx, "https://93.184.216.34/hook", false); err != nil {
+		t.Fatalf("ValidateTarget rejected a public address: %v", err)
+	}
+}
+
+func TestClientRefusesInternalAddressesAtDial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if _, err := newClient(time.Second, false).Get(server.URL); !errors.Is(err, ErrDisallowedTarget) {
+		t.Fatalf("expected the dial to be refused, got %v", err)
+	}
+	resp, err := newClient(time.Second, true).Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected private targets to be allowed: %v", err)
+	}
+	resp.Body.Close()
+}
@@ -34,6 +34,7 @@ func newScoreCmd(m messager.Messager) *cobra.Command {
 	scoreCmd.Flags().BoolP("points-available", "p", false, "Show points available")
 	scoreCmd.Flags().Bool("publish", false, "Publish scores to Discord bot")
 	scoreCmd.Flags().StringSlice("voted-out", []string{}, "Names of contestants voted out this week")
+	scoreCmd.Flags().String("strategy", scorer.StrategyDistance, fmt.Sprintf("Scoring strategy (%s)", strings.Join(scorer.StrategyNames(), ", ")))
 	if err := scoreCmd.MarkFlagRequired("season"); err != nil {
 		slog.Error("creating score command", "error", err)
 		os.Exit(1)
@@ -80,6 +81,17 @@ func runScore(cmd *cobra.Command, args []string, m messager.Messager) {
 		os.Exit(1)
 	}
 
+	strategyName, err := cmd.Flags().GetString("strategy")
+	if err != nil {
+		slog.Error("parsing strategy flag", "error", err)
+		os.Exit(1)
+	}
+	strategy, ok := scorer.StrategyByName(strings.TrimSpace(strategyName))
+	if !ok {
+		slog.Error("unknown scoring strategy", "strategy", strategyName, "available", scorer.StrategyNames())
+		os.Exit(1)
+	}
+
 	if publish && len(votedOut) == 0 {
 		slog.Error("voted-out is required when publishing")
 		os.Exit(1)
@@ -184,8 +196,8 @@ func runScore(cmd *cobra.Command, args []string, m messager.Messager) {
 		fmt.Printf("Validation passed for season %d\n", season)
 	}
 
-	slog.Info("Calculating score for each draft.")
-	scores, err := scorer.Scores(drafts, final)
+	slog.Info("Calculating score for each draft.", "strategy", strategy.Name())
+	scores, err := scorer.ScoresWithStrategy(drafts, final, strategy)
 	if err != nil {
 		slog.Error("Failed to score drafts.", "error", err)
 		os.Exit(1)
@@ -205,7 +217,7 @@ func runScore(cmd *cobra.Command, args []string, m messager.Messager) {
 	})
 
 	if publish {
-		message := buildMessage(season, votedOut, drafts, scores, pointsAvailable)
+		message := buildMessage(season, votedOut, drafts, scores, pointsAvailable, strategy.Name())
 		err = m.Send(message)
 		if err != nil {
 			slog.Error("failed to publish", "error", err)
@@ -221,6 +233,7 @@ func runScore(cmd *cobra.Command, args []string, m messager.Messager) {
 		}
 	}
 
+	fmt.Printf("Scoring strategy: %s\n", strategy.Name())
 	for _, d := range drafts {
 		result := scores[d]
 		if pointsAvailable {
@@ -306,7 +319,7 @@ func validateDraft(draft *scorer.Draft, canonicalNames map[string]bool, name str
 	return nil
 }
 
-func buildMessage(season int, votedOut []string, drafts []*scorer.Draft, scores map[*scorer.Draft]scorer.ScoreResult, showPoints bool) string {
+func buildMessage(season int, votedOut []string, drafts []*scorer.Draft, scores map[*scorer.Draft]scorer.ScoreResult, showPoints bool, strategy string) string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("**Survivor Season %d Scores**\n", season))
 	if len(votedOut) > 0 {
@@ -321,6 +334,6 @@ func buildMessage(season int, votedOut []string, drafts []*scorer.Draft, scores
 			sb.WriteString(fmt.Sprintf("%d. %s: %d\n", i+1, d.Metadata.Drafter, result.Score))
 		}
 	}
-	sb.WriteString("\n*Scores calculated automatically.*")
+	sb.WriteString(fmt.Sprintf("\n*Scores calculated automatically using the %s strategy.*", strategy))
 	return sb.String()
 }
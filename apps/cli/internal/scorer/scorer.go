@@ -41,7 +41,13 @@ type ScoreResult struct {
 // by eliminated players) and the points still available (from remaining
 // players). The internal logic and return values are preserved.
 func score(draft, final *Draft) (ScoreResult, error) {
-	log := slog.With("draft", draft.Metadata.Drafter)
+	return scoreWithStrategy(draft, final, DefaultStrategy())
+}
+
+// scoreWithStrategy is score with the per-pick calculations delegated to
+// strategy.
+func scoreWithStrategy(draft, final *Draft, strategy Strategy) (ScoreResult, error) {
+	log := slog.With("draft", draft.Metadata.Drafter, "strategy", strategy.Name())
 	var result ScoreResult
 	totalPositions := len(final.Entries)
 	log.Debug("final", "total_positions", totalPositions)
@@ -79,8 +85,8 @@ func score(draft, final *Draft) (ScoreResult, error) {
 	// Delegate to focused calculation functions. Each function is
 	// responsible for a single aspect of the computation and can be
 	// tested independently.
-	currentScore := calculateCurrentScore(draft, finalPositions, totalPositions)
-	pointsAvailable := calculatePointsAvailable(draft, finalPositions, totalPositions)
+	currentScore := strategy.CurrentScore(draft, finalPositions, totalPositions)
+	pointsAvailable := strategy.PointsAvailable(draft, finalPositions, totalPositions)
 
 	result.Score = currentScore
 	result.PointsAvailable = pointsAvailable
@@ -225,9 +231,14 @@ func max(a, b int) int {
 }
 
 func Scores(drafts []*Draft, final *Draft) (map[*Draft]ScoreResult, error) {
+	return ScoresWithStrategy(drafts, final, DefaultStrategy())
+}
+
+// ScoresWithStrategy scores every draft against final using strategy.
+func ScoresWithStrategy(drafts []*Draft, final *Draft, strategy Strategy) (map[*Draft]ScoreResult, error) {
 	scores := map[*Draft]ScoreResult{}
 	for _, draft := range drafts {
-		result, err := score(draft, final)
+		result, err := scoreWithStrategy(draft, final, strategy)
 		if err != nil {
 			return nil, err
 		}
@@ -1,6 +1,7 @@
 package scorer
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -292,3 +293,79 @@ func TestScoreCalculation_FixturesRegression(t *testing.T) {
 		})
 	}
 }
+
+func TestStrategies_ScoreEliminatedPicks(t *testing.T) {
+	draft := makeDraft([]string{"A", "B", "C"})
+	finalPositions := map[string]int{"A": 1, "B": 3, "C": 2}
+
+	expected := map[string]int{
+		StrategyDistance:        4,
+		StrategyExactMatchBonus: 4 + ExactMatchBonusPoints,
+		StrategyTopHeavy:        12,
+		StrategyWinnerDouble:    7,
+	}
+	for name, want := range expected {
+		strategy, ok := StrategyByName(name)
+		assert.True(t, ok, "strategy %s should be registered", name)
+		assert.Equal(t, want, strategy.CurrentScore(draft, finalPositions, 3), "strategy %s current score", name)
+	}
+
+	_, ok := StrategyByName("coin-flip")
+	assert.False(t, ok, "unknown strategy should not resolve")
+}
+
+func TestScoresWithStrategy_UsesStrategy(t *testing.T) {
+	draft := makeDraft([]string{"Tom", "Dick", "Harry", "Cosmo", "Elaine", "Larry", "Moe", "Curly"})
+	final := makeFinal(8, map[string]int{"Larry": 5, "Dick": 6, "Harry": 7, "Moe": 8}, "Current")
+
+	distance, err := ScoresWithStrategy([]*Draft{draft}, final, DefaultStrategy())
+	assert.NoError(t, err)
+	assert.Equal(t, ScoreResult{Score: 3, PointsAvailable: 13}, distance[draft])
+
+	winnerDouble, _ := StrategyByName(StrategyWinnerDouble)
+	doubled, err := ScoresWithStrategy([]*Draft{draft}, final, winnerDouble)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, doubled[draft].Score, "winner pick has not been eliminated yet")
+	assert.Greater(t, doubled[draft].PointsAvailable, distance[draft].PointsAvailable, "winner pick is still worth double")
+}
+
+// sharedStrategyCases is the fixture castaway-web's scoring package is also
+// tested against, so the two implementations cannot drift apart.
+type sharedStrategyCases struct {
+	Strategies []struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	} `json:"strategies"`
+	Cases []struct {
+		Name           string         `json:"name"`
+		TotalPositions int            `json:"total_positions"`
+		Draft          []string       `json:"draft"`
+		FinalPositions map[string]int `json:"final_positions"`
+		Expected       map[string]struct {
+			Score           int `json:"score"`
+			PointsAvailable int `json:"points_available"`
+		} `json:"expected"`
+	} `json:"cases"`
+}
+
+func TestStrategies_MatchSharedCases(t *testing.T) {
+	raw, err := os.ReadFile("../../../../testdata/scoring-strategies.json")
+	assert.NoError(t, err)
+	var fixture sharedStrategyCases
+	assert.NoError(t, json.Unmarshal(raw, &fixture))
+
+	assert.Len(t, Strategies(), len(fixture.Strategies), "every strategy should be in the shared fixture")
+	for i, want := range fixture.Strategies {
+		assert.Equal(t, want.Name, Strategies()[i].Name())
+		assert.Equal(t, want.Description, Strategies()[i].Description(), "strategy %s description", want.Name)
+	}
+	for _, tc := range fixture.Cases {
+		draft := makeDraft(tc.Draft)
+		for name, want := range tc.Expected {
+			strategy, ok := StrategyByName(name)
+			assert.True(t, ok, "strategy %s should be registered", name)
+			assert.Equal(t, want.Score, strategy.CurrentScore(draft, tc.FinalPositions, tc.TotalPositions), "%s: %s current score", tc.Name, name)
+			assert.Equal(t, want.PointsAvailable, strategy.PointsAvailable(draft, tc.FinalPositions, tc.TotalPositions), "%s: %s points available", tc.Name, name)
+		}
+	}
+}
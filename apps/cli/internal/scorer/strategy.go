@@ -0,0 +1,179 @@
+package scorer
+
+import "sort"
+
+// Strategy names match the scoring strategies offered by castaway-web so a
+// season scored locally can be compared with the hosted leaderboard. Both
+// implementations are pinned to the cases in testdata/scoring-strategies.json
+// at the repository root; change that file and both packages together.
+const (
+	StrategyDistance        = "distance"
+	StrategyExactMatchBonus = "exact-match-bonus"
+	StrategyTopHeavy        = "top-heavy"
+	StrategyWinnerDouble    = "winner-double"
+
+	// ExactMatchBonusPoints is added to every pick that lands on its exact
+	// final position under the exact-match-bonus strategy.
+	ExactMatchBonusPoints = 5
+)
+
+// Strategy computes the current score and the points still available for a
+// draft given the final positions known so far.
+type Strategy interface {
+	Name() string
+	Description() string
+	CurrentScore(draft *Draft, finalPositions map[string]int, totalPositions int) int
+	PointsAvailable(draft *Draft, finalPositions map[string]int, totalPositions int) int
+}
+
+// DefaultStrategy returns the distance strategy, which is the formula the
+// scorer has always used.
+func DefaultStrategy() Strategy {
+	return distanceStrategy{}
+}
+
+// Strategies returns every built-in strategy, default first.
+func Strategies() []Strategy {
+	return []Strategy{
+		distanceStrategy{},
+		pickStrategy{
+			name:        StrategyExactMatchBonus,
+			description: "Distance scoring plus 5 bonus points for every pick placed in its exact final position.",
+			pickPoints:  exactMatchBonusPoints,
+		},
+		pickStrategy{
+			name:        StrategyTopHeavy,
+			description: "Distance scoring weighted toward the end of the game: top three finishers count triple and fourth to sixth count double.",
+			pickPoints:  topHeavyPoints,
+		},
+		pickStrategy{
+			name:        StrategyWinnerDouble,
+			description: "Distance scoring where the pick drafted to win counts double.",
+			pickPoints:  winnerDoublePoints,
+		},
+	}
+}
+
+// StrategyByName looks up a built-in strategy by name. An empty name selects
+// the default strategy.
+func StrategyByName(name string) (Strategy, bool) {
+	if name == "" {
+		return DefaultStrategy(), true
+	}
+	for _, strategy := range Strategies() {
+		if strategy.Name() == name {
+			return strategy, true
+		}
+	}
+	return nil, false
+}
+
+// StrategyNames returns the names of the built-in strategies in sorted order,
+// for use in help text and error messages.
+func StrategyNames() []string {
+	names := []string{}
+	for _, strategy := range Strategies() {
+		names = append(names, strategy.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// distanceStrategy wraps the legacy calculations so existing regression
+// results are preserved exactly.
+type distanceStrategy struct{}
+
+func (distanceStrategy) Name() string { return StrategyDistance }
+
+func (distanceStrategy) Description() string {
+	return "Each pick earns its final position value minus the distance between the drafted and final positions."
+}
+
+func (distanceStrategy) CurrentScore(draft *Draft, finalPositions map[string]int, totalPositions int) int {
+	return calculateCurrentScore(draft, finalPositions, totalPositions)
+}
+
+func (distanceStrategy) PointsAvailable(draft *Draft, finalPositions map[string]int, totalPositions int) int {
+	return calculatePointsAvailable(draft, finalPositions, totalPositions)
+}
+
+// pickStrategy scores each eliminated pick independently with pickPoints and
+// estimates points available as the best score each remaining pick could
+// still earn in any open final position.
+type pickStrategy struct {
+	name        string
+	description string
+	pickPoints  func(draftPosition, finalPosition, totalPositions int) int
+}
+
+func (s pickStrategy) Name() string { return s.name }
+
+func (s pickStrategy) Description() string { return s.description }
+
+func (s pickStrategy) CurrentScore(draft *Draft, finalPositions map[string]int, totalPositions int) int {
+	currentScore := 0
+	for _, entry := range draft.Entries {
+		if finalPosition, ok := finalPositions[entry.PlayerName]; ok {
+			currentScore += s.pickPoints(entry.position, finalPosition, totalPositions)
+		}
+	}
+	return currentScore
+}
+
+func (s pickStrategy) PointsAvailable(draft *Draft, finalPositions map[string]int, totalPositions int) int {
+	taken := make(map[int]struct{}, len(finalPositions))
+	for _, position := range finalPositions {
+		taken[position] = struct{}{}
+	}
+
+	pointsAvailable := 0
+	for _, entry := range draft.Entries {
+		if _, ok := finalPositions[entry.PlayerName]; ok {
+			continue
+		}
+		best := 0
+		for position := 1; position <= totalPositions; position++ {
+			if _, ok := taken[position]; ok {
+				continue
+			}
+			best = max(best, s.pickPoints(entry.position, position, totalPositions))
+		}
+		pointsAvailable += best
+	}
+	return pointsAvailable
+}
+
+// distancePoints is the per-pick distance formula:
+// max(0, (totalPositions - finalPosition + 1) - |draftPosition - finalPosition|).
+func distancePoints(draftPosition, finalPosition, totalPositions int) int {
+	positionValue := totalPositions - finalPosition + 1
+	return max(0, positionValue-abs(draftPosition-finalPosition))
+}
+
+func exactMatchBonusPoints(draftPosition, finalPosition, totalPositions int) int {
+	points := distancePoints(draftPosition, finalPosition, totalPositions)
+	if draftPosition == finalPosition {
+		points += ExactMatchBonusPoints
+	}
+	return points
+}
+
+func topHeavyPoints(draftPosition, finalPosition, totalPositions int) int {
+	points := distancePoints(draftPosition, finalPosition, totalPositions)
+	switch {
+	case finalPosition <= 3:
+		return points * 3
+	case finalPosition <= 6:
+		return points * 2
+	default:
+		return points
+	}
+}
+
+func winnerDoublePoints(draftPosition, finalPosition, totalPositions int) int {
+	points := distancePoints(draftPosition, finalPosition, totalPositions)
+	if draftPosition == 1 {
+		return points * 2
+	}
+	return points
+}